@@ -6,14 +6,110 @@ import (
 	"io"
 
 	"github.com/encodingx/binary/internal/codecs"
+	"github.com/encodingx/binary/internal/order"
 	"github.com/encodingx/binary/internal/validation"
 )
 
 var (
-	defaultCodec codecs.Codec = codecs.NewCodec()
+	defaultCodec = NewCodec(nil)
 )
 
+// Codec marshals and unmarshals format-structs
+// like the functions Marshal and Unmarshal,
+// with additional configuration.
+type Codec struct {
+	codec codecs.Codec
+}
+
+// KeyProvider provides the keys of MAC words to a Codec.
+// Words are declared to hold a MAC with an option of their struct tag,
+// naming the algorithm ("hmac-sha256" or "aes-cmac")
+// and optionally the range of bytes it covers,
+// by default all preceding words:
+//
+//	Word3 `word:"64,mac=hmac-sha256"`
+//	Word3 `word:"64,mac=aes-cmac,range=4-12"`
+//
+// The MAC is truncated to the length of the word.
+type KeyProvider = codecs.KeyProvider
+
+// KeyProviderFunc adapts a function to a KeyProvider.
+type KeyProviderFunc = codecs.KeyProviderFunc
+
+// AuthenticationError is returned by Unmarshal
+// when a MAC word does not match the MAC computed over its byte range.
+type AuthenticationError = validation.AuthenticationError
+
+// PaddingError is returned by Unmarshal
+// when padding declared "padding=zero" is not zero.
+type PaddingError = validation.PaddingError
+
+// DecodingError is returned by Unmarshal under UnmarshalBestEffort
+// when words fail to decode.
+// Its method Errors returns the error of each,
+// such as a *PaddingError or an *AuthenticationError,
+// which errors.Is and errors.As also find.
+type DecodingError = validation.DecodingError
+
+// Trailer is implemented by pointers to a field of variable length
+// following the words of a format-struct, such as a list of TLVs.
+// It is declared last in a format-struct,
+// optionally tagged with a key "trailer" and an empty value:
+//
+//	Options tlv.TLVListField `trailer:""`
+//
+// Marshal appends the bytes of a trailer to those of the words,
+// and Unmarshal passes it every byte following them.
+// Trailers run to the end of their bytes,
+// so an Encoder or Decoder does not stream them.
+type Trailer = codecs.Trailer
+
+// UnmarshalPolicy determines what Unmarshal leaves in a format-struct
+// when words fail to decode.
+type UnmarshalPolicy = codecs.UnmarshalPolicy
+
+const (
+	// UnmarshalAtomic verifies every word before setting any,
+	// leaving a format-struct untouched on error, and returns the first.
+	// It is the policy of a new codec.
+	UnmarshalAtomic = codecs.UnmarshalAtomic
+
+	// UnmarshalBestEffort sets every word that decodes,
+	// including those covered by a MAC that does not match,
+	// and returns an error wrapping *DecodingError
+	// listing the error of each word that does not.
+	UnmarshalBestEffort = codecs.UnmarshalBestEffort
+)
+
+// NewCodec returns a codec that obtains the keys of MAC words
+// from keyProvider, which may be nil if no format has MAC words.
+func NewCodec(keyProvider KeyProvider) (c *Codec) {
+	c = &Codec{
+		codec: codecs.NewCodec(keyProvider),
+	}
+
+	return
+}
+
+// SetUnmarshalPolicy sets the policy of Unmarshal,
+// and of Decode for decoders of the codec.
+func (c *Codec) SetUnmarshalPolicy(policy UnmarshalPolicy) {
+	c.codec.SetUnmarshalPolicy(policy)
+
+	return
+}
+
 func Marshal(iface interface{}) (bytes []byte, e error) {
+	return defaultCodec.Marshal(iface)
+}
+
+func Unmarshal(bytes []byte, iface interface{}) (e error) {
+	return defaultCodec.Unmarshal(bytes, iface)
+}
+
+// Marshal computes MACs after marshalling the words of a format-struct
+// and writes them into the MAC words in place of their bit fields.
+func (c *Codec) Marshal(iface interface{}) (bytes []byte, e error) {
 	const (
 		functionName = "Marshal"
 	)
@@ -36,7 +132,7 @@ func Marshal(iface interface{}) (bytes []byte, e error) {
 		return
 	}()
 
-	operation, e = defaultCodec.NewOperation(iface)
+	operation, e = c.codec.NewOperation(iface)
 	if e != nil {
 		return
 	}
@@ -49,7 +145,11 @@ func Marshal(iface interface{}) (bytes []byte, e error) {
 	return
 }
 
-func Unmarshal(bytes []byte, iface interface{}) (e error) {
+// Unmarshal verifies MACs in constant time
+// before unmarshalling any word of a format-struct,
+// and returns an error wrapping *AuthenticationError if one does not match.
+// What is unmarshalled on error depends on the policy of the codec.
+func (c *Codec) Unmarshal(bytes []byte, iface interface{}) (e error) {
 	const (
 		functionName = "Unmarshal"
 	)
@@ -72,7 +172,7 @@ func Unmarshal(bytes []byte, iface interface{}) (e error) {
 		return
 	}()
 
-	operation, e = defaultCodec.NewOperation(iface)
+	operation, e = c.codec.NewOperation(iface)
 	if e != nil {
 		return
 	}
@@ -85,6 +185,94 @@ func Unmarshal(bytes []byte, iface interface{}) (e error) {
 	return
 }
 
+// PackWord returns the value of a word-struct as a register value,
+// the bit fields of which are packed into the lowest bits of a uint64.
+// The length of the word is the sum of the lengths of its bit fields.
+func PackWord(iface interface{}) (wordUint64 uint64, e error) {
+	const (
+		functionName = "PackWord"
+	)
+
+	var (
+		operation codecs.WordOperation
+	)
+
+	defer func() {
+		const (
+			packWordError = "PackWord error: %w"
+		)
+
+		if e != nil {
+			e.(validation.FunctionError).SetFunctionName(functionName)
+
+			e = fmt.Errorf(packWordError, e)
+		}
+
+		return
+	}()
+
+	operation, e = defaultCodec.codec.NewWordOperation(iface)
+	if e != nil {
+		return
+	}
+
+	wordUint64 = operation.Pack()
+
+	return
+}
+
+// UnpackWord sets the bit fields of a word-struct from a register value,
+// ignoring bits above the length of the word.
+func UnpackWord(wordUint64 uint64, iface interface{}) (e error) {
+	const (
+		functionName = "UnpackWord"
+	)
+
+	var (
+		operation codecs.WordOperation
+	)
+
+	defer func() {
+		const (
+			unpackWordError = "UnpackWord error: %w"
+		)
+
+		if e != nil {
+			e.(validation.FunctionError).SetFunctionName(functionName)
+
+			e = fmt.Errorf(unpackWordError, e)
+		}
+
+		return
+	}()
+
+	operation, e = defaultCodec.codec.NewWordOperation(iface)
+	if e != nil {
+		return
+	}
+
+	operation.Unpack(wordUint64)
+
+	return
+}
+
+// Byte orders of values spanning several 16-bit registers.
+// Words and bit fields of whole bytes may be declared in the same orders
+// with an option of their struct tags, naming bytes with letters
+// from "a", the most significant:
+//
+//	Word0 `word:"32,order=cdab"`
+//	Value uint32 `bitfield:"32,order=badc"`
+var (
+	// WordSwapped orders 16-bit words from least to most significant,
+	// and bytes within words from most to least significant (CDAB).
+	WordSwapped ByteOrder = order.WordSwapped
+
+	// ByteSwapped orders 16-bit words from most to least significant,
+	// and bytes within words from least to most significant (BADC).
+	ByteSwapped ByteOrder = order.ByteSwapped
+)
+
 // Standard library features
 
 const (
@@ -1,7 +1,12 @@
 package binary
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
 	"fmt"
+	"hash"
+	"reflect"
 	"testing"
 
 	"github.com/encodingx/binary/pkg/rfc791"
@@ -99,6 +104,654 @@ func BenchmarkUnmarshal(b *testing.B) {
 	}
 }
 
+func TestPackWord(t *testing.T) {
+	var (
+		e          error
+		wordUint64 uint64
+	)
+
+	wordUint64, e = PackWord(
+		&internetHeaderStruct.RFC791InternetHeaderFormatWord0,
+	)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint64(0x45e8ffff), wordUint64,
+	)
+}
+
+func TestUnpackWord(t *testing.T) {
+	var (
+		e    error
+		word rfc791.RFC791InternetHeaderFormatWord0
+	)
+
+	// Bits above the length of the word are ignored.
+
+	e = UnpackWord(0xff_45e8ffff, &word)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		internetHeaderStruct.RFC791InternetHeaderFormatWord0, word,
+	)
+}
+
+func TestShouldReturnErrorGivenWordOfIncompatibleLengthToPackWord(
+	t *testing.T,
+) {
+	const (
+		errorMessage = "%[1]s error: " +
+			"The length of a word should be a multiple of eight " +
+			"in the range [8, 64]. " +
+			"Argument to %[1]s points to a format-struct \"binary.Word\" " +
+			"that has a word \"Word\" " +
+			"of length 12 not in {8, 16, 24, ... 64}."
+	)
+
+	type (
+		Word struct {
+			BitField0 uint8  `bitfield:"4"`
+			BitField1 uint16 `bitfield:"8"`
+		}
+	)
+
+	var (
+		e error
+	)
+
+	_, e = PackWord(&Word{})
+
+	assert.Equal(t,
+		fmt.Sprintf(errorMessage, "PackWord"),
+		e.Error(),
+	)
+
+	e = UnpackWord(0, &Word{})
+
+	assert.Equal(t,
+		fmt.Sprintf(errorMessage, "UnpackWord"),
+		e.Error(),
+	)
+
+	_, e = PackWord(Word{})
+
+	assert.Equal(t,
+		"PackWord error: "+
+			"Argument to PackWord should be a pointer to a format-struct. "+
+			"Argument to PackWord is not a pointer.",
+		e.Error(),
+	)
+}
+
+type (
+	commandFormat struct {
+		CommandFormatWord0 `word:"32"`
+		CommandFormatWord1 `word:"32,mac=hmac-sha256"`
+	}
+
+	commandRangeFormat struct {
+		CommandFormatWord0        `word:"32"`
+		CommandFormatShortMACWord `word:"16,mac=aes-cmac,range=2-4"`
+	}
+
+	CommandFormatWord0 struct {
+		Opcode   uint8  `bitfield:"8"`
+		Argument uint32 `bitfield:"24"`
+	}
+
+	CommandFormatWord1 struct {
+		MAC uint32 `bitfield:"32"`
+	}
+
+	CommandFormatShortMACWord struct {
+		MAC uint16 `bitfield:"16"`
+	}
+)
+
+func TestCodecMAC(t *testing.T) {
+	var (
+		authenticationError *AuthenticationError
+		bytes               []byte
+		codec               *Codec
+		command             commandFormat
+		command1            commandFormat
+		e                   error
+		hash                hash.Hash
+		key                 []byte = []byte("key")
+	)
+
+	codec = NewCodec(
+		KeyProviderFunc(
+			func(formatName, algorithm string) ([]byte, error) {
+				assert.Equal(t,
+					"binary.commandFormat", formatName,
+				)
+
+				assert.Equal(t,
+					"hmac-sha256", algorithm,
+				)
+
+				return key, nil
+			},
+		),
+	)
+
+	command.Opcode = 0x01
+	command.Argument = 0x020304
+
+	bytes, e = codec.Marshal(&command)
+
+	assert.Nil(t, e)
+
+	hash = hmac.New(sha256.New, key)
+
+	hash.Write([]byte{0x01, 0x02, 0x03, 0x04})
+
+	assert.Equal(t,
+		hash.Sum([]byte{0x01, 0x02, 0x03, 0x04})[:8], bytes,
+	)
+
+	e = codec.Unmarshal(bytes, &command1)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		command.CommandFormatWord0, command1.CommandFormatWord0,
+	)
+
+	assert.Equal(t,
+		BigEndian.Uint32(bytes[4:]), command1.MAC,
+	)
+
+	// A tampered message is rejected before any word is unmarshalled.
+
+	bytes[3] ^= 0x01
+	command1 = commandFormat{}
+
+	e = codec.Unmarshal(bytes, &command1)
+
+	assert.True(t,
+		errors.As(e, &authenticationError),
+	)
+
+	assert.Equal(t,
+		"hmac-sha256", authenticationError.Algorithm(),
+	)
+
+	assert.Equal(t,
+		"Unmarshal error: "+
+			"A MAC word should match the MAC computed over its byte range. "+
+			"Argument to Unmarshal points to a format-struct "+
+			"\"binary.commandFormat\" "+
+			"that has a word \"CommandFormatWord1\" "+
+			"not matching the hmac-sha256 MAC of the bytes.",
+		e.Error(),
+	)
+
+	assert.Equal(t,
+		commandFormat{}, command1,
+	)
+
+	_, e = Marshal(&command)
+
+	assert.Equal(t,
+		"Marshal error: "+
+			"A format-struct with a MAC word should be marshalled "+
+			"and unmarshalled by a codec with a key provider. "+
+			"Argument to Marshal points to a format-struct "+
+			"\"binary.commandFormat\" "+
+			"that has a MAC word \"CommandFormatWord1\", "+
+			"but the codec has no key provider.",
+		e.Error(),
+	)
+}
+
+func TestCodecMACOverRange(t *testing.T) {
+	var (
+		bytes   []byte
+		codec   *Codec
+		command commandRangeFormat
+		e       error
+	)
+
+	codec = NewCodec(
+		KeyProviderFunc(
+			func(formatName, algorithm string) ([]byte, error) {
+				return make([]byte, 16), nil
+			},
+		),
+	)
+
+	command.Opcode = 0x01
+	command.Argument = 0x020304
+
+	bytes, e = codec.Marshal(&command)
+
+	assert.Nil(t, e)
+
+	// Bytes outside the range are not authenticated.
+
+	bytes[0] = 0xff
+
+	e = codec.Unmarshal(bytes, &command)
+
+	assert.Nil(t, e)
+
+	bytes[2] = 0xff
+
+	e = codec.Unmarshal(bytes, &command)
+
+	assert.NotNil(t, e)
+
+	codec = NewCodec(
+		KeyProviderFunc(
+			func(formatName, algorithm string) ([]byte, error) {
+				return []byte("short"), nil
+			},
+		),
+	)
+
+	_, e = codec.Marshal(&command)
+
+	assert.Equal(t,
+		"Marshal error: "+
+			"The key provided for a MAC word should suit its algorithm. "+
+			"Argument to Marshal points to a format-struct "+
+			"\"binary.commandRangeFormat\" "+
+			"that has a MAC word \"CommandFormatShortMACWord\" "+
+			"the aes-cmac MAC of which cannot be computed: "+
+			"crypto/aes: invalid key size 5",
+		e.Error(),
+	)
+}
+
+type (
+	recordFormat struct {
+		RecordFormatWord0 `word:"8"`
+		RecordFormatWord1 `word:"32,align=4"`
+		RecordSection     `format:"align=8,padding=zero"`
+	}
+
+	RecordFormatWord0 struct {
+		Type uint8 `bitfield:"8"`
+	}
+
+	RecordFormatWord1 struct {
+		Length uint32 `bitfield:"32"`
+	}
+
+	RecordSection struct {
+		RecordFormatWord0 `word:"8"`
+		RecordFormatWord1 `word:"32,align=4"`
+	}
+)
+
+func TestAlignment(t *testing.T) {
+	var (
+		bytes        []byte
+		e            error
+		paddingError *PaddingError
+		record       recordFormat
+		record1      recordFormat
+	)
+
+	record.RecordFormatWord0.Type = 0x01
+	record.RecordFormatWord1.Length = 0x02030405
+	record.RecordSection.RecordFormatWord0.Type = 0x06
+	record.RecordSection.RecordFormatWord1.Length = 0x0708090a
+
+	bytes, e = Marshal(&record)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]byte{
+			0x01, 0x00, 0x00, 0x00, 0x02, 0x03, 0x04, 0x05,
+			0x06, 0x00, 0x00, 0x00, 0x07, 0x08, 0x09, 0x0a,
+		},
+		bytes,
+	)
+
+	e = Unmarshal(bytes, &record1)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		record, record1,
+	)
+
+	// Padding before a word is skipped by default.
+
+	bytes[1] = 0xff
+
+	e = Unmarshal(bytes, &record1)
+
+	assert.Nil(t, e)
+
+	// Padding of a nested format declared "padding=zero" is verified.
+
+	bytes, e = Marshal(
+		&struct {
+			RecordFormatWord0 `word:"8"`
+			RecordSection     `format:"align=4,padding=zero"`
+		}{},
+	)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		12, len(bytes),
+	)
+
+	bytes[2] = 0xff
+
+	e = Unmarshal(bytes,
+		&struct {
+			RecordFormatWord0 `word:"8"`
+			RecordSection     `format:"align=4,padding=zero"`
+		}{},
+	)
+
+	assert.True(t,
+		errors.As(e, &paddingError),
+	)
+
+	assert.Equal(t,
+		2, paddingError.Offset(),
+	)
+}
+
+type (
+	sectionsFormat struct {
+		RecordFormatWord0 `word:"8"`
+		First             RecordSection       `format:"align=4,padding=zero"`
+		Last              sectionsLastSection `format:"align=8,padding=zero"`
+	}
+
+	sectionsLastSection struct {
+		RecordFormatWord0 `word:"8"`
+	}
+)
+
+func TestUnmarshalPolicy(t *testing.T) {
+	var (
+		bytes         []byte
+		codec         *Codec
+		decodingError *DecodingError
+		e             error
+		paddingError  *PaddingError
+		sections      sectionsFormat
+		sections1     sectionsFormat
+	)
+
+	sections.RecordFormatWord0.Type = 0x01
+	sections.First.RecordFormatWord0.Type = 0x02
+	sections.First.RecordFormatWord1.Length = 0x03040506
+	sections.Last.RecordFormatWord0.Type = 0x07
+
+	bytes, e = Marshal(&sections)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]byte{
+			0x01, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00,
+			0x03, 0x04, 0x05, 0x06, 0x00, 0x00, 0x00, 0x00,
+			0x07,
+		},
+		bytes,
+	)
+
+	bytes[2] = 0xff
+	bytes[13] = 0xff
+
+	// Atomic decoding leaves the struct untouched on error.
+
+	sections1.Last.RecordFormatWord0.Type = 0xff
+
+	e = Unmarshal(bytes, &sections1)
+
+	assert.True(t,
+		errors.As(e, &paddingError),
+	)
+
+	assert.Equal(t,
+		2, paddingError.Offset(),
+	)
+
+	assert.Equal(t,
+		sectionsFormat{
+			Last: sectionsLastSection{
+				RecordFormatWord0{
+					Type: 0xff,
+				},
+			},
+		},
+		sections1,
+	)
+
+	// Best-effort decoding sets every word and lists each failure.
+
+	codec = NewCodec(nil)
+
+	codec.SetUnmarshalPolicy(UnmarshalBestEffort)
+
+	e = codec.Unmarshal(bytes, &sections1)
+
+	assert.True(t,
+		errors.As(e, &decodingError),
+	)
+
+	assert.Equal(t,
+		"Unmarshal error: "+
+			"Each word of a format-struct should decode. "+
+			"Argument to Unmarshal points to a format-struct "+
+			"\"binary.sectionsFormat\" "+
+			"that has 2 word(s) failing to decode: "+
+			"\"First\", \"Last\".",
+		e.Error(),
+	)
+
+	assert.Equal(t,
+		2, len(decodingError.Errors()),
+	)
+
+	assert.True(t,
+		errors.As(decodingError.Errors()[1], &paddingError),
+	)
+
+	assert.Equal(t,
+		13, paddingError.Offset(),
+	)
+
+	// The errors of words are found through the DecodingError.
+
+	paddingError = nil
+
+	assert.True(t,
+		errors.As(e, &paddingError),
+	)
+
+	assert.Equal(t,
+		2, paddingError.Offset(),
+	)
+
+	assert.Equal(t,
+		sections, sections1,
+	)
+}
+
+type (
+	trailerFormat struct {
+		RecordFormatWord0 `word:"8"`
+		Trailer           bytesTrailer
+	}
+
+	bytesTrailer []byte
+)
+
+func (b *bytesTrailer) MarshalTrailer() ([]byte, error) {
+	return *b, nil
+}
+
+func (b *bytesTrailer) UnmarshalTrailer(bytes []byte) error {
+	*b = append(bytesTrailer(nil), bytes...)
+
+	return nil
+}
+
+func TestTrailer(t *testing.T) {
+	var (
+		bytes    []byte
+		e        error
+		trailer  trailerFormat
+		trailer1 trailerFormat
+	)
+
+	trailer.Type = 0x01
+	trailer.Trailer = bytesTrailer("abc")
+
+	bytes, e = Marshal(&trailer)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]byte{0x01, 'a', 'b', 'c'},
+		bytes,
+	)
+
+	e = Unmarshal(bytes, &trailer1)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		trailer, trailer1,
+	)
+
+	// The words of a format with a trailer are still required.
+
+	e = Unmarshal(nil, &trailer1)
+
+	assert.NotNil(t, e)
+}
+
+type (
+	registerFormat struct {
+		RegisterFormatWord0 `word:"32,order=cdab"`
+		RegisterFormatWord1 `word:"48"`
+	}
+
+	RegisterFormatWord0 struct {
+		Value uint32 `bitfield:"32"`
+	}
+
+	RegisterFormatWord1 struct {
+		Value    uint32 `bitfield:"32,order=badc"`
+		Register uint16 `bitfield:"16"`
+	}
+)
+
+func TestByteOrder(t *testing.T) {
+	var (
+		bytes     []byte
+		e         error
+		register  registerFormat
+		register1 registerFormat
+	)
+
+	register.RegisterFormatWord0.Value = 0x11223344
+	register.RegisterFormatWord1.Value = 0x55667788
+	register.RegisterFormatWord1.Register = 0x99aa
+
+	bytes, e = Marshal(&register)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]byte{
+			0x33, 0x44, 0x11, 0x22,
+			0x66, 0x55, 0x88, 0x77, 0x99, 0xaa,
+		},
+		bytes,
+	)
+
+	assert.Equal(t,
+		register.RegisterFormatWord0.Value, WordSwapped.Uint32(bytes),
+	)
+
+	assert.Equal(t,
+		register.RegisterFormatWord1.Value, ByteSwapped.Uint32(bytes[4:]),
+	)
+
+	e = Unmarshal(bytes, &register1)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		register, register1,
+	)
+}
+
+func TestShouldReturnErrorGivenWordWithInvalidOption(t *testing.T) {
+	type (
+		Word0 struct {
+			BitField uint32 `bitfield:"32"`
+		}
+
+		Word1 struct {
+			BitField uint32 `bitfield:"32"`
+		}
+	)
+
+	var (
+		e      error
+		option string
+		tag    reflect.StructTag
+		tags   map[reflect.StructTag]string
+	)
+
+	tags = map[reflect.StructTag]string{
+		`word:"32,mac=md5"`:                 "mac=md5",
+		`word:"32,crc"`:                     "crc",
+		`word:"32,range=0-4"`:               "range",
+		`word:"32,mac=aes-cmac,range=0-8"`:  "range=0-8",
+		`word:"32,mac=aes-cmac,range=4-2"`:  "range=4-2",
+		`word:"32,mac=aes-cmac,range=-1-4"`: "range=-1-4",
+		`word:"32,align=3"`:                 "align=3",
+		`word:"32,padding=ones"`:            "padding=ones",
+		`word:"32,order=abc"`:               "order=abc",
+	}
+
+	for tag, option = range tags {
+		_, e = Marshal(
+			reflect.New(
+				reflect.StructOf(
+					[]reflect.StructField{
+						{
+							Name:      "Word0",
+							Type:      reflect.TypeOf(Word0{}),
+							Tag:       `word:"32"`,
+							Anonymous: true,
+						},
+						{
+							Name:      "Word1",
+							Type:      reflect.TypeOf(Word1{}),
+							Tag:       tag,
+							Anonymous: true,
+						},
+					},
+				),
+			).Interface(),
+		)
+
+		assert.Contains(t, e.Error(),
+			fmt.Sprintf("with an invalid option \"%s\".", option),
+		)
+	}
+}
+
 func TestShouldReturnErrorGivenNonPointer(t *testing.T) {
 	const (
 		errorMessage = "%[1]s error: " +
@@ -422,6 +1075,31 @@ func TestShouldReturnErrorGivenWordOfLengthNotEqualToSumOfLengthsOfBitFields(
 	)
 }
 
+func TestShouldReturnErrorGivenFormatWithInvalidTrailer(t *testing.T) {
+	const (
+		errorMessage = "%[1]s error: " +
+			"A trailer should be the last field of a format-struct " +
+			"not nested in another, of a type the pointers to which " +
+			"implement binary.Trailer, " +
+			"and should not be streamed by an Encoder or Decoder " +
+			"(e.g. `trailer:\"\"`). " +
+			"Argument to %[1]s points to a format-struct \"binary.Format\" " +
+			"that has an invalid trailer \"Trailer\"."
+	)
+
+	type (
+		Format struct {
+			Trailer           bytesTrailer
+			RecordFormatWord0 `word:"8"`
+		}
+	)
+
+	testShouldReturnErrorGiven(t,
+		&Format{},
+		errorMessage,
+	)
+}
+
 func testShouldReturnErrorGiven(t *testing.T,
 	pointer interface{}, errorMessage string,
 ) {
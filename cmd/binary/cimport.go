@@ -0,0 +1,1760 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// CImport generates format-structs from a subset of C:
+// struct declarations and typedefs of fixed-width integer types,
+// bit fields, arrays of integers, nested structs,
+// "#pragma pack" and integer "#define" constants.
+// Structs are laid out as a compiler for a chosen ABI would lay them out,
+// and their storage is divided into words of up to 64 bits:
+// runs of bit fields sharing bytes form one word each,
+// and other members, including padding, are grouped into words.
+// On little-endian ABIs, words carry an "order" option
+// reversing the bytes of each member.
+
+const (
+	cimportABISysV   = "sysv"
+	cimportABISysVBE = "sysv-be"
+	cimportABIMSVC   = "msvc"
+
+	cimportWordLengthLimit = 8
+
+	cimportCPlusPlus = "__cplusplus"
+)
+
+type cimportType struct {
+	size   int
+	record *cimportStruct
+}
+
+type cimportMember struct {
+	name     string
+	ctype    cimportType
+	bitField bool
+	width    int
+	count    int
+	line     int
+}
+
+type cimportStruct struct {
+	cName   string
+	goName  string
+	members []cimportMember
+	pack    int
+
+	size      int
+	alignment int
+	items     []cimportItem
+}
+
+type cimportItemKind int
+
+const (
+	cimportItemScalar cimportItemKind = iota
+	cimportItemBitField
+	cimportItemPadding
+	cimportItemStruct
+)
+
+// cimportItem is a member of a struct laid out,
+// its offset in number of bits in the order of allocation of the ABI.
+// Bit fields of big-endian items are allocated from the most significant bit,
+// and other items of little-endian ones have their bytes reversed.
+type cimportItem struct {
+	name      string
+	kind      cimportItemKind
+	offset    int
+	width     int
+	bigEndian bool
+	goType    string
+	record    *cimportStruct
+}
+
+type cimportWord struct {
+	start    int
+	end      int
+	bitField bool
+	items    []cimportItem
+}
+
+type cimportParser struct {
+	abi       string
+	filename  string
+	tokens    []cimportToken
+	position  int
+	packs     []int
+	pack      int
+	skipping  int
+	constants []cimportConstant
+	values    map[string]constant.Value
+	typedefs  map[string]cimportType
+	structs   map[string]*cimportStruct
+	order     []*cimportStruct
+}
+
+type cimportConstant struct {
+	name  string
+	value string
+}
+
+type cimportToken struct {
+	text string
+	line int
+}
+
+func runCImport(args []string, stdout io.Writer) (e error) {
+	const (
+		usage = "" +
+			"Usage: binary cimport [-abi sysv|sysv-be|msvc] " +
+			"[-package name] [-o file] header.h\n" +
+			"\n" +
+			"CImport generates Go format-structs and word-structs\n" +
+			"from the struct declarations of a C header, laid out\n" +
+			"for an ABI, and Go constants from its integer #defines.\n" +
+			"\n"
+	)
+
+	var (
+		abi         string
+		filename    string
+		flags       *flag.FlagSet
+		output      []byte
+		packageName string
+		source      []byte
+	)
+
+	flags = flag.NewFlagSet("cimport", flag.ContinueOnError)
+
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+
+		flags.PrintDefaults()
+	}
+
+	flags.StringVar(&abi, "abi", cimportABISysV,
+		"ABI to lay out structs for: \"sysv\" (GCC and Clang, "+
+			"little-endian, LP64), \"sysv-be\" (GCC, big-endian, LP64) "+
+			"or \"msvc\" (little-endian, LLP64)",
+	)
+
+	flags.StringVar(&packageName, "package", "",
+		"name of the Go package, by default derived from the header",
+	)
+
+	flags.StringVar(&filename, "o", "",
+		"file to write instead of standard output",
+	)
+
+	e = flags.Parse(args)
+	if e != nil {
+		return
+	}
+
+	if flags.NArg() != 1 || abi != cimportABISysV &&
+		abi != cimportABISysVBE && abi != cimportABIMSVC {
+		flags.Usage()
+
+		e = errUsage
+
+		return
+	}
+
+	source, e = os.ReadFile(flags.Arg(0))
+	if e != nil {
+		return
+	}
+
+	if packageName == "" {
+		packageName = cimportPackageName(flags.Arg(0))
+	}
+
+	output, e = generateCImport(flags.Arg(0), source, abi, packageName)
+	if e != nil {
+		return
+	}
+
+	if filename != "" {
+		e = os.WriteFile(filename, output, 0644)
+
+		return
+	}
+
+	_, e = stdout.Write(output)
+
+	return
+}
+
+func cimportPackageName(filename string) string {
+	var (
+		name  []rune
+		r     rune
+		runes string
+	)
+
+	runes = strings.ToLower(
+		strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename)),
+	)
+
+	for _, r = range runes {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) ||
+			unicode.IsDigit(r) && len(name) > 0) {
+			name = append(name, r)
+		}
+	}
+
+	if len(name) == 0 {
+		return "formats"
+	}
+
+	return string(name)
+}
+
+// generateCImport parses a C header and returns the formatted Go source
+// of its constants and structs.
+func generateCImport(filename string, source []byte, abi, packageName string) (
+	output []byte, e error,
+) {
+	var (
+		buffer     bytes.Buffer
+		definition cimportConstant
+		p          *cimportParser
+		record     *cimportStruct
+	)
+
+	p = &cimportParser{
+		abi:      abi,
+		filename: filename,
+		values:   make(map[string]constant.Value),
+		typedefs: make(map[string]cimportType),
+		structs:  make(map[string]*cimportStruct),
+	}
+
+	e = p.parse(source)
+	if e != nil {
+		return
+	}
+
+	fmt.Fprintf(&buffer,
+		"// Code generated by binary cimport from %s for the %s ABI; "+
+			"DO NOT EDIT.\n\npackage %s\n",
+		filepath.Base(filename), abi, packageName,
+	)
+
+	if len(p.constants) > 0 {
+		fmt.Fprintf(&buffer, "\nconst (\n")
+
+		for _, definition = range p.constants {
+			fmt.Fprintf(&buffer, "%s = %s\n", definition.name, definition.value)
+		}
+
+		fmt.Fprintf(&buffer, ")\n")
+	}
+
+	for _, record = range p.order {
+		e = record.write(&buffer, abi)
+		if e != nil {
+			return
+		}
+	}
+
+	output, e = format.Source(buffer.Bytes())
+	if e != nil {
+		return
+	}
+
+	return
+}
+
+func (p *cimportParser) errorf(line int, format string, args ...interface{}) error {
+	return fmt.Errorf("%s:%d: %s",
+		p.filename, line, fmt.Sprintf(format, args...),
+	)
+}
+
+// parse tokenizes a header, handling preprocessor lines as they occur,
+// and parses its declarations.
+func (p *cimportParser) parse(source []byte) (e error) {
+	var (
+		line  string
+		lines []string
+		i     int
+	)
+
+	source = cimportStripComments(source)
+
+	lines = strings.Split(string(source), "\n")
+
+	for i = 0; i < len(lines); i++ {
+		line = lines[i]
+
+		for strings.HasSuffix(line, "\\") && i+1 < len(lines) {
+			line = strings.TrimSuffix(line, "\\") + " " + lines[i+1]
+
+			lines[i+1] = ""
+
+			i++
+		}
+
+		line = strings.TrimSpace(line)
+
+		if p.skipping > 0 {
+			p.skip(line)
+
+			continue
+		}
+
+		if strings.HasPrefix(line, "#pragma") {
+			// Declarations before the directive are parsed first,
+			// since "#pragma pack" applies to those that follow it.
+
+			e = p.parseDeclarations()
+			if e != nil {
+				return
+			}
+		}
+
+		if strings.HasPrefix(line, "#") {
+			e = p.parseDirective(line, i+1)
+			if e != nil {
+				return
+			}
+
+			continue
+		}
+
+		p.tokens = append(p.tokens, cimportTokenize(line, i+1)...)
+	}
+
+	e = p.parseDeclarations()
+	if e != nil {
+		return
+	}
+
+	return
+}
+
+func cimportStripComments(source []byte) []byte {
+	var (
+		i      int
+		output []byte
+	)
+
+	for i = 0; i < len(source); i++ {
+		switch {
+		case bytes.HasPrefix(source[i:], []byte("//")):
+			for i < len(source) && source[i] != '\n' {
+				i++
+			}
+
+			if i < len(source) {
+				output = append(output, '\n')
+			}
+
+		case bytes.HasPrefix(source[i:], []byte("/*")):
+			for i += 2; i < len(source) &&
+				!bytes.HasPrefix(source[i:], []byte("*/")); i++ {
+				if source[i] == '\n' {
+					output = append(output, '\n')
+				}
+			}
+
+			i++
+
+		default:
+			output = append(output, source[i])
+		}
+	}
+
+	return output
+}
+
+func cimportTokenize(line string, number int) (tokens []cimportToken) {
+	var (
+		i int
+		j int
+	)
+
+	for i = 0; i < len(line); {
+		switch {
+		case line[i] == ' ' || line[i] == '\t' || line[i] == '\r':
+			i++
+
+		case line[i] == '_' || unicode.IsLetter(rune(line[i])) ||
+			unicode.IsDigit(rune(line[i])):
+			for j = i; j < len(line) && (line[j] == '_' ||
+				unicode.IsLetter(rune(line[j])) ||
+				unicode.IsDigit(rune(line[j]))); j++ {
+			}
+
+			tokens = append(tokens, cimportToken{line[i:j], number})
+
+			i = j
+
+		default:
+			tokens = append(tokens, cimportToken{line[i : i+1], number})
+
+			i++
+		}
+	}
+
+	return
+}
+
+// skip skips a line of a conditional block for C++,
+// up to its "#else", "#elif" or "#endif".
+func (p *cimportParser) skip(line string) {
+	var (
+		fields []string
+	)
+
+	fields = strings.Fields(
+		strings.TrimSpace(strings.TrimPrefix(line, "#")),
+	)
+
+	if !strings.HasPrefix(line, "#") || len(fields) == 0 {
+		return
+	}
+
+	switch fields[0] {
+	case "if", "ifdef", "ifndef":
+		p.skipping++
+
+	case "else", "elif":
+		if p.skipping == 1 {
+			p.skipping = 0
+		}
+
+	case "endif":
+		p.skipping--
+	}
+}
+
+func (p *cimportParser) parseDirective(line string, number int) (e error) {
+	var (
+		fields []string
+		name   string
+		value  constant.Value
+	)
+
+	fields = strings.Fields(
+		strings.TrimSpace(strings.TrimPrefix(line, "#")),
+	)
+
+	if len(fields) == 0 {
+		return
+	}
+
+	switch fields[0] {
+	case "define":
+		if len(fields) < 2 || strings.Contains(fields[1], "(") {
+			return
+		}
+
+		name = fields[1]
+
+		if len(fields) == 2 {
+			// Include guards and flags define no value.
+
+			return
+		}
+
+		value, e = p.evaluate(strings.Join(fields[2:], " "))
+		if e != nil {
+			e = p.errorf(number, "#define %s: %v", name, e)
+
+			return
+		}
+
+		p.values[name] = value
+
+		p.constants = append(p.constants,
+			cimportConstant{
+				name:  name,
+				value: value.ExactString(),
+			},
+		)
+
+	case "pragma":
+		if len(fields) < 2 || !strings.HasPrefix(fields[1], "pack") {
+			return
+		}
+
+		e = p.parsePack(
+			strings.Join(fields[1:], ""), number,
+		)
+
+	case "if", "ifdef":
+		// Conditional compilation is not evaluated,
+		// except to skip declarations for C++.
+
+		if strings.Contains(line, cimportCPlusPlus) {
+			p.skipping = 1
+		}
+
+	case "include", "ifndef", "else", "elif", "endif", "undef":
+
+	default:
+		e = p.errorf(number, "unsupported directive #%s", fields[0])
+	}
+
+	return
+}
+
+// parsePack handles "pack(n)", "pack()", "pack(push, n)" and "pack(pop)".
+func (p *cimportParser) parsePack(directive string, number int) (e error) {
+	var (
+		argument string
+		n        int
+	)
+
+	argument = strings.TrimSuffix(
+		strings.TrimPrefix(directive, "pack("), ")",
+	)
+
+	switch {
+	case argument == "":
+		p.pack = 0
+
+		return
+
+	case argument == "pop":
+		if len(p.packs) == 0 {
+			e = p.errorf(number, "#pragma pack(pop) without push")
+
+			return
+		}
+
+		p.pack = p.packs[len(p.packs)-1]
+		p.packs = p.packs[:len(p.packs)-1]
+
+		return
+
+	case argument == "push":
+		p.packs = append(p.packs, p.pack)
+
+		return
+
+	case strings.HasPrefix(argument, "push,"):
+		p.packs = append(p.packs, p.pack)
+
+		argument = strings.TrimPrefix(argument, "push,")
+	}
+
+	n, e = strconv.Atoi(argument)
+	if e != nil || n < 1 || n&(n-1) != 0 {
+		e = p.errorf(number, "malformed #pragma %s", directive)
+
+		return
+	}
+
+	p.pack = n
+
+	return
+}
+
+// evaluate evaluates an integer constant expression of C
+// as the equivalent expression of Go.
+func (p *cimportParser) evaluate(expression string) (
+	value constant.Value, e error,
+) {
+	var (
+		builder      strings.Builder
+		goExpression ast.Expr
+		token        cimportToken
+		word         bool
+	)
+
+	for _, token = range cimportTokenize(expression, 0) {
+		switch {
+		case token.text == "~":
+			token.text = "^"
+
+		case len(token.text) > 0 && unicode.IsDigit(rune(token.text[0])):
+			token.text = strings.TrimRight(token.text, "uUlL")
+
+			if len(token.text) > 1 && token.text[0] == '0' &&
+				unicode.IsDigit(rune(token.text[1])) {
+				token.text = "0o" + token.text[1:]
+			}
+		}
+
+		// Operators are rejoined, and words kept apart.
+
+		if word && cimportIsWord(token.text) {
+			builder.WriteByte(' ')
+		}
+
+		builder.WriteString(token.text)
+
+		word = cimportIsWord(token.text)
+	}
+
+	goExpression, e = parser.ParseExpr(builder.String())
+	if e != nil {
+		e = fmt.Errorf("%q is not an integer constant expression", expression)
+
+		return
+	}
+
+	value, e = p.evaluateExpr(goExpression)
+	if e != nil {
+		return
+	}
+
+	if value.Kind() != constant.Int {
+		e = fmt.Errorf("%q is not an integer constant expression", expression)
+
+		return
+	}
+
+	return
+}
+
+func (p *cimportParser) evaluateExpr(expression ast.Expr) (
+	value constant.Value, e error,
+) {
+	var (
+		binary  *ast.BinaryExpr
+		literal *ast.BasicLit
+		ok      bool
+		unary   *ast.UnaryExpr
+		x       constant.Value
+		y       constant.Value
+	)
+
+	switch expression.(type) {
+	case *ast.BasicLit:
+		literal = expression.(*ast.BasicLit)
+
+		value = constant.MakeFromLiteral(literal.Value, literal.Kind, 0)
+
+	case *ast.Ident:
+		value, ok = p.values[expression.(*ast.Ident).Name]
+		if !ok {
+			e = fmt.Errorf("%s is not an integer constant",
+				expression.(*ast.Ident).Name,
+			)
+
+			return
+		}
+
+	case *ast.ParenExpr:
+		value, e = p.evaluateExpr(expression.(*ast.ParenExpr).X)
+		if e != nil {
+			return
+		}
+
+	case *ast.UnaryExpr:
+		unary = expression.(*ast.UnaryExpr)
+
+		x, e = p.evaluateExpr(unary.X)
+		if e != nil {
+			return
+		}
+
+		value = constant.UnaryOp(unary.Op, x, 0)
+
+	case *ast.BinaryExpr:
+		binary = expression.(*ast.BinaryExpr)
+
+		x, e = p.evaluateExpr(binary.X)
+		if e != nil {
+			return
+		}
+
+		y, e = p.evaluateExpr(binary.Y)
+		if e != nil {
+			return
+		}
+
+		switch binary.Op {
+		case token.SHL, token.SHR:
+			value = constant.Shift(x, binary.Op, cimportUint(y))
+
+		case token.QUO, token.REM:
+			if constant.Sign(y) == 0 {
+				e = fmt.Errorf("division by zero")
+
+				return
+			}
+
+			if binary.Op == token.QUO {
+				value = constant.BinaryOp(x, token.QUO_ASSIGN, y)
+			} else {
+				value = constant.BinaryOp(x, token.REM, y)
+			}
+
+		default:
+			value = constant.BinaryOp(x, binary.Op, y)
+		}
+
+	default:
+		e = fmt.Errorf("unsupported expression")
+
+		return
+	}
+
+	if value.Kind() == constant.Unknown {
+		e = fmt.Errorf("unsupported expression")
+
+		return
+	}
+
+	return
+}
+
+func cimportUint(value constant.Value) uint {
+	var (
+		x uint64
+	)
+
+	x, _ = constant.Uint64Val(value)
+
+	return uint(x)
+}
+
+func (p *cimportParser) peek() string {
+	if p.position < len(p.tokens) {
+		return p.tokens[p.position].text
+	}
+
+	return ""
+}
+
+func (p *cimportParser) line() int {
+	if p.position < len(p.tokens) {
+		return p.tokens[p.position].line
+	}
+
+	if len(p.tokens) > 0 {
+		return p.tokens[len(p.tokens)-1].line
+	}
+
+	return 0
+}
+
+func (p *cimportParser) next() (text string) {
+	text = p.peek()
+
+	p.position++
+
+	return
+}
+
+func (p *cimportParser) expect(text string) (e error) {
+	if p.peek() != text {
+		e = p.errorf(p.line(), "expected %q, found %q", text, p.peek())
+
+		return
+	}
+
+	p.position++
+
+	return
+}
+
+// parseDeclarations parses the declarations tokenized so far.
+func (p *cimportParser) parseDeclarations() (e error) {
+	var (
+		alias  string
+		ctype  cimportType
+		line   int
+		record *cimportStruct
+	)
+
+	for p.position < len(p.tokens) {
+		line = p.line()
+
+		switch p.peek() {
+		case ";":
+			p.next()
+
+		case "typedef":
+			p.next()
+
+			ctype, e = p.parseType(true)
+			if e != nil {
+				return
+			}
+
+			alias = p.next()
+
+			if !cimportIsIdentifier(alias) {
+				e = p.errorf(line, "malformed typedef")
+
+				return
+			}
+
+			if ctype.record != nil && ctype.record.goName == "" {
+				ctype.record.cName = alias
+				ctype.record.goName = cimportGoName(alias)
+			}
+
+			p.typedefs[alias] = ctype
+
+			e = p.expect(";")
+			if e != nil {
+				return
+			}
+
+		case "struct":
+			ctype, e = p.parseType(true)
+			if e != nil {
+				return
+			}
+
+			record = ctype.record
+
+			if record == nil || p.peek() != ";" {
+				e = p.errorf(line,
+					"only struct and typedef declarations are supported",
+				)
+
+				return
+			}
+
+		default:
+			e = p.errorf(line,
+				"unsupported declaration beginning %q; "+
+					"only struct and typedef declarations are supported",
+				p.peek(),
+			)
+
+			return
+		}
+	}
+
+	p.tokens = p.tokens[:0]
+	p.position = 0
+
+	return
+}
+
+func cimportIsWord(text string) bool {
+	return len(text) > 0 && (text[0] == '_' ||
+		unicode.IsLetter(rune(text[0])) || unicode.IsDigit(rune(text[0])))
+}
+
+func cimportIsIdentifier(text string) bool {
+	return len(text) > 0 &&
+		(text[0] == '_' || unicode.IsLetter(rune(text[0])))
+}
+
+// cimportGoName converts a C identifier to an exported Go identifier,
+// dropping a "_t" suffix and capitalising the words between underscores.
+func cimportGoName(name string) string {
+	var (
+		builder strings.Builder
+		word    string
+	)
+
+	name = strings.TrimSuffix(name, "_t")
+
+	for _, word = range strings.Split(name, "_") {
+		if word == "" {
+			continue
+		}
+
+		builder.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+
+	return builder.String()
+}
+
+// parseType parses a type specifier,
+// including a struct definition if allowed.
+func (p *cimportParser) parseType(definition bool) (
+	ctype cimportType, e error,
+) {
+	var (
+		line  int
+		longs int
+		ok    bool
+		size  int
+		text  string
+		words []string
+	)
+
+	line = p.line()
+
+	for p.peek() == "const" || p.peek() == "volatile" {
+		p.next()
+	}
+
+	if p.peek() == "struct" {
+		p.next()
+
+		ctype.record, e = p.parseStruct(definition)
+
+		return
+	}
+
+	text = p.peek()
+
+	switch text {
+	case "int8_t", "uint8_t":
+		size = 1
+
+	case "int16_t", "uint16_t":
+		size = 2
+
+	case "int32_t", "uint32_t":
+		size = 4
+
+	case "int64_t", "uint64_t":
+		size = 8
+
+	case "_Bool", "bool":
+		size = 1
+	}
+
+	if size > 0 {
+		p.next()
+
+		ctype.size = size
+
+		return
+	}
+
+	ctype, ok = p.typedefs[text]
+	if ok {
+		p.next()
+
+		return
+	}
+
+	for {
+		text = p.peek()
+
+		switch text {
+		case "signed", "unsigned", "char", "short", "int", "long":
+			words = append(words, p.next())
+
+			continue
+		}
+
+		break
+	}
+
+	// Signed types are laid out as unsigned ones of the same size.
+
+	for _, text = range words {
+		switch text {
+		case "long":
+			longs++
+
+		case "char":
+			size = 1
+
+		case "short":
+			size = 2
+		}
+	}
+
+	switch {
+	case len(words) == 0:
+		e = p.errorf(line, "unsupported type %q", p.peek())
+
+		return
+
+	case size > 0:
+
+	case longs == 2:
+		size = 8
+
+	case longs == 1 && p.abi != cimportABIMSVC:
+		size = 8
+
+	default:
+		size = 4
+	}
+
+	ctype.size = size
+
+	return
+}
+
+func (p *cimportParser) parseStruct(definition bool) (
+	record *cimportStruct, e error,
+) {
+	var (
+		line int
+		name string
+		ok   bool
+	)
+
+	line = p.line()
+
+	if cimportIsIdentifier(p.peek()) {
+		name = p.next()
+	}
+
+	if p.peek() != "{" {
+		record, ok = p.structs[name]
+		if !ok {
+			e = p.errorf(line, "struct %s is not defined", name)
+		}
+
+		return
+	}
+
+	if !definition {
+		e = p.errorf(line, "nested struct definitions are not supported")
+
+		return
+	}
+
+	if _, ok = p.structs[name]; ok && name != "" {
+		e = p.errorf(line, "struct %s is defined more than once", name)
+
+		return
+	}
+
+	record = &cimportStruct{
+		cName:  "struct " + name,
+		goName: cimportGoName(name),
+		pack:   p.pack,
+	}
+
+	p.next()
+
+	for p.peek() != "}" {
+		if p.peek() == "" {
+			e = p.errorf(line, "%s is not terminated", record.cName)
+
+			return
+		}
+
+		e = p.parseMembers(record)
+		if e != nil {
+			return
+		}
+	}
+
+	p.next()
+
+	if name != "" {
+		p.structs[name] = record
+	}
+
+	p.order = append(p.order, record)
+
+	return
+}
+
+// parseMembers parses a member declaration with one or more declarators.
+func (p *cimportParser) parseMembers(record *cimportStruct) (e error) {
+	var (
+		ctype  cimportType
+		member cimportMember
+		value  constant.Value
+		text   string
+		tokens []string
+		line   int
+		count  uint64
+		width  uint64
+		exact  bool
+	)
+
+	line = p.line()
+
+	ctype, e = p.parseType(false)
+	if e != nil {
+		return
+	}
+
+	for {
+		member = cimportMember{
+			ctype: ctype,
+			line:  line,
+		}
+
+		if cimportIsIdentifier(p.peek()) {
+			member.name = p.next()
+		}
+
+		switch p.peek() {
+		case "[":
+			p.next()
+
+			tokens = tokens[:0]
+
+			for p.peek() != "]" && p.peek() != "" {
+				tokens = append(tokens, p.next())
+			}
+
+			e = p.expect("]")
+			if e != nil {
+				return
+			}
+
+			value, e = p.evaluate(strings.Join(tokens, " "))
+			if e != nil {
+				e = p.errorf(line, "array %s: %v", member.name, e)
+
+				return
+			}
+
+			count, exact = constant.Uint64Val(value)
+			if !exact || count == 0 {
+				e = p.errorf(line, "array %s has invalid length", member.name)
+
+				return
+			}
+
+			if ctype.record != nil {
+				e = p.errorf(line, "arrays of structs are not supported")
+
+				return
+			}
+
+			member.count = int(count)
+
+		case ":":
+			p.next()
+
+			tokens = tokens[:0]
+
+			for p.peek() != "," && p.peek() != ";" && p.peek() != "" {
+				tokens = append(tokens, p.next())
+			}
+
+			value, e = p.evaluate(strings.Join(tokens, " "))
+			if e != nil {
+				e = p.errorf(line, "bit field %s: %v", member.name, e)
+
+				return
+			}
+
+			width, exact = constant.Uint64Val(value)
+			if !exact || ctype.record != nil ||
+				width > uint64(8*ctype.size) ||
+				width == 0 && member.name != "" {
+				e = p.errorf(line, "bit field %s has invalid width",
+					member.name,
+				)
+
+				return
+			}
+
+			member.bitField = true
+			member.width = int(width)
+		}
+
+		if member.name == "" && !member.bitField {
+			e = p.errorf(line, "member has no name")
+
+			return
+		}
+
+		record.members = append(record.members, member)
+
+		text = p.next()
+
+		switch text {
+		case ",":
+			continue
+
+		case ";":
+			return
+		}
+
+		e = p.errorf(line, "expected \";\", found %q", text)
+
+		return
+	}
+}
+
+func cimportRoundUp(x, multiple int) int {
+	return (x + multiple - 1) / multiple * multiple
+}
+
+func cimportMin(x, y int) int {
+	if x < y {
+		return x
+	}
+
+	return y
+}
+
+// layout lays out the members of a struct for an ABI,
+// recording its size and alignment.
+func (s *cimportStruct) layout(abi string) {
+	var (
+		alignment int
+		count     int
+		i         int
+		item      cimportItem
+		member    cimportMember
+		natural   int
+		offset    int
+		reserved  int
+		unitEnd   int
+		unitSize  int
+		unitStart int
+		unitUsed  int
+		width     int
+	)
+
+	s.alignment = 1
+
+	for _, member = range s.members {
+		natural = member.ctype.size
+
+		if member.ctype.record != nil {
+			if member.ctype.record.alignment == 0 {
+				member.ctype.record.layout(abi)
+			}
+
+			natural = member.ctype.record.alignment
+		}
+
+		alignment = natural
+
+		if s.pack > 0 {
+			alignment = cimportMin(natural, s.pack)
+		}
+
+		item = cimportItem{
+			name:      cimportGoName(member.name),
+			bigEndian: abi == cimportABISysVBE,
+		}
+
+		if !member.bitField {
+			if unitSize > 0 {
+				offset = unitEnd
+				unitSize = 0
+			}
+
+			offset = cimportRoundUp(offset, 8*alignment)
+
+			if s.alignment < alignment {
+				s.alignment = alignment
+			}
+
+			if member.ctype.record != nil {
+				item.kind = cimportItemStruct
+				item.offset = offset
+				item.width = 8 * member.ctype.record.size
+				item.record = member.ctype.record
+
+				s.items = append(s.items, item)
+
+				offset += item.width
+
+				continue
+			}
+
+			count = member.count
+			if count == 0 {
+				count = 1
+			}
+
+			for i = 0; i < count; i++ {
+				item.kind = cimportItemScalar
+				item.offset = offset
+				item.width = 8 * member.ctype.size
+
+				if member.count > 0 {
+					item.name = cimportGoName(member.name) + strconv.Itoa(i)
+				}
+
+				s.items = append(s.items, item)
+
+				offset += item.width
+			}
+
+			continue
+		}
+
+		width = member.width
+		item.kind = cimportItemBitField
+
+		if member.name == "" && width > 0 {
+			item.name = fmt.Sprintf("Reserved%d", reserved)
+
+			reserved++
+		}
+
+		switch abi {
+		case cimportABIMSVC:
+			// Bit fields occupy storage units of their declared types,
+			// a new unit beginning when the type changes or bits run out.
+
+			if width == 0 {
+				if unitSize > 0 {
+					offset = unitEnd
+					unitSize = 0
+				}
+
+				continue
+			}
+
+			if unitSize != 8*member.ctype.size ||
+				unitUsed+width > unitSize {
+				if unitSize > 0 {
+					offset = unitEnd
+				}
+
+				unitSize = 8 * member.ctype.size
+				unitStart = cimportRoundUp(offset, 8*alignment)
+				unitEnd = unitStart + unitSize
+				unitUsed = 0
+			}
+
+			item.offset = unitStart + unitUsed
+
+			unitUsed += width
+
+			offset = unitEnd
+
+		default:
+			// Bit fields are packed in order of allocation,
+			// not straddling the boundaries of their declared types
+			// unless packed.
+
+			if width == 0 {
+				offset = cimportRoundUp(offset, 8*natural)
+
+				continue
+			}
+
+			if alignment == natural &&
+				offset/(8*natural) != (offset+width-1)/(8*natural) {
+				offset = cimportRoundUp(offset, 8*natural)
+			}
+
+			item.offset = offset
+
+			offset += width
+		}
+
+		// Unnamed bit fields do not affect the alignment of a struct
+		// under the System V ABI.
+
+		if s.alignment < alignment &&
+			(member.name != "" || abi == cimportABIMSVC) {
+			s.alignment = alignment
+		}
+
+		item.width = width
+
+		s.items = append(s.items, item)
+	}
+
+	if unitSize > 0 {
+		offset = unitEnd
+	}
+
+	s.size = cimportRoundUp(
+		cimportRoundUp(offset, 8)/8, s.alignment,
+	)
+
+	s.items = s.withPadding()
+
+	return
+}
+
+// withPadding returns the items of a struct
+// with padding inserted over bytes that no item occupies.
+func (s *cimportStruct) withPadding() (items []cimportItem) {
+	var (
+		cursor int
+		item   cimportItem
+	)
+
+	for _, item = range s.items {
+		items = cimportAppendPadding(items, &cursor, item.offset/8)
+
+		items = append(items, item)
+
+		if cimportRoundUp(item.offset+item.width, 8)/8 > cursor {
+			cursor = cimportRoundUp(item.offset+item.width, 8) / 8
+		}
+	}
+
+	items = cimportAppendPadding(items, &cursor, s.size)
+
+	return
+}
+
+// cimportAppendPadding appends padding from a cursor to an offset in bytes,
+// in lengths of up to a word.
+func cimportAppendPadding(items []cimportItem, cursor *int, end int) []cimportItem {
+	var (
+		item    cimportItem
+		length  int
+		padding int
+	)
+
+	for _, item = range items {
+		if item.kind == cimportItemPadding {
+			padding++
+		}
+	}
+
+	for *cursor < end {
+		length = cimportMin(end-*cursor, cimportWordLengthLimit)
+
+		items = append(items,
+			cimportItem{
+				name:   fmt.Sprintf("Padding%d", padding),
+				kind:   cimportItemPadding,
+				offset: 8 * *cursor,
+				width:  8 * length,
+			},
+		)
+
+		*cursor += length
+		padding++
+	}
+
+	return items
+}
+
+// words divides the items of a struct into words,
+// runs of bit fields sharing bytes forming one word each.
+func (s *cimportStruct) words() (words []cimportWord, e error) {
+	var (
+		current *cimportWord
+		end     int
+		item    cimportItem
+		start   int
+	)
+
+	for _, item = range s.items {
+		start = item.offset / 8
+		end = cimportRoundUp(item.offset+item.width, 8) / 8
+
+		switch {
+		case item.kind == cimportItemStruct:
+			words = append(words,
+				cimportWord{
+					start: start,
+					end:   end,
+					items: []cimportItem{item},
+				},
+			)
+
+			current = nil
+
+			continue
+
+		case current != nil && item.kind == cimportItemBitField &&
+			current.bitField && start < current.end:
+			if end > current.end {
+				current.end = end
+			}
+
+		case current != nil && item.kind != cimportItemBitField &&
+			!current.bitField && start == current.end &&
+			end-current.start <= cimportWordLengthLimit:
+			current.end = end
+
+		default:
+			words = append(words,
+				cimportWord{
+					start:    start,
+					end:      end,
+					bitField: item.kind == cimportItemBitField,
+				},
+			)
+
+			current = &words[len(words)-1]
+		}
+
+		current.items = append(current.items, item)
+
+		if current.end-current.start > cimportWordLengthLimit {
+			e = fmt.Errorf(
+				"%s: bit fields around %s span more than 64 bits",
+				s.cName, item.name,
+			)
+
+			return
+		}
+	}
+
+	return
+}
+
+type cimportBitField struct {
+	name   string
+	goType string
+	length int
+	low    int
+}
+
+// write writes the format-struct and word-structs of a struct.
+func (s *cimportStruct) write(buffer *bytes.Buffer, abi string) (e error) {
+	if s.alignment == 0 {
+		s.layout(abi)
+	}
+
+	if s.goName == "" {
+		e = fmt.Errorf("a struct has neither a name nor a typedef")
+
+		return
+	}
+
+	fmt.Fprintf(buffer,
+		"\n// %sFormat is %s, of %d byte(s) aligned to %d.\n",
+		s.goName, s.cName, s.size, s.alignment,
+	)
+
+	e = s.writeStructs(buffer)
+	if e != nil {
+		return
+	}
+
+	return
+}
+
+// writeStructs writes the format-struct and word-structs of items laid out.
+func (s *cimportStruct) writeStructs(buffer *bytes.Buffer) (e error) {
+	var (
+		bitField  cimportBitField
+		bitFields []cimportBitField
+		i         int
+		item      cimportItem
+		names     []string
+		order     string
+		reserved  int
+		word      cimportWord
+		words     []cimportWord
+	)
+
+	words, e = s.words()
+	if e != nil {
+		return
+	}
+
+	for _, word = range words {
+		for _, item = range word.items {
+			if item.kind == cimportItemBitField &&
+				strings.HasPrefix(item.name, "Reserved") {
+				reserved++
+			}
+		}
+	}
+
+	fmt.Fprintf(buffer, "type %sFormat struct {\n", s.goName)
+
+	names = make([]string, len(words))
+
+	for i, word = range words {
+		if word.items[0].kind == cimportItemStruct {
+			fmt.Fprintf(buffer, "%s %sFormat `format:\"\"`\n",
+				word.items[0].name, word.items[0].record.goName,
+			)
+
+			continue
+		}
+
+		names[i] = fmt.Sprintf("%sFormatWord%d",
+			s.goName, i-cimportCountStructs(words[:i]),
+		)
+
+		order = cimportWordOrder(word)
+
+		if order != "" {
+			order = ",order=" + order
+		}
+
+		fmt.Fprintf(buffer, "%s `word:\"%d%s\"`\n",
+			names[i], 8*(word.end-word.start), order,
+		)
+	}
+
+	fmt.Fprintf(buffer, "}\n")
+
+	for i, word = range words {
+		if names[i] == "" {
+			continue
+		}
+
+		bitFields = cimportWordBitFields(word, &reserved)
+
+		fmt.Fprintf(buffer, "\ntype %s struct {\n", names[i])
+
+		for _, bitField = range bitFields {
+			if bitField.goType == "" {
+				bitField.goType = cimportGoType(bitField.length)
+			}
+
+			fmt.Fprintf(buffer, "%s %s `bitfield:\"%d\"`\n",
+				bitField.name, bitField.goType, bitField.length,
+			)
+		}
+
+		fmt.Fprintf(buffer, "}\n")
+	}
+
+	return
+}
+
+func cimportCountStructs(words []cimportWord) (count int) {
+	var (
+		word cimportWord
+	)
+
+	for _, word = range words {
+		if word.items[0].kind == cimportItemStruct {
+			count++
+		}
+	}
+
+	return
+}
+
+func cimportGoType(length int) string {
+	switch {
+	case length <= 8:
+		return "uint8"
+
+	case length <= 16:
+		return "uint16"
+
+	case length <= 32:
+		return "uint32"
+	}
+
+	return "uint64"
+}
+
+// cimportWordOrder returns the order of the bytes of a word
+// of little-endian items: reversed over a run of bit fields,
+// and within each member otherwise.
+func cimportWordOrder(word cimportWord) string {
+	var (
+		i         int
+		identity  bool
+		item      cimportItem
+		length    int
+		order     []byte
+		start     int
+		itemBytes int
+	)
+
+	if word.bitField && word.items[0].bigEndian {
+		return ""
+	}
+
+	length = word.end - word.start
+
+	order = make([]byte, length)
+
+	if word.bitField {
+		for i = 0; i < length; i++ {
+			order[i] = byte('a' + length - 1 - i)
+		}
+	} else {
+		for _, item = range word.items {
+			start = item.offset/8 - word.start
+			itemBytes = item.width / 8
+
+			for i = 0; i < itemBytes; i++ {
+				if item.kind == cimportItemPadding || item.bigEndian {
+					order[start+i] = byte('a' + start + i)
+				} else {
+					order[start+i] = byte('a' + start + itemBytes - 1 - i)
+				}
+			}
+		}
+	}
+
+	identity = true
+
+	for i = range order {
+		identity = identity && order[i] == byte('a'+i)
+	}
+
+	if identity {
+		return ""
+	}
+
+	return string(order)
+}
+
+// cimportWordBitFields returns the bit fields of a word
+// from most to least significant, filling gaps with reserved bits.
+func cimportWordBitFields(word cimportWord, reserved *int) (
+	bitFields []cimportBitField,
+) {
+	var (
+		bitField cimportBitField
+		cursor   int
+		item     cimportItem
+		length   int
+		low      int
+		sorted   []cimportBitField
+	)
+
+	length = 8 * (word.end - word.start)
+
+	for _, item = range word.items {
+		if word.bitField && !word.items[0].bigEndian {
+			// Bits are allocated from the least significant.
+
+			low = item.offset - 8*word.start
+		} else {
+			low = length - (item.offset - 8*word.start) - item.width
+		}
+
+		sorted = append(sorted,
+			cimportBitField{
+				name:   item.name,
+				goType: item.goType,
+				length: item.width,
+				low:    low,
+			},
+		)
+	}
+
+	sort.SliceStable(sorted,
+		func(i, j int) bool {
+			return sorted[i].low > sorted[j].low
+		},
+	)
+
+	cursor = length
+
+	for _, bitField = range append(sorted, cimportBitField{}) {
+		if bitField.low+bitField.length < cursor {
+			bitFields = append(bitFields,
+				cimportBitField{
+					name:   fmt.Sprintf("Reserved%d", *reserved),
+					length: cursor - bitField.low - bitField.length,
+					low:    bitField.low + bitField.length,
+				},
+			)
+
+			*reserved++
+		}
+
+		if bitField.length > 0 {
+			bitFields = append(bitFields, bitField)
+		}
+
+		cursor = bitField.low
+	}
+
+	return
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateCImport(t *testing.T) {
+	const (
+		header = "" +
+			"#include <stdint.h>\n" +
+			"#define PORTS (1u << 1) /* two */\n" +
+			"\n" +
+			"typedef struct {\n" +
+			"\tuint8_t version : 4, ihl : 4;\n" +
+			"\tuint16_t ports[PORTS];\n" +
+			"\tuint32_t flags : 3;\n" +
+			"} sample_t;\n"
+
+		expected = "" +
+			"// Code generated by binary cimport from sample.h " +
+			"for the sysv ABI; DO NOT EDIT.\n" +
+			"\n" +
+			"package sample\n" +
+			"\n" +
+			"const (\n" +
+			"\tPORTS = 2\n" +
+			")\n" +
+			"\n" +
+			"// SampleFormat is sample_t, of 8 byte(s) aligned to 4.\n" +
+			"type SampleFormat struct {\n" +
+			"\tSampleFormatWord0 `word:\"8\"`\n" +
+			"\tSampleFormatWord1 `word:\"40,order=acbed\"`\n" +
+			"\tSampleFormatWord2 `word:\"8\"`\n" +
+			"\tSampleFormatWord3 `word:\"8\"`\n" +
+			"}\n" +
+			"\n" +
+			"type SampleFormatWord0 struct {\n" +
+			"\tIhl     uint8 `bitfield:\"4\"`\n" +
+			"\tVersion uint8 `bitfield:\"4\"`\n" +
+			"}\n" +
+			"\n" +
+			"type SampleFormatWord1 struct {\n" +
+			"\tPadding0 uint8  `bitfield:\"8\"`\n" +
+			"\tPorts0   uint16 `bitfield:\"16\"`\n" +
+			"\tPorts1   uint16 `bitfield:\"16\"`\n" +
+			"}\n" +
+			"\n" +
+			"type SampleFormatWord2 struct {\n" +
+			"\tReserved0 uint8 `bitfield:\"5\"`\n" +
+			"\tFlags     uint8 `bitfield:\"3\"`\n" +
+			"}\n" +
+			"\n" +
+			"type SampleFormatWord3 struct {\n" +
+			"\tPadding1 uint8 `bitfield:\"8\"`\n" +
+			"}\n"
+	)
+
+	var (
+		e      error
+		output []byte
+	)
+
+	output, e = generateCImport("sample.h", []byte(header),
+		cimportABISysV, "sample",
+	)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		expected, string(output),
+	)
+}
+
+func TestGenerateCImportABIs(t *testing.T) {
+	// Layouts agree with those of GCC on x86-64
+	// and are those documented for MSVC.
+
+	const (
+		header = "" +
+			"struct inner {\n" +
+			"\tuint16_t a;\n" +
+			"\tuint8_t b;\n" +
+			"};\n" +
+			"\n" +
+			"struct odd {\n" +
+			"\tuint8_t a;\n" +
+			"\tuint32_t b : 12;\n" +
+			"\tuint32_t c : 24;\n" +
+			"\tuint16_t d : 5;\n" +
+			"\tlong e;\n" +
+			"\tstruct inner f;\n" +
+			"};\n" +
+			"\n" +
+			"#pragma pack(push, 1)\n" +
+			"struct packed {\n" +
+			"\tuint8_t a;\n" +
+			"\tuint16_t c : 9;\n" +
+			"\tuint16_t d : 9;\n" +
+			"\tuint32_t b;\n" +
+			"};\n" +
+			"#pragma pack(pop)\n"
+	)
+
+	var (
+		e        error
+		expected string
+		output   []byte
+	)
+
+	output, e = generateCImport("odd.h", []byte(header),
+		cimportABISysV, "odd",
+	)
+
+	assert.Nil(t, e)
+
+	for _, expected = range []string{
+		"// InnerFormat is struct inner, of 4 byte(s) aligned to 2.\n",
+		"// OddFormat is struct odd, of 24 byte(s) aligned to 8.\n",
+		"\tOddFormatWord1 `word:\"16,order=ba\"`\n",
+		"\tOddFormatWord3 `word:\"24,order=cba\"`\n",
+		"\tF              InnerFormat `format:\"\"`\n",
+		"\tReserved0 uint8  `bitfield:\"4\"`\n" +
+			"\tB         uint16 `bitfield:\"12\"`\n",
+		"\tE uint64 `bitfield:\"64\"`\n",
+		"// PackedFormat is struct packed, of 8 byte(s) aligned to 1.\n",
+		"\tPackedFormatWord1 `word:\"24,order=cba\"`\n",
+		"\tReserved0 uint8  `bitfield:\"6\"`\n" +
+			"\tD         uint16 `bitfield:\"9\"`\n" +
+			"\tC         uint16 `bitfield:\"9\"`\n",
+	} {
+		assert.Contains(t, string(output), expected)
+	}
+
+	output, e = generateCImport("odd.h", []byte(header),
+		cimportABIMSVC, "odd",
+	)
+
+	assert.Nil(t, e)
+
+	for _, expected = range []string{
+		"// OddFormat is struct odd, of 24 byte(s) aligned to 4.\n",
+		"\tOddFormatWord0 `word:\"32\"`\n",
+		"\tOddFormatWord6 `word:\"56,order=abcgfed\"`\n",
+		"\tPadding3 uint32 `bitfield:\"24\"`\n" +
+			"\tE        uint32 `bitfield:\"32\"`\n",
+	} {
+		assert.Contains(t, string(output), expected)
+	}
+
+	output, e = generateCImport("odd.h", []byte(header),
+		cimportABISysVBE, "odd",
+	)
+
+	assert.Nil(t, e)
+
+	for _, expected = range []string{
+		"\tOddFormatWord1 `word:\"16\"`\n",
+		"\tB         uint16 `bitfield:\"12\"`\n" +
+			"\tReserved0 uint8  `bitfield:\"4\"`\n",
+	} {
+		assert.Contains(t, string(output), expected)
+	}
+}
+
+func TestGenerateCImportErrors(t *testing.T) {
+	var (
+		e error
+	)
+
+	_, e = generateCImport("error.h",
+		[]byte("struct a {\n\tfloat f;\n};\n"), cimportABISysV, "error",
+	)
+
+	assert.EqualError(t, e,
+		"error.h:2: unsupported type \"float\"",
+	)
+
+	_, e = generateCImport("error.h",
+		[]byte("struct a {\n\tuint8_t b[N];\n};\n"), cimportABISysV, "error",
+	)
+
+	assert.EqualError(t, e,
+		"error.h:2: array b: N is not an integer constant",
+	)
+
+	_, e = generateCImport("error.h",
+		[]byte("struct a {\n\tuint8_t b : 9;\n};\n"), cimportABISysV, "error",
+	)
+
+	assert.EqualError(t, e,
+		"error.h:2: bit field b has invalid width",
+	)
+
+	_, e = generateCImport("error.h",
+		[]byte("int x;\n"), cimportABISysV, "error",
+	)
+
+	assert.EqualError(t, e,
+		"error.h:1: unsupported declaration beginning \"int\"; "+
+			"only struct and typedef declarations are supported",
+	)
+}
@@ -0,0 +1,589 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	goimporter "go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/encodingx/binary"
+)
+
+// Decode loads a format-struct from the Go source of a local module
+// and decodes records of that format from files or hexadecimal input.
+// The struct is type-checked with go/types, with the packages of the module
+// and of the standard library that it imports, and rebuilt with
+// reflect.StructOf, so that its layout follows from its tags
+// by the same rules as Unmarshal.
+
+const (
+	decodeBinaryPackagePath = "github.com/encodingx/binary"
+	decodeModuleFilename    = "go.mod"
+)
+
+var (
+	decodeBasicTypes = map[types.BasicKind]reflect.Type{
+		types.Bool:   reflect.TypeOf(false),
+		types.Uint:   reflect.TypeOf(uint(0)),
+		types.Uint8:  reflect.TypeOf(uint8(0)),
+		types.Uint16: reflect.TypeOf(uint16(0)),
+		types.Uint32: reflect.TypeOf(uint32(0)),
+		types.Uint64: reflect.TypeOf(uint64(0)),
+	}
+
+	decodePayloadType = reflect.TypeOf(binary.Payload{})
+	decodeUUIDType    = reflect.TypeOf(binary.UUID{})
+)
+
+func runDecode(args []string, stdin io.Reader, stdout io.Writer) (e error) {
+	const (
+		usage = "" +
+			"Usage: binary decode -type importpath.Type [-C dir] " +
+			"[-hex string] [file ...]\n" +
+			"\n" +
+			"Decode loads a format-struct from the Go source of a module\n" +
+			"and prints the bit fields of each record decoded\n" +
+			"from files, a hexadecimal string or standard input.\n" +
+			"\n"
+	)
+
+	var (
+		directory  string
+		filename   string
+		flags      *flag.FlagSet
+		hexString  string
+		input      []byte
+		reflection reflect.Type
+		typeName   string
+	)
+
+	flags = flag.NewFlagSet("decode", flag.ContinueOnError)
+
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+
+		flags.PrintDefaults()
+	}
+
+	flags.StringVar(&typeName, "type", "",
+		"import path and name of the format-struct "+
+			"(e.g. \"github.com/our/pkg.HeaderFormat\")",
+	)
+
+	flags.StringVar(&directory, "C", ".",
+		"root directory of the module holding the format-struct",
+	)
+
+	flags.StringVar(&hexString, "hex", "",
+		"hexadecimal input, in place of files",
+	)
+
+	e = flags.Parse(args)
+	if e != nil {
+		return
+	}
+
+	if typeName == "" || hexString != "" && flags.NArg() > 0 {
+		flags.Usage()
+
+		e = errUsage
+
+		return
+	}
+
+	reflection, e = decodeLoadType(directory, typeName)
+	if e != nil {
+		return
+	}
+
+	switch {
+	case hexString != "":
+		input, e = hex.DecodeString(
+			strings.Join(strings.Fields(hexString), ""),
+		)
+		if e != nil {
+			return
+		}
+
+		e = decodeRecords(bytes.NewReader(input), reflection, stdout)
+
+	case flags.NArg() == 0:
+		e = decodeRecords(bufio.NewReader(stdin), reflection, stdout)
+
+	default:
+		for _, filename = range flags.Args() {
+			e = decodeFile(filename, reflection, stdout)
+			if e != nil {
+				return
+			}
+		}
+	}
+
+	return
+}
+
+func decodeFile(filename string, reflection reflect.Type, stdout io.Writer) (
+	e error,
+) {
+	var (
+		file *os.File
+	)
+
+	file, e = os.Open(filename)
+	if e != nil {
+		return
+	}
+
+	defer file.Close()
+
+	e = decodeRecords(bufio.NewReader(file), reflection, stdout)
+	if e != nil {
+		e = fmt.Errorf("%s: %w", filename, e)
+
+		return
+	}
+
+	return
+}
+
+// decodeRecords decodes records until the end of a stream,
+// printing each bit field and the length of any payload.
+func decodeRecords(reader io.Reader, reflection reflect.Type,
+	stdout io.Writer,
+) (
+	e error,
+) {
+	var (
+		decoder *binary.Decoder
+		record  int
+		value   reflect.Value
+	)
+
+	decoder = binary.NewDecoder(reader)
+
+	for record = 0; ; record++ {
+		value = reflect.New(reflection)
+
+		e = decoder.Decode(value.Interface())
+		if e == io.EOF {
+			e = nil
+
+			return
+		}
+
+		if e != nil {
+			return
+		}
+
+		fmt.Fprintf(stdout, "record %d\n", record)
+
+		e = decodePrint(stdout, "", value.Elem())
+		if e != nil {
+			return
+		}
+	}
+}
+
+func decodePrint(stdout io.Writer, prefix string, value reflect.Value) (
+	e error,
+) {
+	var (
+		field  reflect.StructField
+		i      int
+		length int64
+	)
+
+	for i = 0; i < value.NumField(); i++ {
+		field = value.Type().Field(i)
+
+		switch {
+		case field.Type == decodePayloadType:
+			length, e = io.Copy(io.Discard,
+				value.Field(i).Interface().(binary.Payload),
+			)
+			if e != nil {
+				return
+			}
+
+			fmt.Fprintf(stdout, "\t%s%s: %d byte(s)\n",
+				prefix, field.Name, length,
+			)
+
+		case field.Type.Kind() == reflect.Struct:
+			e = decodePrint(stdout, prefix+field.Name+".", value.Field(i))
+			if e != nil {
+				return
+			}
+
+		default:
+			fmt.Fprintf(stdout, "\t%s%s: %v\n",
+				prefix, field.Name, value.Field(i).Interface(),
+			)
+		}
+	}
+
+	return
+}
+
+// decodeLoadType type-checks the package of a format-struct
+// in a local module and rebuilds the struct with reflection.
+func decodeLoadType(directory, typeName string) (
+	reflection reflect.Type, e error,
+) {
+	var (
+		dot         int
+		object      types.Object
+		packagePath string
+		pkg         *types.Package
+	)
+
+	dot = strings.LastIndex(typeName, ".")
+	if dot < 0 || strings.LastIndex(typeName, "/") > dot {
+		e = fmt.Errorf("type %q should be of the form importpath.Type",
+			typeName,
+		)
+
+		return
+	}
+
+	packagePath = typeName[:dot]
+
+	pkg, e = decodeLoadPackage(directory, packagePath)
+	if e != nil {
+		return
+	}
+
+	object = pkg.Scope().Lookup(typeName[dot+1:])
+	if object == nil {
+		e = fmt.Errorf("package %s has no type %s",
+			packagePath, typeName[dot+1:],
+		)
+
+		return
+	}
+
+	reflection, e = decodeReflectType(object.Type())
+	if e != nil {
+		e = fmt.Errorf("type %s: %w", typeName, e)
+
+		return
+	}
+
+	return
+}
+
+func decodeLoadPackage(directory, packagePath string) (
+	pkg *types.Package, e error,
+) {
+	var (
+		fileSet  *token.FileSet
+		importer *decodeImporter
+		source   types.Importer
+	)
+
+	fileSet = token.NewFileSet()
+
+	source = goimporter.ForCompiler(fileSet, "source", nil)
+
+	importer = &decodeImporter{
+		directory: directory,
+		fileSet:   fileSet,
+		packages:  make(map[string]*types.Package),
+		source:    source.(types.ImporterFrom),
+	}
+
+	importer.modulePath, e = decodeModulePath(directory)
+	if e != nil {
+		return
+	}
+
+	if packagePath != importer.modulePath &&
+		!strings.HasPrefix(packagePath, importer.modulePath+"/") {
+		e = fmt.Errorf("package %s is not in module %s at %s",
+			packagePath, importer.modulePath, directory,
+		)
+
+		return
+	}
+
+	pkg, e = importer.load(packagePath)
+	if e != nil {
+		return
+	}
+
+	return
+}
+
+// decodeModulePath reads the module path from the go.mod file of a module.
+func decodeModulePath(directory string) (modulePath string, e error) {
+	const (
+		prefix = "module"
+	)
+
+	var (
+		line   string
+		source []byte
+	)
+
+	source, e = os.ReadFile(
+		filepath.Join(directory, decodeModuleFilename),
+	)
+	if e != nil {
+		return
+	}
+
+	for _, line = range strings.Split(string(source), "\n") {
+		line = strings.TrimSpace(line)
+
+		if !strings.HasPrefix(line, prefix) {
+			continue
+		}
+
+		modulePath = strings.Trim(
+			strings.TrimSpace(strings.TrimPrefix(line, prefix)),
+			"\"`",
+		)
+
+		if modulePath != "" {
+			return
+		}
+	}
+
+	e = fmt.Errorf("%s has no module path",
+		filepath.Join(directory, decodeModuleFilename),
+	)
+
+	return
+}
+
+// decodeImporter type-checks the packages of a module from source,
+// selecting files by build constraints as the go command does,
+// and imports those of the standard library from source.
+// It declares this module's root package
+// with only binary.Payload and binary.UUID,
+// so that modules need not have it downloaded.
+// Packages of other modules are not found.
+type decodeImporter struct {
+	directory  string
+	modulePath string
+	fileSet    *token.FileSet
+	packages   map[string]*types.Package
+	source     types.ImporterFrom
+}
+
+func (i *decodeImporter) Import(path string) (*types.Package, error) {
+	return i.ImportFrom(path, "", 0)
+}
+
+func (i *decodeImporter) ImportFrom(path, directory string,
+	mode types.ImportMode,
+) (
+	pkg *types.Package, e error,
+) {
+	switch {
+	case path == decodeBinaryPackagePath:
+		break
+
+	case path == i.modulePath || strings.HasPrefix(path, i.modulePath+"/"):
+		pkg, e = i.load(path)
+
+		return
+
+	// Paths of the standard library have no dot in their first element.
+
+	case !strings.Contains(strings.SplitN(path, "/", 2)[0], "."):
+		pkg, e = i.source.ImportFrom(path, directory, mode)
+
+		return
+
+	default:
+		e = fmt.Errorf("package %s is not in module %s "+
+			"or the standard library",
+			path, i.modulePath,
+		)
+
+		return
+	}
+
+	pkg = types.NewPackage(path, "binary")
+
+	pkg.Scope().Insert(
+		types.NewNamed(
+			types.NewTypeName(token.NoPos, pkg,
+				decodePayloadType.Name(), nil,
+			),
+			types.NewStruct(nil, nil),
+			nil,
+		).Obj(),
+	)
+
+	pkg.Scope().Insert(
+		types.NewNamed(
+			types.NewTypeName(token.NoPos, pkg,
+				decodeUUIDType.Name(), nil,
+			),
+			types.NewArray(types.Typ[types.Uint8],
+				int64(decodeUUIDType.Len()),
+			),
+			nil,
+		).Obj(),
+	)
+
+	pkg.MarkComplete()
+
+	return
+}
+
+// load type-checks a package of the module, reporting its type errors.
+func (i *decodeImporter) load(packagePath string) (
+	pkg *types.Package, e error,
+) {
+	var (
+		buildPackage *build.Package
+		config       types.Config
+		file         *ast.File
+		filename     string
+		files        []*ast.File
+		inCache      bool
+		packageDir   string
+	)
+
+	pkg, inCache = i.packages[packagePath]
+	if inCache {
+		if pkg == nil {
+			e = fmt.Errorf("package %s imports itself", packagePath)
+		}
+
+		return
+	}
+
+	i.packages[packagePath] = nil
+
+	packageDir = filepath.Join(i.directory,
+		filepath.FromSlash(
+			strings.TrimPrefix(
+				strings.TrimPrefix(packagePath, i.modulePath), "/",
+			),
+		),
+	)
+
+	buildPackage, e = build.ImportDir(packageDir, 0)
+	if e != nil {
+		e = fmt.Errorf("package %s: %w", packagePath, e)
+
+		return
+	}
+
+	for _, filename = range buildPackage.GoFiles {
+		file, e = parser.ParseFile(i.fileSet,
+			filepath.Join(packageDir, filename), nil, 0,
+		)
+		if e != nil {
+			return
+		}
+
+		files = append(files, file)
+	}
+
+	config = types.Config{
+		Importer: i,
+	}
+
+	pkg, e = config.Check(packagePath, i.fileSet, files, nil)
+	if e != nil {
+		pkg = nil
+
+		e = fmt.Errorf("package %s: %w", packagePath, e)
+
+		return
+	}
+
+	i.packages[packagePath] = pkg
+
+	return
+}
+
+// decodeReflectType rebuilds a format-struct, word-struct or bit field type.
+func decodeReflectType(t types.Type) (reflection reflect.Type, e error) {
+	var (
+		basic     *types.Basic
+		fields    []reflect.StructField
+		i         int
+		named     *types.Named
+		ok        bool
+		structure *types.Struct
+	)
+
+	named, ok = t.(*types.Named)
+	if ok && named.Obj().Pkg() != nil &&
+		named.Obj().Pkg().Path() == decodeBinaryPackagePath {
+		switch named.Obj().Name() {
+		case decodePayloadType.Name():
+			reflection = decodePayloadType
+
+			return
+
+		case decodeUUIDType.Name():
+			reflection = decodeUUIDType
+
+			return
+		}
+	}
+
+	basic, ok = t.Underlying().(*types.Basic)
+	if ok {
+		reflection, ok = decodeBasicTypes[basic.Kind()]
+		if !ok {
+			e = fmt.Errorf("type %s is not supported", t)
+		}
+
+		return
+	}
+
+	structure, ok = t.Underlying().(*types.Struct)
+	if !ok {
+		e = fmt.Errorf("type %s is not supported", t)
+
+		return
+	}
+
+	fields = make([]reflect.StructField, structure.NumFields())
+
+	for i = 0; i < structure.NumFields(); i++ {
+		if !structure.Field(i).Exported() {
+			e = fmt.Errorf("field %s is not exported",
+				structure.Field(i).Name(),
+			)
+
+			return
+		}
+
+		fields[i] = reflect.StructField{
+			Name: structure.Field(i).Name(),
+			Tag:  reflect.StructTag(structure.Tag(i)),
+		}
+
+		fields[i].Type, e = decodeReflectType(structure.Field(i).Type())
+		if e != nil {
+			e = fmt.Errorf("field %s: %w", structure.Field(i).Name(), e)
+
+			return
+		}
+	}
+
+	reflection = reflect.StructOf(fields)
+
+	return
+}
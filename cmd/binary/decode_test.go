@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunDecodeRFC791Hex(t *testing.T) {
+	const (
+		expected = "" +
+			"record 0\n" +
+			"\tRFC791InternetHeaderFormatWord0.Version: 4\n" +
+			"\tRFC791InternetHeaderFormatWord0.IHL: 5\n"
+	)
+
+	var (
+		e      error
+		stdout bytes.Buffer
+	)
+
+	e = runDecode(
+		[]string{
+			"-C", "../..",
+			"-type", "github.com/encodingx/binary/pkg/rfc791." +
+				"RFC791InternetHeaderFormatWithoutOptions",
+			"-hex", "45000054 a6f24000 40010000 c0a80001 c0a800c7",
+		},
+		nil,
+		&stdout,
+	)
+
+	assert.Nil(t, e)
+
+	assert.True(t,
+		strings.HasPrefix(stdout.String(), expected),
+	)
+
+	assert.Contains(t, stdout.String(),
+		"\tRFC791InternetHeaderFormatWord4.DestinationAddressOctet3: 199\n",
+	)
+}
+
+func TestRunDecodePayloadsFromStandardInput(t *testing.T) {
+	const (
+		goMod = "module example.com\n"
+
+		source = "package chunks\n" +
+			"\n" +
+			"import \"github.com/encodingx/binary\"\n" +
+			"\n" +
+			"type ChunkFormat struct {\n" +
+			"\tChunkFormatWord0 `word:\"32\"`\n" +
+			"\tID binary.UUID `uuid:\"guid\"`\n" +
+			"\tPayload binary.Payload `payload:\"Length\"`\n" +
+			"}\n" +
+			"\n" +
+			"type ChunkFormatWord0 struct {\n" +
+			"\tType uint8 `bitfield:\"8\"`\n" +
+			"\tLength uint32 `bitfield:\"24\"`\n" +
+			"}\n"
+
+		expected = "" +
+			"record 0\n" +
+			"\tChunkFormatWord0.Type: 1\n" +
+			"\tChunkFormatWord0.Length: 2\n" +
+			"\tID: 00112233-4455-6677-8899-aabbccddeeff\n" +
+			"\tPayload: 2 byte(s)\n" +
+			"record 1\n" +
+			"\tChunkFormatWord0.Type: 2\n" +
+			"\tChunkFormatWord0.Length: 0\n" +
+			"\tID: 00000000-0000-0000-0000-000000000000\n" +
+			"\tPayload: 0 byte(s)\n"
+	)
+
+	var (
+		directory string
+		e         error
+		stdout    bytes.Buffer
+	)
+
+	directory = t.TempDir()
+
+	e = os.WriteFile(filepath.Join(directory, "go.mod"), []byte(goMod), 0644)
+
+	assert.Nil(t, e)
+
+	e = os.MkdirAll(filepath.Join(directory, "chunks"), 0755)
+
+	assert.Nil(t, e)
+
+	e = os.WriteFile(filepath.Join(directory, "chunks", "chunks.go"),
+		[]byte(source), 0644,
+	)
+
+	assert.Nil(t, e)
+
+	e = runDecode(
+		[]string{
+			"-C", directory,
+			"-type", "example.com/chunks.ChunkFormat",
+		},
+		bytes.NewReader(
+			[]byte{
+				0x01, 0x00, 0x00, 0x02,
+				0x33, 0x22, 0x11, 0x00, 0x55, 0x44, 0x77, 0x66,
+				0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff,
+				'h', 'i',
+				0x02, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+		),
+		&stdout,
+	)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		expected, stdout.String(),
+	)
+
+	e = runDecode(
+		[]string{
+			"-C", directory,
+			"-type", "example.com/chunks.ChunkFormatWord1",
+		},
+		nil,
+		&stdout,
+	)
+
+	assert.EqualError(t, e,
+		"package example.com/chunks has no type ChunkFormatWord1",
+	)
+}
+
+func TestRunDecodeImportsAndBuildConstraints(t *testing.T) {
+	const (
+		goMod = "module example.com\n"
+
+		units = "package units\n" +
+			"\n" +
+			"import \"strconv\"\n" +
+			"\n" +
+			"type Celsius uint8\n" +
+			"\n" +
+			"func (c Celsius) String() string {\n" +
+			"\treturn strconv.Itoa(int(c)) + \" C\"\n" +
+			"}\n"
+
+		source = "package readings\n" +
+			"\n" +
+			"import \"example.com/units\"\n" +
+			"\n" +
+			"type ReadingFormat struct {\n" +
+			"\tReadingFormatWord0 `word:\"8\"`\n" +
+			"}\n" +
+			"\n" +
+			"type ReadingFormatWord0 struct {\n" +
+			"\tTemperature units.Celsius `bitfield:\"8\"`\n" +
+			"}\n"
+
+		// A file excluded by a build constraint
+		// would otherwise redeclare the format-struct.
+		ignored = "//go:build ignore\n" +
+			"\n" +
+			"package readings\n" +
+			"\n" +
+			"type ReadingFormat struct{}\n"
+
+		invalid = "package readings\n" +
+			"\n" +
+			"type InvalidFormat struct {\n" +
+			"\tInvalidFormatWord0 `word:\"8\"`\n" +
+			"}\n" +
+			"\n" +
+			"type InvalidFormatWord0 struct {\n" +
+			"\tValue Undeclared `bitfield:\"8\"`\n" +
+			"}\n"
+	)
+
+	var (
+		directory string
+		e         error
+		filename  string
+		stdout    bytes.Buffer
+		text      string
+	)
+
+	directory = t.TempDir()
+
+	for filename, text = range map[string]string{
+		"go.mod":                goMod,
+		"units/units.go":        units,
+		"readings/readings.go":  source,
+		"readings/ignored.go":   ignored,
+		"readings/invalid.go.x": invalid,
+	} {
+		e = os.MkdirAll(
+			filepath.Dir(filepath.Join(directory, filename)), 0755,
+		)
+
+		assert.Nil(t, e)
+
+		e = os.WriteFile(filepath.Join(directory, filename),
+			[]byte(text), 0644,
+		)
+
+		assert.Nil(t, e)
+	}
+
+	e = runDecode(
+		[]string{
+			"-C", directory,
+			"-type", "example.com/readings.ReadingFormat",
+		},
+		bytes.NewReader([]byte{0x15}),
+		&stdout,
+	)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		"record 0\n\tReadingFormatWord0.Temperature: 21\n", stdout.String(),
+	)
+
+	// Type errors in the package are reported.
+
+	e = os.Rename(filepath.Join(directory, "readings", "invalid.go.x"),
+		filepath.Join(directory, "readings", "invalid.go"),
+	)
+
+	assert.Nil(t, e)
+
+	e = runDecode(
+		[]string{
+			"-C", directory,
+			"-type", "example.com/readings.ReadingFormat",
+		},
+		bytes.NewReader([]byte{0x15}),
+		&stdout,
+	)
+
+	assert.NotNil(t, e)
+
+	assert.Contains(t, e.Error(),
+		"package example.com/readings: ",
+	)
+
+	assert.Contains(t, e.Error(),
+		"undefined: Undeclared",
+	)
+}
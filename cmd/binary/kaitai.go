@@ -0,0 +1,1196 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// KSYImport generates format-structs from the types of a Kaitai Struct spec,
+// within the subset of Kaitai that format-structs can express:
+// sequences of integers, "bN" bit fields, fixed-size byte arrays and strings,
+// fixed contents, nested types, enums, "if" and constant "repeat".
+// Each attribute conditional on "if" begins a format of its own,
+// to be decoded, following the format before it, if its condition holds.
+// Any other construct is reported with its line, rather than dropped.
+
+const (
+	kaitaiEndianBig    = "be"
+	kaitaiEndianLittle = "le"
+
+	kaitaiRepeatExpression = "expr"
+)
+
+var (
+	kaitaiIntegerType = regexp.MustCompile(`^([us])([1248])(le|be)?$`)
+	kaitaiBitType     = regexp.MustCompile(`^b([1-9][0-9]*)(le|be)?$`)
+
+	// Keys that document a spec and do not affect its layout.
+	kaitaiDocumentationKeys = map[string]bool{
+		"doc":                    true,
+		"doc-ref":                true,
+		"-orig-id":               true,
+		"-webide-representation": true,
+	}
+
+	kaitaiMetaKeys = map[string]bool{
+		"id":             true,
+		"title":          true,
+		"application":    true,
+		"file-extension": true,
+		"xref":           true,
+		"license":        true,
+		"ks-version":     true,
+		"ks-debug":       true,
+		"encoding":       true,
+		"endian":         true,
+		"bit-endian":     true,
+		"tags":           true,
+	}
+)
+
+type kaitaiPair struct {
+	key   string
+	value *yaml.Node
+}
+
+type kaitaiType struct {
+	id         string
+	goName     string
+	node       *yaml.Node
+	endian     string
+	bitEndian  string
+	segments   []*cimportStruct
+	conditions []string
+	contents   [][]string
+	laidOut    bool
+	layingOut  bool
+}
+
+type kaitaiEnum struct {
+	id     string
+	goName string
+	names  []string
+	values []uint64
+	width  int
+}
+
+type kaitaiGenerator struct {
+	filename string
+	types    []*kaitaiType
+	typeMap  map[string]*kaitaiType
+	enums    []*kaitaiEnum
+	enumMap  map[string]*kaitaiEnum
+}
+
+func runKSYImport(args []string, stdout io.Writer) (e error) {
+	const (
+		usage = "" +
+			"Usage: binary ksyimport [-package name] [-o file] spec.ksy\n" +
+			"\n" +
+			"KSYImport generates Go format-structs and word-structs\n" +
+			"from the types of a Kaitai Struct spec, and Go constants\n" +
+			"from its enums.\n" +
+			"\n"
+	)
+
+	var (
+		filename    string
+		flags       *flag.FlagSet
+		output      []byte
+		packageName string
+		source      []byte
+	)
+
+	flags = flag.NewFlagSet("ksyimport", flag.ContinueOnError)
+
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+
+		flags.PrintDefaults()
+	}
+
+	flags.StringVar(&packageName, "package", "",
+		"name of the Go package, by default derived from the spec",
+	)
+
+	flags.StringVar(&filename, "o", "",
+		"file to write instead of standard output",
+	)
+
+	e = flags.Parse(args)
+	if e != nil {
+		return
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+
+		e = errUsage
+
+		return
+	}
+
+	source, e = os.ReadFile(flags.Arg(0))
+	if e != nil {
+		return
+	}
+
+	if packageName == "" {
+		packageName = cimportPackageName(flags.Arg(0))
+	}
+
+	output, e = generateKSYImport(flags.Arg(0), source, packageName)
+	if e != nil {
+		return
+	}
+
+	if filename != "" {
+		e = os.WriteFile(filename, output, 0644)
+
+		return
+	}
+
+	_, e = stdout.Write(output)
+
+	return
+}
+
+// generateKSYImport parses a Kaitai Struct spec
+// and returns the formatted Go source of its enums and types.
+func generateKSYImport(filename string, source []byte, packageName string) (
+	output []byte, e error,
+) {
+	var (
+		buffer    bytes.Buffer
+		document  yaml.Node
+		enum      *kaitaiEnum
+		generator *kaitaiGenerator
+		i         int
+		t         *kaitaiType
+	)
+
+	e = yaml.Unmarshal(source, &document)
+	if e != nil {
+		e = fmt.Errorf("%s: %w", filename, e)
+
+		return
+	}
+
+	if len(document.Content) == 0 {
+		e = fmt.Errorf("%s: spec is empty", filename)
+
+		return
+	}
+
+	generator = &kaitaiGenerator{
+		filename: filename,
+		typeMap:  make(map[string]*kaitaiType),
+		enumMap:  make(map[string]*kaitaiEnum),
+	}
+
+	e = generator.parseRoot(document.Content[0])
+	if e != nil {
+		return
+	}
+
+	for _, t = range generator.types {
+		e = generator.layout(t)
+		if e != nil {
+			return
+		}
+	}
+
+	fmt.Fprintf(&buffer,
+		"// Code generated by binary ksyimport from %s; DO NOT EDIT.\n\n"+
+			"package %s\n",
+		filepath.Base(filename), packageName,
+	)
+
+	for _, enum = range generator.enums {
+		fmt.Fprintf(&buffer,
+			"\n// %s is enum %s.\ntype %s %s\n\nconst (\n",
+			enum.goName, enum.id, enum.goName, cimportGoType(enum.width),
+		)
+
+		for i = range enum.names {
+			fmt.Fprintf(&buffer, "%s%s %s = %d\n",
+				enum.goName, cimportGoName(enum.names[i]), enum.goName,
+				enum.values[i],
+			)
+		}
+
+		fmt.Fprintf(&buffer, ")\n")
+	}
+
+	for _, t = range generator.types {
+		e = generator.write(&buffer, t)
+		if e != nil {
+			return
+		}
+	}
+
+	output, e = format.Source(buffer.Bytes())
+	if e != nil {
+		return
+	}
+
+	return
+}
+
+func (g *kaitaiGenerator) errorf(node *yaml.Node, format string,
+	args ...interface{},
+) error {
+	return fmt.Errorf("%s:%d: %s",
+		g.filename, node.Line, fmt.Sprintf(format, args...),
+	)
+}
+
+// mapping returns the pairs of a mapping node in order.
+func (g *kaitaiGenerator) mapping(node *yaml.Node, name string) (
+	pairs []kaitaiPair, e error,
+) {
+	var (
+		i int
+	)
+
+	if node.Kind != yaml.MappingNode {
+		e = g.errorf(node, "%s should be a mapping", name)
+
+		return
+	}
+
+	for i = 0; i+1 < len(node.Content); i += 2 {
+		pairs = append(pairs,
+			kaitaiPair{
+				key:   node.Content[i].Value,
+				value: node.Content[i+1],
+			},
+		)
+	}
+
+	return
+}
+
+// parseRoot parses the meta section, enums and types of a spec,
+// the spec itself being the first type.
+func (g *kaitaiGenerator) parseRoot(node *yaml.Node) (e error) {
+	var (
+		id     string
+		pair   kaitaiPair
+		pairs  []kaitaiPair
+		endian string
+		bits   string
+	)
+
+	pairs, e = g.mapping(node, "spec")
+	if e != nil {
+		return
+	}
+
+	for _, pair = range pairs {
+		if pair.key != "meta" {
+			continue
+		}
+
+		id, endian, bits, e = g.parseMeta(pair.value,
+			"", kaitaiEndianBig,
+		)
+		if e != nil {
+			return
+		}
+	}
+
+	if id == "" {
+		e = g.errorf(node, "meta/id is required")
+
+		return
+	}
+
+	e = g.parseType(id, node, endian, bits)
+	if e != nil {
+		return
+	}
+
+	return
+}
+
+// parseMeta parses a meta section, inheriting endianness from the parent.
+func (g *kaitaiGenerator) parseMeta(node *yaml.Node,
+	parentEndian, parentBitEndian string,
+) (
+	id, endian, bitEndian string, e error,
+) {
+	var (
+		pair  kaitaiPair
+		pairs []kaitaiPair
+	)
+
+	endian = parentEndian
+	bitEndian = parentBitEndian
+
+	pairs, e = g.mapping(node, "meta")
+	if e != nil {
+		return
+	}
+
+	for _, pair = range pairs {
+		if !kaitaiMetaKeys[pair.key] {
+			e = g.errorf(pair.value, "meta/%s is not supported", pair.key)
+
+			return
+		}
+
+		switch pair.key {
+		case "id":
+			id = pair.value.Value
+
+		case "endian":
+			if pair.value.Kind != yaml.ScalarNode ||
+				pair.value.Value != kaitaiEndianBig &&
+					pair.value.Value != kaitaiEndianLittle {
+				e = g.errorf(pair.value,
+					"meta/endian should be \"be\" or \"le\"; "+
+						"switched endianness is not supported",
+				)
+
+				return
+			}
+
+			endian = pair.value.Value
+
+		case "bit-endian":
+			if pair.value.Value != kaitaiEndianBig &&
+				pair.value.Value != kaitaiEndianLittle {
+				e = g.errorf(pair.value,
+					"meta/bit-endian should be \"be\" or \"le\"",
+				)
+
+				return
+			}
+
+			bitEndian = pair.value.Value
+		}
+	}
+
+	return
+}
+
+// parseType registers a type, its enums and the types nested in it.
+func (g *kaitaiGenerator) parseType(id string, node *yaml.Node,
+	endian, bitEndian string,
+) (e error) {
+	var (
+		pair     kaitaiPair
+		pairs    []kaitaiPair
+		nested   []kaitaiPair
+		ok       bool
+		t        *kaitaiType
+		typePair kaitaiPair
+	)
+
+	if _, ok = g.typeMap[id]; ok {
+		e = g.errorf(node, "type %s is defined more than once", id)
+
+		return
+	}
+
+	t = &kaitaiType{
+		id:        id,
+		goName:    cimportGoName(id),
+		node:      node,
+		endian:    endian,
+		bitEndian: bitEndian,
+	}
+
+	g.types = append(g.types, t)
+	g.typeMap[id] = t
+
+	pairs, e = g.mapping(node, "type "+id)
+	if e != nil {
+		return
+	}
+
+	for _, pair = range pairs {
+		switch {
+		case pair.key == "meta":
+			// The meta section of the spec itself is parsed by parseRoot.
+
+			if len(g.types) > 1 {
+				_, t.endian, t.bitEndian, e = g.parseMeta(pair.value,
+					endian, bitEndian,
+				)
+				if e != nil {
+					return
+				}
+			}
+
+		case pair.key == "seq":
+
+		case pair.key == "enums":
+			e = g.parseEnums(pair.value)
+			if e != nil {
+				return
+			}
+
+		case pair.key == "types":
+			nested, e = g.mapping(pair.value, "types")
+			if e != nil {
+				return
+			}
+
+			for _, typePair = range nested {
+				e = g.parseType(typePair.key, typePair.value,
+					t.endian, t.bitEndian,
+				)
+				if e != nil {
+					return
+				}
+			}
+
+		case kaitaiDocumentationKeys[pair.key]:
+
+		default:
+			e = g.errorf(pair.value, "%s of type %s is not supported",
+				pair.key, id,
+			)
+
+			return
+		}
+	}
+
+	return
+}
+
+func (g *kaitaiGenerator) parseEnums(node *yaml.Node) (e error) {
+	var (
+		enum      *kaitaiEnum
+		enumPair  kaitaiPair
+		enumPairs []kaitaiPair
+		name      string
+		ok        bool
+		pair      kaitaiPair
+		pairs     []kaitaiPair
+		value     uint64
+		width     int
+	)
+
+	enumPairs, e = g.mapping(node, "enums")
+	if e != nil {
+		return
+	}
+
+	for _, enumPair = range enumPairs {
+		if _, ok = g.enumMap[enumPair.key]; ok {
+			e = g.errorf(enumPair.value, "enum %s is defined more than once",
+				enumPair.key,
+			)
+
+			return
+		}
+
+		enum = &kaitaiEnum{
+			id:     enumPair.key,
+			goName: cimportGoName(enumPair.key),
+		}
+
+		pairs, e = g.mapping(enumPair.value, "enum "+enumPair.key)
+		if e != nil {
+			return
+		}
+
+		for _, pair = range pairs {
+			value, e = strconv.ParseUint(pair.key, 0, 64)
+			if e != nil {
+				e = g.errorf(pair.value,
+					"value %s of enum %s should be a non-negative integer",
+					pair.key, enumPair.key,
+				)
+
+				return
+			}
+
+			name, e = g.enumName(pair.value)
+			if e != nil {
+				return
+			}
+
+			enum.names = append(enum.names, name)
+			enum.values = append(enum.values, value)
+
+			for width = 8; width < 64 && value >= 1<<uint(width); width *= 2 {
+			}
+
+			if enum.width < width {
+				enum.width = width
+			}
+		}
+
+		g.enums = append(g.enums, enum)
+		g.enumMap[enum.id] = enum
+	}
+
+	return
+}
+
+// enumName returns the identifier of an enum value,
+// given alone or with documentation.
+func (g *kaitaiGenerator) enumName(node *yaml.Node) (name string, e error) {
+	var (
+		pair  kaitaiPair
+		pairs []kaitaiPair
+	)
+
+	if node.Kind == yaml.ScalarNode {
+		name = node.Value
+
+		return
+	}
+
+	pairs, e = g.mapping(node, "enum value")
+	if e != nil {
+		return
+	}
+
+	for _, pair = range pairs {
+		switch {
+		case pair.key == "id":
+			name = pair.value.Value
+
+		case kaitaiDocumentationKeys[pair.key]:
+
+		default:
+			e = g.errorf(pair.value, "%s of enum value is not supported",
+				pair.key,
+			)
+
+			return
+		}
+	}
+
+	if name == "" {
+		e = g.errorf(node, "enum value has no id")
+
+		return
+	}
+
+	return
+}
+
+// layout lays out the sequence of a type in segments,
+// each conditional attribute beginning a segment.
+func (g *kaitaiGenerator) layout(t *kaitaiType) (e error) {
+	var (
+		attribute *yaml.Node
+		condition string
+		contents  []string
+		i         int
+		items     []cimportItem
+		offset    int
+		pair      kaitaiPair
+		pairs     []kaitaiPair
+		segment   *cimportStruct
+		sequence  *yaml.Node
+	)
+
+	if t.laidOut {
+		return
+	}
+
+	if t.layingOut {
+		e = g.errorf(t.node, "type %s contains itself", t.id)
+
+		return
+	}
+
+	t.layingOut = true
+
+	pairs, e = g.mapping(t.node, "type "+t.id)
+	if e != nil {
+		return
+	}
+
+	for _, pair = range pairs {
+		if pair.key == "seq" {
+			sequence = pair.value
+		}
+	}
+
+	segment = &cimportStruct{
+		cName:  t.id,
+		goName: t.goName,
+	}
+
+	t.segments = append(t.segments, segment)
+	t.conditions = append(t.conditions, "")
+	t.contents = append(t.contents, nil)
+
+	if sequence == nil || sequence.Kind != yaml.SequenceNode {
+		e = g.errorf(t.node, "type %s has no seq", t.id)
+
+		return
+	}
+
+	for i, attribute = range sequence.Content {
+		condition = g.attributeValue(attribute, "if")
+
+		// A conditional attribute begins a segment of its own,
+		// and the attributes following it begin another.
+
+		switch {
+		case condition == "" && t.conditions[len(t.conditions)-1] == "":
+
+		case len(segment.items) == 0:
+			t.conditions[len(t.conditions)-1] = condition
+
+		case offset%8 != 0:
+			e = g.errorf(attribute,
+				"attribute %s of type %s does not begin on a byte boundary, "+
+					"as conditional attributes and those following them should",
+				g.attributeID(attribute, i), t.id,
+			)
+
+			return
+
+		default:
+			g.endSegment(segment, offset)
+
+			segment = &cimportStruct{
+				cName:  t.id,
+				goName: t.goName + cimportGoName(g.attributeID(attribute, i)),
+			}
+
+			if condition == "" {
+				segment.goName = t.goName + "After" +
+					strings.TrimPrefix(t.segments[len(t.segments)-1].goName,
+						t.goName,
+					)
+			}
+
+			t.segments = append(t.segments, segment)
+			t.conditions = append(t.conditions, condition)
+			t.contents = append(t.contents, nil)
+
+			offset = 0
+		}
+
+		items, contents, e = g.attribute(t, i, attribute, offset)
+		if e != nil {
+			return
+		}
+
+		t.contents[len(t.contents)-1] = append(
+			t.contents[len(t.contents)-1], contents...,
+		)
+
+		segment.items = append(segment.items, items...)
+
+		if len(items) > 0 {
+			offset = items[len(items)-1].offset + items[len(items)-1].width
+		}
+	}
+
+	g.endSegment(segment, offset)
+
+	t.layingOut = false
+	t.laidOut = true
+
+	return
+}
+
+// endSegment records the size of a segment,
+// its last bit field padded to a byte boundary.
+func (g *kaitaiGenerator) endSegment(segment *cimportStruct, offset int) {
+	segment.size = cimportRoundUp(offset, 8) / 8
+	segment.alignment = 1
+}
+
+func (g *kaitaiGenerator) attributeID(node *yaml.Node, i int) string {
+	var (
+		id string
+	)
+
+	id = g.attributeValue(node, "id")
+	if id == "" {
+		id = fmt.Sprintf("unnamed%d", i)
+	}
+
+	return id
+}
+
+// attributeValue returns the scalar value of a key of an attribute.
+func (g *kaitaiGenerator) attributeValue(node *yaml.Node, key string) string {
+	var (
+		i int
+	)
+
+	for i = 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1].Value
+		}
+	}
+
+	return ""
+}
+
+// attribute lays out an attribute of a sequence from an offset in bits,
+// returning its items and a description of any contents.
+func (g *kaitaiGenerator) attribute(t *kaitaiType, index int,
+	node *yaml.Node, offset int,
+) (
+	items []cimportItem, contents []string, e error,
+) {
+	var (
+		attributeType string
+		count         int
+		enum          *kaitaiEnum
+		enumNode      *yaml.Node
+		expected      []byte
+		i             int
+		id            string
+		ok            bool
+		pair          kaitaiPair
+		pairs         []kaitaiPair
+		repeat        string
+		repeatNode    *yaml.Node
+		repeats       int
+		size          int
+		sized         bool
+		typeNode      *yaml.Node
+		unit          []cimportItem
+		name          string
+	)
+
+	pairs, e = g.mapping(node, "attribute")
+	if e != nil {
+		return
+	}
+
+	id = g.attributeID(node, index)
+
+	for _, pair = range pairs {
+		switch {
+		case pair.key == "id":
+
+		case pair.key == "type":
+			if pair.value.Kind != yaml.ScalarNode {
+				e = g.errorf(pair.value,
+					"attribute %s: switch-on types are not supported", id,
+				)
+
+				return
+			}
+
+			typeNode = pair.value
+			attributeType = pair.value.Value
+
+		case pair.key == "size":
+			size, e = strconv.Atoi(pair.value.Value)
+			if e != nil || size <= 0 {
+				e = g.errorf(pair.value,
+					"attribute %s: size %q is not a positive integer; "+
+						"only constant sizes are supported",
+					id, pair.value.Value,
+				)
+
+				return
+			}
+
+			sized = true
+
+		case pair.key == "contents":
+			expected, e = g.contents(pair.value)
+			if e != nil {
+				return
+			}
+
+		case pair.key == "enum":
+			enumNode = pair.value
+
+		case pair.key == "if":
+
+		case pair.key == "repeat":
+			repeat = pair.value.Value
+			repeatNode = pair.value
+
+		case pair.key == "repeat-expr":
+			repeats, e = strconv.Atoi(pair.value.Value)
+			if e != nil || repeats <= 0 {
+				e = g.errorf(pair.value,
+					"attribute %s: repeat-expr %q is not a positive "+
+						"integer; only constant repeats are supported",
+					id, pair.value.Value,
+				)
+
+				return
+			}
+
+		case pair.key == "encoding" && attributeType == "str":
+
+		case kaitaiDocumentationKeys[pair.key]:
+
+		default:
+			e = g.errorf(pair.value, "attribute %s: %s is not supported",
+				id, pair.key,
+			)
+
+			return
+		}
+	}
+
+	count = 1
+
+	switch {
+	case repeatNode == nil:
+
+	case repeat != kaitaiRepeatExpression:
+		e = g.errorf(repeatNode,
+			"attribute %s: repeat %q is not supported; "+
+				"only repeat: expr with a constant repeat-expr is",
+			id, repeat,
+		)
+
+		return
+
+	case repeats == 0:
+		e = g.errorf(repeatNode, "attribute %s: repeat-expr is required", id)
+
+		return
+
+	default:
+		count = repeats
+	}
+
+	name = cimportGoName(id)
+
+	for i = 0; i < count; i++ {
+		if count > 1 {
+			name = fmt.Sprintf("%s%d", cimportGoName(id), i)
+		}
+
+		switch {
+		case expected != nil:
+			unit = g.bytes(name, &offset, len(expected))
+
+			contents = append(contents,
+				fmt.Sprintf("%s should hold % x.", name, expected),
+			)
+
+		case attributeType == "" || attributeType == "str":
+			if !sized {
+				e = g.errorf(node,
+					"attribute %s: a size is required; "+
+						"only fixed-size attributes are supported",
+					id,
+				)
+
+				return
+			}
+
+			unit = g.bytes(name, &offset, size)
+
+		default:
+			if sized {
+				e = g.errorf(node,
+					"attribute %s: size of a typed attribute is not supported",
+					id,
+				)
+
+				return
+			}
+
+			unit, e = g.typed(t, name, typeNode, &offset)
+			if e != nil {
+				return
+			}
+		}
+
+		if enumNode != nil {
+			enum, ok = g.enumMap[enumNode.Value]
+
+			switch {
+			case !ok:
+				e = g.errorf(enumNode, "attribute %s: enum %s is not defined",
+					id, enumNode.Value,
+				)
+
+				return
+
+			case len(unit) != 1 || unit[0].kind == cimportItemStruct ||
+				unit[0].goType == "bool":
+				e = g.errorf(enumNode,
+					"attribute %s: enum %s applies only to integers",
+					id, enumNode.Value,
+				)
+
+				return
+			}
+
+			if enum.width < unit[0].width {
+				enum.width = unit[0].width
+			}
+
+			unit[0].goType = enum.goName
+		}
+
+		items = append(items, unit...)
+	}
+
+	return
+}
+
+// contents returns the bytes expected of an attribute,
+// given as a string or a list of bytes and strings.
+func (g *kaitaiGenerator) contents(node *yaml.Node) (
+	expected []byte, e error,
+) {
+	var (
+		element *yaml.Node
+		value   uint64
+	)
+
+	if node.Kind == yaml.ScalarNode {
+		expected = []byte(node.Value)
+
+		return
+	}
+
+	if node.Kind != yaml.SequenceNode {
+		e = g.errorf(node, "contents should be a string or a list")
+
+		return
+	}
+
+	for _, element = range node.Content {
+		if element.Tag == "!!str" {
+			expected = append(expected, element.Value...)
+
+			continue
+		}
+
+		value, e = strconv.ParseUint(element.Value, 0, 8)
+		if e != nil {
+			e = g.errorf(element, "contents %q is not a byte", element.Value)
+
+			return
+		}
+
+		expected = append(expected, byte(value))
+	}
+
+	return
+}
+
+// bytes lays out a byte array as bytes named in order.
+func (g *kaitaiGenerator) bytes(name string, offset *int, size int) (
+	items []cimportItem,
+) {
+	var (
+		i int
+	)
+
+	*offset = cimportRoundUp(*offset, 8)
+
+	for i = 0; i < size; i++ {
+		items = append(items,
+			cimportItem{
+				name:      fmt.Sprintf("%s%d", name, i),
+				kind:      cimportItemScalar,
+				offset:    *offset,
+				width:     8,
+				bigEndian: true,
+			},
+		)
+
+		*offset += 8
+	}
+
+	return
+}
+
+// typed lays out an integer, a bit field or a nested type.
+func (g *kaitaiGenerator) typed(t *kaitaiType, name string,
+	node *yaml.Node, offset *int,
+) (
+	items []cimportItem, e error,
+) {
+	var (
+		endian string
+		match  []string
+		nested *kaitaiType
+		ok     bool
+		width  int
+	)
+
+	match = kaitaiIntegerType.FindStringSubmatch(node.Value)
+	if match != nil {
+		width, _ = strconv.Atoi(match[2])
+
+		endian = match[3]
+		if endian == "" {
+			endian = t.endian
+		}
+
+		if endian == "" && width > 1 {
+			e = g.errorf(node,
+				"type %s: endianness is not specified by meta/endian",
+				node.Value,
+			)
+
+			return
+		}
+
+		*offset = cimportRoundUp(*offset, 8)
+
+		items = append(items,
+			cimportItem{
+				name:      name,
+				kind:      cimportItemScalar,
+				offset:    *offset,
+				width:     8 * width,
+				bigEndian: endian != kaitaiEndianLittle,
+			},
+		)
+
+		*offset += 8 * width
+
+		return
+	}
+
+	match = kaitaiBitType.FindStringSubmatch(node.Value)
+	if match != nil {
+		width, _ = strconv.Atoi(match[1])
+
+		if width > p4WordLengthLimit {
+			e = g.errorf(node, "type %s: bit fields of more than 64 bits "+
+				"are not supported", node.Value,
+			)
+
+			return
+		}
+
+		endian = match[2]
+		if endian == "" {
+			endian = t.bitEndian
+		}
+
+		items = append(items,
+			cimportItem{
+				name:      name,
+				kind:      cimportItemBitField,
+				offset:    *offset,
+				width:     width,
+				bigEndian: endian != kaitaiEndianLittle,
+			},
+		)
+
+		if width == 1 {
+			items[0].goType = "bool"
+		}
+
+		*offset += width
+
+		return
+	}
+
+	nested, ok = g.typeMap[node.Value]
+	if !ok {
+		e = g.errorf(node, "type %s is not supported", node.Value)
+
+		return
+	}
+
+	e = g.layout(nested)
+	if e != nil {
+		return
+	}
+
+	if len(nested.segments) > 1 {
+		e = g.errorf(node, "type %s has conditional attributes "+
+			"and cannot be nested", node.Value,
+		)
+
+		return
+	}
+
+	*offset = cimportRoundUp(*offset, 8)
+
+	items = append(items,
+		cimportItem{
+			name:   name,
+			kind:   cimportItemStruct,
+			offset: *offset,
+			width:  8 * nested.segments[0].size,
+			record: nested.segments[0],
+		},
+	)
+
+	*offset += items[0].width
+
+	return
+}
+
+// write writes the format-structs of the segments of a type.
+func (g *kaitaiGenerator) write(buffer *bytes.Buffer, t *kaitaiType) (
+	e error,
+) {
+	var (
+		content string
+		i       int
+		segment *cimportStruct
+	)
+
+	for i, segment = range t.segments {
+		switch {
+		case i == 0 && t.conditions[i] != "":
+			fmt.Fprintf(buffer,
+				"\n// %sFormat is type %s if %s,\n// and is of %d byte(s).\n",
+				segment.goName, t.id, t.conditions[i], segment.size,
+			)
+
+		case i == 0:
+			fmt.Fprintf(buffer, "\n// %sFormat is type %s, of %d byte(s).\n",
+				segment.goName, t.id, segment.size,
+			)
+
+		case t.conditions[i] != "":
+			fmt.Fprintf(buffer,
+				"\n// %sFormat follows %sFormat in type %s if %s,\n"+
+					"// and is of %d byte(s).\n",
+				segment.goName, t.segments[i-1].goName, t.id, t.conditions[i],
+				segment.size,
+			)
+
+		default:
+			fmt.Fprintf(buffer,
+				"\n// %sFormat follows %sFormat in type %s,\n"+
+					"// and is of %d byte(s).\n",
+				segment.goName, t.segments[i-1].goName, t.id, segment.size,
+			)
+		}
+
+		for _, content = range t.contents[i] {
+			fmt.Fprintf(buffer, "// %s\n", content)
+		}
+
+		segment.items = segment.withPadding()
+
+		e = segment.writeStructs(buffer)
+		if e != nil {
+			e = fmt.Errorf("%s: %w", g.filename, e)
+
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateKSYImport(t *testing.T) {
+	const (
+		spec = "" +
+			"meta:\n" +
+			"  id: sample\n" +
+			"  endian: le\n" +
+			"seq:\n" +
+			"  - id: magic\n" +
+			"    contents: [0x53, 0x4d]\n" +
+			"  - id: version\n" +
+			"    type: u2\n" +
+			"  - id: has_extra\n" +
+			"    type: b1\n" +
+			"  - id: mode\n" +
+			"    type: b7\n" +
+			"    enum: mode\n" +
+			"  - id: points\n" +
+			"    type: point\n" +
+			"    repeat: expr\n" +
+			"    repeat-expr: 2\n" +
+			"  - id: extra\n" +
+			"    type: u4be\n" +
+			"    if: has_extra\n" +
+			"  - id: name\n" +
+			"    type: str\n" +
+			"    size: 2\n" +
+			"    encoding: ASCII\n" +
+			"types:\n" +
+			"  point:\n" +
+			"    seq:\n" +
+			"      - id: x\n" +
+			"        type: b12le\n" +
+			"      - id: y\n" +
+			"        type: b4le\n" +
+			"enums:\n" +
+			"  mode:\n" +
+			"    0: off\n" +
+			"    0x7f:\n" +
+			"      id: all\n" +
+			"      doc: Every mode.\n"
+
+		expected = "" +
+			"// Code generated by binary ksyimport from sample.ksy; " +
+			"DO NOT EDIT.\n" +
+			"\n" +
+			"package sample\n" +
+			"\n" +
+			"// Mode is enum mode.\n" +
+			"type Mode uint8\n" +
+			"\n" +
+			"const (\n" +
+			"\tModeOff Mode = 0\n" +
+			"\tModeAll Mode = 127\n" +
+			")\n" +
+			"\n" +
+			"// SampleFormat is type sample, of 9 byte(s).\n" +
+			"// Magic should hold 53 4d.\n" +
+			"type SampleFormat struct {\n" +
+			"\tSampleFormatWord0 `word:\"32,order=abdc\"`\n" +
+			"\tSampleFormatWord1 `word:\"8\"`\n" +
+			"\tPoints0           PointFormat `format:\"\"`\n" +
+			"\tPoints1           PointFormat `format:\"\"`\n" +
+			"}\n" +
+			"\n" +
+			"type SampleFormatWord0 struct {\n" +
+			"\tMagic0  uint8  `bitfield:\"8\"`\n" +
+			"\tMagic1  uint8  `bitfield:\"8\"`\n" +
+			"\tVersion uint16 `bitfield:\"16\"`\n" +
+			"}\n" +
+			"\n" +
+			"type SampleFormatWord1 struct {\n" +
+			"\tHasExtra bool `bitfield:\"1\"`\n" +
+			"\tMode     Mode `bitfield:\"7\"`\n" +
+			"}\n" +
+			"\n" +
+			"// SampleExtraFormat follows SampleFormat in type sample if has_extra,\n" +
+			"// and is of 4 byte(s).\n" +
+			"type SampleExtraFormat struct {\n" +
+			"\tSampleExtraFormatWord0 `word:\"32\"`\n" +
+			"}\n" +
+			"\n" +
+			"type SampleExtraFormatWord0 struct {\n" +
+			"\tExtra uint32 `bitfield:\"32\"`\n" +
+			"}\n" +
+			"\n" +
+			"// SampleAfterExtraFormat follows SampleExtraFormat in type sample,\n" +
+			"// and is of 2 byte(s).\n" +
+			"type SampleAfterExtraFormat struct {\n" +
+			"\tSampleAfterExtraFormatWord0 `word:\"16\"`\n" +
+			"}\n" +
+			"\n" +
+			"type SampleAfterExtraFormatWord0 struct {\n" +
+			"\tName0 uint8 `bitfield:\"8\"`\n" +
+			"\tName1 uint8 `bitfield:\"8\"`\n" +
+			"}\n" +
+			"\n" +
+			"// PointFormat is type point, of 2 byte(s).\n" +
+			"type PointFormat struct {\n" +
+			"\tPointFormatWord0 `word:\"16,order=ba\"`\n" +
+			"}\n" +
+			"\n" +
+			"type PointFormatWord0 struct {\n" +
+			"\tY uint8  `bitfield:\"4\"`\n" +
+			"\tX uint16 `bitfield:\"12\"`\n" +
+			"}\n"
+	)
+
+	var (
+		e      error
+		output []byte
+	)
+
+	output, e = generateKSYImport("sample.ksy", []byte(spec), "sample")
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		expected, string(output),
+	)
+}
+
+func TestGenerateKSYImportUnsupported(t *testing.T) {
+	const (
+		meta = "" +
+			"meta:\n" +
+			"  id: sample\n" +
+			"  endian: be\n" +
+			"seq:\n" +
+			"  - id: length\n" +
+			"    type: u4\n"
+	)
+
+	var (
+		e error
+	)
+
+	_, e = generateKSYImport("sample.ksy",
+		[]byte(meta+"  - id: body\n    size: length\n"), "sample",
+	)
+
+	assert.EqualError(t, e,
+		"sample.ksy:8: attribute body: size \"length\" is not "+
+			"a positive integer; only constant sizes are supported",
+	)
+
+	_, e = generateKSYImport("sample.ksy",
+		[]byte(meta+"  - id: values\n    type: u1\n    repeat: eos\n"),
+		"sample",
+	)
+
+	assert.EqualError(t, e,
+		"sample.ksy:9: attribute values: repeat \"eos\" is not supported; "+
+			"only repeat: expr with a constant repeat-expr is",
+	)
+
+	_, e = generateKSYImport("sample.ksy",
+		[]byte(meta+"  - id: body\n    size: 4\n    process: zlib\n"),
+		"sample",
+	)
+
+	assert.EqualError(t, e,
+		"sample.ksy:9: attribute body: process is not supported",
+	)
+
+	_, e = generateKSYImport("sample.ksy",
+		[]byte(meta+"  - id: ratio\n    type: f4\n"), "sample",
+	)
+
+	assert.EqualError(t, e,
+		"sample.ksy:8: type f4 is not supported",
+	)
+
+	_, e = generateKSYImport("sample.ksy",
+		[]byte(meta+"instances:\n  twice:\n    value: length * 2\n"),
+		"sample",
+	)
+
+	assert.EqualError(t, e,
+		"sample.ksy:8: instances of type sample is not supported",
+	)
+
+	_, e = generateKSYImport("sample.ksy",
+		[]byte(meta+"  - id: flag\n    type: b1\n"+
+			"  - id: extra\n    type: b7\n    if: flag\n"),
+		"sample",
+	)
+
+	assert.EqualError(t, e,
+		"sample.ksy:9: attribute extra of type sample does not begin "+
+			"on a byte boundary, as conditional attributes "+
+			"and those following them should",
+	)
+}
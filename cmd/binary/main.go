@@ -0,0 +1,98 @@
+// Command binary provides tools for working with format-structs.
+//
+// Usage:
+//
+//	binary <command> [arguments]
+//
+// The commands are:
+//
+//	cimport     generate format-structs from C struct declarations
+//	decode      decode records with a format-struct loaded from Go source
+//	ksyimport   generate format-structs from Kaitai Struct specs
+//	p4export    generate a P4 header type from a format-struct
+//	p4import    generate format-structs from P4 header types
+//	tags        rewrite bit field tags between implicit and explicit offsets
+//	typescript  generate TypeScript encoding and decoding a format-struct
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+const (
+	usage = "" +
+		"Usage:\n" +
+		"\n" +
+		"\tbinary <command> [arguments]\n" +
+		"\n" +
+		"The commands are:\n" +
+		"\n" +
+		"\tcimport     generate format-structs from C struct declarations\n" +
+		"\tdecode      decode records with a format-struct loaded from Go source\n" +
+		"\tksyimport   generate format-structs from Kaitai Struct specs\n" +
+		"\tp4export    generate a P4 header type from a format-struct\n" +
+		"\tp4import    generate format-structs from P4 header types\n" +
+		"\ttags        rewrite bit field tags between implicit and explicit offsets\n" +
+		"\ttypescript  generate TypeScript encoding and decoding a format-struct\n"
+)
+
+var (
+	errUsage = errors.New("usage")
+)
+
+func main() {
+	var (
+		e error
+	)
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "cimport":
+		e = runCImport(os.Args[2:], os.Stdout)
+
+	case "decode":
+		e = runDecode(os.Args[2:], os.Stdin, os.Stdout)
+
+	case "ksyimport":
+		e = runKSYImport(os.Args[2:], os.Stdout)
+
+	case "p4export":
+		e = runP4Export(os.Args[2:], os.Stdout)
+
+	case "p4import":
+		e = runP4Import(os.Args[2:], os.Stdout)
+
+	case "tags":
+		e = runTags(os.Args[2:], os.Stdout)
+
+	case "typescript":
+		e = runTypeScript(os.Args[2:], os.Stdout)
+
+	default:
+		fmt.Fprintf(os.Stderr, "binary: unknown command %q\n\n", os.Args[1])
+		fmt.Fprint(os.Stderr, usage)
+
+		os.Exit(2)
+	}
+
+	switch {
+	case e == nil:
+		return
+
+	case errors.Is(e, errUsage), errors.Is(e, flag.ErrHelp):
+		os.Exit(2)
+
+	default:
+		fmt.Fprintf(os.Stderr, "binary: %v\n", e)
+
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,981 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/constant"
+	"go/format"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/encodingx/binary"
+	"github.com/encodingx/binary/internal/codecs/metadata"
+)
+
+// P4Import generates format-structs from the header types of a P4 program,
+// and P4Export generates a header type from a format-struct,
+// so that a Go control plane and a P4 data plane may share one definition.
+// P4 headers are big-endian and packed to the bit:
+// their fields are divided into words of up to 64 bits
+// at the furthest byte boundary, fields longer than 64 bits being split.
+// Other declarations of a P4 program are skipped.
+
+const (
+	p4Indent          = "    "
+	p4FormatSuffix    = "Format"
+	p4TypeSuffix      = "_t"
+	p4WordLengthLimit = 64
+)
+
+type p4Field struct {
+	name   string
+	offset uint
+	length int
+	bool   bool
+}
+
+type p4Header struct {
+	name   string
+	fields []p4Field
+}
+
+type p4Word struct {
+	fields []p4Field
+	length int
+}
+
+type p4Parser struct {
+	cimportParser
+	widths  map[string]p4Field
+	headers []p4Header
+}
+
+func runP4Import(args []string, stdout io.Writer) (e error) {
+	const (
+		usage = "" +
+			"Usage: binary p4import [-package name] [-o file] program.p4\n" +
+			"\n" +
+			"P4Import generates Go format-structs and word-structs\n" +
+			"from the header types of a P4 program, and Go constants\n" +
+			"from its integer constants.\n" +
+			"\n"
+	)
+
+	var (
+		filename    string
+		flags       *flag.FlagSet
+		output      []byte
+		packageName string
+		source      []byte
+	)
+
+	flags = flag.NewFlagSet("p4import", flag.ContinueOnError)
+
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+
+		flags.PrintDefaults()
+	}
+
+	flags.StringVar(&packageName, "package", "",
+		"name of the Go package, by default derived from the program",
+	)
+
+	flags.StringVar(&filename, "o", "",
+		"file to write instead of standard output",
+	)
+
+	e = flags.Parse(args)
+	if e != nil {
+		return
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+
+		e = errUsage
+
+		return
+	}
+
+	source, e = os.ReadFile(flags.Arg(0))
+	if e != nil {
+		return
+	}
+
+	if packageName == "" {
+		packageName = cimportPackageName(flags.Arg(0))
+	}
+
+	output, e = generateP4Import(flags.Arg(0), source, packageName)
+	if e != nil {
+		return
+	}
+
+	if filename != "" {
+		e = os.WriteFile(filename, output, 0644)
+
+		return
+	}
+
+	_, e = stdout.Write(output)
+
+	return
+}
+
+func runP4Export(args []string, stdout io.Writer) (e error) {
+	const (
+		usage = "" +
+			"Usage: binary p4export -type importpath.Type [-C dir] " +
+			"[-name header_t] [-o file]\n" +
+			"\n" +
+			"P4Export loads a format-struct from the Go source of a module\n" +
+			"and generates the equivalent P4 header type.\n" +
+			"\n"
+	)
+
+	var (
+		directory  string
+		filename   string
+		flags      *flag.FlagSet
+		name       string
+		output     []byte
+		reflection reflect.Type
+		typeName   string
+	)
+
+	flags = flag.NewFlagSet("p4export", flag.ContinueOnError)
+
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+
+		flags.PrintDefaults()
+	}
+
+	flags.StringVar(&typeName, "type", "",
+		"import path and name of the format-struct "+
+			"(e.g. \"github.com/our/pkg.HeaderFormat\")",
+	)
+
+	flags.StringVar(&directory, "C", ".",
+		"root directory of the module holding the format-struct",
+	)
+
+	flags.StringVar(&name, "name", "",
+		"name of the P4 header type, by default derived from the Go type "+
+			"(e.g. \"header_t\" from \"HeaderFormat\")",
+	)
+
+	flags.StringVar(&filename, "o", "",
+		"file to write instead of standard output",
+	)
+
+	e = flags.Parse(args)
+	if e != nil {
+		return
+	}
+
+	if typeName == "" || flags.NArg() > 0 {
+		flags.Usage()
+
+		e = errUsage
+
+		return
+	}
+
+	reflection, e = decodeLoadType(directory, typeName)
+	if e != nil {
+		return
+	}
+
+	if name == "" {
+		name = p4HeaderName(typeName[strings.LastIndex(typeName, ".")+1:])
+	}
+
+	output, e = generateP4Export(name, typeName, reflection)
+	if e != nil {
+		return
+	}
+
+	if filename != "" {
+		e = os.WriteFile(filename, output, 0644)
+
+		return
+	}
+
+	_, e = stdout.Write(output)
+
+	return
+}
+
+// generateP4Import parses the header types of a P4 program
+// and returns the formatted Go source of their format-structs.
+func generateP4Import(filename string, source []byte, packageName string) (
+	output []byte, e error,
+) {
+	var (
+		buffer     bytes.Buffer
+		definition cimportConstant
+		header     p4Header
+		p          *p4Parser
+	)
+
+	p = &p4Parser{
+		cimportParser: cimportParser{
+			filename: filename,
+			values:   make(map[string]constant.Value),
+		},
+		widths: make(map[string]p4Field),
+	}
+
+	e = p.parse(source)
+	if e != nil {
+		return
+	}
+
+	fmt.Fprintf(&buffer,
+		"// Code generated by binary p4import from %s; DO NOT EDIT.\n\n"+
+			"package %s\n",
+		filepath.Base(filename), packageName,
+	)
+
+	if len(p.constants) > 0 {
+		fmt.Fprintf(&buffer, "\nconst (\n")
+
+		for _, definition = range p.constants {
+			fmt.Fprintf(&buffer, "%s = %s\n", definition.name, definition.value)
+		}
+
+		fmt.Fprintf(&buffer, ")\n")
+	}
+
+	for _, header = range p.headers {
+		e = header.write(&buffer)
+		if e != nil {
+			return
+		}
+	}
+
+	output, e = format.Source(buffer.Bytes())
+	if e != nil {
+		return
+	}
+
+	return
+}
+
+// parse tokenizes a P4 program, evaluating "#define" directives,
+// and parses its declarations.
+func (p *p4Parser) parse(source []byte) (e error) {
+	var (
+		i     int
+		line  string
+		lines []string
+	)
+
+	lines = strings.Split(string(cimportStripComments(source)), "\n")
+
+	for i, line = range lines {
+		line = strings.TrimSpace(line)
+
+		if strings.HasPrefix(line, "#") {
+			p.parseP4Directive(line, i+1)
+
+			continue
+		}
+
+		p.tokens = append(p.tokens, cimportTokenize(line, i+1)...)
+	}
+
+	for p.position < len(p.tokens) {
+		e = p.parseDeclaration()
+		if e != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// parseP4Directive evaluates "#define" directives of integer constants.
+// Other directives and macros are not needed for header types.
+func (p *p4Parser) parseP4Directive(line string, number int) {
+	var (
+		fields []string
+	)
+
+	fields = strings.Fields(
+		strings.TrimSpace(strings.TrimPrefix(line, "#")),
+	)
+
+	if len(fields) < 3 || fields[0] != "define" ||
+		strings.Contains(fields[1], "(") {
+		return
+	}
+
+	_ = p.parseDirective(line, number)
+}
+
+// parseDeclaration parses a header type, a typedef or a constant,
+// and skips any other declaration.
+func (p *p4Parser) parseDeclaration() (e error) {
+	var (
+		field  p4Field
+		line   int
+		name   string
+		tokens []string
+		value  constant.Value
+	)
+
+	p.skipAnnotations()
+
+	line = p.line()
+
+	switch p.peek() {
+	case "header":
+		p.next()
+
+		e = p.parseHeader()
+
+	case "typedef", "type":
+		p.next()
+
+		field, e = p.parseType()
+		if e != nil {
+			// Other types are not needed for headers.
+
+			e = nil
+
+			p.skipDeclaration()
+
+			return
+		}
+
+		name = p.next()
+
+		if !cimportIsIdentifier(name) {
+			e = p.errorf(line, "malformed typedef")
+
+			return
+		}
+
+		p.widths[name] = field
+
+		e = p.expect(";")
+
+	case "const":
+		p.next()
+
+		_, e = p.parseType()
+		if e != nil {
+			// Constants of other types are not needed for headers.
+
+			e = nil
+
+			p.skipDeclaration()
+
+			return
+		}
+
+		name = p.next()
+
+		e = p.expect("=")
+		if e != nil {
+			return
+		}
+
+		for p.peek() != ";" && p.peek() != "" {
+			tokens = append(tokens, p4Literal(p.next()))
+		}
+
+		e = p.expect(";")
+		if e != nil {
+			return
+		}
+
+		value, e = p.evaluate(strings.Join(tokens, " "))
+		if e != nil {
+			e = p.errorf(line, "constant %s: %v", name, e)
+
+			return
+		}
+
+		p.values[name] = value
+
+		p.constants = append(p.constants,
+			cimportConstant{
+				name:  name,
+				value: value.ExactString(),
+			},
+		)
+
+	default:
+		p.skipDeclaration()
+	}
+
+	return
+}
+
+// p4Literal strips the width and signedness of an integer literal,
+// as in "16w0x800".
+func p4Literal(text string) string {
+	var (
+		i int
+	)
+
+	if len(text) == 0 || !unicode.IsDigit(rune(text[0])) {
+		return text
+	}
+
+	i = strings.IndexAny(text, "ws")
+	if i > 0 && !strings.HasPrefix(text, "0x") &&
+		!strings.HasPrefix(text, "0X") {
+		return text[i+1:]
+	}
+
+	return text
+}
+
+func (p *p4Parser) skipAnnotations() {
+	var (
+		depth int
+	)
+
+	for p.peek() == "@" {
+		p.next()
+		p.next()
+
+		if p.peek() != "(" {
+			continue
+		}
+
+		for depth = 0; p.peek() != ""; {
+			switch p.next() {
+			case "(":
+				depth++
+
+			case ")":
+				depth--
+			}
+
+			if depth == 0 {
+				break
+			}
+		}
+	}
+}
+
+// skipDeclaration skips tokens to a semicolon or closing brace
+// at the top level.
+func (p *p4Parser) skipDeclaration() {
+	var (
+		depth int
+	)
+
+	for p.peek() != "" {
+		switch p.next() {
+		case "{", "(":
+			depth++
+
+		case ")":
+			depth--
+
+		case "}":
+			depth--
+
+			if depth == 0 {
+				if p.peek() == ";" {
+					p.next()
+				}
+
+				return
+			}
+
+		case ";":
+			if depth == 0 {
+				return
+			}
+		}
+	}
+}
+
+// parseType parses "bit<W>", "int<W>", "bool" or the name of a typedef.
+func (p *p4Parser) parseType() (field p4Field, e error) {
+	var (
+		line   int
+		ok     bool
+		tokens []string
+		value  constant.Value
+		width  uint64
+		exact  bool
+	)
+
+	line = p.line()
+
+	switch p.peek() {
+	case "bool":
+		p.next()
+
+		field = p4Field{
+			length: 1,
+			bool:   true,
+		}
+
+		return
+
+	case "bit", "int":
+		p.next()
+
+		e = p.expect("<")
+		if e != nil {
+			return
+		}
+
+		for p.peek() != ">" && p.peek() != "" {
+			tokens = append(tokens, p.next())
+		}
+
+		e = p.expect(">")
+		if e != nil {
+			return
+		}
+
+		value, e = p.evaluate(strings.Join(tokens, " "))
+		if e != nil {
+			e = p.errorf(line, "width: %v", e)
+
+			return
+		}
+
+		width, exact = constant.Uint64Val(value)
+		if !exact || width == 0 {
+			e = p.errorf(line, "invalid width %s", value)
+
+			return
+		}
+
+		field.length = int(width)
+
+		return
+
+	case "varbit":
+		e = p.errorf(line, "varbit fields are not supported")
+
+		return
+	}
+
+	field, ok = p.widths[p.peek()]
+	if !ok {
+		e = p.errorf(line, "unsupported type %q", p.peek())
+
+		return
+	}
+
+	p.next()
+
+	return
+}
+
+func (p *p4Parser) parseHeader() (e error) {
+	var (
+		field  p4Field
+		header p4Header
+		line   int
+	)
+
+	line = p.line()
+
+	header.name = p.next()
+
+	if !cimportIsIdentifier(header.name) {
+		e = p.errorf(line, "malformed header type")
+
+		return
+	}
+
+	e = p.expect("{")
+	if e != nil {
+		return
+	}
+
+	for p.peek() != "}" {
+		if p.peek() == "" {
+			e = p.errorf(line, "header %s is not terminated", header.name)
+
+			return
+		}
+
+		p.skipAnnotations()
+
+		field, e = p.parseType()
+		if e != nil {
+			return
+		}
+
+		field.name = p.next()
+
+		if !cimportIsIdentifier(field.name) {
+			e = p.errorf(p.line(), "malformed field of header %s",
+				header.name,
+			)
+
+			return
+		}
+
+		e = p.expect(";")
+		if e != nil {
+			return
+		}
+
+		header.fields = append(header.fields, field)
+	}
+
+	p.next()
+
+	if p.peek() == ";" {
+		p.next()
+	}
+
+	p.headers = append(p.headers, header)
+
+	return
+}
+
+// words divides the fields of a header into words of up to 64 bits,
+// each ending at the furthest byte boundary.
+func (h p4Header) words() (words []p4Word, e error) {
+	var (
+		cut    int
+		field  p4Field
+		i      int
+		j      int
+		length int
+		part   int
+	)
+
+	for i = 0; i < len(h.fields); i = cut {
+		field = h.fields[i]
+
+		if field.length > p4WordLengthLimit {
+			if field.length%8 != 0 {
+				e = fmt.Errorf("header %s: field %s of %d bits "+
+					"does not divide into words of up to %d bits",
+					h.name, field.name, field.length, p4WordLengthLimit,
+				)
+
+				return
+			}
+
+			for part = 0; part*p4WordLengthLimit < field.length; part++ {
+				length = field.length - part*p4WordLengthLimit
+
+				if length > p4WordLengthLimit {
+					length = p4WordLengthLimit
+				}
+
+				words = append(words,
+					p4Word{
+						fields: []p4Field{
+							{
+								name:   fmt.Sprintf("%s%d", field.name, part),
+								length: length,
+							},
+						},
+						length: length,
+					},
+				)
+			}
+
+			cut = i + 1
+
+			continue
+		}
+
+		cut = 0
+		length = 0
+
+		for j = i; j < len(h.fields); j++ {
+			length += h.fields[j].length
+
+			if length > p4WordLengthLimit {
+				break
+			}
+
+			if length%8 == 0 {
+				cut = j + 1
+			}
+		}
+
+		if cut == 0 {
+			e = fmt.Errorf("header %s: fields from %s "+
+				"do not divide into words of up to %d bits",
+				h.name, field.name, p4WordLengthLimit,
+			)
+
+			return
+		}
+
+		words = append(words,
+			p4Word{
+				fields: h.fields[i:cut],
+			},
+		)
+
+		for _, field = range h.fields[i:cut] {
+			words[len(words)-1].length += field.length
+		}
+	}
+
+	return
+}
+
+func (h p4Header) write(buffer *bytes.Buffer) (e error) {
+	var (
+		field  p4Field
+		i      int
+		length int
+		name   string
+		word   p4Word
+		words  []p4Word
+	)
+
+	words, e = h.words()
+	if e != nil {
+		return
+	}
+
+	name = p4GoName(h.name)
+
+	for _, word = range words {
+		length += word.length
+	}
+
+	fmt.Fprintf(buffer,
+		"\n// %s is header %s, of %d byte(s).\ntype %s struct {\n",
+		name, h.name, length/8, name,
+	)
+
+	for i, word = range words {
+		fmt.Fprintf(buffer, "%sWord%d `word:\"%d\"`\n", name, i, word.length)
+	}
+
+	fmt.Fprintf(buffer, "}\n")
+
+	for i, word = range words {
+		fmt.Fprintf(buffer, "\ntype %sWord%d struct {\n", name, i)
+
+		for _, field = range word.fields {
+			if field.bool {
+				fmt.Fprintf(buffer, "%s bool `bitfield:\"1\"`\n",
+					cimportGoName(field.name),
+				)
+
+				continue
+			}
+
+			fmt.Fprintf(buffer, "%s %s `bitfield:\"%d\"`\n",
+				cimportGoName(field.name), cimportGoType(field.length),
+				field.length,
+			)
+		}
+
+		fmt.Fprintf(buffer, "}\n")
+	}
+
+	return
+}
+
+// p4GoName converts the name of a header type to that of a format-struct,
+// as "ethernet_t" to "EthernetFormat".
+func p4GoName(name string) string {
+	return cimportGoName(name) + p4FormatSuffix
+}
+
+// p4HeaderName converts the name of a format-struct
+// to that of a header type, as "EthernetFormat" to "ethernet_t".
+func p4HeaderName(name string) string {
+	return p4SnakeCase(
+		strings.TrimSuffix(name, p4FormatSuffix),
+	) + p4TypeSuffix
+}
+
+func p4SnakeCase(name string) string {
+	var (
+		builder strings.Builder
+		i       int
+		r       rune
+		runes   []rune
+	)
+
+	runes = []rune(name)
+
+	for i, r = range runes {
+		if i > 0 && unicode.IsUpper(r) && (unicode.IsLower(runes[i-1]) ||
+			i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			builder.WriteRune('_')
+		}
+
+		builder.WriteRune(unicode.ToLower(r))
+	}
+
+	return builder.String()
+}
+
+// p4FieldName converts the name of a bit field to that of a P4 field,
+// as "SrcAddr" to "srcAddr" and "IHL" to "ihl".
+func p4FieldName(name string) string {
+	var (
+		i     int
+		runes []rune
+	)
+
+	runes = []rune(name)
+
+	for i = 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+
+		runes[i] = unicode.ToLower(runes[i])
+	}
+
+	return string(runes)
+}
+
+// generateP4Export returns a P4 header type declaring the bit fields
+// of a format-struct, including those of nested formats, in order.
+func generateP4Export(name, typeName string, reflection reflect.Type) (
+	output []byte, e error,
+) {
+	var (
+		buffer   bytes.Buffer
+		field    p4Field
+		fields   []p4Field
+		format   metadata.FormatMetadata
+		i        int
+		offset   int
+		padding  int
+		uuid     metadata.UUIDLayout
+		word     metadata.WordLayout
+		wordType reflect.Type
+	)
+
+	// Marshal validates the format-struct, and errors name the function.
+
+	_, e = binary.Marshal(
+		reflect.New(reflection).Interface(),
+	)
+	if e != nil {
+		return
+	}
+
+	format, e = metadata.NewFormatMetadataFromTypeReflection(reflection)
+	if e != nil {
+		return
+	}
+
+	for _, uuid = range format.UUIDs() {
+		e = fmt.Errorf("field %s is a UUID; UUID fields are not supported",
+			reflection.FieldByIndex(uuid.Index).Name,
+		)
+
+		return
+	}
+
+	fmt.Fprintf(&buffer, "// Generated by binary p4export from %s.\n\n",
+		typeName,
+	)
+
+	fmt.Fprintf(&buffer, "header %s {\n", name)
+
+	for _, word = range format.Words() {
+		wordType = reflection.FieldByIndex(word.Index).Type
+
+		if word.Order != nil {
+			e = fmt.Errorf("word %s has a byte order; "+
+				"P4 headers are big-endian",
+				wordType.Name(),
+			)
+
+			return
+		}
+
+		if word.Offset > offset {
+			fmt.Fprintf(&buffer, "%sbit<%d> padding%d;\n",
+				p4Indent, 8*(word.Offset-offset), padding,
+			)
+
+			padding++
+		}
+
+		fields = fields[:0]
+
+		for i = 0; i < len(word.BitFields); i++ {
+			if word.BitFields[i].Order != nil {
+				e = fmt.Errorf("bit field %s.%s has a byte order; "+
+					"P4 headers are big-endian",
+					wordType.Name(), wordType.Field(i).Name,
+				)
+
+				return
+			}
+
+			fields = append(fields,
+				p4Field{
+					name:   p4FieldName(wordType.Field(i).Name),
+					offset: word.BitFields[i].Offset,
+					length: int(word.BitFields[i].Length),
+					bool:   wordType.Field(i).Type.Kind() == reflect.Bool,
+				},
+			)
+		}
+
+		// Bit fields with explicit offsets may be declared in any order.
+
+		sort.SliceStable(fields,
+			func(i, j int) bool {
+				return fields[i].offset > fields[j].offset
+			},
+		)
+
+		for _, field = range fields {
+			if field.bool {
+				fmt.Fprintf(&buffer, "%sbool %s;\n", p4Indent, field.name)
+
+				continue
+			}
+
+			fmt.Fprintf(&buffer, "%sbit<%d> %s;\n",
+				p4Indent, field.length, field.name,
+			)
+		}
+
+		offset = word.Offset + word.Length
+	}
+
+	if format.LengthInBytes() > offset {
+		fmt.Fprintf(&buffer, "%sbit<%d> padding%d;\n",
+			p4Indent, 8*(format.LengthInBytes()-offset), padding,
+		)
+	}
+
+	fmt.Fprintf(&buffer, "}\n")
+
+	if reflection.NumField() > 0 &&
+		reflection.Field(reflection.NumField()-1).Type == decodePayloadType {
+		fmt.Fprintf(&buffer,
+			"\n// The payload %s follows the header.\n",
+			reflection.Field(reflection.NumField()-1).Name,
+		)
+	}
+
+	output = buffer.Bytes()
+
+	return
+}
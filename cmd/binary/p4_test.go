@@ -0,0 +1,207 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/encodingx/binary"
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	p4TestFormat struct {
+		P4TestFormatWord0 `word:"16"`
+		P4TestFormatWord1 `word:"8"`
+		P4TestFormatWord2 `word:"32,order=dcba"`
+	}
+
+	P4TestFormatWord0 struct {
+		Version uint8  `bitfield:"4"`
+		IHL     uint8  `bitfield:"4"`
+		DstPort uint16 `bitfield:"8"`
+	}
+
+	P4TestFormatWord1 struct {
+		Urgent bool  `bitfield:"1"`
+		Rest   uint8 `bitfield:"7"`
+	}
+
+	P4TestFormatWord2 struct {
+		Value uint32 `bitfield:"32"`
+	}
+
+	p4TestFormatWithSection struct {
+		P4TestFormatWord1 `word:"8"`
+		Section           p4TestSection `format:"align=4,padding=zero"`
+	}
+
+	p4TestSection struct {
+		P4TestFormatWord0 `word:"16"`
+	}
+
+	p4TestFormatWithUUID struct {
+		P4TestFormatWord1 `word:"8"`
+		ID                binary.UUID
+	}
+)
+
+func TestGenerateP4Import(t *testing.T) {
+	const (
+		program = "" +
+			"#include <core.p4>\n" +
+			"#define MAX_HOPS 9\n" +
+			"const bit<16> TYPE_IPV4 = 16w0x800;\n" +
+			"typedef bit<48> macAddr_t;\n" +
+			"\n" +
+			"header ethernet_t {\n" +
+			"    macAddr_t dstAddr;\n" +
+			"    macAddr_t srcAddr;\n" +
+			"    bit<16>   etherType;\n" +
+			"}\n" +
+			"\n" +
+			"@name(\"flags\")\n" +
+			"header flags_t {\n" +
+			"    bool   urgent;\n" +
+			"    bit<7> rest;\n" +
+			"    bit<128> address; // split into words\n" +
+			"}\n" +
+			"\n" +
+			"struct headers_t {\n" +
+			"    ethernet_t ethernet;\n" +
+			"}\n" +
+			"\n" +
+			"parser MyParser(packet_in packet, out headers_t hdr) {\n" +
+			"    state start { packet.extract(hdr.ethernet); " +
+			"transition accept; }\n" +
+			"}\n"
+
+		expected = "" +
+			"// Code generated by binary p4import from headers.p4; " +
+			"DO NOT EDIT.\n" +
+			"\n" +
+			"package headers\n" +
+			"\n" +
+			"const (\n" +
+			"\tMAX_HOPS  = 9\n" +
+			"\tTYPE_IPV4 = 2048\n" +
+			")\n" +
+			"\n" +
+			"// EthernetFormat is header ethernet_t, of 14 byte(s).\n" +
+			"type EthernetFormat struct {\n" +
+			"\tEthernetFormatWord0 `word:\"48\"`\n" +
+			"\tEthernetFormatWord1 `word:\"64\"`\n" +
+			"}\n" +
+			"\n" +
+			"type EthernetFormatWord0 struct {\n" +
+			"\tDstAddr uint64 `bitfield:\"48\"`\n" +
+			"}\n" +
+			"\n" +
+			"type EthernetFormatWord1 struct {\n" +
+			"\tSrcAddr   uint64 `bitfield:\"48\"`\n" +
+			"\tEtherType uint16 `bitfield:\"16\"`\n" +
+			"}\n" +
+			"\n" +
+			"// FlagsFormat is header flags_t, of 17 byte(s).\n" +
+			"type FlagsFormat struct {\n" +
+			"\tFlagsFormatWord0 `word:\"8\"`\n" +
+			"\tFlagsFormatWord1 `word:\"64\"`\n" +
+			"\tFlagsFormatWord2 `word:\"64\"`\n" +
+			"}\n" +
+			"\n" +
+			"type FlagsFormatWord0 struct {\n" +
+			"\tUrgent bool  `bitfield:\"1\"`\n" +
+			"\tRest   uint8 `bitfield:\"7\"`\n" +
+			"}\n" +
+			"\n" +
+			"type FlagsFormatWord1 struct {\n" +
+			"\tAddress0 uint64 `bitfield:\"64\"`\n" +
+			"}\n" +
+			"\n" +
+			"type FlagsFormatWord2 struct {\n" +
+			"\tAddress1 uint64 `bitfield:\"64\"`\n" +
+			"}\n"
+	)
+
+	var (
+		e      error
+		output []byte
+	)
+
+	output, e = generateP4Import("headers.p4", []byte(program), "headers")
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		expected, string(output),
+	)
+
+	_, e = generateP4Import("headers.p4",
+		[]byte("header h_t {\n    bit<3> a;\n    bit<63> b;\n}\n"), "headers",
+	)
+
+	assert.EqualError(t, e,
+		"header h_t: fields from a do not divide into words of up to 64 bits",
+	)
+
+	_, e = generateP4Import("headers.p4",
+		[]byte("header h_t {\n    varbit<320> options;\n}\n"), "headers",
+	)
+
+	assert.EqualError(t, e,
+		"headers.p4:2: varbit fields are not supported",
+	)
+}
+
+func TestGenerateP4Export(t *testing.T) {
+	const (
+		expected = "" +
+			"// Generated by binary p4export from " +
+			"example.com/p4.TestFormatWithSection.\n" +
+			"\n" +
+			"header test_format_with_section_t {\n" +
+			"    bool urgent;\n" +
+			"    bit<7> rest;\n" +
+			"    bit<24> padding0;\n" +
+			"    bit<4> version;\n" +
+			"    bit<4> ihl;\n" +
+			"    bit<8> dstPort;\n" +
+			"}\n"
+	)
+
+	var (
+		e      error
+		output []byte
+	)
+
+	assert.Equal(t,
+		"test_format_with_section_t",
+		p4HeaderName("TestFormatWithSection"),
+	)
+
+	output, e = generateP4Export("test_format_with_section_t",
+		"example.com/p4.TestFormatWithSection",
+		reflect.TypeOf(p4TestFormatWithSection{}),
+	)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		expected, string(output),
+	)
+
+	_, e = generateP4Export("test_t", "example.com/p4.TestFormat",
+		reflect.TypeOf(p4TestFormat{}),
+	)
+
+	assert.EqualError(t, e,
+		"word P4TestFormatWord2 has a byte order; P4 headers are big-endian",
+	)
+
+	_, e = generateP4Export("test_t", "example.com/p4.TestFormatWithUUID",
+		reflect.TypeOf(p4TestFormatWithUUID{}),
+	)
+
+	assert.EqualError(t, e,
+		"field ID is a UUID; UUID fields are not supported",
+	)
+}
@@ -0,0 +1,582 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Bit field tags come in two styles.
+// In the implicit style, a tag holds the length of a bit field alone,
+// and its offset follows from the order in which bit fields are declared:
+//
+//	Version uint8 `bitfield:"4"`
+//
+// In the explicit style of v1.1, a tag also holds the offset of the bit field
+// from the least significant bit of its word:
+//
+//	Version uint8 `bitfield:"4,28"`
+//
+// The length of a word is the sum of the lengths of its bit fields.
+
+const (
+	tagsStyleExplicit = "explicit"
+	tagsStyleImplicit = "implicit"
+
+	tagsBitFieldKey = "bitfield"
+)
+
+type tagsBitField struct {
+	name     string
+	length   uint
+	offset   uint
+	explicit bool
+	options  []string
+	tag      *ast.BasicLit
+}
+
+type tagsWord struct {
+	position  token.Position
+	bitFields []tagsBitField
+}
+
+func runTags(args []string, stdout io.Writer) (e error) {
+	const (
+		usage = "" +
+			"Usage: binary tags [-style explicit|implicit] [-l] [-w] " +
+			"path ...\n" +
+			"\n" +
+			"Tags rewrites the bit field tags of word-structs in Go files,\n" +
+			"adding or removing offsets, and verifies that the layout\n" +
+			"of every word is unchanged. Directories are processed\n" +
+			"without recursion. By default, rewritten files are printed\n" +
+			"to standard output.\n" +
+			"\n"
+	)
+
+	var (
+		filename  string
+		filenames []string
+		flags     *flag.FlagSet
+		list      bool
+		output    []byte
+		source    []byte
+		style     string
+		write     bool
+	)
+
+	flags = flag.NewFlagSet("tags", flag.ContinueOnError)
+
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+
+		flags.PrintDefaults()
+	}
+
+	flags.StringVar(&style, "style", tagsStyleExplicit,
+		"style of tags to rewrite into, \"explicit\" or \"implicit\"",
+	)
+
+	flags.BoolVar(&list, "l", false,
+		"list files whose tags would change instead of printing them",
+	)
+
+	flags.BoolVar(&write, "w", false,
+		"write rewritten files in place instead of printing them",
+	)
+
+	e = flags.Parse(args)
+	if e != nil {
+		return
+	}
+
+	if style != tagsStyleExplicit && style != tagsStyleImplicit ||
+		flags.NArg() == 0 {
+		flags.Usage()
+
+		e = errUsage
+
+		return
+	}
+
+	filenames, e = tagsExpandPaths(flags.Args())
+	if e != nil {
+		return
+	}
+
+	for _, filename = range filenames {
+		source, e = os.ReadFile(filename)
+		if e != nil {
+			return
+		}
+
+		output, e = rewriteTags(filename, source, style)
+		if e != nil {
+			return
+		}
+
+		switch {
+		case list:
+			if !bytes.Equal(source, output) {
+				fmt.Fprintln(stdout, filename)
+			}
+
+		case write:
+			if bytes.Equal(source, output) {
+				continue
+			}
+
+			e = os.WriteFile(filename, output, 0644)
+			if e != nil {
+				return
+			}
+
+		default:
+			_, e = stdout.Write(output)
+			if e != nil {
+				return
+			}
+		}
+	}
+
+	return
+}
+
+func tagsExpandPaths(paths []string) (filenames []string, e error) {
+	var (
+		info    os.FileInfo
+		matches []string
+		path    string
+	)
+
+	for _, path = range paths {
+		info, e = os.Stat(path)
+		if e != nil {
+			return
+		}
+
+		if !info.IsDir() {
+			filenames = append(filenames, path)
+
+			continue
+		}
+
+		matches, e = filepath.Glob(
+			filepath.Join(path, "*.go"),
+		)
+		if e != nil {
+			return
+		}
+
+		filenames = append(filenames, matches...)
+	}
+
+	return
+}
+
+// rewriteTags rewrites the bit field tags of all word-structs in a file
+// into a style, returning the formatted source.
+func rewriteTags(filename string, source []byte, style string) (
+	output []byte, e error,
+) {
+	type edit struct {
+		start int
+		end   int
+		text  string
+	}
+
+	var (
+		bitField tagsBitField
+		edits    []edit
+		fileSet  *token.FileSet
+		i        int
+		text     string
+		word     tagsWord
+		words    []tagsWord
+		words1   []tagsWord
+	)
+
+	fileSet = token.NewFileSet()
+
+	words, e = tagsParseWords(fileSet, filename, source)
+	if e != nil {
+		return
+	}
+
+	for _, word = range words {
+		for _, bitField = range word.bitFields {
+			text, e = tagsRewriteTag(bitField, style)
+			if e != nil {
+				return
+			}
+
+			edits = append(edits,
+				edit{
+					start: fileSet.Position(bitField.tag.Pos()).Offset,
+					end:   fileSet.Position(bitField.tag.End()).Offset,
+					text:  text,
+				},
+			)
+		}
+	}
+
+	sort.Slice(edits,
+		func(i, j int) bool {
+			return edits[i].start > edits[j].start
+		},
+	)
+
+	output = append([]byte(nil), source...)
+
+	for i = range edits {
+		output = append(output[:edits[i].start],
+			append([]byte(edits[i].text), output[edits[i].end:]...)...,
+		)
+	}
+
+	output, e = format.Source(output)
+	if e != nil {
+		return
+	}
+
+	// Verify that the layout of every word is unchanged.
+
+	words1, e = tagsParseWords(token.NewFileSet(), filename, output)
+	if e != nil {
+		return
+	}
+
+	e = tagsCompareLayouts(words, words1)
+	if e != nil {
+		return
+	}
+
+	return
+}
+
+// tagsParseWords finds the word-structs in a file,
+// being structs with at least one field tagged with a bit field,
+// and verifies that any explicit offsets match the declaration order.
+func tagsParseWords(fileSet *token.FileSet, filename string, source []byte) (
+	words []tagsWord, e error,
+) {
+	var (
+		file *ast.File
+	)
+
+	file, e = parser.ParseFile(fileSet, filename, source, parser.ParseComments)
+	if e != nil {
+		return
+	}
+
+	ast.Inspect(file,
+		func(node ast.Node) bool {
+			var (
+				structType *ast.StructType
+				ok         bool
+				word       tagsWord
+			)
+
+			if e != nil {
+				return false
+			}
+
+			structType, ok = node.(*ast.StructType)
+			if !ok {
+				return true
+			}
+
+			word, ok, e = tagsParseWord(fileSet, structType)
+			if e != nil || !ok {
+				return e == nil
+			}
+
+			words = append(words, word)
+
+			return true
+		},
+	)
+
+	return
+}
+
+func tagsParseWord(fileSet *token.FileSet, structType *ast.StructType) (
+	word tagsWord, ok bool, e error,
+) {
+	var (
+		bitField tagsBitField
+		field    *ast.Field
+		i        int
+		length   uint
+		tag      string
+		value    string
+	)
+
+	word.position = fileSet.Position(structType.Pos())
+
+	for _, field = range structType.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+
+		tag, e = strconv.Unquote(field.Tag.Value)
+		if e != nil {
+			return
+		}
+
+		value, ok = reflect.StructTag(tag).Lookup(tagsBitFieldKey)
+		if !ok {
+			continue
+		}
+
+		if len(field.Names) != 1 {
+			e = fmt.Errorf(
+				"%s: a bit field should be declared with exactly one name",
+				fileSet.Position(field.Pos()),
+			)
+
+			return
+		}
+
+		bitField = tagsBitField{
+			name: field.Names[0].Name,
+			tag:  field.Tag,
+		}
+
+		e = tagsParseValue(value, &bitField)
+		if e != nil {
+			e = fmt.Errorf("%s: bit field %s: %w",
+				fileSet.Position(field.Pos()), bitField.name, e,
+			)
+
+			return
+		}
+
+		word.bitFields = append(word.bitFields, bitField)
+	}
+
+	ok = len(word.bitFields) > 0
+	if !ok {
+		return
+	}
+
+	for i = range word.bitFields {
+		length += word.bitFields[i].length
+	}
+
+	for i = range word.bitFields {
+		length -= word.bitFields[i].length
+
+		if word.bitFields[i].explicit && word.bitFields[i].offset != length {
+			e = fmt.Errorf(
+				"%s: bit field %s has offset %d "+
+					"but its position in the word implies offset %d",
+				word.position, word.bitFields[i].name,
+				word.bitFields[i].offset, length,
+			)
+
+			return
+		}
+
+		word.bitFields[i].offset = length
+	}
+
+	return
+}
+
+// tagsParseValue parses the length and any offset of a bit field,
+// keeping the options that follow them (e.g. "order=cdab") as they are.
+func tagsParseValue(value string, bitField *tagsBitField) (e error) {
+	var (
+		fields []string
+		parsed uint64
+	)
+
+	fields = strings.Split(value, ",")
+
+	parsed, e = strconv.ParseUint(fields[0], 10, 8)
+	if e != nil {
+		e = fmt.Errorf("malformed length in tag value %q", value)
+
+		return
+	}
+
+	bitField.length = uint(parsed)
+
+	fields = fields[1:]
+
+	if len(fields) > 0 && !strings.Contains(fields[0], "=") {
+		parsed, e = strconv.ParseUint(fields[0], 10, 8)
+		if e != nil {
+			e = fmt.Errorf("malformed offset in tag value %q", value)
+
+			return
+		}
+
+		bitField.offset = uint(parsed)
+		bitField.explicit = true
+
+		fields = fields[1:]
+	}
+
+	for _, value = range fields {
+		if !strings.Contains(value, "=") {
+			e = fmt.Errorf("malformed option %q in tag value", value)
+
+			return
+		}
+	}
+
+	bitField.options = fields
+
+	return
+}
+
+// tagsRewriteTag returns the literal of a tag with its bit field value
+// in a style, preserving the other keys of the tag.
+func tagsRewriteTag(bitField tagsBitField, style string) (
+	literal string, e error,
+) {
+	const (
+		keyPrefix = tagsBitFieldKey + ":\""
+	)
+
+	var (
+		end   int
+		start int
+		tag   string
+		value string
+	)
+
+	tag, e = strconv.Unquote(bitField.tag.Value)
+	if e != nil {
+		return
+	}
+
+	value = strconv.FormatUint(uint64(bitField.length), 10)
+
+	if style == tagsStyleExplicit {
+		value += "," + strconv.FormatUint(uint64(bitField.offset), 10)
+	}
+
+	if len(bitField.options) > 0 {
+		value += "," + strings.Join(bitField.options, ",")
+	}
+
+	start = tagsIndexKey(tag, tagsBitFieldKey)
+	if start < 0 {
+		e = fmt.Errorf("bit field %s: tag %s has no key %q",
+			bitField.name, bitField.tag.Value, tagsBitFieldKey,
+		)
+
+		return
+	}
+
+	start += len(keyPrefix)
+	end = start + strings.IndexByte(tag[start:], '"')
+
+	tag = tag[:start] + value + tag[end:]
+
+	if strings.HasPrefix(bitField.tag.Value, "`") &&
+		!strings.Contains(tag, "`") {
+		literal = "`" + tag + "`"
+
+	} else {
+		literal = strconv.Quote(tag)
+	}
+
+	return
+}
+
+// tagsIndexKey returns the index of a key in a conventional struct tag,
+// skipping keys that end in it and quoted values that contain it.
+func tagsIndexKey(tag, key string) (index int) {
+	var (
+		i int
+		j int
+	)
+
+	for i < len(tag) {
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+
+		j = i
+
+		for j < len(tag) && tag[j] > ' ' && tag[j] != ':' && tag[j] != '"' {
+			j++
+		}
+
+		if j+1 >= len(tag) || tag[j] != ':' || tag[j+1] != '"' {
+			break
+		}
+
+		if tag[i:j] == key {
+			index = i
+
+			return
+		}
+
+		for j += 2; j < len(tag) && tag[j] != '"'; j++ {
+			if tag[j] == '\\' {
+				j++
+			}
+		}
+
+		i = j + 1
+	}
+
+	index = -1
+
+	return
+}
+
+func tagsCompareLayouts(words, words1 []tagsWord) (e error) {
+	var (
+		i int
+		j int
+	)
+
+	if len(words) != len(words1) {
+		e = fmt.Errorf("rewriting changed the number of word-structs")
+
+		return
+	}
+
+	for i = range words {
+		if len(words[i].bitFields) != len(words1[i].bitFields) {
+			e = fmt.Errorf("%s: rewriting changed the number of bit fields",
+				words[i].position,
+			)
+
+			return
+		}
+
+		for j = range words[i].bitFields {
+			if words[i].bitFields[j].name != words1[i].bitFields[j].name ||
+				words[i].bitFields[j].length !=
+					words1[i].bitFields[j].length ||
+				words[i].bitFields[j].offset !=
+					words1[i].bitFields[j].offset {
+				e = fmt.Errorf("%s: rewriting changed bit field %s",
+					words[i].position, words[i].bitFields[j].name,
+				)
+
+				return
+			}
+		}
+	}
+
+	return
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	tagsTestImplicitFilename = "../../pkg/rfc791/rfc-791-internet-header-format.go"
+	tagsTestExplicitFilename = "../../pkg/rfc791/v1p1/rfc-791-internet-header-format.go"
+)
+
+func TestRewriteTagsBetweenRFC791Styles(t *testing.T) {
+	var (
+		e        error
+		explicit []byte
+		implicit []byte
+		output   []byte
+	)
+
+	implicit, e = os.ReadFile(tagsTestImplicitFilename)
+
+	assert.Nil(t, e)
+
+	explicit, e = os.ReadFile(tagsTestExplicitFilename)
+
+	assert.Nil(t, e)
+
+	// The two files differ only in their package clauses and tags.
+
+	explicit = bytes.Replace(explicit,
+		[]byte("package v1p1"), []byte("package rfc791"), 1,
+	)
+
+	output, e = rewriteTags(tagsTestImplicitFilename, implicit,
+		tagsStyleExplicit,
+	)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		string(explicit), string(output),
+	)
+
+	output, e = rewriteTags(tagsTestExplicitFilename, explicit,
+		tagsStyleImplicit,
+	)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		string(implicit), string(output),
+	)
+}
+
+func TestRewriteTagsPreservesOtherKeys(t *testing.T) {
+	const (
+		source = "package p\n" +
+			"\n" +
+			"type Word struct {\n" +
+			"\tA uint8 `json:\"a\" bitfield:\"3\"` // A\n" +
+			"\tB uint8 `bitfield:\"5\" json:\"bitfield:\\\"\"`\n" +
+			"\tC uint8\n" +
+			"}\n"
+
+		expected = "package p\n" +
+			"\n" +
+			"type Word struct {\n" +
+			"\tA uint8 `json:\"a\" bitfield:\"3,5\"` // A\n" +
+			"\tB uint8 `bitfield:\"5,0\" json:\"bitfield:\\\"\"`\n" +
+			"\tC uint8\n" +
+			"}\n"
+	)
+
+	var (
+		e      error
+		output []byte
+	)
+
+	output, e = rewriteTags("word.go", []byte(source), tagsStyleExplicit)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		expected, string(output),
+	)
+}
+
+func TestRewriteTagsPreservesOptions(t *testing.T) {
+	const (
+		source = "package p\n" +
+			"\n" +
+			"type Word struct {\n" +
+			"\tA uint32 `bitfield:\"32,order=cdab\"`\n" +
+			"\tB uint16 `bitfield:\"16\"`\n" +
+			"}\n"
+
+		expected = "package p\n" +
+			"\n" +
+			"type Word struct {\n" +
+			"\tA uint32 `bitfield:\"32,16,order=cdab\"`\n" +
+			"\tB uint16 `bitfield:\"16,0\"`\n" +
+			"}\n"
+	)
+
+	var (
+		e      error
+		output []byte
+	)
+
+	output, e = rewriteTags("word.go", []byte(source), tagsStyleExplicit)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		expected, string(output),
+	)
+
+	output, e = rewriteTags("word.go", output, tagsStyleImplicit)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		source, string(output),
+	)
+}
+
+func TestRewriteTagsRejectsOffsetsNotMatchingOrder(t *testing.T) {
+	const (
+		source = "package p\n" +
+			"\n" +
+			"type Word struct {\n" +
+			"\tA uint8 `bitfield:\"4,0\"`\n" +
+			"\tB uint8 `bitfield:\"4,4\"`\n" +
+			"}\n"
+	)
+
+	var (
+		e error
+	)
+
+	_, e = rewriteTags("word.go", []byte(source), tagsStyleImplicit)
+
+	assert.EqualError(t, e,
+		"word.go:3:11: bit field A has offset 0 "+
+			"but its position in the word implies offset 4",
+	)
+}
@@ -0,0 +1,789 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/encodingx/binary"
+	"github.com/encodingx/binary/internal/codecs/metadata"
+)
+
+// TypeScript generates an interface and functions encoding and decoding
+// a format-struct with DataView and bit operations,
+// from the layout of the format derived by package metadata.
+// Words of up to 32 bits are handled as numbers, and longer words as bigints;
+// bit fields longer than 53 bits are bigints, and others numbers.
+// Payloads are left to the caller.
+// Golden vectors marshalled by Go are embedded to check the generated code.
+
+const (
+	typeScriptIndent = "\t"
+
+	// The longest bit field that a number holds exactly,
+	// and the longest word that bit operations on numbers handle.
+	typeScriptNumberLengthLimit = 53
+	typeScriptWordLengthLimit   = 4
+)
+
+func runTypeScript(args []string, stdout io.Writer) (e error) {
+	const (
+		usage = "" +
+			"Usage: binary typescript -type importpath.Type [-C dir] " +
+			"[-vectors n] [-o file]\n" +
+			"\n" +
+			"TypeScript loads a format-struct from the Go source of a module\n" +
+			"and generates a TypeScript interface and functions encoding\n" +
+			"and decoding it with DataView, with golden vectors\n" +
+			"marshalled by Go.\n" +
+			"\n"
+	)
+
+	var (
+		directory  string
+		filename   string
+		flags      *flag.FlagSet
+		output     []byte
+		reflection reflect.Type
+		typeName   string
+		vectors    int
+	)
+
+	flags = flag.NewFlagSet("typescript", flag.ContinueOnError)
+
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+
+		flags.PrintDefaults()
+	}
+
+	flags.StringVar(&typeName, "type", "",
+		"import path and name of the format-struct "+
+			"(e.g. \"github.com/our/pkg.HeaderFormat\")",
+	)
+
+	flags.StringVar(&directory, "C", ".",
+		"root directory of the module holding the format-struct",
+	)
+
+	flags.IntVar(&vectors, "vectors", 4,
+		"number of golden vectors to embed",
+	)
+
+	flags.StringVar(&filename, "o", "",
+		"file to write instead of standard output",
+	)
+
+	e = flags.Parse(args)
+	if e != nil {
+		return
+	}
+
+	if typeName == "" || vectors < 0 || flags.NArg() > 0 {
+		flags.Usage()
+
+		e = errUsage
+
+		return
+	}
+
+	reflection, e = decodeLoadType(directory, typeName)
+	if e != nil {
+		return
+	}
+
+	output, e = generateTypeScript(
+		typeName[strings.LastIndex(typeName, ".")+1:],
+		reflection,
+		vectors,
+	)
+	if e != nil {
+		return
+	}
+
+	if filename != "" {
+		e = os.WriteFile(filename, output, 0644)
+
+		return
+	}
+
+	_, e = stdout.Write(output)
+
+	return
+}
+
+type typeScriptGenerator struct {
+	buffer  bytes.Buffer
+	name    string
+	layouts []metadata.WordLayout
+	words   map[string]metadata.WordLayout
+	ordered bool
+}
+
+func generateTypeScript(name string, reflection reflect.Type, vectors int) (
+	output []byte, e error,
+) {
+	var (
+		format    metadata.FormatMetadata
+		generator typeScriptGenerator
+		uuid      metadata.UUIDLayout
+		vectorHex []string
+		vectorSet []reflect.Value
+		word      metadata.WordLayout
+	)
+
+	// Marshal validates the format-struct, and errors name the function.
+
+	_, e = binary.Marshal(
+		reflect.New(reflection).Interface(),
+	)
+	if e != nil {
+		return
+	}
+
+	format, e = metadata.NewFormatMetadataFromTypeReflection(reflection)
+	if e != nil {
+		return
+	}
+
+	for _, uuid = range format.UUIDs() {
+		e = fmt.Errorf("field %s is a UUID; UUID fields are not supported",
+			reflection.FieldByIndex(uuid.Index).Name,
+		)
+
+		return
+	}
+
+	generator = typeScriptGenerator{
+		name:    name,
+		layouts: format.Words(),
+		words:   make(map[string]metadata.WordLayout),
+	}
+
+	for _, word = range generator.layouts {
+		generator.words[fmt.Sprint(word.Index)] = word
+
+		generator.ordered = generator.ordered || typeScriptHasFieldOrder(word)
+	}
+
+	vectorSet, vectorHex, e = generator.vectors(reflection, vectors)
+	if e != nil {
+		return
+	}
+
+	generator.printf(0,
+		"// Code generated by binary typescript; DO NOT EDIT.\n\n",
+	)
+
+	generator.printf(0, "export interface %s ", name)
+	generator.writeInterface(reflection, nil, 0)
+	generator.printf(0, "\n\n")
+
+	generator.printf(0, "export const %sLength = %d;\n\n",
+		name, format.LengthInBytes(),
+	)
+
+	if generator.ordered {
+		generator.writePermutationFunctions()
+	}
+
+	generator.writeDecode(reflection)
+	generator.writeEncode(reflection)
+	generator.writeVectors(reflection, vectorSet, vectorHex)
+
+	output = generator.buffer.Bytes()
+
+	return
+}
+
+func typeScriptHasFieldOrder(word metadata.WordLayout) bool {
+	var (
+		bitField metadata.BitFieldLayout
+	)
+
+	for _, bitField = range word.BitFields {
+		if bitField.Order != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (g *typeScriptGenerator) printf(indent int, format string,
+	args ...interface{},
+) {
+	g.buffer.WriteString(
+		strings.Repeat(typeScriptIndent, indent),
+	)
+
+	fmt.Fprintf(&g.buffer, format, args...)
+
+	return
+}
+
+// word returns the layout of the word-struct at an index sequence, if any.
+func (g *typeScriptGenerator) word(index []int) (
+	word metadata.WordLayout, ok bool,
+) {
+	word, ok = g.words[fmt.Sprint(index)]
+
+	return
+}
+
+func typeScriptIndex(index []int, i int) []int {
+	return append(append([]int(nil), index...), i)
+}
+
+func typeScriptBitFieldType(field reflect.StructField,
+	bitField metadata.BitFieldLayout,
+) string {
+	switch {
+	case field.Type.Kind() == reflect.Bool:
+		return "boolean"
+
+	case bitField.Length > typeScriptNumberLengthLimit:
+		return "bigint"
+	}
+
+	return "number"
+}
+
+// writeInterface writes the type of a format-struct or word-struct
+// as an object type, nesting words and formats by their field names.
+func (g *typeScriptGenerator) writeInterface(reflection reflect.Type,
+	index []int, indent int,
+) {
+	var (
+		field reflect.StructField
+		i     int
+		ok    bool
+		word  metadata.WordLayout
+	)
+
+	word, ok = g.word(index)
+
+	g.printf(0, "{\n")
+
+	for i = 0; i < reflection.NumField(); i++ {
+		field = reflection.Field(i)
+
+		switch {
+		case ok:
+			g.printf(indent+1, "%s: %s;\n",
+				field.Name, typeScriptBitFieldType(field, word.BitFields[i]),
+			)
+
+		case field.Type == decodePayloadType:
+			continue
+
+		default:
+			g.printf(indent+1, "%s: ", field.Name)
+			g.writeInterface(field.Type, typeScriptIndex(index, i), indent+1)
+			g.printf(0, ";\n")
+		}
+	}
+
+	g.printf(indent, "}")
+
+	return
+}
+
+func (g *typeScriptGenerator) writePermutationFunctions() {
+	const (
+		functions = "" +
+			"// Bytes of a value on the wire are named by the permutation\n" +
+			"// of their significance, 0 being the most significant.\n" +
+			"\n" +
+			"function permute(value: bigint, order: number[]): bigint {\n" +
+			"\tlet permuted = 0n;\n" +
+			"\tfor (let i = 0; i < order.length; i++) {\n" +
+			"\t\tpermuted = permuted << 8n |\n" +
+			"\t\t\tvalue >> BigInt(8 * (order.length - 1 - order[i])) & 0xffn;\n" +
+			"\t}\n" +
+			"\treturn permuted;\n" +
+			"}\n" +
+			"\n" +
+			"function restore(permuted: bigint, order: number[]): bigint {\n" +
+			"\tlet value = 0n;\n" +
+			"\tfor (let i = 0; i < order.length; i++) {\n" +
+			"\t\tvalue |= (permuted >> BigInt(8 * (order.length - 1 - i)) & 0xffn) <<\n" +
+			"\t\t\tBigInt(8 * (order.length - 1 - order[i]));\n" +
+			"\t}\n" +
+			"\treturn value;\n" +
+			"}\n" +
+			"\n"
+	)
+
+	g.buffer.WriteString(functions)
+
+	return
+}
+
+// typeScriptByteShift returns the shift of byte i on the wire of a word,
+// in number of bits from the least significant bit of its value.
+func typeScriptByteShift(word metadata.WordLayout, i int) int {
+	if word.Order == nil {
+		return 8 * (word.Length - 1 - i)
+	}
+
+	return 8 * (word.Length - 1 - word.Order[i])
+}
+
+func typeScriptOrder(order []int) string {
+	var (
+		i        int
+		elements []string
+	)
+
+	elements = make([]string, len(order))
+
+	for i = range order {
+		elements[i] = fmt.Sprint(order[i])
+	}
+
+	return strings.Join(elements, ", ")
+}
+
+func typeScriptMask(length uint) string {
+	return fmt.Sprintf("0x%x", uint64(1)<<length-1)
+}
+
+func (g *typeScriptGenerator) writeDecode(reflection reflect.Type) {
+	var (
+		i     int
+		terms []string
+		word  metadata.WordLayout
+	)
+
+	g.printf(0,
+		"export function decode%s(view: DataView, offset = 0): %s {\n",
+		g.name, g.name,
+	)
+
+	for _, word = range g.layouts {
+		terms = terms[:0]
+
+		switch {
+		case word.Length > typeScriptWordLengthLimit &&
+			word.Length == 8 && word.Order == nil:
+			g.printf(1, "const w%d = view.getBigUint64(offset + %d);\n",
+				word.Offset, word.Offset,
+			)
+
+		case word.Length > typeScriptWordLengthLimit:
+			for i = 0; i < word.Length; i++ {
+				terms = append(terms,
+					fmt.Sprintf("BigInt(view.getUint8(offset + %d)) << %dn",
+						word.Offset+i, typeScriptByteShift(word, i),
+					),
+				)
+			}
+
+			g.printf(1, "const w%d =\n%s%s;\n",
+				word.Offset,
+				strings.Repeat(typeScriptIndent, 2),
+				strings.Join(terms, " |\n"+strings.Repeat(typeScriptIndent, 2)),
+			)
+
+		case word.Order == nil && word.Length != 3:
+			g.printf(1, "const w%d = view.getUint%d(offset + %d);\n",
+				word.Offset, 8*word.Length, word.Offset,
+			)
+
+		default:
+			for i = 0; i < word.Length; i++ {
+				terms = append(terms,
+					fmt.Sprintf("view.getUint8(offset + %d) << %d",
+						word.Offset+i, typeScriptByteShift(word, i),
+					),
+				)
+			}
+
+			g.printf(1, "const w%d = (\n%s%s\n%s) >>> 0;\n",
+				word.Offset,
+				strings.Repeat(typeScriptIndent, 2),
+				strings.Join(terms, " |\n"+strings.Repeat(typeScriptIndent, 2)),
+				typeScriptIndent,
+			)
+		}
+	}
+
+	g.printf(1, "return ")
+	g.writeDecodeValue(reflection, nil, 1)
+	g.printf(0, ";\n}\n\n")
+
+	return
+}
+
+func (g *typeScriptGenerator) writeDecodeValue(reflection reflect.Type,
+	index []int, indent int,
+) {
+	var (
+		field reflect.StructField
+		i     int
+		ok    bool
+		word  metadata.WordLayout
+	)
+
+	word, ok = g.word(index)
+
+	g.printf(0, "{\n")
+
+	for i = 0; i < reflection.NumField(); i++ {
+		field = reflection.Field(i)
+
+		switch {
+		case ok:
+			g.printf(indent+1, "%s: %s,\n",
+				field.Name, g.decodeBitField(field, word, word.BitFields[i]),
+			)
+
+		case field.Type == decodePayloadType:
+			continue
+
+		default:
+			g.printf(indent+1, "%s: ", field.Name)
+			g.writeDecodeValue(field.Type, typeScriptIndex(index, i), indent+1)
+			g.printf(0, ",\n")
+		}
+	}
+
+	g.printf(indent, "}")
+
+	return
+}
+
+// decodeBitField returns an expression extracting a bit field from a word.
+func (g *typeScriptGenerator) decodeBitField(field reflect.StructField,
+	word metadata.WordLayout, bitField metadata.BitFieldLayout,
+) (
+	expression string,
+) {
+	var (
+		isBigInt bool
+		tsType   string
+	)
+
+	tsType = typeScriptBitFieldType(field, bitField)
+
+	isBigInt = word.Length > typeScriptWordLengthLimit
+
+	switch {
+	case isBigInt:
+		expression = fmt.Sprintf("w%d >> %dn & %sn",
+			word.Offset, bitField.Offset, typeScriptMask(bitField.Length),
+		)
+
+	case bitField.Length == 32:
+		expression = fmt.Sprintf("w%d", word.Offset)
+
+	default:
+		expression = fmt.Sprintf("w%d >>> %d & %s",
+			word.Offset, bitField.Offset, typeScriptMask(bitField.Length),
+		)
+	}
+
+	if bitField.Order != nil {
+		if !isBigInt {
+			expression = "BigInt(" + expression + ")"
+		}
+
+		expression = fmt.Sprintf("restore(%s, [%s])",
+			expression, typeScriptOrder(bitField.Order),
+		)
+
+		isBigInt = true
+	}
+
+	switch {
+	case tsType == "boolean" && isBigInt:
+		expression = "(" + expression + ") === 1n"
+
+	case tsType == "boolean":
+		expression = "(" + expression + ") === 1"
+
+	case tsType == "number" && isBigInt:
+		expression = "Number(" + expression + ")"
+	}
+
+	return
+}
+
+func (g *typeScriptGenerator) writeEncode(reflection reflect.Type) {
+	var (
+		i          int
+		j          int
+		usesBigInt bool
+		usesNumber bool
+		word       metadata.WordLayout
+	)
+
+	for _, word = range g.layouts {
+		usesBigInt = usesBigInt || word.Length > typeScriptWordLengthLimit
+		usesNumber = usesNumber || word.Length <= typeScriptWordLengthLimit
+	}
+
+	g.printf(0,
+		"export function encode%s(value: %s): Uint8Array {\n",
+		g.name, g.name,
+	)
+
+	g.printf(1, "const bytes = new Uint8Array(%sLength);\n", g.name)
+	g.printf(1, "const view = new DataView(bytes.buffer);\n")
+
+	if usesNumber {
+		g.printf(1, "let w: number;\n")
+	}
+
+	if usesBigInt {
+		g.printf(1, "let b: bigint;\n")
+	}
+
+	for _, word = range g.layouts {
+		g.printf(0, "\n")
+
+		if word.Length > typeScriptWordLengthLimit {
+			g.printf(1, "b = 0n;\n")
+		} else {
+			g.printf(1, "w = 0;\n")
+		}
+
+		for j = range word.BitFields {
+			g.printf(1, "%s;\n",
+				g.encodeBitField(reflection, word, j),
+			)
+		}
+
+		switch {
+		case word.Length > typeScriptWordLengthLimit &&
+			word.Length == 8 && word.Order == nil:
+			g.printf(1, "view.setBigUint64(%d, b);\n", word.Offset)
+
+		case word.Length > typeScriptWordLengthLimit:
+			for i = 0; i < word.Length; i++ {
+				g.printf(1, "view.setUint8(%d, Number(b >> %dn & 0xffn));\n",
+					word.Offset+i, typeScriptByteShift(word, i),
+				)
+			}
+
+		case word.Order == nil && word.Length != 3:
+			g.printf(1, "view.setUint%d(%d, w >>> 0);\n",
+				8*word.Length, word.Offset,
+			)
+
+		default:
+			for i = 0; i < word.Length; i++ {
+				g.printf(1, "view.setUint8(%d, w >>> %d & 0xff);\n",
+					word.Offset+i, typeScriptByteShift(word, i),
+				)
+			}
+		}
+	}
+
+	g.printf(0, "\n")
+	g.printf(1, "return bytes;\n")
+	g.printf(0, "}\n\n")
+
+	return
+}
+
+// encodeBitField returns a statement setting a bit field into a word.
+func (g *typeScriptGenerator) encodeBitField(reflection reflect.Type,
+	word metadata.WordLayout, j int,
+) (
+	statement string,
+) {
+	var (
+		bitField metadata.BitFieldLayout
+		field    reflect.StructField
+		isBigInt bool
+		name     string
+		path     []string
+		value    string
+	)
+
+	bitField = word.BitFields[j]
+
+	for _, j = range typeScriptIndex(word.Index, j) {
+		field = reflection.Field(j)
+		reflection = field.Type
+
+		path = append(path, field.Name)
+	}
+
+	name = "value." + strings.Join(path, ".")
+
+	isBigInt = word.Length > typeScriptWordLengthLimit
+
+	switch {
+	case field.Type.Kind() == reflect.Bool && isBigInt:
+		value = fmt.Sprintf("(%s ? 1n : 0n)", name)
+
+	case field.Type.Kind() == reflect.Bool:
+		value = fmt.Sprintf("(%s ? 1 : 0)", name)
+
+	case bitField.Order != nil:
+		value = fmt.Sprintf("permute(BigInt(%s) & %sn, [%s])",
+			name, typeScriptMask(bitField.Length),
+			typeScriptOrder(bitField.Order),
+		)
+
+		if !isBigInt {
+			value = "Number(" + value + ")"
+		}
+
+	case isBigInt:
+		value = fmt.Sprintf("(BigInt(%s) & %sn)",
+			name, typeScriptMask(bitField.Length),
+		)
+
+	default:
+		value = fmt.Sprintf("(%s & %s)",
+			name, typeScriptMask(bitField.Length),
+		)
+	}
+
+	if isBigInt {
+		statement = fmt.Sprintf("b |= %s << %dn", value, bitField.Offset)
+	} else {
+		statement = fmt.Sprintf("w |= %s << %d", value, bitField.Offset)
+	}
+
+	return
+}
+
+// vectors returns values of a format-struct and their bytes marshalled by Go:
+// all bit fields zero, all bit fields ones, and pseudo-random values.
+func (g *typeScriptGenerator) vectors(reflection reflect.Type, n int) (
+	values []reflect.Value, hexStrings []string, e error,
+) {
+	const (
+		seed = 1
+	)
+
+	var (
+		bitField metadata.BitFieldLayout
+		field    reflect.Value
+		i        int
+		j        int
+		marshal  []byte
+		random   *rand.Rand
+		value    reflect.Value
+		word     metadata.WordLayout
+		x        uint64
+	)
+
+	random = rand.New(
+		rand.NewSource(seed),
+	)
+
+	for i = 0; i < n; i++ {
+		value = reflect.New(reflection)
+
+		for _, word = range g.layouts {
+			for j, bitField = range word.BitFields {
+				switch i {
+				case 0:
+					x = 0
+
+				case 1:
+					x = 1<<bitField.Length - 1
+
+				default:
+					x = random.Uint64() & (1<<bitField.Length - 1)
+				}
+
+				field = value.Elem().FieldByIndex(word.Index).Field(j)
+
+				if field.Kind() == reflect.Bool {
+					field.SetBool(x&1 == 1)
+				} else {
+					field.SetUint(x)
+				}
+			}
+		}
+
+		marshal, e = binary.Marshal(value.Interface())
+		if e != nil {
+			return
+		}
+
+		values = append(values, value.Elem())
+		hexStrings = append(hexStrings, hex.EncodeToString(marshal))
+	}
+
+	return
+}
+
+func (g *typeScriptGenerator) writeVectors(reflection reflect.Type,
+	values []reflect.Value, hexStrings []string,
+) {
+	var (
+		i int
+	)
+
+	g.printf(0,
+		"// Golden vectors marshalled by Go.\n"+
+			"export const %sVectors: { hex: string; value: %s }[] = [\n",
+		g.name, g.name,
+	)
+
+	for i = range values {
+		g.printf(1, "{\n")
+		g.printf(2, "hex: \"%s\",\n", hexStrings[i])
+		g.printf(2, "value: ")
+		g.writeValue(values[i], nil, 2)
+		g.printf(0, ",\n")
+		g.printf(1, "},\n")
+	}
+
+	g.printf(0, "];\n")
+
+	return
+}
+
+func (g *typeScriptGenerator) writeValue(value reflect.Value, index []int,
+	indent int,
+) {
+	var (
+		field reflect.StructField
+		i     int
+		ok    bool
+		word  metadata.WordLayout
+	)
+
+	word, ok = g.word(index)
+
+	g.printf(0, "{\n")
+
+	for i = 0; i < value.NumField(); i++ {
+		field = value.Type().Field(i)
+
+		switch {
+		case ok && typeScriptBitFieldType(field, word.BitFields[i]) == "bigint":
+			g.printf(indent+1, "%s: %dn,\n", field.Name, value.Field(i).Uint())
+
+		case ok:
+			g.printf(indent+1, "%s: %v,\n", field.Name, value.Field(i).Interface())
+
+		case field.Type == decodePayloadType:
+			continue
+
+		default:
+			g.printf(indent+1, "%s: ", field.Name)
+			g.writeValue(value.Field(i), typeScriptIndex(index, i), indent+1)
+			g.printf(0, ",\n")
+		}
+	}
+
+	g.printf(indent, "}")
+
+	return
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	typeScriptTestFormat struct {
+		TypeScriptTestFormatWord0 `word:"16,order=ba"`
+		TypeScriptTestFormatWord1 `word:"64"`
+	}
+
+	TypeScriptTestFormatWord0 struct {
+		Flag  bool   `bitfield:"1"`
+		Value uint16 `bitfield:"15"`
+	}
+
+	TypeScriptTestFormatWord1 struct {
+		Big   uint64 `bitfield:"60"`
+		Small uint8  `bitfield:"4"`
+	}
+)
+
+func TestGenerateTypeScript(t *testing.T) {
+	const (
+		vectors = 3
+	)
+
+	var (
+		e        error
+		expected string
+		output   []byte
+		output1  []byte
+	)
+
+	output, e = generateTypeScript("TestFormat",
+		reflect.TypeOf(typeScriptTestFormat{}), vectors,
+	)
+
+	assert.Nil(t, e)
+
+	for _, expected = range []string{
+		"export interface TestFormat {\n" +
+			"\tTypeScriptTestFormatWord0: {\n" +
+			"\t\tFlag: boolean;\n" +
+			"\t\tValue: number;\n" +
+			"\t};\n" +
+			"\tTypeScriptTestFormatWord1: {\n" +
+			"\t\tBig: bigint;\n" +
+			"\t\tSmall: number;\n" +
+			"\t};\n" +
+			"}\n",
+		"export const TestFormatLength = 10;\n",
+		"\tconst w0 = (\n" +
+			"\t\tview.getUint8(offset + 0) << 0 |\n" +
+			"\t\tview.getUint8(offset + 1) << 8\n" +
+			"\t) >>> 0;\n",
+		"\tconst w2 = view.getBigUint64(offset + 2);\n",
+		"\t\t\tFlag: (w0 >>> 15 & 0x1) === 1,\n",
+		"\t\t\tBig: w2 >> 4n & 0xfffffffffffffffn,\n",
+		"\t\t\tSmall: Number(w2 >> 0n & 0xfn),\n",
+		"\tw |= (value.TypeScriptTestFormatWord0.Value & 0x7fff) << 0;\n",
+		"\tview.setUint8(1, w >>> 8 & 0xff);\n",
+		"\tview.setBigUint64(2, b);\n",
+		"\t\thex: \"00000000000000000000\",\n",
+		"\t\thex: \"ffffffffffffffffffff\",\n",
+		"\t\t\t\tBig: 1152921504606846975n,\n",
+	} {
+		assert.Contains(t, string(output), expected)
+	}
+
+	// Golden vectors are pseudo-random but reproducible.
+
+	output1, e = generateTypeScript("TestFormat",
+		reflect.TypeOf(typeScriptTestFormat{}), vectors,
+	)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		string(output), string(output1),
+	)
+}
@@ -9,11 +9,33 @@ import (
 
 type Codec struct {
 	formatMetadataCache map[reflect.Type]metadata.FormatMetadata
+	wordMetadataCache   map[reflect.Type]metadata.WordMetadata
+	keyProvider         KeyProvider
+	unmarshalPolicy     UnmarshalPolicy
 }
 
-func NewCodec() (c Codec) {
+// UnmarshalPolicy determines what Unmarshal leaves in a format-struct
+// when words fail to decode.
+type UnmarshalPolicy int
+
+const (
+	// UnmarshalAtomic verifies every word before setting any,
+	// leaving a format-struct untouched on error, and returns the first.
+	UnmarshalAtomic UnmarshalPolicy = iota
+
+	// UnmarshalBestEffort sets every word that decodes,
+	// including those covered by a MAC that does not match,
+	// and returns a *validation.DecodingError listing those that do not.
+	UnmarshalBestEffort
+)
+
+// NewCodec returns a codec that obtains the keys of MAC words
+// from keyProvider, which may be nil if no format has MAC words.
+func NewCodec(keyProvider KeyProvider) (c Codec) {
 	c = Codec{
 		formatMetadataCache: make(map[reflect.Type]metadata.FormatMetadata),
+		wordMetadataCache:   make(map[reflect.Type]metadata.WordMetadata),
+		keyProvider:         keyProvider,
 	}
 
 	return
@@ -56,6 +78,53 @@ func (c Codec) formatMetadataFromTypeReflection(reflection reflect.Type) (
 	return
 }
 
+func (c Codec) wordMetadataFromTypeReflection(reflection reflect.Type) (
+	word metadata.WordMetadata, e error,
+) {
+	var (
+		inCache bool
+	)
+
+	word, inCache = c.wordMetadataCache[reflection]
+
+	if inCache {
+		return
+	}
+
+	if reflection.Kind() != reflect.Ptr {
+		e = validation.NewNonPointerError()
+
+		return
+	}
+
+	if reflection.Elem().Kind() != reflect.Struct {
+		e = validation.NewPointerToNonStructVariableError()
+
+		return
+	}
+
+	word, e = metadata.NewWordMetadataFromTypeReflection(
+		reflection.Elem(),
+	)
+	if e != nil {
+		e.(validation.FormatError).SetFormatName(
+			reflection.Elem().String(),
+		)
+
+		return
+	}
+
+	c.wordMetadataCache[reflection] = word
+
+	return
+}
+
+func (c *Codec) SetUnmarshalPolicy(policy UnmarshalPolicy) {
+	c.unmarshalPolicy = policy
+
+	return
+}
+
 func (c Codec) NewOperation(iface interface{}) (
 	operation CodecOperation, e error,
 ) {
@@ -67,6 +136,8 @@ func (c Codec) NewOperation(iface interface{}) (
 	}
 
 	operation.valueReflection = reflect.ValueOf(iface).Elem()
+	operation.keyProvider = c.keyProvider
+	operation.unmarshalPolicy = c.unmarshalPolicy
 
 	return
 }
@@ -74,29 +145,162 @@ func (c Codec) NewOperation(iface interface{}) (
 type CodecOperation struct {
 	format          metadata.FormatMetadata
 	valueReflection reflect.Value
+	keyProvider     KeyProvider
+	unmarshalPolicy UnmarshalPolicy
 }
 
 func (c CodecOperation) Marshal() (bytes []byte, e error) {
+	var (
+		mac     metadata.MACMetadata
+		tag     []byte
+		trailer []byte
+	)
+
 	bytes = c.format.Marshal(c.valueReflection)
 
+	for _, mac = range c.format.MACs() {
+		tag, e = c.computeMAC(mac, bytes)
+		if e != nil {
+			bytes = nil
+
+			return
+		}
+
+		copy(bytes[mac.Offset:mac.Offset+mac.Length], tag)
+	}
+
+	if c.format.HasTrailer() {
+		trailer, e = c.marshalTrailer()
+		if e != nil {
+			bytes = nil
+
+			return
+		}
+
+		bytes = append(bytes, trailer...)
+	}
+
 	return
 }
 
 func (c CodecOperation) Unmarshal(bytes []byte) (e error) {
-	if len(bytes) != c.format.LengthInBytes() {
+	var (
+		errors    []validation.WordError
+		macErrors []validation.WordError
+		trailer   []byte
+	)
+
+	defer func() {
+		if e != nil {
+			e.(validation.FormatError).SetFormatName(
+				c.valueReflection.Type().String(),
+			)
+		}
+
+		return
+	}()
+
+	// A trailer is passed every byte following the words.
+
+	if len(bytes) != c.format.LengthInBytes() &&
+		!(c.format.HasTrailer() && len(bytes) > c.format.LengthInBytes()) {
 		e = validation.NewLengthOfByteSliceNotEqualToFormatLengthError(
 			uint(c.format.LengthInBytes()),
 			uint(len(bytes)),
 		)
 
-		e.(validation.FormatError).SetFormatName(
-			c.valueReflection.Type().String(),
-		)
+		return
+	}
+
+	if c.format.HasTrailer() {
+		trailer = bytes[c.format.LengthInBytes():]
+
+		bytes = bytes[:c.format.LengthInBytes()]
+	}
+
+	errors = c.format.VerifyPadding(bytes)
+
+	if len(errors) > 0 && c.unmarshalPolicy == UnmarshalAtomic {
+		e = errors[0]
 
 		return
 	}
 
-	c.format.Unmarshal(bytes, c.valueReflection)
+	macErrors, e = c.verifyMACs(bytes)
+	if e != nil {
+		return
+	}
+
+	errors = append(errors, macErrors...)
+
+	switch c.unmarshalPolicy {
+	case UnmarshalBestEffort:
+		c.format.Unmarshal(bytes, c.valueReflection)
+
+		if c.format.HasTrailer() {
+			e = c.unmarshalTrailer(trailer)
+			if e != nil {
+				errors = append(errors, e.(validation.WordError))
+			}
+		}
+
+		e = nil
+
+		if len(errors) > 0 {
+			e = validation.NewDecodingError(errors)
+		}
+
+	default:
+		if len(errors) > 0 {
+			e = errors[0]
+
+			return
+		}
+
+		// A trailer leaves itself untouched on error,
+		// so it is unmarshalled before the words.
+
+		if c.format.HasTrailer() {
+			e = c.unmarshalTrailer(trailer)
+			if e != nil {
+				return
+			}
+		}
+
+		c.format.Unmarshal(bytes, c.valueReflection)
+	}
+
+	return
+}
+
+func (c Codec) NewWordOperation(iface interface{}) (
+	operation WordOperation, e error,
+) {
+	operation.word, e = c.wordMetadataFromTypeReflection(
+		reflect.TypeOf(iface),
+	)
+	if e != nil {
+		return
+	}
+
+	operation.valueReflection = reflect.ValueOf(iface).Elem()
+
+	return
+}
+
+type WordOperation struct {
+	word            metadata.WordMetadata
+	valueReflection reflect.Value
+}
+
+func (c WordOperation) Pack() (wordUint64 uint64) {
+	wordUint64 = c.word.Pack(c.valueReflection)
+
+	return
+}
+
+func (c WordOperation) Unpack(wordUint64 uint64) {
+	c.word.Unpack(wordUint64, c.valueReflection)
 
 	return
 }
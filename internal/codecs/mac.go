@@ -0,0 +1,102 @@
+package codecs
+
+import (
+	"crypto/subtle"
+
+	"github.com/encodingx/binary/internal/codecs/metadata"
+	"github.com/encodingx/binary/internal/mac"
+	"github.com/encodingx/binary/internal/validation"
+)
+
+// KeyProvider provides the keys of MAC words.
+type KeyProvider interface {
+	// MACKey returns the key for the MAC words of a format-struct,
+	// given the name of its type (e.g. "pkg.CommandFormat")
+	// and the algorithm of the MAC (e.g. "hmac-sha256").
+	MACKey(formatName, algorithm string) (key []byte, e error)
+}
+
+// KeyProviderFunc adapts a function to a KeyProvider.
+type KeyProviderFunc func(formatName, algorithm string) ([]byte, error)
+
+func (f KeyProviderFunc) MACKey(formatName, algorithm string) ([]byte, error) {
+	return f(formatName, algorithm)
+}
+
+func (c CodecOperation) computeMAC(word metadata.MACMetadata, bytes []byte) (
+	tag []byte, e error,
+) {
+	var (
+		formatName string
+		key        []byte
+	)
+
+	formatName = c.valueReflection.Type().String()
+
+	defer func() {
+		if e != nil {
+			e.(validation.WordError).SetFormatName(formatName)
+			e.(validation.WordError).SetWordName(word.WordName)
+		}
+	}()
+
+	if c.keyProvider == nil {
+		e = validation.NewWordWithMACAndNoKeyProviderError()
+
+		return
+	}
+
+	key, e = c.keyProvider.MACKey(formatName, word.Algorithm)
+	if e != nil {
+		e = validation.NewWordWithMACKeyUnavailableError(e)
+
+		return
+	}
+
+	tag, e = mac.Compute(word.Algorithm, key, bytes[word.Start:word.End])
+	if e != nil {
+		e = validation.NewWordWithMACNotComputableError(word.Algorithm, e)
+
+		return
+	}
+
+	// Truncate the MAC to the length of the word.
+
+	tag = tag[:word.Length]
+
+	return
+}
+
+// verifyMACs returns an error for each MAC word
+// not matching the MAC computed over its byte range,
+// or an error if a MAC cannot be computed.
+func (c CodecOperation) verifyMACs(bytes []byte) (
+	errors []validation.WordError, e error,
+) {
+	var (
+		authenticationError validation.WordError
+		tag                 []byte
+		word                metadata.MACMetadata
+	)
+
+	for _, word = range c.format.MACs() {
+		tag, e = c.computeMAC(word, bytes)
+		if e != nil {
+			return
+		}
+
+		if subtle.ConstantTimeCompare(tag,
+			bytes[word.Offset:word.Offset+word.Length],
+		) != 1 {
+			authenticationError = validation.NewAuthenticationError(
+				word.Algorithm, word.Offset,
+			)
+
+			authenticationError.SetWordName(word.WordName)
+
+			errors = append(errors, authenticationError)
+		}
+	}
+
+	return
+}
@@ -1,10 +1,11 @@
 package metadata
 
 import (
-	"encoding/binary"
 	"fmt"
 	"reflect"
+	"strings"
 
+	"github.com/encodingx/binary/internal/order"
 	"github.com/encodingx/binary/internal/validation"
 )
 
@@ -12,6 +13,48 @@ type bitFieldMetadata struct {
 	length uint
 	offset uint64
 	kind   reflect.Kind
+	order  order.Permutation
+}
+
+// Options follow the length of a bit field in its struct tag,
+// separated by commas, each of the form key=value.
+// Other values, such as the offsets of the explicit style, are ignored.
+const (
+	bitFieldOptionOrder = "order"
+)
+
+func (m *bitFieldMetadata) parseOptions(options []string) (e error) {
+	var (
+		keyValue []string
+		ok       bool
+		option   string
+	)
+
+	for _, option = range options {
+		keyValue = strings.SplitN(option, wordOptionValueSeparator, 2)
+
+		if len(keyValue) != 2 {
+			continue
+		}
+
+		switch keyValue[0] {
+		case bitFieldOptionOrder:
+			m.order, ok = order.Parse(keyValue[1])
+
+			if !ok || uint(len(m.order))*8 != m.length {
+				e = validation.NewBitFieldWithInvalidOptionError(option)
+
+				return
+			}
+
+		default:
+			e = validation.NewBitFieldWithInvalidOptionError(option)
+
+			return
+		}
+	}
+
+	return
 }
 
 func newBitFieldMetadataFromStructFieldReflection(
@@ -26,6 +69,7 @@ func newBitFieldMetadataFromStructFieldReflection(
 
 	var (
 		bitFieldLengthCap uint
+		options           []string
 	)
 
 	defer func() {
@@ -71,8 +115,10 @@ func newBitFieldMetadataFromStructFieldReflection(
 		return
 	}
 
+	options = strings.Split(reflection.Tag.Get(tagKey), wordOptionSeparator)
+
 	_, e = fmt.Sscanf(
-		reflection.Tag.Get(tagKey),
+		options[0],
 		tagValueFormat,
 		&bitField.length,
 	)
@@ -87,6 +133,13 @@ func newBitFieldMetadataFromStructFieldReflection(
 			bitField.length,
 			reflection.Type.String(),
 		)
+
+		return
+	}
+
+	e = bitField.parseOptions(options[1:])
+	if e != nil {
+		return
 	}
 
 	return
@@ -106,17 +159,27 @@ func (m bitFieldMetadata) marshal(reflection reflect.Value) (value uint64) {
 		}
 	}
 
-	value = value & (1<<m.length - 1) << m.offset // XXX: mask if overflowing
+	value = value & (1<<m.length - 1) // XXX: mask if overflowing
+
+	if m.order != nil {
+		value = m.order.Permute(value)
+	}
+
+	value = value << m.offset
 
 	return
 }
 
-func (m bitFieldMetadata) unmarshal(bytes []byte, reflection reflect.Value) {
+func (m bitFieldMetadata) unpack(wordUint64 uint64, reflection reflect.Value) {
 	var (
 		value uint64
 	)
 
-	value = binary.BigEndian.Uint64(bytes) >> m.offset & (1<<m.length - 1)
+	value = wordUint64 >> m.offset & (1<<m.length - 1)
+
+	if m.order != nil {
+		value = m.order.Restore(value)
+	}
 
 	switch m.kind {
 	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
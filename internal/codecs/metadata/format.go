@@ -1,23 +1,42 @@
 package metadata
 
 import (
+	"fmt"
+	"math/bits"
 	"reflect"
+	"strings"
 
 	"github.com/encodingx/binary/internal/validation"
 )
 
 type FormatMetadata struct {
-	words         []wordMetadata
+	words         []formatWordMetadata
+	paddings      []paddingMetadata
 	lengthInBytes int
+	macs          []MACMetadata
+	payload       *payloadMetadata
+	trailer       *trailerMetadata
+	uuids         []uuidMetadata
 }
 
+// formatWordMetadata locates a word within the outermost format.
+// Words of nested formats are flattened into it.
+type formatWordMetadata struct {
+	wordMetadata
+	index  []int
+	offset int
+}
+
+const (
+	// A format-struct may nest another format-struct
+	// tagged with a key "format" and a value of options, possibly empty
+	// (e.g. `format:""`).
+	formatTagKey = "format"
+)
+
 func NewFormatMetadataFromTypeReflection(reflection reflect.Type) (
 	format FormatMetadata, e error,
 ) {
-	var (
-		i int
-	)
-
 	defer func() {
 		if e != nil {
 			e.(validation.FormatError).SetFormatName(
@@ -26,53 +45,275 @@ func NewFormatMetadataFromTypeReflection(reflection reflect.Type) (
 		}
 	}()
 
+	e = format.appendFormat(reflection, nil)
+	if e != nil {
+		return
+	}
+
+	if format.payload != nil {
+		e = format.resolvePayloadLength(reflection)
+		if e != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (m *FormatMetadata) appendFormat(reflection reflect.Type, index []int) (
+	e error,
+) {
+	var (
+		field      reflect.StructField
+		fieldIndex []int
+		i          int
+		nested     bool
+		options    string
+		payload    bool
+		trailer    bool
+		uuid       bool
+		word       wordMetadata
+	)
+
 	if reflection.NumField() == 0 {
 		e = validation.NewFormatWithNoWordsError()
 
 		return
 	}
 
-	format = FormatMetadata{
-		words: make([]wordMetadata,
-			reflection.NumField(),
-		),
+	for i = 0; i < reflection.NumField(); i++ {
+		field = reflection.Field(i)
+
+		fieldIndex = make([]int, len(index)+1)
+
+		copy(fieldIndex, index)
+
+		fieldIndex[len(index)] = i
+
+		options, payload = field.Tag.Lookup(payloadTagKey)
+
+		if payload || field.Type == payloadType {
+			e = m.appendPayload(reflection, fieldIndex, options)
+			if e != nil {
+				return
+			}
+
+			continue
+		}
+
+		_, trailer = field.Tag.Lookup(trailerTagKey)
+
+		if trailer || isTrailer(field) {
+			e = m.appendTrailer(reflection, fieldIndex)
+			if e != nil {
+				return
+			}
+
+			continue
+		}
+
+		options, uuid = field.Tag.Lookup(uuidTagKey)
+
+		if uuid || field.Type == uuidType {
+			e = m.appendUUID(field, fieldIndex, options)
+			if e != nil {
+				return
+			}
+
+			continue
+		}
+
+		options, nested = field.Tag.Lookup(formatTagKey)
+
+		if nested {
+			e = m.appendNestedFormat(field, options)
+			if e != nil {
+				return
+			}
+
+			e = m.appendFormat(field.Type, fieldIndex)
+			if e != nil {
+				return
+			}
+
+			continue
+		}
+
+		word, e = newWordMetadataFromStructFieldReflection(field)
+		if e != nil {
+			return
+		}
+
+		e = m.appendWord(word, field.Name, fieldIndex)
+		if e != nil {
+			return
+		}
 	}
 
-	for i = 0; i < reflection.NumField(); i++ {
-		format.words[i], e = newWordMetadataFromStructFieldReflection(
-			reflection.Field(i),
-		)
+	return
+}
+
+// appendNestedFormat parses the options of a nested format-struct
+// and pads the format to its alignment.
+func (m *FormatMetadata) appendNestedFormat(field reflect.StructField,
+	options string,
+) (
+	e error,
+) {
+	var (
+		keyValue []string
+		layout   layoutMetadata
+		option   string
+	)
+
+	defer func() {
+		if e != nil {
+			e.(validation.WordError).SetWordName(field.Name)
+		}
+	}()
+
+	if field.Type.Kind() != reflect.Struct {
+		e = validation.NewWordNotStructError()
+
+		return
+	}
+
+	for _, option = range strings.Split(options, wordOptionSeparator) {
+		if option == "" {
+			continue
+		}
+
+		keyValue = strings.SplitN(option, wordOptionValueSeparator, 2)
+
+		if len(keyValue) != 2 ||
+			!layout.parseOption(keyValue[0], keyValue[1]) {
+			e = validation.NewWordWithInvalidOptionError(option)
+
+			return
+		}
+	}
+
+	m.appendPadding(layout, field.Name)
+
+	return
+}
+
+func (m *FormatMetadata) appendWord(word wordMetadata, name string,
+	index []int,
+) (
+	e error,
+) {
+	m.appendPadding(word.layout, name)
+
+	if word.mac.algorithm != "" {
+		e = m.appendMAC(word, name)
 		if e != nil {
 			return
 		}
+	}
 
-		format.lengthInBytes += format.words[i].lengthInBytes
+	m.words = append(m.words,
+		formatWordMetadata{
+			wordMetadata: word,
+			index:        index,
+			offset:       m.lengthInBytes,
+		},
+	)
+
+	m.lengthInBytes += word.lengthInBytes
+
+	return
+}
+
+// appendPadding pads the format to the alignment of the next word
+// or nested format.
+func (m *FormatMetadata) appendPadding(layout layoutMetadata, name string) {
+	var (
+		length int
+	)
+
+	length = layout.paddingLength(m.lengthInBytes)
+
+	if length == 0 {
+		return
 	}
 
+	m.paddings = append(m.paddings,
+		paddingMetadata{
+			name:   name,
+			offset: m.lengthInBytes,
+			length: length,
+			zero:   layout.zeroPadding,
+		},
+	)
+
+	m.lengthInBytes += length
+
+	return
+}
+
+// appendMAC records a MAC word at the current length of the format.
+func (m *FormatMetadata) appendMAC(word wordMetadata, wordName string) (
+	e error,
+) {
+	var (
+		mac MACMetadata
+	)
+
+	mac = MACMetadata{
+		Algorithm: word.mac.algorithm,
+		WordName:  wordName,
+		Offset:    m.lengthInBytes,
+		Length:    word.lengthInBytes,
+		Start:     0,
+		End:       m.lengthInBytes,
+	}
+
+	if word.mac.ranged {
+		mac.Start = word.mac.start
+		mac.End = word.mac.end
+	}
+
+	if mac.Start < 0 || mac.End > mac.Offset {
+		e = validation.NewWordWithInvalidOptionError(
+			fmt.Sprintf(wordOptionMACRange+"="+macRangeFormat,
+				mac.Start, mac.End,
+			),
+		)
+
+		e.(validation.WordError).SetWordName(wordName)
+
+		return
+	}
+
+	m.macs = append(m.macs, mac)
+
 	return
 }
 
 func (m FormatMetadata) Marshal(reflection reflect.Value) (bytes []byte) {
 	// Merge byte slices marshalled from words,
-	// in the order they appear in the format.
+	// at their offsets in the format, leaving padding zero.
 
 	var (
-		copyIndex int
-		i         int
-		word      wordMetadata
-		wordBytes []byte
+		uuid uuidMetadata
+		word formatWordMetadata
 	)
 
 	bytes = make([]byte, m.lengthInBytes)
 
-	for i, word = range m.words {
-		wordBytes = word.marshal(
-			reflection.Field(i),
+	for _, word = range m.words {
+		copy(bytes[word.offset:],
+			word.marshal(
+				reflection.FieldByIndex(word.index),
+			),
 		)
+	}
 
-		copy(bytes[copyIndex:], wordBytes)
-
-		copyIndex += word.lengthInBytes
+	for _, uuid = range m.uuids {
+		uuid.marshal(bytes[uuid.offset:uuid.offset+uuidLengthInBytes],
+			reflection.FieldByIndex(uuid.index),
+		)
 	}
 
 	return
@@ -80,27 +321,54 @@ func (m FormatMetadata) Marshal(reflection reflect.Value) (bytes []byte) {
 
 func (m FormatMetadata) Unmarshal(bytes []byte, reflection reflect.Value) {
 	var (
-		i    int
-		j    int
-		k    int
-		word wordMetadata
+		uuid uuidMetadata
+		word formatWordMetadata
 	)
 
-	for i, word = range m.words {
-		k = j + word.lengthInBytes
+	for _, word = range m.words {
+		word.unmarshal(bytes[word.offset:word.offset+word.lengthInBytes],
+			reflection.FieldByIndex(word.index),
+		)
+	}
+
+	for _, uuid = range m.uuids {
+		uuid.unmarshal(bytes[uuid.offset:uuid.offset+uuidLengthInBytes],
+			reflection.FieldByIndex(uuid.index),
+		)
+	}
+
+	return
+}
 
-		if k < wordLengthUpperLimitBytes {
-			j = 0
+// VerifyPadding returns an error for each padding declared "padding=zero"
+// that is not zero.
+func (m FormatMetadata) VerifyPadding(bytes []byte) (
+	errors []validation.WordError,
+) {
+	var (
+		e       validation.WordError
+		i       int
+		padding paddingMetadata
+	)
 
-		} else {
-			j = k - wordLengthUpperLimitBytes
+	for _, padding = range m.paddings {
+		if !padding.zero {
+			continue
 		}
 
-		word.unmarshal(bytes[j:k],
-			reflection.Field(i),
-		)
+		for i = padding.offset; i < padding.offset+padding.length; i++ {
+			if bytes[i] != 0 {
+				e = validation.NewPaddingError(i,
+					8*i+bits.LeadingZeros8(bytes[i]),
+				)
+
+				e.SetWordName(padding.name)
 
-		j = k
+				errors = append(errors, e)
+
+				break
+			}
+		}
 	}
 
 	return
@@ -0,0 +1,182 @@
+package metadata
+
+import (
+	"strconv"
+)
+
+// Words and nested formats may be aligned to boundaries
+// relative to the start of the outermost format,
+// with zero padding inserted before them on Marshal:
+//
+//	Word2 `word:"32,align=4"`
+//	Section `format:"align=8,padding=zero"`
+//
+// Padding is skipped on Unmarshal,
+// unless declared "padding=zero", in which case it is verified to be zero.
+
+const (
+	layoutOptionAlign   = "align"
+	layoutOptionPadding = "padding"
+
+	layoutPaddingSkip = "skip"
+	layoutPaddingZero = "zero"
+)
+
+type layoutMetadata struct {
+	alignment   int
+	zeroPadding bool
+}
+
+// parseOption parses an option of the layout of a word or nested format,
+// returning false if the option is unsupported or malformed.
+func (m *layoutMetadata) parseOption(key, value string) (ok bool) {
+	var (
+		e         error
+		alignment int
+	)
+
+	switch key {
+	case layoutOptionAlign:
+		alignment, e = strconv.Atoi(value)
+
+		// Alignments are powers of two.
+
+		if e != nil || alignment < 1 || alignment&(alignment-1) != 0 {
+			return
+		}
+
+		m.alignment = alignment
+
+	case layoutOptionPadding:
+		switch value {
+		case layoutPaddingSkip:
+			m.zeroPadding = false
+
+		case layoutPaddingZero:
+			m.zeroPadding = true
+
+		default:
+			return
+		}
+
+	default:
+		return
+	}
+
+	ok = true
+
+	return
+}
+
+// paddingLength returns the length of padding needed at an offset.
+func (m layoutMetadata) paddingLength(offset int) (length int) {
+	if m.alignment <= 1 {
+		return
+	}
+
+	length = (m.alignment - offset%m.alignment) % m.alignment
+
+	return
+}
+
+type paddingMetadata struct {
+	name   string
+	offset int
+	length int
+	zero   bool
+}
+
+// WordLayout describes where a word lies in a format
+// and how its bit fields are packed, for tools generating code.
+type WordLayout struct {
+	// Index is the index sequence of the word-struct in the format-struct,
+	// as for reflect.Value.FieldByIndex.
+	Index []int
+
+	// Offset and Length are in number of bytes.
+	Offset int
+	Length int
+
+	// Order is the permutation of the bytes of the word, if not big-endian.
+	Order []int
+
+	BitFields []BitFieldLayout
+}
+
+type BitFieldLayout struct {
+	// Offset is from the least significant bit of the word;
+	// Offset and Length are in number of bits.
+	Offset uint
+	Length uint
+
+	// Order is the permutation of the bytes of the bit field,
+	// if not big-endian.
+	Order []int
+}
+
+// Words returns the layout of the words of a format,
+// including those of nested formats, in order.
+func (m FormatMetadata) Words() (words []WordLayout) {
+	var (
+		bitField bitFieldMetadata
+		i        int
+		word     formatWordMetadata
+	)
+
+	words = make([]WordLayout, len(m.words))
+
+	for i, word = range m.words {
+		words[i] = WordLayout{
+			Index:     word.index,
+			Offset:    word.offset,
+			Length:    word.lengthInBytes,
+			Order:     word.order,
+			BitFields: make([]BitFieldLayout, 0, len(word.bitFields)),
+		}
+
+		for _, bitField = range word.bitFields {
+			words[i].BitFields = append(words[i].BitFields,
+				BitFieldLayout{
+					Offset: uint(bitField.offset),
+					Length: bitField.length,
+					Order:  bitField.order,
+				},
+			)
+		}
+	}
+
+	return
+}
+
+// UUIDLayout describes where a UUID field lies in a format.
+type UUIDLayout struct {
+	// Index is the index sequence of the field in the format-struct,
+	// as for reflect.Value.FieldByIndex.
+	Index []int
+
+	// Offset is in number of bytes.
+	Offset int
+
+	// GUID is true if the first three groups of the UUID are little-endian.
+	GUID bool
+}
+
+// UUIDs returns the layout of the UUID fields of a format,
+// including those of nested formats, in order.
+func (m FormatMetadata) UUIDs() (uuids []UUIDLayout) {
+	var (
+		uuid uuidMetadata
+	)
+
+	for _, uuid = range m.uuids {
+		uuids = append(uuids,
+			UUIDLayout{
+				Index:  uuid.index,
+				Offset: uuid.offset,
+				GUID:   uuid.guid,
+			},
+		)
+	}
+
+	return
+}
@@ -0,0 +1,37 @@
+package metadata
+
+// A MAC word holds a keyed message authentication code
+// computed over a range of bytes of its format and truncated to its length:
+//
+//	Word3 `word:"64,mac=hmac-sha256"`
+//	Word3 `word:"64,mac=aes-cmac,range=4-12"`
+//
+// The range is of byte offsets from the start of the format,
+// inclusive of the first and exclusive of the second,
+// and is by default that of all preceding words.
+// It must end at or before the start of the MAC word.
+
+const (
+	macRangeFormat = "%d-%d"
+)
+
+type macMetadata struct {
+	algorithm string
+	ranged    bool
+	start     int
+	end       int
+}
+
+// MACMetadata describes a MAC word of a format.
+type MACMetadata struct {
+	Algorithm string
+	WordName  string
+	Offset    int
+	Length    int
+	Start     int
+	End       int
+}
+
+func (m FormatMetadata) MACs() []MACMetadata {
+	return m.macs
+}
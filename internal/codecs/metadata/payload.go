@@ -0,0 +1,132 @@
+package metadata
+
+import (
+	"io"
+	"reflect"
+
+	"github.com/encodingx/binary/internal/validation"
+)
+
+// Payload is a field of variable length following the words of a format,
+// streamed rather than held in memory.
+// It is declared last in a format-struct, tagged with a key "payload"
+// and the name of a bit field holding its length in number of bytes:
+//
+//	Payload binary.Payload `payload:"Length"`
+//
+// Marshal and Unmarshal ignore payloads;
+// a Decoder sets them to readers limited to their length,
+// and an Encoder streams that many bytes from them.
+type Payload struct {
+	io.Reader
+}
+
+const (
+	payloadTagKey = "payload"
+)
+
+var (
+	payloadType = reflect.TypeOf(Payload{})
+)
+
+type payloadMetadata struct {
+	name        string
+	index       []int
+	lengthName  string
+	lengthIndex []int
+}
+
+// appendPayload records the payload of a format-struct,
+// which must be its last field and not that of a nested format.
+func (m *FormatMetadata) appendPayload(reflection reflect.Type,
+	index []int, lengthName string,
+) (
+	e error,
+) {
+	var (
+		field reflect.StructField
+	)
+
+	field = reflection.Field(index[len(index)-1])
+
+	if len(index) != 1 ||
+		index[0] != reflection.NumField()-1 ||
+		field.Type != payloadType ||
+		lengthName == "" {
+		e = validation.NewFormatWithInvalidPayloadError(field.Name)
+
+		return
+	}
+
+	m.payload = &payloadMetadata{
+		name:       field.Name,
+		index:      index,
+		lengthName: lengthName,
+	}
+
+	return
+}
+
+// resolvePayloadLength finds the bit field holding the length of the payload
+// among the words of a format-struct.
+func (m *FormatMetadata) resolvePayloadLength(reflection reflect.Type) (
+	e error,
+) {
+	var (
+		bitField reflect.StructField
+		ok       bool
+		word     formatWordMetadata
+	)
+
+	for _, word = range m.words {
+		bitField, ok = reflection.FieldByIndex(word.index).Type.FieldByName(
+			m.payload.lengthName,
+		)
+		if !ok {
+			continue
+		}
+
+		switch bitField.Type.Kind() {
+		case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			fallthrough
+
+		case reflect.Uint:
+			m.payload.lengthIndex = append(
+				append([]int(nil), word.index...),
+				bitField.Index...,
+			)
+
+			return
+		}
+
+		break
+	}
+
+	e = validation.NewFormatWithInvalidPayloadError(m.payload.name)
+
+	return
+}
+
+func (m FormatMetadata) HasPayload() bool {
+	return m.payload != nil
+}
+
+// PayloadLength returns the length of the payload of a format-struct
+// in number of bytes, as held by its length bit field.
+func (m FormatMetadata) PayloadLength(reflection reflect.Value) uint64 {
+	return reflection.FieldByIndex(m.payload.lengthIndex).Uint()
+}
+
+func (m FormatMetadata) Payload(reflection reflect.Value) io.Reader {
+	return reflection.FieldByIndex(m.payload.index).Interface().(Payload).Reader
+}
+
+func (m FormatMetadata) SetPayload(reflection reflect.Value, reader io.Reader) {
+	reflection.FieldByIndex(m.payload.index).Set(
+		reflect.ValueOf(
+			Payload{Reader: reader},
+		),
+	)
+
+	return
+}
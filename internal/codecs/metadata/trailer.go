@@ -0,0 +1,79 @@
+package metadata
+
+import (
+	"reflect"
+
+	"github.com/encodingx/binary/internal/validation"
+)
+
+// Trailer is implemented by pointers to a field of variable length
+// following the words of a format, such as a list of TLVs.
+// It is declared last in a format-struct,
+// optionally tagged with a key "trailer" and an empty value:
+//
+//	Options tlv.TLVListField `trailer:""`
+//
+// Marshal appends the bytes of a trailer to those of the words,
+// and Unmarshal passes it every byte following them.
+// UnmarshalTrailer should leave the trailer untouched on error.
+type Trailer interface {
+	MarshalTrailer() ([]byte, error)
+	UnmarshalTrailer([]byte) error
+}
+
+const (
+	trailerTagKey = "trailer"
+)
+
+var (
+	trailerType = reflect.TypeOf((*Trailer)(nil)).Elem()
+)
+
+type trailerMetadata struct {
+	name  string
+	index []int
+}
+
+// isTrailer reports whether pointers to a field implement Trailer.
+func isTrailer(field reflect.StructField) bool {
+	return reflect.PtrTo(field.Type).Implements(trailerType)
+}
+
+// appendTrailer records the trailer of a format-struct,
+// which must be its last field and not that of a nested format.
+func (m *FormatMetadata) appendTrailer(reflection reflect.Type, index []int) (
+	e error,
+) {
+	var (
+		field reflect.StructField
+	)
+
+	field = reflection.Field(index[len(index)-1])
+
+	if len(index) != 1 ||
+		index[0] != reflection.NumField()-1 ||
+		!isTrailer(field) {
+		e = validation.NewFormatWithInvalidTrailerError(field.Name)
+
+		return
+	}
+
+	m.trailer = &trailerMetadata{
+		name:  field.Name,
+		index: index,
+	}
+
+	return
+}
+
+func (m FormatMetadata) HasTrailer() bool {
+	return m.trailer != nil
+}
+
+func (m FormatMetadata) TrailerName() string {
+	return m.trailer.name
+}
+
+func (m FormatMetadata) Trailer(reflection reflect.Value) Trailer {
+	return reflection.FieldByIndex(m.trailer.index).Addr().Interface().(Trailer)
+}
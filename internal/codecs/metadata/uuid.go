@@ -0,0 +1,223 @@
+package metadata
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/encodingx/binary/internal/validation"
+)
+
+// UUID is a 128-bit field holding a UUID (RFC 9562) or GUID.
+// It is declared in a format-struct as a field of type binary.UUID,
+// optionally tagged with a key "uuid" and a value naming its layout,
+// followed by options of alignment as for words:
+//
+//	ID       binary.UUID                       // big-endian, as in RFC 9562
+//	TypeGUID binary.UUID `uuid:"guid"`         // mixed-endian
+//	Entry    binary.UUID `uuid:"guid,align=8"`
+//
+// The layout "guid" stores the first three groups little-endian
+// and the last two big-endian, as GPT and SMB do.
+// The bytes of a UUID value are in the order of its string form,
+// whatever its layout.
+type UUID [16]byte
+
+const (
+	uuidTagKey = "uuid"
+
+	uuidLayoutRFC9562 = "rfc9562"
+	uuidLayoutGUID    = "guid"
+
+	uuidLengthInBytes = 16
+
+	// The string form of a UUID groups its bytes 4-2-2-2-6,
+	// separated by hyphens.
+	uuidStringFormat = "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx"
+)
+
+var (
+	uuidType = reflect.TypeOf(UUID{})
+
+	// The layout of a GUID reverses the bytes of its first three groups,
+	// and is its own inverse.
+	uuidGUIDOrder = [uuidLengthInBytes]int{
+		3, 2, 1, 0, 5, 4, 7, 6, 8, 9, 10, 11, 12, 13, 14, 15,
+	}
+)
+
+type uuidMetadata struct {
+	index  []int
+	offset int
+	guid   bool
+}
+
+func (u UUID) String() string {
+	var (
+		buffer [len(uuidStringFormat)]byte
+	)
+
+	hex.Encode(buffer[0:8], u[0:4])
+	hex.Encode(buffer[9:13], u[4:6])
+	hex.Encode(buffer[14:18], u[6:8])
+	hex.Encode(buffer[19:23], u[8:10])
+	hex.Encode(buffer[24:36], u[10:16])
+
+	buffer[8] = '-'
+	buffer[13] = '-'
+	buffer[18] = '-'
+	buffer[23] = '-'
+
+	return string(buffer[:])
+}
+
+// ParseUUID parses the string form of a UUID,
+// in either case and optionally enclosed in braces, as GUIDs often are.
+func ParseUUID(s string) (u UUID, e error) {
+	const (
+		parseError = "" +
+			"ParseUUID error: " +
+			"A UUID should be of the form " + uuidStringFormat + ". " +
+			"Argument to ParseUUID \"%s\" is not."
+	)
+
+	var (
+		digits string
+		i      int
+		value  string
+	)
+
+	value = s
+
+	if strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}") {
+		value = value[1 : len(value)-1]
+	}
+
+	if len(value) != len(uuidStringFormat) {
+		e = fmt.Errorf(parseError, s)
+
+		return
+	}
+
+	for i = 0; i < len(uuidStringFormat); i++ {
+		if (uuidStringFormat[i] == '-') != (value[i] == '-') {
+			e = fmt.Errorf(parseError, s)
+
+			return
+		}
+	}
+
+	digits = strings.ReplaceAll(value, "-", "")
+
+	_, e = hex.Decode(u[:], []byte(digits))
+	if e != nil {
+		u = UUID{}
+
+		e = fmt.Errorf(parseError, s)
+
+		return
+	}
+
+	return
+}
+
+// appendUUID records a UUID field at the current length of the format,
+// padded to its alignment.
+func (m *FormatMetadata) appendUUID(field reflect.StructField, index []int,
+	options string,
+) (
+	e error,
+) {
+	var (
+		guid     bool
+		keyValue []string
+		layout   layoutMetadata
+		option   string
+	)
+
+	defer func() {
+		if e != nil {
+			e.(validation.WordError).SetWordName(field.Name)
+		}
+	}()
+
+	if field.Type != uuidType {
+		e = validation.NewUUIDOfUnsupportedTypeError(field.Type.String())
+
+		return
+	}
+
+	for _, option = range strings.Split(options, wordOptionSeparator) {
+		switch option {
+		case "", uuidLayoutRFC9562:
+			guid = false
+
+			continue
+
+		case uuidLayoutGUID:
+			guid = true
+
+			continue
+		}
+
+		keyValue = strings.SplitN(option, wordOptionValueSeparator, 2)
+
+		if len(keyValue) != 2 ||
+			!layout.parseOption(keyValue[0], keyValue[1]) {
+			e = validation.NewWordWithInvalidOptionError(option)
+
+			return
+		}
+	}
+
+	m.appendPadding(layout, field.Name)
+
+	m.uuids = append(m.uuids,
+		uuidMetadata{
+			index:  index,
+			offset: m.lengthInBytes,
+			guid:   guid,
+		},
+	)
+
+	m.lengthInBytes += uuidLengthInBytes
+
+	return
+}
+
+func (m uuidMetadata) marshal(bytes []byte, reflection reflect.Value) {
+	var (
+		i int
+	)
+
+	for i = 0; i < uuidLengthInBytes; i++ {
+		if m.guid {
+			bytes[i] = byte(reflection.Index(uuidGUIDOrder[i]).Uint())
+
+			continue
+		}
+
+		bytes[i] = byte(reflection.Index(i).Uint())
+	}
+
+	return
+}
+
+func (m uuidMetadata) unmarshal(bytes []byte, reflection reflect.Value) {
+	var (
+		i int
+	)
+
+	for i = 0; i < uuidLengthInBytes; i++ {
+		if m.guid {
+			reflection.Index(uuidGUIDOrder[i]).SetUint(uint64(bytes[i]))
+
+			continue
+		}
+
+		reflection.Index(i).SetUint(uint64(bytes[i]))
+	}
+
+	return
+}
@@ -4,7 +4,10 @@ import (
 	"encoding/binary"
 	"fmt"
 	"reflect"
+	"strings"
 
+	"github.com/encodingx/binary/internal/mac"
+	"github.com/encodingx/binary/internal/order"
 	"github.com/encodingx/binary/internal/validation"
 )
 
@@ -12,6 +15,89 @@ type wordMetadata struct {
 	bitFields     []bitFieldMetadata
 	lengthInBits  uint
 	lengthInBytes int
+	mac           macMetadata
+	layout        layoutMetadata
+	order         order.Permutation
+}
+
+// Options follow the length of a word in its struct tag,
+// separated by commas, each of the form key=value.
+const (
+	wordOptionSeparator      = ","
+	wordOptionValueSeparator = "="
+
+	wordOptionMAC      = "mac"
+	wordOptionMACRange = "range"
+
+	// The order of the bytes of a word, if not big-endian,
+	// named with letters from "a", the most significant byte
+	// (e.g. `word:"32,order=cdab"`).
+	wordOptionOrder = "order"
+)
+
+func (m *wordMetadata) parseOptions(options []string) (e error) {
+	var (
+		keyValue []string
+		ok       bool
+		option   string
+	)
+
+	for _, option = range options {
+		keyValue = strings.SplitN(option, wordOptionValueSeparator, 2)
+
+		if len(keyValue) != 2 {
+			e = validation.NewWordWithInvalidOptionError(option)
+
+			return
+		}
+
+		switch keyValue[0] {
+		case wordOptionMAC:
+			if !mac.Supported(keyValue[1]) {
+				e = validation.NewWordWithInvalidOptionError(option)
+
+				return
+			}
+
+			m.mac.algorithm = keyValue[1]
+
+		case wordOptionMACRange:
+			_, e = fmt.Sscanf(keyValue[1], macRangeFormat,
+				&m.mac.start, &m.mac.end,
+			)
+			if e != nil || m.mac.start < 0 || m.mac.start >= m.mac.end {
+				e = validation.NewWordWithInvalidOptionError(option)
+
+				return
+			}
+
+			m.mac.ranged = true
+
+		case wordOptionOrder:
+			m.order, ok = order.Parse(keyValue[1])
+
+			if !ok || len(m.order) != m.lengthInBytes {
+				e = validation.NewWordWithInvalidOptionError(option)
+
+				return
+			}
+
+		default:
+			if !m.layout.parseOption(keyValue[0], keyValue[1]) {
+				e = validation.NewWordWithInvalidOptionError(option)
+
+				return
+			}
+		}
+	}
+
+	if m.mac.ranged && m.mac.algorithm == "" {
+		e = validation.NewWordWithInvalidOptionError(wordOptionMACRange)
+
+		return
+	}
+
+	return
 }
 
 func newWordMetadataFromStructFieldReflection(reflection reflect.StructField) (
@@ -27,11 +113,10 @@ func newWordMetadataFromStructFieldReflection(reflection reflect.StructField) (
 	)
 
 	var (
-		offset       uint
+		options      []string
+		sum          uint
 		wordLength   uint
 		wordLengthOK bool
-
-		i int
 	)
 
 	defer func() {
@@ -52,8 +137,10 @@ func newWordMetadataFromStructFieldReflection(reflection reflect.StructField) (
 		return
 	}
 
+	options = strings.Split(reflection.Tag.Get(tagKey), wordOptionSeparator)
+
 	_, e = fmt.Sscanf(
-		reflection.Tag.Get(tagKey),
+		options[0],
 		tagValueFormat,
 		&wordLength,
 	)
@@ -80,46 +167,114 @@ func newWordMetadataFromStructFieldReflection(reflection reflect.StructField) (
 	}
 
 	word = wordMetadata{
-		bitFields: make([]bitFieldMetadata,
-			reflection.Type.NumField(),
-		),
 		lengthInBits:  wordLength,
 		lengthInBytes: int(wordLength / wordLengthFactor),
 	}
 
-	offset = wordLength
+	e = word.parseOptions(options[1:])
+	if e != nil {
+		return
+	}
+
+	word.bitFields, sum, e = newBitFieldsMetadataFromTypeReflection(
+		reflection.Type, wordLength,
+	)
+	if e != nil {
+		return
+	}
 
-	for i = 0; i < reflection.Type.NumField(); i++ {
-		word.bitFields[i], e = newBitFieldMetadataFromStructFieldReflection(
-			reflection.Type.Field(i),
+	if sum != wordLength {
+		e = validation.NewWordOfLengthNotEqualToSumOfLengthsOfBitFieldsError(
+			wordLength,
+			sum,
+		)
+
+		return
+	}
+
+	return
+}
+
+// newBitFieldsMetadataFromTypeReflection returns the metadata of bit fields
+// of a word-struct, with offsets counted down from the word length,
+// and the sum of their lengths.
+func newBitFieldsMetadataFromTypeReflection(reflection reflect.Type,
+	wordLength uint,
+) (
+	bitFields []bitFieldMetadata, sum uint, e error,
+) {
+	var (
+		i int
+	)
+
+	bitFields = make([]bitFieldMetadata,
+		reflection.NumField(),
+	)
+
+	for i = 0; i < reflection.NumField(); i++ {
+		bitFields[i], e = newBitFieldMetadataFromStructFieldReflection(
+			reflection.Field(i),
 		)
 		if e != nil {
 			return
 		}
 
-		offset -= word.bitFields[i].length
+		sum += bitFields[i].length
 
-		word.bitFields[i].offset = uint64(offset)
+		if sum <= wordLength {
+			bitFields[i].offset = uint64(wordLength - sum)
+		}
 	}
 
-	if offset != 0 {
-		e = validation.NewWordOfLengthNotEqualToSumOfLengthsOfBitFieldsError(
-			wordLength,
-			wordLength-offset,
-		)
+	return
+}
 
-		return
+func (m wordMetadata) marshal(reflection reflect.Value) (bytes []byte) {
+	var (
+		wordUint64 uint64
+	)
+
+	wordUint64 = m.pack(reflection)
+
+	if m.order != nil {
+		wordUint64 = m.order.Permute(wordUint64)
 	}
 
+	bytes = make([]byte, wordLengthUpperLimitBytes)
+
+	binary.BigEndian.PutUint64(bytes, wordUint64)
+
+	bytes = bytes[wordLengthUpperLimitBytes-m.lengthInBytes:]
+
 	return
 }
 
-func (m wordMetadata) marshal(reflection reflect.Value) (bytes []byte) {
+func (m wordMetadata) unmarshal(bytes []byte, reflection reflect.Value) {
+	var (
+		wordBytes  []byte
+		wordUint64 uint64
+	)
+
+	wordBytes = make([]byte, wordLengthUpperLimitBytes)
+
+	copy(wordBytes[wordLengthUpperLimitBytes-len(bytes):], bytes)
+
+	wordUint64 = binary.BigEndian.Uint64(wordBytes)
+
+	if m.order != nil {
+		wordUint64 = m.order.Restore(wordUint64)
+	}
+
+	m.unpack(wordUint64, reflection)
+
+	return
+}
+
+func (m wordMetadata) pack(reflection reflect.Value) (wordUint64 uint64) {
 	var (
 		bitField       bitFieldMetadata
 		bitFieldUint64 uint64
 		i              int
-		wordUint64     uint64
 	)
 
 	for i, bitField = range m.bitFields {
@@ -130,37 +285,93 @@ func (m wordMetadata) marshal(reflection reflect.Value) (bytes []byte) {
 		wordUint64 = wordUint64 | bitFieldUint64
 	}
 
-	bytes = make([]byte, wordLengthUpperLimitBytes)
+	return
+}
 
-	binary.BigEndian.PutUint64(bytes, wordUint64)
+func (m wordMetadata) unpack(wordUint64 uint64, reflection reflect.Value) {
+	var (
+		i int
+	)
 
-	bytes = bytes[wordLengthUpperLimitBytes-m.lengthInBytes:]
+	for i = 0; i < len(m.bitFields); i++ {
+		m.bitFields[i].unpack(wordUint64,
+			reflection.Field(i),
+		)
+	}
 
 	return
 }
 
-func (m wordMetadata) unmarshal(bytes []byte, reflection reflect.Value) {
-	var (
-		bitFieldBytes []byte
-		i             int
+// WordMetadata describes a word-struct standing alone,
+// as a register value rather than part of a format.
+type WordMetadata struct {
+	word wordMetadata
+}
+
+func NewWordMetadataFromTypeReflection(reflection reflect.Type) (
+	word WordMetadata, e error,
+) {
+	// The length of a word standing alone is the sum of the lengths
+	// of its bit fields, since there is no struct tag to declare it.
+
+	const (
+		wordLengthFactor     = 8
+		wordLengthLowerLimit = 8
+		wordLengthUpperLimit = 64
 	)
 
-	for i = 0; i < len(m.bitFields); i++ {
-		if len(bytes) < wordLengthUpperLimitBytes {
-			bitFieldBytes = make([]byte, wordLengthUpperLimitBytes)
+	var (
+		sum uint
 
-			copy(bitFieldBytes[wordLengthUpperLimitBytes-len(bytes):],
-				bytes,
-			)
+		i int
+	)
 
-		} else {
-			bitFieldBytes = bytes
+	defer func() {
+		if e != nil {
+			e.(validation.WordError).SetWordName(reflection.Name())
 		}
+	}()
 
-		m.bitFields[i].unmarshal(bitFieldBytes,
-			reflection.Field(i),
-		)
+	if reflection.NumField() == 0 {
+		e = validation.NewWordWithNoBitFieldsError()
+
+		return
 	}
 
+	word.word.bitFields, sum, e = newBitFieldsMetadataFromTypeReflection(
+		reflection, wordLengthUpperLimit,
+	)
+	if e != nil {
+		return
+	}
+
+	if sum%wordLengthFactor != 0 ||
+		sum < wordLengthLowerLimit ||
+		sum > wordLengthUpperLimit {
+		e = validation.NewWordOfIncompatibleLengthError(sum)
+
+		return
+	}
+
+	// Offsets were counted down from the upper limit;
+	// shift them down to the length of the word.
+
+	word.word.lengthInBits = sum
+	word.word.lengthInBytes = int(sum / wordLengthFactor)
+
+	for i = range word.word.bitFields {
+		word.word.bitFields[i].offset -= uint64(wordLengthUpperLimit - sum)
+	}
+
+	return
+}
+
+func (m WordMetadata) Pack(reflection reflect.Value) uint64 {
+	return m.word.pack(reflection)
+}
+
+func (m WordMetadata) Unpack(wordUint64 uint64, reflection reflect.Value) {
+	m.word.unpack(wordUint64, reflection)
+
 	return
 }
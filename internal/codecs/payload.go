@@ -0,0 +1,46 @@
+package codecs
+
+import (
+	"io"
+	"reflect"
+
+	"github.com/encodingx/binary/internal/codecs/metadata"
+)
+
+type Payload = metadata.Payload
+
+func (c CodecOperation) HasPayload() bool {
+	return c.format.HasPayload()
+}
+
+func (c CodecOperation) PayloadLength() uint64 {
+	return c.format.PayloadLength(c.valueReflection)
+}
+
+// PayloadLengthOf returns the length of the payload held by the words in
+// bytes, whether or not they unmarshal into the format-struct.
+func (c CodecOperation) PayloadLengthOf(bytes []byte) uint64 {
+	var (
+		reflection reflect.Value
+	)
+
+	reflection = reflect.New(c.valueReflection.Type()).Elem()
+
+	c.format.Unmarshal(bytes, reflection)
+
+	return c.format.PayloadLength(reflection)
+}
+
+func (c CodecOperation) Payload() io.Reader {
+	return c.format.Payload(c.valueReflection)
+}
+
+func (c CodecOperation) SetPayload(reader io.Reader) {
+	c.format.SetPayload(c.valueReflection, reader)
+
+	return
+}
+
+func (c CodecOperation) LengthInBytes() int {
+	return c.format.LengthInBytes()
+}
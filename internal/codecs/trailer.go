@@ -0,0 +1,57 @@
+package codecs
+
+import (
+	"github.com/encodingx/binary/internal/codecs/metadata"
+	"github.com/encodingx/binary/internal/validation"
+)
+
+type Trailer = metadata.Trailer
+
+func (c CodecOperation) HasTrailer() bool {
+	return c.format.HasTrailer()
+}
+
+func (c CodecOperation) marshalTrailer() (bytes []byte, e error) {
+	bytes, e = c.format.Trailer(c.valueReflection).MarshalTrailer()
+	if e != nil {
+		e = c.newTrailerNotCodableError(e)
+
+		return
+	}
+
+	return
+}
+
+func (c CodecOperation) unmarshalTrailer(bytes []byte) (e error) {
+	e = c.format.Trailer(c.valueReflection).UnmarshalTrailer(bytes)
+	if e != nil {
+		e = c.newTrailerNotCodableError(e)
+
+		return
+	}
+
+	return
+}
+
+func (c CodecOperation) newTrailerNotCodableError(cause error) (
+	e validation.WordError,
+) {
+	e = validation.NewTrailerNotCodableError(cause)
+
+	e.SetFormatName(c.valueReflection.Type().String())
+	e.SetWordName(c.format.TrailerName())
+
+	return
+}
+
+// NewFormatWithInvalidTrailerError is returned by an Encoder or Decoder
+// given a format-struct with a trailer, which runs to the end of its bytes.
+func (c CodecOperation) NewFormatWithInvalidTrailerError() (
+	e validation.FormatError,
+) {
+	e = validation.NewFormatWithInvalidTrailerError(c.format.TrailerName())
+
+	e.SetFormatName(c.valueReflection.Type().String())
+
+	return
+}
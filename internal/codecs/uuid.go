@@ -0,0 +1,11 @@
+package codecs
+
+import (
+	"github.com/encodingx/binary/internal/codecs/metadata"
+)
+
+type UUID = metadata.UUID
+
+func ParseUUID(s string) (UUID, error) {
+	return metadata.ParseUUID(s)
+}
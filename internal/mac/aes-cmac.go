@@ -0,0 +1,146 @@
+package mac
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+)
+
+// AESCMAC returns the AES-CMAC of data under a key of 16, 24 or 32 bytes.
+//
+// Reference: Section 2.4 "MAC Generation Algorithm" of
+// RFC 4493 The AES-CMAC Algorithm
+// https://datatracker.ietf.org/doc/html/rfc4493#section-2.4
+func AESCMAC(key, data []byte) (tag []byte, e error) {
+	var (
+		block     cipher.Block
+		complete  bool
+		i         int
+		k1        []byte
+		k2        []byte
+		last      []byte
+		n         int
+		blockSize int
+	)
+
+	block, e = aes.NewCipher(key)
+	if e != nil {
+		return
+	}
+
+	blockSize = block.BlockSize()
+
+	k1, k2 = aesCMACSubkeys(block)
+
+	// > Step 2.  n := ceil(len/const_Bsize);
+	// > Step 3.  if n = 0
+	// >          then
+	// >               n := 1;
+	// >               flag := false;
+	// >          else
+	// >               if len mod const_Bsize is 0
+	// >               then flag := true;
+	// >               else flag := false;
+
+	n = (len(data) + blockSize - 1) / blockSize
+
+	if n == 0 {
+		n = 1
+
+	} else {
+		complete = len(data)%blockSize == 0
+	}
+
+	// > Step 4.  if flag is true
+	// >          then M_last := M_n XOR K1;
+	// >          else M_last := padding(M_n) XOR K2;
+
+	last = make([]byte, blockSize)
+
+	copy(last, data[(n-1)*blockSize:])
+
+	if complete {
+		aesCMACXOR(last, k1)
+
+	} else {
+		last[len(data)-(n-1)*blockSize] = 0x80
+
+		aesCMACXOR(last, k2)
+	}
+
+	// > Step 5.  X := const_Zero;
+	// > Step 6.  for i := 1 to n-1 do
+	// >              begin
+	// >                Y := X XOR M_i;
+	// >                X := AES-128(K,Y);
+	// >              end
+	// >          Y := M_last XOR X;
+	// >          T := AES-128(K,Y);
+
+	tag = make([]byte, blockSize)
+
+	for i = 0; i < n-1; i++ {
+		aesCMACXOR(tag, data[i*blockSize:(i+1)*blockSize])
+
+		block.Encrypt(tag, tag)
+	}
+
+	aesCMACXOR(tag, last)
+
+	block.Encrypt(tag, tag)
+
+	return
+}
+
+// aesCMACSubkeys implements Section 2.3 "Subkey Generation Algorithm".
+func aesCMACSubkeys(block cipher.Block) (k1, k2 []byte) {
+	var (
+		l []byte
+	)
+
+	l = make([]byte, block.BlockSize())
+
+	block.Encrypt(l, l)
+
+	k1 = aesCMACDouble(l)
+	k2 = aesCMACDouble(k1)
+
+	return
+}
+
+// aesCMACDouble shifts a block left by one bit,
+// XORing const_Rb into it if the most significant bit was set.
+func aesCMACDouble(in []byte) (out []byte) {
+	const (
+		rb = 0x87
+	)
+
+	var (
+		i int
+	)
+
+	out = make([]byte, len(in))
+
+	for i = 0; i < len(in)-1; i++ {
+		out[i] = in[i]<<1 | in[i+1]>>7
+	}
+
+	out[len(in)-1] = in[len(in)-1] << 1
+
+	if in[0]&0x80 != 0 {
+		out[len(in)-1] ^= rb
+	}
+
+	return
+}
+
+func aesCMACXOR(dst, src []byte) {
+	var (
+		i int
+	)
+
+	for i = range dst {
+		dst[i] ^= src[i]
+	}
+
+	return
+}
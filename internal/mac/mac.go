@@ -0,0 +1,56 @@
+// Package mac computes the message authentication codes
+// that may be declared over the words of a format.
+package mac
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"hash"
+)
+
+const (
+	AlgorithmAESCMAC    = "aes-cmac"
+	AlgorithmHMACSHA256 = "hmac-sha256"
+)
+
+var (
+	ErrAlgorithmUnsupported = errors.New(
+		"The algorithm of a MAC should be one of \"aes-cmac\" and " +
+			"\"hmac-sha256\". " +
+			"The algorithm is not supported.",
+	)
+)
+
+func Supported(algorithm string) bool {
+	switch algorithm {
+	case AlgorithmAESCMAC, AlgorithmHMACSHA256:
+		return true
+	}
+
+	return false
+}
+
+// Compute returns the untruncated MAC of data under a key.
+func Compute(algorithm string, key, data []byte) (tag []byte, e error) {
+	var (
+		hash hash.Hash
+	)
+
+	switch algorithm {
+	case AlgorithmAESCMAC:
+		tag, e = AESCMAC(key, data)
+
+	case AlgorithmHMACSHA256:
+		hash = hmac.New(sha256.New, key)
+
+		hash.Write(data)
+
+		tag = hash.Sum(nil)
+
+	default:
+		e = ErrAlgorithmUnsupported
+	}
+
+	return
+}
@@ -0,0 +1,77 @@
+package mac
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAESCMAC(t *testing.T) {
+	// Reference: Section 4 "Test Vectors" of
+	// RFC 4493 The AES-CMAC Algorithm
+
+	var (
+		e       error
+		key     []byte
+		message []byte
+		tag     []byte
+		vectors map[int]string
+		length  int
+	)
+
+	key, _ = hex.DecodeString("2b7e151628aed2a6abf7158809cf4f3c")
+
+	message, _ = hex.DecodeString("" +
+		"6bc1bee22e409f96e93d7e117393172a" +
+		"ae2d8a571e03ac9c9eb76fac45af8e51" +
+		"30c81c46a35ce411e5fbc1191a0a52ef" +
+		"f69f2445df4f9b17ad2b417be66c3710",
+	)
+
+	vectors = map[int]string{
+		0:  "bb1d6929e95937287fa37d129b756746",
+		16: "070a16b46b4d4144f79bdd9dd04a287c",
+		40: "dfa66747de9ae63030ca32611497c827",
+		64: "51f0bebf7e3b9d92fc49741779363cfe",
+	}
+
+	for length = range vectors {
+		tag, e = AESCMAC(key, message[:length])
+
+		assert.Nil(t, e)
+
+		assert.Equal(t,
+			vectors[length], hex.EncodeToString(tag),
+		)
+	}
+}
+
+func TestHMACSHA256(t *testing.T) {
+	// Reference: Section 4.2 "Test Case 1" of
+	// RFC 4231 Identifiers and Test Vectors for HMAC-SHA-224, HMAC-SHA-256,
+	// HMAC-SHA-384, and HMAC-SHA-512
+
+	var (
+		e   error
+		key []byte
+		tag []byte
+	)
+
+	key, _ = hex.DecodeString("0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b")
+
+	tag, e = Compute(AlgorithmHMACSHA256, key, []byte("Hi There"))
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		"b0344c61d8db38535ca8afceaf0bf12b881dc200c9833da726e9376c2e32cff7",
+		hex.EncodeToString(tag),
+	)
+
+	_, e = Compute("hmac-md5", key, nil)
+
+	assert.Equal(t,
+		ErrAlgorithmUnsupported, e,
+	)
+}
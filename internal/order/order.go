@@ -0,0 +1,154 @@
+// Package order permutes the bytes of words and bit fields
+// that are not stored in big-endian order,
+// such as values spanning several 16-bit registers.
+package order
+
+import (
+	stdlib "encoding/binary"
+)
+
+// A Permutation names the order of the bytes of a value on the wire
+// with letters, "a" being the most significant byte:
+// "abcd" is big-endian, "dcba" little-endian,
+// "cdab" word-swapped and "badc" byte-swapped.
+// Byte i on the wire is byte Permutation[i] of the value in big-endian order.
+type Permutation []int
+
+const (
+	lengthUpperLimit = 8
+)
+
+// Parse returns the permutation named by a string of letters,
+// returning false unless each of the first len(s) letters occurs once.
+func Parse(s string) (p Permutation, ok bool) {
+	var (
+		i    int
+		seen [lengthUpperLimit]bool
+	)
+
+	if len(s) == 0 || len(s) > lengthUpperLimit {
+		return
+	}
+
+	p = make(Permutation, len(s))
+
+	for i = 0; i < len(s); i++ {
+		if s[i] < 'a' || int(s[i]-'a') >= len(s) || seen[s[i]-'a'] {
+			p = nil
+
+			return
+		}
+
+		seen[s[i]-'a'] = true
+
+		p[i] = int(s[i] - 'a')
+	}
+
+	ok = true
+
+	return
+}
+
+func (p Permutation) String() string {
+	var (
+		bytes []byte
+		i     int
+	)
+
+	bytes = make([]byte, len(p))
+
+	for i = range p {
+		bytes[i] = byte('a' + p[i])
+	}
+
+	return string(bytes)
+}
+
+// Permute returns a value whose big-endian bytes are those of another value,
+// of len(p) bytes, in the order of the permutation.
+func (p Permutation) Permute(value uint64) (permuted uint64) {
+	var (
+		i int
+	)
+
+	for i = range p {
+		permuted = permuted<<8 | p.byte(value, p[i])
+	}
+
+	return
+}
+
+// Restore reverses Permute.
+func (p Permutation) Restore(permuted uint64) (value uint64) {
+	var (
+		i int
+	)
+
+	for i = range p {
+		value = value | p.byte(permuted, i)<<(8*(len(p)-1-p[i]))
+	}
+
+	return
+}
+
+// byte returns byte i of a value of len(p) bytes in big-endian order.
+func (p Permutation) byte(value uint64, i int) uint64 {
+	return value >> (8 * (len(p) - 1 - i)) & 0xff
+}
+
+// ByteOrder implements the ByteOrder interface of encoding/binary
+// with a permutation for each length of value.
+type ByteOrder struct {
+	name string
+	p16  Permutation
+	p32  Permutation
+	p64  Permutation
+}
+
+var (
+	// WordSwapped orders 16-bit words from least to most significant,
+	// and bytes within words from most to least significant (CDAB).
+	WordSwapped = ByteOrder{
+		name: "WordSwapped",
+		p16:  Permutation{0, 1},
+		p32:  Permutation{2, 3, 0, 1},
+		p64:  Permutation{6, 7, 4, 5, 2, 3, 0, 1},
+	}
+
+	// ByteSwapped orders 16-bit words from most to least significant,
+	// and bytes within words from least to most significant (BADC).
+	ByteSwapped = ByteOrder{
+		name: "ByteSwapped",
+		p16:  Permutation{1, 0},
+		p32:  Permutation{1, 0, 3, 2},
+		p64:  Permutation{1, 0, 3, 2, 5, 4, 7, 6},
+	}
+)
+
+func (o ByteOrder) Uint16(bytes []byte) uint16 {
+	return uint16(o.p16.Restore(uint64(stdlib.BigEndian.Uint16(bytes))))
+}
+
+func (o ByteOrder) Uint32(bytes []byte) uint32 {
+	return uint32(o.p32.Restore(uint64(stdlib.BigEndian.Uint32(bytes))))
+}
+
+func (o ByteOrder) Uint64(bytes []byte) uint64 {
+	return o.p64.Restore(stdlib.BigEndian.Uint64(bytes))
+}
+
+func (o ByteOrder) PutUint16(bytes []byte, value uint16) {
+	stdlib.BigEndian.PutUint16(bytes, uint16(o.p16.Permute(uint64(value))))
+}
+
+func (o ByteOrder) PutUint32(bytes []byte, value uint32) {
+	stdlib.BigEndian.PutUint32(bytes, uint32(o.p32.Permute(uint64(value))))
+}
+
+func (o ByteOrder) PutUint64(bytes []byte, value uint64) {
+	stdlib.BigEndian.PutUint64(bytes, o.p64.Permute(value))
+}
+
+func (o ByteOrder) String() string {
+	return o.name
+}
@@ -0,0 +1,106 @@
+package order
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	var (
+		ok          bool
+		permutation Permutation
+		s           string
+	)
+
+	for _, s = range []string{"abcd", "dcba", "cdab", "badc", "ghefcdab"} {
+		permutation, ok = Parse(s)
+
+		assert.True(t, ok)
+
+		assert.Equal(t,
+			s, permutation.String(),
+		)
+	}
+
+	for _, s = range []string{"", "abce", "abca", "ABCD", "abcdefghi"} {
+		_, ok = Parse(s)
+
+		assert.False(t, ok)
+	}
+}
+
+func TestPermute(t *testing.T) {
+	const (
+		value = 0x11223344
+	)
+
+	var (
+		expected    map[string]uint64
+		permutation Permutation
+		s           string
+	)
+
+	expected = map[string]uint64{
+		"abcd": 0x11223344,
+		"dcba": 0x44332211,
+		"cdab": 0x33441122,
+		"badc": 0x22114433,
+	}
+
+	for s = range expected {
+		permutation, _ = Parse(s)
+
+		assert.Equal(t,
+			expected[s], permutation.Permute(value),
+		)
+
+		assert.Equal(t,
+			uint64(value), permutation.Restore(permutation.Permute(value)),
+		)
+	}
+}
+
+func TestByteOrder(t *testing.T) {
+	var (
+		bytes []byte
+	)
+
+	bytes = make([]byte, 8)
+
+	WordSwapped.PutUint32(bytes, 0x11223344)
+
+	assert.Equal(t,
+		[]byte{0x33, 0x44, 0x11, 0x22}, bytes[:4],
+	)
+
+	assert.Equal(t,
+		uint32(0x11223344), WordSwapped.Uint32(bytes),
+	)
+
+	ByteSwapped.PutUint64(bytes, 0x1122334455667788)
+
+	assert.Equal(t,
+		[]byte{0x22, 0x11, 0x44, 0x33, 0x66, 0x55, 0x88, 0x77}, bytes,
+	)
+
+	assert.Equal(t,
+		uint64(0x1122334455667788), ByteSwapped.Uint64(bytes),
+	)
+
+	WordSwapped.PutUint64(bytes, 0x1122334455667788)
+
+	assert.Equal(t,
+		[]byte{0x77, 0x88, 0x55, 0x66, 0x33, 0x44, 0x11, 0x22}, bytes,
+	)
+
+	ByteSwapped.PutUint16(bytes, 0x1122)
+
+	assert.Equal(t,
+		uint16(0x1122), ByteSwapped.Uint16(bytes),
+	)
+
+	assert.Equal(t,
+		[]byte{0x22, 0x11}, bytes[:2],
+	)
+}
@@ -96,6 +96,41 @@ func (e *bitFieldOfLengthOverflowingTypeError) Error() (s string) {
 	return
 }
 
+type bitFieldWithInvalidOptionError struct {
+	DefaultBitFieldError
+	option string
+}
+
+func NewBitFieldWithInvalidOptionError(option string) (
+	e *bitFieldWithInvalidOptionError,
+) {
+	e = &bitFieldWithInvalidOptionError{
+		option: option,
+	}
+
+	return
+}
+
+func (e *bitFieldWithInvalidOptionError) Error() (s string) {
+	const (
+		format = "" +
+			"Options following the length of a bit field in its struct tag " +
+			"should be supported and well-formed " +
+			"(e.g. `bitfield:\"32,order=cdab\"`). " +
+			"Argument to %s points to a format-struct \"%s\" " +
+			"nesting a word-struct \"%s\" " +
+			"that has a bit field \"%s\" " +
+			"with an invalid option \"%s\"."
+	)
+
+	s = fmt.Sprintf(format,
+		e.functionName, e.formatName, e.wordName, e.bitFieldName,
+		e.option,
+	)
+
+	return
+}
+
 type bitFieldWithMalformedTagError struct {
 	DefaultBitFieldError
 }
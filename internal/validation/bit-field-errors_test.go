@@ -76,6 +76,39 @@ func TestBitFieldOfLengthOverflowingTypeError(t *testing.T) {
 	)
 }
 
+func TestBitFieldWithInvalidOptionError(t *testing.T) {
+	const (
+		option = "order=cdab"
+
+		errorMessage = "" +
+			"Options following the length of a bit field in its struct tag " +
+			"should be supported and well-formed " +
+			"(e.g. `bitfield:\"32,order=cdab\"`). " +
+			"Argument to Marshal points to a format-struct \"Format\" " +
+			"nesting a word-struct \"Word\" " +
+			"that has a bit field \"BitField\" " +
+			"with an invalid option \"order=cdab\"."
+	)
+
+	var (
+		e BitFieldError
+	)
+
+	e = NewBitFieldWithInvalidOptionError(option)
+
+	e.SetFunctionName(functionName)
+
+	e.SetFormatName(formatName)
+
+	e.SetWordName(wordName)
+
+	e.SetBitFieldName(bitFieldName)
+
+	assert.Equal(t,
+		errorMessage, e.Error(),
+	)
+}
+
 func TestBitFieldWithMalformedTagError(t *testing.T) {
 	const (
 		errorMessage = "" +
@@ -1,7 +1,9 @@
 package validation
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 )
 
 type FormatError interface {
@@ -77,3 +79,223 @@ func (e *lengthOfByteSliceNotEqualToFormatLengthError) Error() (s string) {
 
 	return
 }
+
+type formatWithInvalidPayloadError struct {
+	DefaultFormatError
+	payloadName string
+}
+
+func NewFormatWithInvalidPayloadError(payloadName string) (
+	e *formatWithInvalidPayloadError,
+) {
+	e = &formatWithInvalidPayloadError{
+		payloadName: payloadName,
+	}
+
+	return
+}
+
+func (e *formatWithInvalidPayloadError) Error() (s string) {
+	const (
+		format = "" +
+			"A payload should be the last field of a format-struct, " +
+			"of type binary.Payload, tagged with a key \"payload\" " +
+			"and a value naming an unsigned bit field " +
+			"holding the length of the payload in number of bytes " +
+			"(e.g. `payload:\"Length\"`). " +
+			"Argument to %s points to a format-struct \"%s\" " +
+			"that has an invalid payload \"%s\"."
+	)
+
+	s = fmt.Sprintf(format,
+		e.functionName, e.formatName,
+		e.payloadName,
+	)
+
+	return
+}
+
+type formatWithInvalidTrailerError struct {
+	DefaultFormatError
+	trailerName string
+}
+
+func NewFormatWithInvalidTrailerError(trailerName string) (
+	e *formatWithInvalidTrailerError,
+) {
+	e = &formatWithInvalidTrailerError{
+		trailerName: trailerName,
+	}
+
+	return
+}
+
+func (e *formatWithInvalidTrailerError) Error() (s string) {
+	const (
+		format = "" +
+			"A trailer should be the last field of a format-struct " +
+			"not nested in another, of a type the pointers to which " +
+			"implement binary.Trailer, " +
+			"and should not be streamed by an Encoder or Decoder " +
+			"(e.g. `trailer:\"\"`). " +
+			"Argument to %s points to a format-struct \"%s\" " +
+			"that has an invalid trailer \"%s\"."
+	)
+
+	s = fmt.Sprintf(format,
+		e.functionName, e.formatName,
+		e.trailerName,
+	)
+
+	return
+}
+
+// DecodingError is returned by Unmarshal decoding on a best-effort basis
+// when words of a format-struct fail to decode, listing the error of each.
+// Words covered by a MAC that does not match are nonetheless decoded.
+type DecodingError struct {
+	DefaultFormatError
+	errors  []WordError
+	wrapped []error
+}
+
+func NewDecodingError(errors []WordError) (e *DecodingError) {
+	e = &DecodingError{
+		errors: errors,
+	}
+
+	return
+}
+
+func (e *DecodingError) SetFunctionName(functionName string) {
+	var (
+		wordError WordError
+	)
+
+	e.DefaultFormatError.SetFunctionName(functionName)
+
+	for _, wordError = range e.errors {
+		wordError.SetFunctionName(functionName)
+	}
+
+	return
+}
+
+func (e *DecodingError) SetFormatName(formatName string) {
+	var (
+		wordError WordError
+	)
+
+	e.DefaultFormatError.SetFormatName(formatName)
+
+	for _, wordError = range e.errors {
+		wordError.SetFormatName(formatName)
+	}
+
+	return
+}
+
+// Errors returns the error of each word failing to decode.
+func (e *DecodingError) Errors() (wordErrors []error) {
+	var (
+		wordError WordError
+	)
+
+	if e.wrapped != nil {
+		wordErrors = append(wordErrors, e.wrapped...)
+
+		return
+	}
+
+	for _, wordError = range e.errors {
+		wordErrors = append(wordErrors, wordError)
+	}
+
+	return
+}
+
+// WrapErrors replaces the error of each word failing to decode,
+// as returned by Errors, with the result of wrap applied to it.
+func (e *DecodingError) WrapErrors(wrap func(error) error) {
+	var (
+		wordError  error
+		wordErrors []error
+	)
+
+	wordErrors = e.Errors()
+
+	e.wrapped = make([]error, 0, len(wordErrors))
+
+	for _, wordError = range wordErrors {
+		e.wrapped = append(e.wrapped, wrap(wordError))
+	}
+
+	return
+}
+
+// Unwrap returns the error of each word failing to decode,
+// for errors.Is and errors.As from Go 1.20.
+func (e *DecodingError) Unwrap() []error {
+	return e.Errors()
+}
+
+// Is reports whether the error of any word failing to decode matches target,
+// for errors.Is before Go 1.20.
+func (e *DecodingError) Is(target error) (is bool) {
+	var (
+		wordError error
+	)
+
+	for _, wordError = range e.Errors() {
+		if errors.Is(wordError, target) {
+			is = true
+
+			return
+		}
+	}
+
+	return
+}
+
+// As finds the first error of a word failing to decode that matches target,
+// for errors.As before Go 1.20.
+func (e *DecodingError) As(target interface{}) (as bool) {
+	var (
+		wordError error
+	)
+
+	for _, wordError = range e.Errors() {
+		if errors.As(wordError, target) {
+			as = true
+
+			return
+		}
+	}
+
+	return
+}
+
+func (e *DecodingError) Error() (s string) {
+	const (
+		format = "" +
+			"Each word of a format-struct should decode. " +
+			"Argument to %s points to a format-struct \"%s\" " +
+			"that has %d word(s) failing to decode: %s."
+	)
+
+	var (
+		names     []string
+		wordError WordError
+	)
+
+	for _, wordError = range e.errors {
+		names = append(names, fmt.Sprintf("%q", wordError.WordName()))
+	}
+
+	s = fmt.Sprintf(format,
+		e.functionName, e.formatName,
+		len(e.errors), strings.Join(names, ", "),
+	)
+
+	return
+}
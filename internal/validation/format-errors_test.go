@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -62,3 +63,122 @@ func TestLengthOfByteSliceNotEqualToFormatLengthError(t *testing.T) {
 		errorMessage, e.Error(),
 	)
 }
+
+func TestFormatWithInvalidPayloadError(t *testing.T) {
+	const (
+		payloadName = "Payload"
+
+		errorMessage = "" +
+			"A payload should be the last field of a format-struct, " +
+			"of type binary.Payload, tagged with a key \"payload\" " +
+			"and a value naming an unsigned bit field " +
+			"holding the length of the payload in number of bytes " +
+			"(e.g. `payload:\"Length\"`). " +
+			"Argument to Marshal points to a format-struct \"Format\" " +
+			"that has an invalid payload \"Payload\"."
+	)
+
+	var (
+		e FormatError
+	)
+
+	e = NewFormatWithInvalidPayloadError(payloadName)
+
+	e.SetFunctionName(functionName)
+
+	e.SetFormatName(formatName)
+
+	assert.Equal(t,
+		errorMessage, e.Error(),
+	)
+}
+
+func TestFormatWithInvalidTrailerError(t *testing.T) {
+	const (
+		trailerName = "Options"
+
+		errorMessage = "" +
+			"A trailer should be the last field of a format-struct " +
+			"not nested in another, of a type the pointers to which " +
+			"implement binary.Trailer, " +
+			"and should not be streamed by an Encoder or Decoder " +
+			"(e.g. `trailer:\"\"`). " +
+			"Argument to Marshal points to a format-struct \"Format\" " +
+			"that has an invalid trailer \"Options\"."
+	)
+
+	var (
+		e FormatError
+	)
+
+	e = NewFormatWithInvalidTrailerError(trailerName)
+
+	e.SetFunctionName(functionName)
+
+	e.SetFormatName(formatName)
+
+	assert.Equal(t,
+		errorMessage, e.Error(),
+	)
+}
+
+func TestDecodingError(t *testing.T) {
+	const (
+		errorMessage = "" +
+			"Each word of a format-struct should decode. " +
+			"Argument to Marshal points to a format-struct \"Format\" " +
+			"that has 2 word(s) failing to decode: \"Word\", \"MAC\"."
+	)
+
+	var (
+		authenticationError        WordError
+		authenticationErrorPointer *AuthenticationError
+		e                          FormatError
+		paddingError               WordError
+	)
+
+	paddingError = NewPaddingError(1, 15)
+
+	paddingError.SetWordName(wordName)
+
+	authenticationError = NewAuthenticationError("hmac-sha256", 4)
+
+	authenticationError.SetWordName("MAC")
+
+	e = NewDecodingError(
+		[]WordError{paddingError, authenticationError},
+	)
+
+	e.SetFunctionName(functionName)
+
+	e.SetFormatName(formatName)
+
+	assert.Equal(t,
+		errorMessage, e.Error(),
+	)
+
+	assert.Equal(t,
+		[]error{paddingError, authenticationError},
+		e.(*DecodingError).Errors(),
+	)
+
+	assert.True(t,
+		errors.Is(e, authenticationError),
+	)
+
+	assert.True(t,
+		errors.As(e, &authenticationErrorPointer),
+	)
+
+	assert.Equal(t,
+		4, authenticationErrorPointer.Offset(),
+	)
+
+	assert.Equal(t,
+		"Padding declared \"padding=zero\" should be zero. "+
+			"Argument to Marshal points to a format-struct \"Format\" "+
+			"that has a word \"Word\" "+
+			"preceded by padding with a non-zero byte at offset 1.",
+		paddingError.Error(),
+	)
+}
@@ -7,6 +7,7 @@ import (
 type WordError interface {
 	FormatError
 	SetWordName(string)
+	WordName() string
 }
 
 type DefaultWordError struct {
@@ -20,6 +21,173 @@ func (e *DefaultWordError) SetWordName(wordName string) {
 	return
 }
 
+func (e *DefaultWordError) WordName() string {
+	return e.wordName
+}
+
+// AuthenticationError is returned by Unmarshal
+// when a MAC word does not match the MAC computed over its byte range.
+type AuthenticationError struct {
+	DefaultWordError
+	algorithm string
+	offset    int
+}
+
+func NewAuthenticationError(algorithm string, offset int) (
+	e *AuthenticationError,
+) {
+	e = &AuthenticationError{
+		algorithm: algorithm,
+		offset:    offset,
+	}
+
+	return
+}
+
+func (e *AuthenticationError) Algorithm() string {
+	return e.algorithm
+}
+
+// Offset returns the offset of the MAC word in its format.
+func (e *AuthenticationError) Offset() int {
+	return e.offset
+}
+
+// BitOffset returns the offset of the MAC word in its format
+// in number of bits.
+func (e *AuthenticationError) BitOffset() int {
+	return e.offset * 8
+}
+
+func (e *AuthenticationError) Error() (s string) {
+	const (
+		format = "" +
+			"A MAC word should match the MAC computed over its byte range. " +
+			"Argument to %s points to a format-struct \"%s\" " +
+			"that has a word \"%s\" " +
+			"not matching the %s MAC of the bytes."
+	)
+
+	s = fmt.Sprintf(format,
+		e.functionName, e.formatName, e.wordName,
+		e.algorithm,
+	)
+
+	return
+}
+
+// PaddingError is returned by Unmarshal
+// when padding declared "padding=zero" before a word is not zero.
+type PaddingError struct {
+	DefaultWordError
+	offset    int
+	bitOffset int
+}
+
+func NewPaddingError(offset, bitOffset int) (e *PaddingError) {
+	e = &PaddingError{
+		offset:    offset,
+		bitOffset: bitOffset,
+	}
+
+	return
+}
+
+// Offset returns the offset of the first non-zero byte of padding.
+func (e *PaddingError) Offset() int {
+	return e.offset
+}
+
+// BitOffset returns the offset of the first non-zero bit of padding
+// in number of bits, counting from the most significant bit of a byte.
+func (e *PaddingError) BitOffset() int {
+	return e.bitOffset
+}
+
+func (e *PaddingError) Error() (s string) {
+	const (
+		format = "" +
+			"Padding declared \"padding=zero\" should be zero. " +
+			"Argument to %s points to a format-struct \"%s\" " +
+			"that has a word \"%s\" " +
+			"preceded by padding with a non-zero byte at offset %d."
+	)
+
+	s = fmt.Sprintf(format,
+		e.functionName, e.formatName, e.wordName,
+		e.offset,
+	)
+
+	return
+}
+
+type trailerNotCodableError struct {
+	DefaultWordError
+	cause error
+}
+
+func NewTrailerNotCodableError(cause error) (e *trailerNotCodableError) {
+	e = &trailerNotCodableError{
+		cause: cause,
+	}
+
+	return
+}
+
+func (e *trailerNotCodableError) Error() (s string) {
+	const (
+		format = "" +
+			"The trailer of a format-struct should marshal and unmarshal. " +
+			"Argument to %s points to a format-struct \"%s\" " +
+			"that has a trailer \"%s\" " +
+			"that cannot be marshalled or unmarshalled: %v"
+	)
+
+	s = fmt.Sprintf(format,
+		e.functionName, e.formatName, e.wordName,
+		e.cause,
+	)
+
+	return
+}
+
+func (e *trailerNotCodableError) Unwrap() error {
+	return e.cause
+}
+
+type uuidOfUnsupportedTypeError struct {
+	DefaultWordError
+	uuidType string
+}
+
+func NewUUIDOfUnsupportedTypeError(uuidType string) (
+	e *uuidOfUnsupportedTypeError,
+) {
+	e = &uuidOfUnsupportedTypeError{
+		uuidType: uuidType,
+	}
+
+	return
+}
+
+func (e *uuidOfUnsupportedTypeError) Error() (s string) {
+	const (
+		format = "" +
+			"A field tagged with a key \"uuid\" " +
+			"should be of type binary.UUID. " +
+			"Argument to %s points to a format-struct \"%s\" " +
+			"that has a UUID \"%s\" " +
+			"of unsupported type \"%s\"."
+	)
+
+	s = fmt.Sprintf(format,
+		e.functionName, e.formatName, e.wordName,
+		e.uuidType,
+	)
+
+	return
+}
+
 type wordNotStructError struct {
 	DefaultWordError
 }
@@ -111,6 +279,136 @@ func (e *wordOfLengthNotEqualToSumOfLengthsOfBitFieldsError) Error() (s string)
 	return
 }
 
+type wordWithInvalidOptionError struct {
+	DefaultWordError
+	option string
+}
+
+func NewWordWithInvalidOptionError(option string) (
+	e *wordWithInvalidOptionError,
+) {
+	e = &wordWithInvalidOptionError{
+		option: option,
+	}
+
+	return
+}
+
+func (e *wordWithInvalidOptionError) Error() (s string) {
+	const (
+		format = "" +
+			"Options following the length of a word in its struct tag " +
+			"should be supported and well-formed " +
+			"(e.g. `word:\"64,mac=hmac-sha256\"`). " +
+			"Argument to %s points to a format-struct \"%s\" " +
+			"that has a word \"%s\" " +
+			"with an invalid option \"%s\"."
+	)
+
+	s = fmt.Sprintf(format,
+		e.functionName, e.formatName, e.wordName,
+		e.option,
+	)
+
+	return
+}
+
+type wordWithMACAndNoKeyProviderError struct {
+	DefaultWordError
+}
+
+func NewWordWithMACAndNoKeyProviderError() *wordWithMACAndNoKeyProviderError {
+	return new(wordWithMACAndNoKeyProviderError)
+}
+
+func (e *wordWithMACAndNoKeyProviderError) Error() string {
+	const (
+		format = "" +
+			"A format-struct with a MAC word should be marshalled " +
+			"and unmarshalled by a codec with a key provider. " +
+			"Argument to %s points to a format-struct \"%s\" " +
+			"that has a MAC word \"%s\", " +
+			"but the codec has no key provider."
+	)
+
+	return fmt.Sprintf(format, e.functionName, e.formatName, e.wordName)
+}
+
+type wordWithMACKeyUnavailableError struct {
+	DefaultWordError
+	cause error
+}
+
+func NewWordWithMACKeyUnavailableError(cause error) (
+	e *wordWithMACKeyUnavailableError,
+) {
+	e = &wordWithMACKeyUnavailableError{
+		cause: cause,
+	}
+
+	return
+}
+
+func (e *wordWithMACKeyUnavailableError) Error() (s string) {
+	const (
+		format = "" +
+			"The key provider of a codec should provide a valid key " +
+			"for each MAC word. " +
+			"Argument to %s points to a format-struct \"%s\" " +
+			"that has a MAC word \"%s\" " +
+			"for which no valid key is available: %v"
+	)
+
+	s = fmt.Sprintf(format,
+		e.functionName, e.formatName, e.wordName,
+		e.cause,
+	)
+
+	return
+}
+
+func (e *wordWithMACKeyUnavailableError) Unwrap() error {
+	return e.cause
+}
+
+type wordWithMACNotComputableError struct {
+	DefaultWordError
+	algorithm string
+	cause     error
+}
+
+func NewWordWithMACNotComputableError(algorithm string, cause error) (
+	e *wordWithMACNotComputableError,
+) {
+	e = &wordWithMACNotComputableError{
+		algorithm: algorithm,
+		cause:     cause,
+	}
+
+	return
+}
+
+func (e *wordWithMACNotComputableError) Error() (s string) {
+	const (
+		format = "" +
+			"The key provided for a MAC word should suit its algorithm. " +
+			"Argument to %s points to a format-struct \"%s\" " +
+			"that has a MAC word \"%s\" " +
+			"the %s MAC of which cannot be computed: %v"
+	)
+
+	s = fmt.Sprintf(format,
+		e.functionName, e.formatName, e.wordName,
+		e.algorithm, e.cause,
+	)
+
+	return
+}
+
+func (e *wordWithMACNotComputableError) Unwrap() error {
+	return e.cause
+}
+
 type wordWithMalformedTagError struct {
 	DefaultWordError
 }
@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -10,6 +11,35 @@ const (
 	wordName = "Word"
 )
 
+func TestUUIDOfUnsupportedTypeError(t *testing.T) {
+	const (
+		uuidType = "[16]uint8"
+
+		errorMessage = "" +
+			"A field tagged with a key \"uuid\" " +
+			"should be of type binary.UUID. " +
+			"Argument to Marshal points to a format-struct \"Format\" " +
+			"that has a UUID \"Word\" " +
+			"of unsupported type \"[16]uint8\"."
+	)
+
+	var (
+		e WordError
+	)
+
+	e = NewUUIDOfUnsupportedTypeError(uuidType)
+
+	e.SetFunctionName(functionName)
+
+	e.SetFormatName(formatName)
+
+	e.SetWordName(wordName)
+
+	assert.Equal(t,
+		errorMessage, e.Error(),
+	)
+}
+
 func TestWordNotStructError(t *testing.T) {
 	const (
 		errorMessage = "" +
@@ -182,3 +212,234 @@ func TestWordWithNoStructTagError(t *testing.T) {
 		errorMessage, e.Error(),
 	)
 }
+
+func TestAuthenticationError(t *testing.T) {
+	const (
+		algorithm = "hmac-sha256"
+		offset    = 8
+
+		errorMessage = "" +
+			"A MAC word should match the MAC computed over its byte range. " +
+			"Argument to Marshal points to a format-struct \"Format\" " +
+			"that has a word \"Word\" " +
+			"not matching the hmac-sha256 MAC of the bytes."
+	)
+
+	var (
+		e WordError
+	)
+
+	e = NewAuthenticationError(algorithm, offset)
+
+	e.SetFunctionName(functionName)
+
+	e.SetFormatName(formatName)
+
+	e.SetWordName(wordName)
+
+	assert.Equal(t,
+		errorMessage, e.Error(),
+	)
+
+	assert.Equal(t,
+		algorithm, e.(*AuthenticationError).Algorithm(),
+	)
+
+	assert.Equal(t,
+		offset, e.(*AuthenticationError).Offset(),
+	)
+
+	assert.Equal(t,
+		offset*8, e.(*AuthenticationError).BitOffset(),
+	)
+}
+
+func TestPaddingError(t *testing.T) {
+	const (
+		offset    = 6
+		bitOffset = 53
+
+		errorMessage = "" +
+			"Padding declared \"padding=zero\" should be zero. " +
+			"Argument to Unmarshal points to a format-struct \"Format\" " +
+			"that has a word \"Word\" " +
+			"preceded by padding with a non-zero byte at offset 6."
+	)
+
+	var (
+		e WordError
+	)
+
+	e = NewPaddingError(offset, bitOffset)
+
+	e.SetFunctionName("Unmarshal")
+
+	e.SetFormatName(formatName)
+
+	e.SetWordName(wordName)
+
+	assert.Equal(t,
+		errorMessage, e.Error(),
+	)
+
+	assert.Equal(t,
+		offset, e.(*PaddingError).Offset(),
+	)
+
+	assert.Equal(t,
+		bitOffset, e.(*PaddingError).BitOffset(),
+	)
+}
+
+func TestWordWithInvalidOptionError(t *testing.T) {
+	const (
+		option = "mac=md5"
+
+		errorMessage = "" +
+			"Options following the length of a word in its struct tag " +
+			"should be supported and well-formed " +
+			"(e.g. `word:\"64,mac=hmac-sha256\"`). " +
+			"Argument to Marshal points to a format-struct \"Format\" " +
+			"that has a word \"Word\" " +
+			"with an invalid option \"mac=md5\"."
+	)
+
+	var (
+		e WordError
+	)
+
+	e = NewWordWithInvalidOptionError(option)
+
+	e.SetFunctionName(functionName)
+
+	e.SetFormatName(formatName)
+
+	e.SetWordName(wordName)
+
+	assert.Equal(t,
+		errorMessage, e.Error(),
+	)
+}
+
+func TestWordWithMACAndNoKeyProviderError(t *testing.T) {
+	const (
+		errorMessage = "" +
+			"A format-struct with a MAC word should be marshalled " +
+			"and unmarshalled by a codec with a key provider. " +
+			"Argument to Marshal points to a format-struct \"Format\" " +
+			"that has a MAC word \"Word\", " +
+			"but the codec has no key provider."
+	)
+
+	var (
+		e WordError
+	)
+
+	e = NewWordWithMACAndNoKeyProviderError()
+
+	e.SetFunctionName(functionName)
+
+	e.SetFormatName(formatName)
+
+	e.SetWordName(wordName)
+
+	assert.Equal(t,
+		errorMessage, e.Error(),
+	)
+}
+
+func TestWordWithMACKeyUnavailableError(t *testing.T) {
+	const (
+		errorMessage = "" +
+			"The key provider of a codec should provide a valid key " +
+			"for each MAC word. " +
+			"Argument to Marshal points to a format-struct \"Format\" " +
+			"that has a MAC word \"Word\" " +
+			"for which no valid key is available: key revoked"
+	)
+
+	var (
+		cause error = errors.New("key revoked")
+		e     WordError
+	)
+
+	e = NewWordWithMACKeyUnavailableError(cause)
+
+	e.SetFunctionName(functionName)
+
+	e.SetFormatName(formatName)
+
+	e.SetWordName(wordName)
+
+	assert.Equal(t,
+		errorMessage, e.Error(),
+	)
+
+	assert.True(t,
+		errors.Is(e, cause),
+	)
+}
+
+func TestWordWithMACNotComputableError(t *testing.T) {
+	const (
+		errorMessage = "" +
+			"The key provided for a MAC word should suit its algorithm. " +
+			"Argument to Marshal points to a format-struct \"Format\" " +
+			"that has a MAC word \"Word\" " +
+			"the aes-cmac MAC of which cannot be computed: invalid key size"
+	)
+
+	var (
+		cause error = errors.New("invalid key size")
+		e     WordError
+	)
+
+	e = NewWordWithMACNotComputableError("aes-cmac", cause)
+
+	e.SetFunctionName(functionName)
+
+	e.SetFormatName(formatName)
+
+	e.SetWordName(wordName)
+
+	assert.Equal(t,
+		errorMessage, e.Error(),
+	)
+
+	assert.True(t,
+		errors.Is(e, cause),
+	)
+}
+
+func TestTrailerNotCodableError(t *testing.T) {
+	const (
+		errorMessage = "" +
+			"The trailer of a format-struct should marshal and unmarshal. " +
+			"Argument to Marshal points to a format-struct \"Format\" " +
+			"that has a trailer \"Word\" " +
+			"that cannot be marshalled or unmarshalled: truncated"
+	)
+
+	var (
+		cause error
+		e     WordError
+	)
+
+	cause = errors.New("truncated")
+
+	e = NewTrailerNotCodableError(cause)
+
+	e.SetFunctionName(functionName)
+
+	e.SetFormatName(formatName)
+
+	e.SetWordName(wordName)
+
+	assert.Equal(t,
+		errorMessage, e.Error(),
+	)
+
+	assert.True(t,
+		errors.Is(e, cause),
+	)
+}
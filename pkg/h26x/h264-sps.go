@@ -0,0 +1,555 @@
+package h26x
+
+import (
+	"github.com/encodingx/binary"
+)
+
+type H264SPSProfileLevelFormat struct {
+	// Reference: Section 7.3.2.1.1 "Sequence parameter set data syntax" of
+	// ITU-T H.264
+
+	// >   seq_parameter_set_data( ) {                      C   Descriptor
+	// >     profile_idc                                    0   u(8)
+	// >     constraint_set0_flag                           0   u(1)
+	// >     constraint_set1_flag                           0   u(1)
+	// >     constraint_set2_flag                           0   u(1)
+	// >     constraint_set3_flag                           0   u(1)
+	// >     constraint_set4_flag                           0   u(1)
+	// >     constraint_set5_flag                           0   u(1)
+	// >     reserved_zero_2bits /* equal to 0 */           0   u(2)
+	// >     level_idc                                      0   u(8)
+	// >     seq_parameter_set_id                           0   ue(v)
+	// >     ...
+	// >   }
+	//
+	// The fixed-length fields up to level_idc are byte-aligned,
+	// and so are decoded as a word. The Exp-Golomb coded fields that follow
+	// are read by an H26xBitReader.
+
+	H264SPSProfileLevelFormatWord0 `word:"24"`
+}
+
+type H264SPSProfileLevelFormatWord0 struct {
+	ProfileIDC         uint8 `bitfield:"8"`
+	ConstraintSet0Flag bool  `bitfield:"1"`
+	ConstraintSet1Flag bool  `bitfield:"1"`
+	ConstraintSet2Flag bool  `bitfield:"1"`
+	ConstraintSet3Flag bool  `bitfield:"1"`
+	ConstraintSet4Flag bool  `bitfield:"1"`
+	ConstraintSet5Flag bool  `bitfield:"1"`
+	ReservedZero2Bits  uint8 `bitfield:"2"`
+	LevelIDC           uint8 `bitfield:"8"`
+	// > level_idc ... indicate[s] the level to which the coded video
+	// > sequence conforms. ... level_idc is equal to a value of ten times
+	// > the level number.
+}
+
+const (
+	h264SPSProfileLevelLengthInBytes = 3
+)
+
+const (
+	H264ProfileIDCBaseline                   = 66
+	H264ProfileIDCMain                       = 77
+	H264ProfileIDCExtended                   = 88
+	H264ProfileIDCHigh                       = 100
+	H264ProfileIDCHigh10                     = 110
+	H264ProfileIDCHigh422                    = 122
+	H264ProfileIDCHigh444Predictive          = 244
+	H264ProfileIDCCAVLC444Intra              = 44
+	H264ProfileIDCScalableBaseline           = 83
+	H264ProfileIDCScalableHigh               = 86
+	H264ProfileIDCMultiviewHigh              = 118
+	H264ProfileIDCStereoHigh                 = 128
+	H264ProfileIDCMultiviewDepthHigh         = 138
+	H264ProfileIDCEnhancedMultiviewDepthHigh = 139
+	H264ProfileIDCMFCHigh                    = 134
+	H264ProfileIDCMFCDepthHigh               = 135
+)
+
+const (
+	h264MacroblockWidth = 16
+)
+
+// H264SPS holds the fields of a sequence parameter set up to and including
+// vui_parameters_present_flag. The VUI parameters are not decoded.
+type H264SPS struct {
+	H264NALUnitHeaderFormat
+	H264SPSProfileLevelFormat
+
+	SeqParameterSetID uint64
+
+	ChromaFormatIDC                 uint64
+	SeparateColourPlaneFlag         bool
+	BitDepthLumaMinus8              uint64
+	BitDepthChromaMinus8            uint64
+	QPPrimeYZeroTransformBypassFlag bool
+	SeqScalingMatrixPresentFlag     bool
+
+	Log2MaxFrameNumMinus4 uint64
+
+	PicOrderCntType             uint64
+	Log2MaxPicOrderCntLSBMinus4 uint64
+	DeltaPicOrderAlwaysZeroFlag bool
+	OffsetForNonRefPic          int64
+	OffsetForTopToBottomField   int64
+	OffsetForRefFrame           []int64
+
+	MaxNumRefFrames                uint64
+	GapsInFrameNumValueAllowedFlag bool
+
+	PicWidthInMBsMinus1       uint64
+	PicHeightInMapUnitsMinus1 uint64
+	FrameMBsOnlyFlag          bool
+	MBAdaptiveFrameFieldFlag  bool
+	Direct8x8InferenceFlag    bool
+
+	FrameCroppingFlag     bool
+	FrameCropLeftOffset   uint64
+	FrameCropRightOffset  uint64
+	FrameCropTopOffset    uint64
+	FrameCropBottomOffset uint64
+
+	VUIParametersPresentFlag bool
+}
+
+// ParseH264SPS parses a sequence parameter set NAL unit,
+// which may contain emulation prevention bytes.
+func ParseH264SPS(nalUnit []byte) (sps H264SPS, e error) {
+	var (
+		count  uint64
+		i      uint64
+		offset int64
+		r      *H26xBitReader
+		rbsp   []byte
+	)
+
+	sps.H264NALUnitHeaderFormat, e = ParseH264NALUnitHeader(nalUnit)
+	if e != nil {
+		return
+	}
+
+	if sps.NALUnitType != H264NALUnitTypeSPS {
+		e = ErrH26xNALUnitTypeMismatch
+
+		return
+	}
+
+	rbsp = RemoveH26xEmulationPrevention(
+		nalUnit[H264NALUnitHeaderLengthInBytes:],
+	)
+
+	if len(rbsp) < h264SPSProfileLevelLengthInBytes {
+		e = ErrH26xRBSPTruncated
+
+		return
+	}
+
+	e = binary.Unmarshal(rbsp[:h264SPSProfileLevelLengthInBytes],
+		&sps.H264SPSProfileLevelFormat,
+	)
+	if e != nil {
+		return
+	}
+
+	r = NewH26xBitReader(rbsp[h264SPSProfileLevelLengthInBytes:])
+
+	sps.SeqParameterSetID, e = r.ReadUE()
+	if e != nil {
+		return
+	}
+
+	sps.ChromaFormatIDC = 1
+
+	if sps.hasChromaFormat() {
+		e = sps.readChromaFormat(r)
+		if e != nil {
+			return
+		}
+	}
+
+	sps.Log2MaxFrameNumMinus4, e = r.ReadUE()
+	if e != nil {
+		return
+	}
+
+	sps.PicOrderCntType, e = r.ReadUE()
+	if e != nil {
+		return
+	}
+
+	switch sps.PicOrderCntType {
+	case 0:
+		sps.Log2MaxPicOrderCntLSBMinus4, e = r.ReadUE()
+		if e != nil {
+			return
+		}
+
+	case 1:
+		sps.DeltaPicOrderAlwaysZeroFlag, e = r.ReadFlag()
+		if e != nil {
+			return
+		}
+
+		sps.OffsetForNonRefPic, e = r.ReadSE()
+		if e != nil {
+			return
+		}
+
+		sps.OffsetForTopToBottomField, e = r.ReadSE()
+		if e != nil {
+			return
+		}
+
+		count, e = r.ReadUE()
+		if e != nil {
+			return
+		}
+
+		for i = 0; i < count; i++ {
+			offset, e = r.ReadSE()
+			if e != nil {
+				return
+			}
+
+			sps.OffsetForRefFrame = append(sps.OffsetForRefFrame, offset)
+		}
+	}
+
+	sps.MaxNumRefFrames, e = r.ReadUE()
+	if e != nil {
+		return
+	}
+
+	sps.GapsInFrameNumValueAllowedFlag, e = r.ReadFlag()
+	if e != nil {
+		return
+	}
+
+	sps.PicWidthInMBsMinus1, e = r.ReadUE()
+	if e != nil {
+		return
+	}
+
+	sps.PicHeightInMapUnitsMinus1, e = r.ReadUE()
+	if e != nil {
+		return
+	}
+
+	sps.FrameMBsOnlyFlag, e = r.ReadFlag()
+	if e != nil {
+		return
+	}
+
+	if !sps.FrameMBsOnlyFlag {
+		sps.MBAdaptiveFrameFieldFlag, e = r.ReadFlag()
+		if e != nil {
+			return
+		}
+	}
+
+	sps.Direct8x8InferenceFlag, e = r.ReadFlag()
+	if e != nil {
+		return
+	}
+
+	sps.FrameCroppingFlag, e = r.ReadFlag()
+	if e != nil {
+		return
+	}
+
+	if sps.FrameCroppingFlag {
+		sps.FrameCropLeftOffset, e = r.ReadUE()
+		if e != nil {
+			return
+		}
+
+		sps.FrameCropRightOffset, e = r.ReadUE()
+		if e != nil {
+			return
+		}
+
+		sps.FrameCropTopOffset, e = r.ReadUE()
+		if e != nil {
+			return
+		}
+
+		sps.FrameCropBottomOffset, e = r.ReadUE()
+		if e != nil {
+			return
+		}
+	}
+
+	sps.VUIParametersPresentFlag, e = r.ReadFlag()
+	if e != nil {
+		return
+	}
+
+	return
+}
+
+func (sps *H264SPS) hasChromaFormat() bool {
+	switch sps.ProfileIDC {
+	case H264ProfileIDCHigh,
+		H264ProfileIDCHigh10,
+		H264ProfileIDCHigh422,
+		H264ProfileIDCHigh444Predictive,
+		H264ProfileIDCCAVLC444Intra,
+		H264ProfileIDCScalableBaseline,
+		H264ProfileIDCScalableHigh,
+		H264ProfileIDCMultiviewHigh,
+		H264ProfileIDCStereoHigh,
+		H264ProfileIDCMultiviewDepthHigh,
+		H264ProfileIDCEnhancedMultiviewDepthHigh,
+		H264ProfileIDCMFCHigh,
+		H264ProfileIDCMFCDepthHigh:
+		return true
+	}
+
+	return false
+}
+
+func (sps *H264SPS) readChromaFormat(r *H26xBitReader) (e error) {
+	var (
+		i       int
+		lists   int
+		present bool
+	)
+
+	sps.ChromaFormatIDC, e = r.ReadUE()
+	if e != nil {
+		return
+	}
+
+	if sps.ChromaFormatIDC == 3 {
+		sps.SeparateColourPlaneFlag, e = r.ReadFlag()
+		if e != nil {
+			return
+		}
+	}
+
+	sps.BitDepthLumaMinus8, e = r.ReadUE()
+	if e != nil {
+		return
+	}
+
+	sps.BitDepthChromaMinus8, e = r.ReadUE()
+	if e != nil {
+		return
+	}
+
+	sps.QPPrimeYZeroTransformBypassFlag, e = r.ReadFlag()
+	if e != nil {
+		return
+	}
+
+	sps.SeqScalingMatrixPresentFlag, e = r.ReadFlag()
+	if e != nil {
+		return
+	}
+
+	if !sps.SeqScalingMatrixPresentFlag {
+		return
+	}
+
+	lists = 8
+
+	if sps.ChromaFormatIDC == 3 {
+		lists = 12
+	}
+
+	for i = 0; i < lists; i++ {
+		present, e = r.ReadFlag()
+		if e != nil {
+			return
+		}
+
+		if !present {
+			continue
+		}
+
+		if i < 6 {
+			e = skipH264ScalingList(r, 16)
+		} else {
+			e = skipH264ScalingList(r, 64)
+		}
+
+		if e != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func skipH264ScalingList(r *H26xBitReader, size int) (e error) {
+	// Reference: Section 7.3.2.1.1.1 "Scaling list syntax"
+
+	// >   scaling_list( scalingList, sizeOfScalingList,
+	// >       useDefaultScalingMatrixFlag ) {
+	// >     lastScale = 8
+	// >     nextScale = 8
+	// >     for( j = 0; j < sizeOfScalingList; j++ ) {
+	// >       if( nextScale != 0 ) {
+	// >         delta_scale                                0 | 1 se(v)
+	// >         nextScale = ( lastScale + delta_scale + 256 ) % 256
+	// >         ...
+	// >       }
+	// >       lastScale = ( nextScale = = 0 ) ? lastScale : nextScale
+	// >     }
+	// >   }
+
+	var (
+		deltaScale int64
+		j          int
+		lastScale  int64
+		nextScale  int64
+	)
+
+	lastScale = 8
+	nextScale = 8
+
+	for j = 0; j < size; j++ {
+		if nextScale != 0 {
+			deltaScale, e = r.ReadSE()
+			if e != nil {
+				return
+			}
+
+			nextScale = (lastScale + deltaScale + 256) % 256
+		}
+
+		if nextScale != 0 {
+			lastScale = nextScale
+		}
+	}
+
+	return
+}
+
+// ChromaArrayType is 0 for monochrome or separately coded colour planes,
+// and chroma_format_idc otherwise.
+func (sps H264SPS) ChromaArrayType() uint64 {
+	if sps.SeparateColourPlaneFlag {
+		return 0
+	}
+
+	return sps.ChromaFormatIDC
+}
+
+// Width returns the width in luma samples of the cropped frame.
+func (sps H264SPS) Width() (width uint64) {
+	// > CropUnitX = 1                   if ChromaArrayType is equal to 0
+	// > CropUnitX = SubWidthC           otherwise
+
+	var (
+		cropUnitX uint64
+	)
+
+	cropUnitX = 1
+
+	if sps.ChromaArrayType() == 1 || sps.ChromaArrayType() == 2 {
+		cropUnitX = 2
+	}
+
+	width = (sps.PicWidthInMBsMinus1+1)*h264MacroblockWidth -
+		cropUnitX*(sps.FrameCropLeftOffset+sps.FrameCropRightOffset)
+
+	return
+}
+
+// Height returns the height in luma samples of the cropped frame.
+func (sps H264SPS) Height() (height uint64) {
+	// > FrameHeightInMbs = ( 2 − frame_mbs_only_flag ) *
+	// >   PicHeightInMapUnits
+	// > CropUnitY = 2 − frame_mbs_only_flag
+	// >                                 if ChromaArrayType is equal to 0
+	// > CropUnitY = SubHeightC * ( 2 − frame_mbs_only_flag )
+	// >                                 otherwise
+
+	var (
+		cropUnitY  uint64
+		fieldCount uint64
+	)
+
+	fieldCount = 2
+
+	if sps.FrameMBsOnlyFlag {
+		fieldCount = 1
+	}
+
+	cropUnitY = fieldCount
+
+	if sps.ChromaArrayType() == 1 {
+		cropUnitY = 2 * fieldCount
+	}
+
+	height = fieldCount*(sps.PicHeightInMapUnitsMinus1+1)*h264MacroblockWidth -
+		cropUnitY*(sps.FrameCropTopOffset+sps.FrameCropBottomOffset)
+
+	return
+}
+
+type H264PPS struct {
+	H264NALUnitHeaderFormat
+
+	PicParameterSetID                     uint64
+	SeqParameterSetID                     uint64
+	EntropyCodingModeFlag                 bool
+	BottomFieldPicOrderInFramePresentFlag bool
+}
+
+// ParseH264PPS parses the leading fields of a picture parameter set,
+// which identify it and the sequence parameter set it refers to.
+func ParseH264PPS(nalUnit []byte) (pps H264PPS, e error) {
+	// Reference: Section 7.3.2.2 "Picture parameter set RBSP syntax"
+
+	// >   pic_parameter_set_rbsp( ) {                      C   Descriptor
+	// >     pic_parameter_set_id                           1   ue(v)
+	// >     seq_parameter_set_id                           1   ue(v)
+	// >     entropy_coding_mode_flag                       1   u(1)
+	// >     bottom_field_pic_order_in_frame_present_flag   1   u(1)
+	// >     ...
+	// >   }
+
+	var (
+		r *H26xBitReader
+	)
+
+	pps.H264NALUnitHeaderFormat, e = ParseH264NALUnitHeader(nalUnit)
+	if e != nil {
+		return
+	}
+
+	if pps.NALUnitType != H264NALUnitTypePPS {
+		e = ErrH26xNALUnitTypeMismatch
+
+		return
+	}
+
+	r = NewH26xBitReader(
+		RemoveH26xEmulationPrevention(nalUnit[H264NALUnitHeaderLengthInBytes:]),
+	)
+
+	pps.PicParameterSetID, e = r.ReadUE()
+	if e != nil {
+		return
+	}
+
+	pps.SeqParameterSetID, e = r.ReadUE()
+	if e != nil {
+		return
+	}
+
+	pps.EntropyCodingModeFlag, e = r.ReadFlag()
+	if e != nil {
+		return
+	}
+
+	pps.BottomFieldPicOrderInFramePresentFlag, e = r.ReadFlag()
+	if e != nil {
+		return
+	}
+
+	return
+}
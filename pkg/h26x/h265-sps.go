@@ -0,0 +1,451 @@
+package h26x
+
+import (
+	"github.com/encodingx/binary"
+)
+
+type H265SPSHeaderFormat struct {
+	// Reference: Section 7.3.2.2.1 "General sequence parameter set RBSP
+	// syntax" of ITU-T H.265
+
+	// >   seq_parameter_set_rbsp( ) {                      Descriptor
+	// >     sps_video_parameter_set_id                     u(4)
+	// >     sps_max_sub_layers_minus1                      u(3)
+	// >     sps_temporal_id_nesting_flag                   u(1)
+	// >     profile_tier_level( 1, sps_max_sub_layers_minus1 )
+	// >     sps_seq_parameter_set_id                       ue(v)
+	// >     chroma_format_idc                              ue(v)
+	// >     if( chroma_format_idc = = 3 )
+	// >       separate_colour_plane_flag                   u(1)
+	// >     pic_width_in_luma_samples                      ue(v)
+	// >     pic_height_in_luma_samples                     ue(v)
+	// >     conformance_window_flag                        u(1)
+	// >     if( conformance_window_flag ) {
+	// >       conf_win_left_offset                         ue(v)
+	// >       conf_win_right_offset                        ue(v)
+	// >       conf_win_top_offset                          ue(v)
+	// >       conf_win_bottom_offset                       ue(v)
+	// >     }
+	// >     bit_depth_luma_minus8                          ue(v)
+	// >     bit_depth_chroma_minus8                        ue(v)
+	// >     log2_max_pic_order_cnt_lsb_minus4              ue(v)
+	// >     ...
+	// >   }
+
+	H265SPSHeaderFormatWord0 `word:"8"`
+}
+
+type H265SPSHeaderFormatWord0 struct {
+	SPSVideoParameterSetID   uint8 `bitfield:"4"`
+	SPSMaxSubLayersMinus1    uint8 `bitfield:"3"`
+	SPSTemporalIDNestingFlag bool  `bitfield:"1"`
+}
+
+type H265ProfileTierLevelFormat struct {
+	// Reference: Section 7.3.3 "Profile, tier and level syntax"
+
+	// >   profile_tier_level( profilePresentFlag,
+	// >       maxNumSubLayersMinus1 ) {                    Descriptor
+	// >     if( profilePresentFlag ) {
+	// >       general_profile_space                        u(2)
+	// >       general_tier_flag                            u(1)
+	// >       general_profile_idc                          u(5)
+	// >       for( j = 0; j < 32; j++ )
+	// >         general_profile_compatibility_flag[ j ]    u(1)
+	// >       general_progressive_source_flag              u(1)
+	// >       general_interlaced_source_flag               u(1)
+	// >       general_non_packed_constraint_flag           u(1)
+	// >       general_frame_only_constraint_flag           u(1)
+	// >       ...                                          u(43)
+	// >       ...                                          u(1)
+	// >     }
+	// >     general_level_idc                              u(8)
+	// >     ...
+	// >   }
+	//
+	// Only the general profile, tier and level is decoded;
+	// the 44 bits of profile-specific constraint flags are kept together.
+
+	H265ProfileTierLevelFormatWord0 `word:"8"`
+	H265ProfileTierLevelFormatWord1 `word:"32"`
+	H265ProfileTierLevelFormatWord2 `word:"48"`
+	H265ProfileTierLevelFormatWord3 `word:"8"`
+}
+
+type H265ProfileTierLevelFormatWord0 struct {
+	GeneralProfileSpace uint8 `bitfield:"2"`
+	GeneralTierFlag     bool  `bitfield:"1"`
+	GeneralProfileIDC   uint8 `bitfield:"5"`
+}
+
+type H265ProfileTierLevelFormatWord1 struct {
+	GeneralProfileCompatibilityFlags uint32 `bitfield:"32"`
+	// > general_profile_compatibility_flag[ j ] equal to 1, when
+	// > general_profile_space is equal to 0, indicates that the CVS
+	// > conforms to the profile indicated by general_profile_idc equal to j.
+	//
+	// Flag j is bit 31-j of the field.
+}
+
+type H265ProfileTierLevelFormatWord2 struct {
+	GeneralProgressiveSourceFlag   bool   `bitfield:"1"`
+	GeneralInterlacedSourceFlag    bool   `bitfield:"1"`
+	GeneralNonPackedConstraintFlag bool   `bitfield:"1"`
+	GeneralFrameOnlyConstraintFlag bool   `bitfield:"1"`
+	GeneralConstraintFlags         uint64 `bitfield:"44"`
+}
+
+type H265ProfileTierLevelFormatWord3 struct {
+	GeneralLevelIDC uint8 `bitfield:"8"`
+	// > general_level_idc indicates a level to which the CVS conforms as
+	// > specified in Annex A. ... general_level_idc [is] set equal to a
+	// > value of 30 times the level number specified in Table A.8.
+}
+
+const (
+	h265SPSHeaderLengthInBytes        = 1
+	h265ProfileTierLevelLengthInBytes = 12
+	h265SubLayerProfileLengthInBits   = 88
+	h265SubLayerLevelLengthInBits     = 8
+	h265MaxSubLayers                  = 8
+)
+
+const (
+	H265GeneralProfileIDCMain                  = 1
+	H265GeneralProfileIDCMain10                = 2
+	H265GeneralProfileIDCMainStillPicture      = 3
+	H265GeneralProfileIDCFormatRangeExtensions = 4
+	H265GeneralProfileIDCHighThroughput        = 5
+	H265GeneralProfileIDCScreenContentCoding   = 9
+)
+
+// H265SPS holds the fields of a sequence parameter set up to and including
+// log2_max_pic_order_cnt_lsb_minus4.
+// Sub-layer profiles and levels are skipped.
+type H265SPS struct {
+	H265NALUnitHeaderFormat
+	H265SPSHeaderFormat
+	H265ProfileTierLevelFormat
+
+	SPSSeqParameterSetID    uint64
+	ChromaFormatIDC         uint64
+	SeparateColourPlaneFlag bool
+
+	PicWidthInLumaSamples  uint64
+	PicHeightInLumaSamples uint64
+
+	ConformanceWindowFlag bool
+	ConfWinLeftOffset     uint64
+	ConfWinRightOffset    uint64
+	ConfWinTopOffset      uint64
+	ConfWinBottomOffset   uint64
+
+	BitDepthLumaMinus8          uint64
+	BitDepthChromaMinus8        uint64
+	Log2MaxPicOrderCntLSBMinus4 uint64
+}
+
+// ParseH265SPS parses a sequence parameter set NAL unit,
+// which may contain emulation prevention bytes.
+func ParseH265SPS(nalUnit []byte) (sps H265SPS, e error) {
+	var (
+		r    *H26xBitReader
+		rbsp []byte
+	)
+
+	sps.H265NALUnitHeaderFormat, e = ParseH265NALUnitHeader(nalUnit)
+	if e != nil {
+		return
+	}
+
+	if sps.NALUnitType != H265NALUnitTypeSPS {
+		e = ErrH26xNALUnitTypeMismatch
+
+		return
+	}
+
+	rbsp = RemoveH26xEmulationPrevention(
+		nalUnit[H265NALUnitHeaderLengthInBytes:],
+	)
+
+	if len(rbsp) < h265SPSHeaderLengthInBytes+h265ProfileTierLevelLengthInBytes {
+		e = ErrH26xRBSPTruncated
+
+		return
+	}
+
+	e = binary.Unmarshal(rbsp[:h265SPSHeaderLengthInBytes],
+		&sps.H265SPSHeaderFormat,
+	)
+	if e != nil {
+		return
+	}
+
+	rbsp = rbsp[h265SPSHeaderLengthInBytes:]
+
+	e = binary.Unmarshal(rbsp[:h265ProfileTierLevelLengthInBytes],
+		&sps.H265ProfileTierLevelFormat,
+	)
+	if e != nil {
+		return
+	}
+
+	r = NewH26xBitReader(rbsp[h265ProfileTierLevelLengthInBytes:])
+
+	e = skipH265SubLayers(r, int(sps.SPSMaxSubLayersMinus1))
+	if e != nil {
+		return
+	}
+
+	sps.SPSSeqParameterSetID, e = r.ReadUE()
+	if e != nil {
+		return
+	}
+
+	sps.ChromaFormatIDC, e = r.ReadUE()
+	if e != nil {
+		return
+	}
+
+	if sps.ChromaFormatIDC == 3 {
+		sps.SeparateColourPlaneFlag, e = r.ReadFlag()
+		if e != nil {
+			return
+		}
+	}
+
+	sps.PicWidthInLumaSamples, e = r.ReadUE()
+	if e != nil {
+		return
+	}
+
+	sps.PicHeightInLumaSamples, e = r.ReadUE()
+	if e != nil {
+		return
+	}
+
+	sps.ConformanceWindowFlag, e = r.ReadFlag()
+	if e != nil {
+		return
+	}
+
+	if sps.ConformanceWindowFlag {
+		sps.ConfWinLeftOffset, e = r.ReadUE()
+		if e != nil {
+			return
+		}
+
+		sps.ConfWinRightOffset, e = r.ReadUE()
+		if e != nil {
+			return
+		}
+
+		sps.ConfWinTopOffset, e = r.ReadUE()
+		if e != nil {
+			return
+		}
+
+		sps.ConfWinBottomOffset, e = r.ReadUE()
+		if e != nil {
+			return
+		}
+	}
+
+	sps.BitDepthLumaMinus8, e = r.ReadUE()
+	if e != nil {
+		return
+	}
+
+	sps.BitDepthChromaMinus8, e = r.ReadUE()
+	if e != nil {
+		return
+	}
+
+	sps.Log2MaxPicOrderCntLSBMinus4, e = r.ReadUE()
+	if e != nil {
+		return
+	}
+
+	return
+}
+
+func skipH265SubLayers(r *H26xBitReader, maxSubLayersMinus1 int) (e error) {
+	// >     for( i = 0; i < maxNumSubLayersMinus1; i++ ) {
+	// >       sub_layer_profile_present_flag[ i ]          u(1)
+	// >       sub_layer_level_present_flag[ i ]            u(1)
+	// >     }
+	// >     if( maxNumSubLayersMinus1 > 0 )
+	// >       for( i = maxNumSubLayersMinus1; i < 8; i++ )
+	// >         reserved_zero_2bits[ i ]                   u(2)
+	// >     for( i = 0; i < maxNumSubLayersMinus1; i++ ) {
+	// >       if( sub_layer_profile_present_flag[ i ] ) {
+	// >         ...                                        88 bits
+	// >       }
+	// >       if( sub_layer_level_present_flag[ i ] )
+	// >         sub_layer_level_idc[ i ]                   u(8)
+	// >     }
+
+	var (
+		i                   int
+		levelPresentFlags   [h265MaxSubLayers]bool
+		profilePresentFlags [h265MaxSubLayers]bool
+	)
+
+	for i = 0; i < maxSubLayersMinus1; i++ {
+		profilePresentFlags[i], e = r.ReadFlag()
+		if e != nil {
+			return
+		}
+
+		levelPresentFlags[i], e = r.ReadFlag()
+		if e != nil {
+			return
+		}
+	}
+
+	if maxSubLayersMinus1 > 0 {
+		e = r.SkipBits(2 * (h265MaxSubLayers - maxSubLayersMinus1))
+		if e != nil {
+			return
+		}
+	}
+
+	for i = 0; i < maxSubLayersMinus1; i++ {
+		if profilePresentFlags[i] {
+			e = r.SkipBits(h265SubLayerProfileLengthInBits)
+			if e != nil {
+				return
+			}
+		}
+
+		if levelPresentFlags[i] {
+			e = r.SkipBits(h265SubLayerLevelLengthInBits)
+			if e != nil {
+				return
+			}
+		}
+	}
+
+	return
+}
+
+// ChromaArrayType is 0 for monochrome or separately coded colour planes,
+// and chroma_format_idc otherwise.
+func (sps H265SPS) ChromaArrayType() uint64 {
+	if sps.SeparateColourPlaneFlag {
+		return 0
+	}
+
+	return sps.ChromaFormatIDC
+}
+
+// Width returns the width in luma samples of the conformance window.
+func (sps H265SPS) Width() (width uint64) {
+	var (
+		subWidthC uint64
+	)
+
+	subWidthC = 1
+
+	if sps.ChromaArrayType() == 1 || sps.ChromaArrayType() == 2 {
+		subWidthC = 2
+	}
+
+	width = sps.PicWidthInLumaSamples -
+		subWidthC*(sps.ConfWinLeftOffset+sps.ConfWinRightOffset)
+
+	return
+}
+
+// Height returns the height in luma samples of the conformance window.
+func (sps H265SPS) Height() (height uint64) {
+	var (
+		subHeightC uint64
+	)
+
+	subHeightC = 1
+
+	if sps.ChromaArrayType() == 1 {
+		subHeightC = 2
+	}
+
+	height = sps.PicHeightInLumaSamples -
+		subHeightC*(sps.ConfWinTopOffset+sps.ConfWinBottomOffset)
+
+	return
+}
+
+type H265PPS struct {
+	H265NALUnitHeaderFormat
+
+	PPSPicParameterSetID              uint64
+	PPSSeqParameterSetID              uint64
+	DependentSliceSegmentsEnabledFlag bool
+	OutputFlagPresentFlag             bool
+	NumExtraSliceHeaderBits           uint8
+}
+
+// ParseH265PPS parses the leading fields of a picture parameter set,
+// which identify it and the sequence parameter set it refers to.
+func ParseH265PPS(nalUnit []byte) (pps H265PPS, e error) {
+	// Reference: Section 7.3.2.3.1 "General picture parameter set RBSP
+	// syntax"
+
+	// >   pic_parameter_set_rbsp( ) {                      Descriptor
+	// >     pps_pic_parameter_set_id                       ue(v)
+	// >     pps_seq_parameter_set_id                       ue(v)
+	// >     dependent_slice_segments_enabled_flag          u(1)
+	// >     output_flag_present_flag                       u(1)
+	// >     num_extra_slice_header_bits                    u(3)
+	// >     ...
+	// >   }
+
+	var (
+		r     *H26xBitReader
+		value uint64
+	)
+
+	pps.H265NALUnitHeaderFormat, e = ParseH265NALUnitHeader(nalUnit)
+	if e != nil {
+		return
+	}
+
+	if pps.NALUnitType != H265NALUnitTypePPS {
+		e = ErrH26xNALUnitTypeMismatch
+
+		return
+	}
+
+	r = NewH26xBitReader(
+		RemoveH26xEmulationPrevention(nalUnit[H265NALUnitHeaderLengthInBytes:]),
+	)
+
+	pps.PPSPicParameterSetID, e = r.ReadUE()
+	if e != nil {
+		return
+	}
+
+	pps.PPSSeqParameterSetID, e = r.ReadUE()
+	if e != nil {
+		return
+	}
+
+	pps.DependentSliceSegmentsEnabledFlag, e = r.ReadFlag()
+	if e != nil {
+		return
+	}
+
+	pps.OutputFlagPresentFlag, e = r.ReadFlag()
+	if e != nil {
+		return
+	}
+
+	value, e = r.ReadBits(3)
+	if e != nil {
+		return
+	}
+
+	pps.NumExtraSliceHeaderBits = uint8(value)
+
+	return
+}
@@ -0,0 +1,144 @@
+package h26x
+
+// Reference: Annex B "Byte stream format" of
+// ITU-T H.264 Advanced video coding for generic audiovisual services
+// and ITU-T H.265 High efficiency video coding
+
+// > B.1.1 Byte stream NAL unit syntax
+// >
+// >   byte_stream_nal_unit( NumBytesInNalUnit ) {
+// >     while( next_bits( 24 ) != 0x000001 &&
+// >         next_bits( 32 ) != 0x00000001 )
+// >       leading_zero_8bits /* equal to 0x00 */              f(8)
+// >     if( next_bits( 24 ) != 0x000001 )
+// >       zero_byte /* equal to 0x00 */                       f(8)
+// >     start_code_prefix_one_3bytes /* equal to 0x000001 */  f(24)
+// >     nal_unit( NumBytesInNalUnit )
+// >     while( more_data_in_byte_stream( ) &&
+// >         next_bits( 24 ) != 0x000001 &&
+// >         next_bits( 32 ) != 0x00000001 )
+// >       trailing_zero_8bits /* equal to 0x00 */             f(8)
+// >   }
+
+const (
+	h26xEmulationPreventionByte = 0x03
+)
+
+// SplitH26xAnnexB splits a byte stream into NAL units
+// at three- and four-byte start codes,
+// stripping the start codes and any trailing zero bytes.
+// The NAL units share memory with the stream;
+// bytes before the first start code are ignored.
+func SplitH26xAnnexB(stream []byte) (nalUnits [][]byte) {
+	var (
+		end   int
+		i     int
+		start int
+	)
+
+	start = -1
+
+	for i = 0; i+2 < len(stream); i++ {
+		if stream[i] != 0 || stream[i+1] != 0 || stream[i+2] != 1 {
+			continue
+		}
+
+		if start >= 0 {
+			end = i
+
+			for end > start && stream[end-1] == 0 {
+				end--
+			}
+
+			if end > start {
+				nalUnits = append(nalUnits, stream[start:end])
+			}
+		}
+
+		i += 2
+
+		start = i + 1
+	}
+
+	if start >= 0 && start < len(stream) {
+		nalUnits = append(nalUnits, stream[start:])
+	}
+
+	return
+}
+
+// RemoveH26xEmulationPrevention converts a NAL unit
+// into its raw byte sequence payload by removing
+// each emulation_prevention_three_byte following two zero bytes.
+// The NAL unit is returned as-is if it contains none.
+func RemoveH26xEmulationPrevention(nalUnit []byte) (rbsp []byte) {
+	var (
+		b     byte
+		i     int
+		zeros int
+	)
+
+	for i = 2; i < len(nalUnit); i++ {
+		if nalUnit[i] == h26xEmulationPreventionByte &&
+			nalUnit[i-1] == 0 && nalUnit[i-2] == 0 {
+			break
+		}
+	}
+
+	if i >= len(nalUnit) {
+		rbsp = nalUnit
+
+		return
+	}
+
+	rbsp = make([]byte, 0, len(nalUnit))
+
+	for _, b = range nalUnit {
+		if zeros >= 2 && b == h26xEmulationPreventionByte {
+			zeros = 0
+
+			continue
+		}
+
+		rbsp = append(rbsp, b)
+
+		if b == 0 {
+			zeros++
+		} else {
+			zeros = 0
+		}
+	}
+
+	return
+}
+
+// AddH26xEmulationPrevention is the inverse of
+// RemoveH26xEmulationPrevention,
+// inserting an emulation_prevention_three_byte wherever
+// two zero bytes are followed by a byte no greater than three.
+func AddH26xEmulationPrevention(rbsp []byte) (nalUnit []byte) {
+	var (
+		b     byte
+		zeros int
+	)
+
+	nalUnit = make([]byte, 0, len(rbsp)+len(rbsp)/2)
+
+	for _, b = range rbsp {
+		if zeros >= 2 && b <= h26xEmulationPreventionByte {
+			nalUnit = append(nalUnit, h26xEmulationPreventionByte)
+
+			zeros = 0
+		}
+
+		nalUnit = append(nalUnit, b)
+
+		if b == 0 {
+			zeros++
+		} else {
+			zeros = 0
+		}
+	}
+
+	return
+}
@@ -0,0 +1,163 @@
+package h26x
+
+import (
+	"errors"
+)
+
+// H26xBitReader reads the syntax elements of an RBSP,
+// from which emulation prevention bytes should already be removed.
+// Reference: Section 7.2 "Specification of syntax functions,
+// categories, and descriptors" and Section 9.1 "Parsing process for
+// Exp-Golomb codes" of ITU-T H.264
+type H26xBitReader struct {
+	rbsp   []byte
+	offset int
+}
+
+const (
+	h26xExpGolombMaxLeadingZeroBits = 32
+)
+
+var (
+	ErrH26xExpGolombCodeTooLong = errors.New(
+		"An Exp-Golomb code should have at most 32 leading zero bits. " +
+			"The code has more leading zero bits than can be decoded.",
+	)
+
+	ErrH26xRBSPTruncated = errors.New(
+		"An RBSP should contain all syntax elements " +
+			"indicated by those preceding them. " +
+			"The RBSP ends before a syntax element does.",
+	)
+)
+
+func NewH26xBitReader(rbsp []byte) (r *H26xBitReader) {
+	r = &H26xBitReader{
+		rbsp: rbsp,
+	}
+
+	return
+}
+
+// ReadBits reads an unsigned integer of up to 64 bits, u(n).
+func (r *H26xBitReader) ReadBits(n int) (value uint64, e error) {
+	var (
+		i int
+	)
+
+	if r.offset+n > len(r.rbsp)*8 {
+		e = ErrH26xRBSPTruncated
+
+		return
+	}
+
+	for i = 0; i < n; i++ {
+		value = value<<1 |
+			uint64(r.rbsp[r.offset/8]>>(7-r.offset%8)&1)
+
+		r.offset++
+	}
+
+	return
+}
+
+// ReadFlag reads a single bit, u(1).
+func (r *H26xBitReader) ReadFlag() (flag bool, e error) {
+	var (
+		value uint64
+	)
+
+	value, e = r.ReadBits(1)
+
+	flag = value == 1
+
+	return
+}
+
+// ReadUE reads an unsigned Exp-Golomb code, ue(v).
+func (r *H26xBitReader) ReadUE() (value uint64, e error) {
+	// > leadingZeroBits = −1
+	// > for( b = 0; !b; leadingZeroBits++ )
+	// >   b = read_bits( 1 )
+	// > codeNum = 2^leadingZeroBits − 1 + read_bits( leadingZeroBits )
+
+	var (
+		b               uint64
+		leadingZeroBits int
+	)
+
+	for {
+		b, e = r.ReadBits(1)
+		if e != nil {
+			return
+		}
+
+		if b == 1 {
+			break
+		}
+
+		leadingZeroBits++
+
+		if leadingZeroBits > h26xExpGolombMaxLeadingZeroBits {
+			e = ErrH26xExpGolombCodeTooLong
+
+			return
+		}
+	}
+
+	value, e = r.ReadBits(leadingZeroBits)
+	if e != nil {
+		return
+	}
+
+	value += 1<<leadingZeroBits - 1
+
+	return
+}
+
+// ReadSE reads a signed Exp-Golomb code, se(v).
+func (r *H26xBitReader) ReadSE() (value int64, e error) {
+	// > Table 9-3 – Assignment of syntax element to codeNum for signed
+	// > Exp-Golomb coded syntax elements se(v)
+	// >
+	// >   codeNum  syntax element value
+	// >   0         0
+	// >   1         1
+	// >   2        −1
+	// >   3         2
+	// >   4        −2
+
+	var (
+		codeNum uint64
+	)
+
+	codeNum, e = r.ReadUE()
+	if e != nil {
+		return
+	}
+
+	value = int64((codeNum + 1) / 2)
+
+	if codeNum%2 == 0 {
+		value = -value
+	}
+
+	return
+}
+
+func (r *H26xBitReader) SkipBits(n int) (e error) {
+	if r.offset+n > len(r.rbsp)*8 {
+		e = ErrH26xRBSPTruncated
+
+		return
+	}
+
+	r.offset += n
+
+	return
+}
+
+// Offset returns the number of bits read so far.
+func (r *H26xBitReader) Offset() int {
+	return r.offset
+}
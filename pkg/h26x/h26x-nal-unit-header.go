@@ -0,0 +1,194 @@
+package h26x
+
+import (
+	"errors"
+
+	"github.com/encodingx/binary"
+)
+
+type H264NALUnitHeaderFormat struct {
+	// Reference: Section 7.3.1 "NAL unit syntax" of ITU-T H.264
+
+	// >   nal_unit( NumBytesInNALunit ) {                  C   Descriptor
+	// >     forbidden_zero_bit                             All f(1)
+	// >     nal_ref_idc                                    All u(2)
+	// >     nal_unit_type                                  All u(5)
+	// >     ...
+	// >   }
+
+	H264NALUnitHeaderFormatWord0 `word:"8"`
+}
+
+type H264NALUnitHeaderFormatWord0 struct {
+	ForbiddenZeroBit bool `bitfield:"1"`
+	// > forbidden_zero_bit shall be equal to 0.
+
+	NALRefIDC uint8 `bitfield:"2"`
+	// > nal_ref_idc not equal to 0 specifies that the content of the NAL
+	// > unit contains a sequence parameter set, a sequence parameter set
+	// > extension, a subset sequence parameter set, a picture parameter set,
+	// > a slice of a reference picture, a slice data partition of a
+	// > reference picture, or a prefix NAL unit preceding a slice of a
+	// > reference picture.
+
+	NALUnitType uint8 `bitfield:"5"`
+	// > nal_unit_type specifies the type of RBSP data structure contained
+	// > in the NAL unit as specified in Table 7-1.
+}
+
+const (
+	H264NALUnitHeaderLengthInBytes = 1
+)
+
+const (
+	H264NALUnitTypeSliceNonIDR                = 1
+	H264NALUnitTypeSliceDataPartitionA        = 2
+	H264NALUnitTypeSliceDataPartitionB        = 3
+	H264NALUnitTypeSliceDataPartitionC        = 4
+	H264NALUnitTypeSliceIDR                   = 5
+	H264NALUnitTypeSEI                        = 6
+	H264NALUnitTypeSPS                        = 7
+	H264NALUnitTypePPS                        = 8
+	H264NALUnitTypeAccessUnitDelimiter        = 9
+	H264NALUnitTypeEndOfSequence              = 10
+	H264NALUnitTypeEndOfStream                = 11
+	H264NALUnitTypeFillerData                 = 12
+	H264NALUnitTypeSPSExtension               = 13
+	H264NALUnitTypePrefix                     = 14
+	H264NALUnitTypeSubsetSPS                  = 15
+	H264NALUnitTypeSliceAuxiliary             = 19
+	H264NALUnitTypeSliceExtension             = 20
+	H264NALUnitTypeSliceExtensionDepthOrView3 = 21
+)
+
+type H265NALUnitHeaderFormat struct {
+	// Reference: Section 7.3.1.2 "NAL unit header syntax" of ITU-T H.265
+
+	// >   nal_unit_header( ) {                             Descriptor
+	// >     forbidden_zero_bit                             f(1)
+	// >     nal_unit_type                                  u(6)
+	// >     nuh_layer_id                                   u(6)
+	// >     nuh_temporal_id_plus1                          u(3)
+	// >   }
+
+	H265NALUnitHeaderFormatWord0 `word:"16"`
+}
+
+type H265NALUnitHeaderFormatWord0 struct {
+	ForbiddenZeroBit bool `bitfield:"1"`
+	// > forbidden_zero_bit shall be equal to 0.
+
+	NALUnitType uint8 `bitfield:"6"`
+	// > nal_unit_type specifies the type of RBSP data structure contained
+	// > in the NAL unit as specified in Table 7-1.
+
+	NuhLayerID uint8 `bitfield:"6"`
+	// > nuh_layer_id specifies the identifier of the layer to which a VCL
+	// > NAL unit belongs or the identifier of a layer to which a non-VCL
+	// > NAL unit applies.
+
+	NuhTemporalIDPlus1 uint8 `bitfield:"3"`
+	// > nuh_temporal_id_plus1 minus 1 specifies a temporal identifier for
+	// > the NAL unit. The value of nuh_temporal_id_plus1 shall not be equal
+	// > to 0.
+}
+
+const (
+	H265NALUnitHeaderLengthInBytes = 2
+)
+
+const (
+	H265NALUnitTypeTrailN              = 0
+	H265NALUnitTypeTrailR              = 1
+	H265NALUnitTypeBLAWLP              = 16
+	H265NALUnitTypeBLAWRADL            = 17
+	H265NALUnitTypeBLANLP              = 18
+	H265NALUnitTypeIDRWRADL            = 19
+	H265NALUnitTypeIDRNLP              = 20
+	H265NALUnitTypeCRA                 = 21
+	H265NALUnitTypeReservedIRAP23      = 23
+	H265NALUnitTypeVPS                 = 32
+	H265NALUnitTypeSPS                 = 33
+	H265NALUnitTypePPS                 = 34
+	H265NALUnitTypeAccessUnitDelimiter = 35
+	H265NALUnitTypeEndOfSequence       = 36
+	H265NALUnitTypeEndOfBitstream      = 37
+	H265NALUnitTypeFillerData          = 38
+	H265NALUnitTypePrefixSEI           = 39
+	H265NALUnitTypeSuffixSEI           = 40
+)
+
+func (h H265NALUnitHeaderFormat) TemporalID() uint8 {
+	return h.NuhTemporalIDPlus1 - 1
+}
+
+// IsIRAP reports whether the NAL unit is a slice of
+// an intra random access point picture.
+func (h H265NALUnitHeaderFormat) IsIRAP() bool {
+	return h.NALUnitType >= H265NALUnitTypeBLAWLP &&
+		h.NALUnitType <= H265NALUnitTypeReservedIRAP23
+}
+
+var (
+	ErrH26xForbiddenZeroBitSet = errors.New(
+		"The forbidden_zero_bit of a NAL unit header shall be equal to 0. " +
+			"The forbidden_zero_bit of the header is 1.",
+	)
+
+	ErrH26xNALUnitTooShort = errors.New(
+		"A NAL unit should be at least as long as its NAL unit header. " +
+			"The NAL unit is shorter than its header.",
+	)
+
+	ErrH26xNALUnitTypeMismatch = errors.New(
+		"The nal_unit_type of a NAL unit should match " +
+			"the type of RBSP being parsed. " +
+			"The NAL unit holds a different type of RBSP.",
+	)
+)
+
+func ParseH264NALUnitHeader(nalUnit []byte) (
+	header H264NALUnitHeaderFormat, e error,
+) {
+	if len(nalUnit) < H264NALUnitHeaderLengthInBytes {
+		e = ErrH26xNALUnitTooShort
+
+		return
+	}
+
+	e = binary.Unmarshal(nalUnit[:H264NALUnitHeaderLengthInBytes], &header)
+	if e != nil {
+		return
+	}
+
+	if header.ForbiddenZeroBit {
+		e = ErrH26xForbiddenZeroBitSet
+
+		return
+	}
+
+	return
+}
+
+func ParseH265NALUnitHeader(nalUnit []byte) (
+	header H265NALUnitHeaderFormat, e error,
+) {
+	if len(nalUnit) < H265NALUnitHeaderLengthInBytes {
+		e = ErrH26xNALUnitTooShort
+
+		return
+	}
+
+	e = binary.Unmarshal(nalUnit[:H265NALUnitHeaderLengthInBytes], &header)
+	if e != nil {
+		return
+	}
+
+	if header.ForbiddenZeroBit {
+		e = ErrH26xForbiddenZeroBitSet
+
+		return
+	}
+
+	return
+}
@@ -0,0 +1,279 @@
+package h26x
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	h264SPSBytes = []byte{
+		0x67, 0x64, 0x00, 0x1f, 0xac, 0xd9, 0x40, 0x50,
+		0x05, 0xbb, 0x01, 0x10, 0x00, 0x00, 0x03, 0x00,
+		0x10, 0x00, 0x00, 0x03, 0x03, 0xc0, 0xf1, 0x83,
+		0x19, 0x60,
+	}
+
+	h264PPSBytes = []byte{
+		0x68, 0xeb, 0xe3, 0xcb, 0x22, 0xc0,
+	}
+
+	h265SPSBytes = []byte{
+		0x42, 0x01, 0x01, 0x01, 0x60, 0x00, 0x00, 0x03,
+		0x00, 0x90, 0x00, 0x00, 0x03, 0x00, 0x00, 0x03,
+		0x00, 0x78, 0xa0, 0x03, 0xc0, 0x80, 0x10, 0xe5,
+		0x8d, 0x94, 0xb3, 0xd9, 0xc8,
+	}
+)
+
+func TestSplitH26xAnnexB(t *testing.T) {
+	var (
+		nalUnits [][]byte
+		stream   []byte
+	)
+
+	stream = []byte{0xff, 0x00, 0x00, 0x00, 0x01}
+	stream = append(stream, h264SPSBytes...)
+	stream = append(stream, 0x00, 0x00, 0x01)
+	stream = append(stream, h264PPSBytes...)
+	stream = append(stream, 0x00, 0x00, 0x00, 0x00, 0x01, 0x65, 0x88)
+
+	nalUnits = SplitH26xAnnexB(stream)
+
+	assert.Equal(t,
+		[][]byte{h264SPSBytes, h264PPSBytes, {0x65, 0x88}}, nalUnits,
+	)
+
+	assert.Nil(t,
+		SplitH26xAnnexB([]byte{0x67, 0x64, 0x00}),
+	)
+}
+
+func TestH26xEmulationPrevention(t *testing.T) {
+	var (
+		nalUnit []byte
+		rbsp    []byte
+	)
+
+	rbsp = []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x04}
+
+	nalUnit = AddH26xEmulationPrevention(rbsp)
+
+	assert.Equal(t,
+		[]byte{
+			0x00, 0x00, 0x03, 0x00, 0x00, 0x03, 0x00, 0x01,
+			0x00, 0x00, 0x04,
+		},
+		nalUnit,
+	)
+
+	assert.Equal(t,
+		rbsp, RemoveH26xEmulationPrevention(nalUnit),
+	)
+
+	assert.Equal(t,
+		h264PPSBytes, RemoveH26xEmulationPrevention(h264PPSBytes),
+	)
+}
+
+func TestParseH26xNALUnitHeader(t *testing.T) {
+	var (
+		e          error
+		h264Header H264NALUnitHeaderFormat
+		h265Header H265NALUnitHeaderFormat
+	)
+
+	h264Header, e = ParseH264NALUnitHeader([]byte{0x65})
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint8(3), h264Header.NALRefIDC,
+	)
+
+	assert.Equal(t,
+		uint8(H264NALUnitTypeSliceIDR), h264Header.NALUnitType,
+	)
+
+	_, e = ParseH264NALUnitHeader([]byte{0xe5})
+
+	assert.Equal(t,
+		ErrH26xForbiddenZeroBitSet, e,
+	)
+
+	h265Header, e = ParseH265NALUnitHeader([]byte{0x26, 0x01})
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint8(H265NALUnitTypeIDRWRADL), h265Header.NALUnitType,
+	)
+
+	assert.Equal(t,
+		uint8(0), h265Header.TemporalID(),
+	)
+
+	assert.True(t, h265Header.IsIRAP())
+
+	_, e = ParseH265NALUnitHeader([]byte{0x26})
+
+	assert.Equal(t,
+		ErrH26xNALUnitTooShort, e,
+	)
+}
+
+func TestH26xBitReader(t *testing.T) {
+	var (
+		e             error
+		r             *H26xBitReader
+		signed        int64
+		signedValue   int64
+		unsigned      uint64
+		unsignedValue uint64
+	)
+
+	// 1 010 011 00100 00101 00110 00111
+
+	r = NewH26xBitReader([]byte{
+		0b10100110, 0b01000010, 0b10011000, 0b11100000,
+	})
+
+	for _, unsigned = range []uint64{0, 1, 2, 3} {
+		unsignedValue, e = r.ReadUE()
+
+		assert.Nil(t, e)
+
+		assert.Equal(t,
+			unsigned, unsignedValue,
+		)
+	}
+
+	for _, signed = range []int64{-2, 3, -3} {
+		signedValue, e = r.ReadSE()
+
+		assert.Nil(t, e)
+
+		assert.Equal(t,
+			signed, signedValue,
+		)
+	}
+
+	assert.Equal(t,
+		27, r.Offset(),
+	)
+
+	_, e = r.ReadBits(6)
+
+	assert.Equal(t,
+		ErrH26xRBSPTruncated, e,
+	)
+
+	r = NewH26xBitReader(make([]byte, 5))
+
+	_, e = r.ReadUE()
+
+	assert.Equal(t,
+		ErrH26xExpGolombCodeTooLong, e,
+	)
+}
+
+func TestParseH264SPSAndPPS(t *testing.T) {
+	var (
+		e   error
+		pps H264PPS
+		sps H264SPS
+	)
+
+	sps, e = ParseH264SPS(h264SPSBytes)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint8(H264ProfileIDCHigh), sps.ProfileIDC,
+	)
+
+	assert.Equal(t,
+		uint8(31), sps.LevelIDC,
+	)
+
+	assert.Equal(t,
+		uint64(1), sps.ChromaFormatIDC,
+	)
+
+	assert.True(t, sps.FrameMBsOnlyFlag)
+
+	assert.Equal(t,
+		uint64(1280), sps.Width(),
+	)
+
+	assert.Equal(t,
+		uint64(720), sps.Height(),
+	)
+
+	assert.True(t, sps.VUIParametersPresentFlag)
+
+	pps, e = ParseH264PPS(h264PPSBytes)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint64(0), pps.PicParameterSetID,
+	)
+
+	assert.Equal(t,
+		uint64(0), pps.SeqParameterSetID,
+	)
+
+	assert.True(t, pps.EntropyCodingModeFlag)
+
+	_, e = ParseH264SPS(h264PPSBytes)
+
+	assert.Equal(t,
+		ErrH26xNALUnitTypeMismatch, e,
+	)
+
+	_, e = ParseH264SPS(h264SPSBytes[:8])
+
+	assert.Equal(t,
+		ErrH26xRBSPTruncated, e,
+	)
+}
+
+func TestParseH265SPS(t *testing.T) {
+	var (
+		e   error
+		sps H265SPS
+	)
+
+	sps, e = ParseH265SPS(h265SPSBytes)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint8(H265GeneralProfileIDCMain), sps.GeneralProfileIDC,
+	)
+
+	assert.False(t, sps.GeneralTierFlag)
+
+	assert.Equal(t,
+		uint8(120), sps.GeneralLevelIDC,
+	)
+
+	assert.Equal(t,
+		uint32(0x60000000), sps.GeneralProfileCompatibilityFlags,
+	)
+
+	assert.True(t, sps.GeneralProgressiveSourceFlag)
+
+	assert.Equal(t,
+		uint64(1920), sps.Width(),
+	)
+
+	assert.Equal(t,
+		uint64(1080), sps.Height(),
+	)
+
+	assert.Equal(t,
+		uint64(0), sps.BitDepthLumaMinus8,
+	)
+}
@@ -0,0 +1,463 @@
+package hid
+
+import (
+	"errors"
+
+	"github.com/encodingx/binary"
+)
+
+type HIDShortItemPrefixFormat struct {
+	// Reference: Section 6.2.2.2 "Short Items" of
+	// Device Class Definition for Human Interface Devices (HID)
+	// Version 1.11
+	// https://www.usb.org/sites/default/files/hid1_11.pdf
+
+	// > The item header (prefix) of a short item is 1 byte long:
+	// >
+	// >   Parts    bTag     bType    bSize    [data]
+	// >   Bit      7 6 5 4  3 2      1 0      ...
+	// >
+	// >   bSize:  Numeric expression specifying size of data:
+	// >             0 = 0 bytes
+	// >             1 = 1 byte
+	// >             2 = 2 bytes
+	// >             3 = 4 bytes
+	// >
+	// >   bType:  Numeric expression identifying type of item where:
+	// >             0 = Main
+	// >             1 = Global
+	// >             2 = Local
+	// >             3 = Reserved
+	// >
+	// >   bTag:   Numeric expression specifying the function of the item.
+
+	HIDShortItemPrefixFormatWord0 `word:"8"`
+}
+
+type HIDShortItemPrefixFormatWord0 struct {
+	Tag  uint8 `bitfield:"4"`
+	Type uint8 `bitfield:"2"`
+	Size uint8 `bitfield:"2"`
+}
+
+const (
+	HIDItemTypeMain = iota
+	HIDItemTypeGlobal
+	HIDItemTypeLocal
+	HIDItemTypeReserved
+)
+
+const (
+	HIDMainItemTagInput         = 0b1000
+	HIDMainItemTagOutput        = 0b1001
+	HIDMainItemTagCollection    = 0b1010
+	HIDMainItemTagFeature       = 0b1011
+	HIDMainItemTagEndCollection = 0b1100
+)
+
+const (
+	HIDGlobalItemTagUsagePage = iota
+	HIDGlobalItemTagLogicalMinimum
+	HIDGlobalItemTagLogicalMaximum
+	HIDGlobalItemTagPhysicalMinimum
+	HIDGlobalItemTagPhysicalMaximum
+	HIDGlobalItemTagUnitExponent
+	HIDGlobalItemTagUnit
+	HIDGlobalItemTagReportSize
+	HIDGlobalItemTagReportID
+	HIDGlobalItemTagReportCount
+	HIDGlobalItemTagPush
+	HIDGlobalItemTagPop
+)
+
+const (
+	HIDLocalItemTagUsage = iota
+	HIDLocalItemTagUsageMinimum
+	HIDLocalItemTagUsageMaximum
+)
+
+const (
+	HIDLongItemPrefix = 0b11111110
+)
+
+type HIDMainItemDataFormat struct {
+	// Reference: Section 6.2.2.5 "Input, Output, and Feature Items" of
+	// Device Class Definition for Human Interface Devices (HID)
+	// Version 1.11
+
+	// > Bit 0  {Data (0) | Constant (1)}
+	// > Bit 1  {Array (0) | Variable (1)}
+	// > Bit 2  {Absolute (0) | Relative (1)}
+	// > Bit 3  {No Wrap (0) | Wrap (1)}
+	// > Bit 4  {Linear (0) | Non Linear (1)}
+	// > Bit 5  {Preferred State (0) | No Preferred (1)}
+	// > Bit 6  {No Null position (0) | Null state(1)}
+	// > Bit 7  {Non Volatile (0) | Volatile (1)}  (Output and Feature only)
+	// > Bit 8  {Bit Field (0) | Buffered Bytes (1)}
+	// > Bit 31-9  Reserved (0)
+
+	HIDMainItemDataFormatWord0 `word:"16"`
+}
+
+type HIDMainItemDataFormatWord0 struct {
+	Reserved      uint8 `bitfield:"7"`
+	BufferedBytes bool  `bitfield:"1"`
+	Volatile      bool  `bitfield:"1"`
+	NullState     bool  `bitfield:"1"`
+	NoPreferred   bool  `bitfield:"1"`
+	NonLinear     bool  `bitfield:"1"`
+	Wrap          bool  `bitfield:"1"`
+	Relative      bool  `bitfield:"1"`
+	Variable      bool  `bitfield:"1"`
+	Constant      bool  `bitfield:"1"`
+}
+
+type HIDReportType uint8
+
+const (
+	HIDReportTypeInput HIDReportType = iota + 1
+	HIDReportTypeOutput
+	HIDReportTypeFeature
+)
+
+var (
+	ErrHIDItemTruncated = errors.New(
+		"A HID report descriptor item should be followed by " +
+			"the number of data bytes indicated by its prefix. " +
+			"The descriptor ends before the data of an item.",
+	)
+
+	ErrHIDPopWithoutPush = errors.New(
+		"A HID Pop item should restore global state saved by a Push item. " +
+			"The descriptor has a Pop item with no matching Push.",
+	)
+
+	ErrHIDReportFieldTooLong = errors.New(
+		"A HID report field should be at most 32 bits long. " +
+			"The descriptor has a Report Size exceeding 32 bits.",
+	)
+
+	ErrHIDReportFieldEmpty = errors.New(
+		"A HID report field of a nonzero Report Count " +
+			"should have a nonzero Report Size. " +
+			"The descriptor has a main item of Report Size zero " +
+			"and a nonzero Report Count.",
+	)
+)
+
+type HIDReportField struct {
+	OffsetInBits uint
+	Size         uint
+	Count        uint
+
+	// Usages are extended usages, with the usage page in the upper 16 bits.
+	Usages       []uint32
+	UsageMinimum uint32
+	UsageMaximum uint32
+
+	LogicalMinimum int32
+	LogicalMaximum int32
+
+	Flags HIDMainItemDataFormatWord0
+}
+
+func (f HIDReportField) Signed() bool {
+	return f.LogicalMinimum < 0
+}
+
+func (f HIDReportField) usage(i uint) (usage uint32, ok bool) {
+	if len(f.Usages) > 0 {
+		if i >= uint(len(f.Usages)) {
+			i = uint(len(f.Usages)) - 1
+		}
+
+		usage, ok = f.Usages[i], true
+
+		return
+	}
+
+	if f.UsageMaximum == 0 && f.UsageMinimum == 0 {
+		return
+	}
+
+	usage, ok = f.UsageMinimum+uint32(i), true
+
+	if usage > f.UsageMaximum {
+		usage, ok = 0, false
+	}
+
+	return
+}
+
+type HIDReportFormat struct {
+	Type         HIDReportType
+	ID           uint8
+	Fields       []HIDReportField
+	LengthInBits uint
+}
+
+func (f HIDReportFormat) LengthInBytes() int {
+	return int((f.LengthInBits + 7) / 8)
+}
+
+type HIDReportDescriptor struct {
+	Reports []HIDReportFormat
+
+	// Numbered is true if the descriptor declares report IDs,
+	// in which case every report is prefixed by its ID byte.
+	Numbered bool
+}
+
+type hidGlobalState struct {
+	usagePage      uint32
+	logicalMinimum int32
+	logicalMaximum int32
+	reportSize     uint32
+	reportID       uint8
+	reportCount    uint32
+}
+
+type hidLocalState struct {
+	usages       []uint32
+	usageMinimum uint32
+	usageMaximum uint32
+}
+
+func ParseHIDReportDescriptor(descriptor []byte) (
+	d HIDReportDescriptor, e error,
+) {
+	var (
+		data       uint32
+		dataLength int
+		global     hidGlobalState
+		globals    []hidGlobalState
+		i          int
+		local      hidLocalState
+		prefix     HIDShortItemPrefixFormat
+		reports    map[hidReportKey]int
+		signed     int32
+	)
+
+	reports = make(map[hidReportKey]int)
+
+	for i < len(descriptor) {
+		if descriptor[i] == HIDLongItemPrefix {
+			// Long items are reserved for future use and carry no layout.
+
+			if i+1 >= len(descriptor) ||
+				i+3+int(descriptor[i+1]) > len(descriptor) {
+				e = ErrHIDItemTruncated
+
+				return
+			}
+
+			i += 3 + int(descriptor[i+1])
+
+			continue
+		}
+
+		e = binary.Unmarshal(descriptor[i:i+1], &prefix)
+		if e != nil {
+			return
+		}
+
+		dataLength = int(prefix.Size)
+
+		if dataLength == 3 {
+			dataLength = 4
+		}
+
+		if i+1+dataLength > len(descriptor) {
+			e = ErrHIDItemTruncated
+
+			return
+		}
+
+		data, signed = hidItemData(descriptor[i+1 : i+1+dataLength])
+
+		i += 1 + dataLength
+
+		switch prefix.Type {
+		case HIDItemTypeMain:
+			switch prefix.Tag {
+			case HIDMainItemTagInput:
+				e = d.addField(reports, HIDReportTypeInput, global, local, data)
+
+			case HIDMainItemTagOutput:
+				e = d.addField(reports, HIDReportTypeOutput, global, local, data)
+
+			case HIDMainItemTagFeature:
+				e = d.addField(reports, HIDReportTypeFeature, global, local,
+					data,
+				)
+			}
+
+			if e != nil {
+				return
+			}
+
+			local = hidLocalState{}
+
+		case HIDItemTypeGlobal:
+			switch prefix.Tag {
+			case HIDGlobalItemTagUsagePage:
+				global.usagePage = data
+
+			case HIDGlobalItemTagLogicalMinimum:
+				global.logicalMinimum = signed
+
+			case HIDGlobalItemTagLogicalMaximum:
+				global.logicalMaximum = signed
+
+				if global.logicalMaximum < global.logicalMinimum {
+					// Devices commonly encode unsigned maxima
+					// in too few bytes to survive sign extension.
+
+					global.logicalMaximum = int32(data)
+				}
+
+			case HIDGlobalItemTagReportSize:
+				global.reportSize = data
+
+			case HIDGlobalItemTagReportID:
+				global.reportID = uint8(data)
+
+				d.Numbered = true
+
+			case HIDGlobalItemTagReportCount:
+				global.reportCount = data
+
+			case HIDGlobalItemTagPush:
+				globals = append(globals, global)
+
+			case HIDGlobalItemTagPop:
+				if len(globals) == 0 {
+					e = ErrHIDPopWithoutPush
+
+					return
+				}
+
+				global = globals[len(globals)-1]
+
+				globals = globals[:len(globals)-1]
+			}
+
+		case HIDItemTypeLocal:
+			if dataLength < 4 {
+				data = global.usagePage<<16 | data
+			}
+
+			switch prefix.Tag {
+			case HIDLocalItemTagUsage:
+				local.usages = append(local.usages, data)
+
+			case HIDLocalItemTagUsageMinimum:
+				local.usageMinimum = data
+
+			case HIDLocalItemTagUsageMaximum:
+				local.usageMaximum = data
+			}
+		}
+	}
+
+	return
+}
+
+type hidReportKey struct {
+	reportType HIDReportType
+	reportID   uint8
+}
+
+func (d *HIDReportDescriptor) addField(reports map[hidReportKey]int,
+	reportType HIDReportType, global hidGlobalState, local hidLocalState,
+	data uint32,
+) (
+	e error,
+) {
+	var (
+		field  HIDReportField
+		flags  HIDMainItemDataFormat
+		format *HIDReportFormat
+		i      int
+		inMap  bool
+		key    hidReportKey
+	)
+
+	if global.reportSize > 32 {
+		e = ErrHIDReportFieldTooLong
+
+		return
+	}
+
+	if global.reportSize == 0 && global.reportCount != 0 {
+		e = ErrHIDReportFieldEmpty
+
+		return
+	}
+
+	key = hidReportKey{reportType, global.reportID}
+
+	i, inMap = reports[key]
+
+	if !inMap {
+		d.Reports = append(d.Reports,
+			HIDReportFormat{
+				Type: reportType,
+				ID:   global.reportID,
+			},
+		)
+
+		i = len(d.Reports) - 1
+
+		reports[key] = i
+	}
+
+	format = &d.Reports[i]
+
+	field = HIDReportField{
+		OffsetInBits:   format.LengthInBits,
+		Size:           uint(global.reportSize),
+		Count:          uint(global.reportCount),
+		Usages:         local.usages,
+		UsageMinimum:   local.usageMinimum,
+		UsageMaximum:   local.usageMaximum,
+		LogicalMinimum: global.logicalMinimum,
+		LogicalMaximum: global.logicalMaximum,
+	}
+
+	e = binary.Unmarshal([]byte{byte(data >> 8), byte(data)}, &flags)
+	if e != nil {
+		return
+	}
+
+	field.Flags = flags.HIDMainItemDataFormatWord0
+
+	format.Fields = append(format.Fields, field)
+
+	format.LengthInBits += field.Size * field.Count
+
+	return
+}
+
+func hidItemData(bytes []byte) (data uint32, signed int32) {
+	var (
+		i int
+	)
+
+	for i = len(bytes) - 1; i >= 0; i-- {
+		data = data<<8 | uint32(bytes[i])
+	}
+
+	switch len(bytes) {
+	case 1:
+		signed = int32(int8(data))
+
+	case 2:
+		signed = int32(int16(data))
+
+	default:
+		signed = int32(data)
+	}
+
+	return
+}
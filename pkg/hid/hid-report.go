@@ -0,0 +1,209 @@
+package hid
+
+import (
+	"errors"
+
+	"github.com/encodingx/binary"
+)
+
+var (
+	ErrHIDReportFormatNotFound = errors.New(
+		"A HID report should match a report declared in the descriptor. " +
+			"The descriptor declares no report of that type and ID.",
+	)
+
+	ErrHIDReportTooShort = errors.New(
+		"A HID report should be at least as long as its declared format. " +
+			"The report is shorter than the sum of lengths of its fields.",
+	)
+)
+
+type HIDUsageValue struct {
+	UsagePage uint16
+	Usage     uint16
+	Value     int64
+}
+
+func (d HIDReportDescriptor) Format(reportType HIDReportType, id uint8) (
+	format HIDReportFormat, ok bool,
+) {
+	for _, format = range d.Reports {
+		if format.Type == reportType && format.ID == id {
+			ok = true
+
+			return
+		}
+	}
+
+	format = HIDReportFormat{}
+
+	return
+}
+
+func (d HIDReportDescriptor) DecodeReport(reportType HIDReportType,
+	report []byte,
+) (
+	values []HIDUsageValue, e error,
+) {
+	var (
+		format HIDReportFormat
+		id     uint8
+		ok     bool
+	)
+
+	if d.Numbered {
+		if len(report) == 0 {
+			e = ErrHIDReportTooShort
+
+			return
+		}
+
+		id, report = report[0], report[1:]
+	}
+
+	format, ok = d.Format(reportType, id)
+	if !ok {
+		e = ErrHIDReportFormatNotFound
+
+		return
+	}
+
+	values, e = format.Decode(report)
+	if e != nil {
+		return
+	}
+
+	return
+}
+
+func (f HIDReportFormat) Decode(report []byte) (
+	values []HIDUsageValue, e error,
+) {
+	// Report data is little-endian and bit fields are packed LSB-first,
+	// so the bits of a field are consecutive in the little-endian value
+	// of the bytes it spans, read with the byte order of HIDReportRunFormat.
+
+	var (
+		count uint
+		field HIDReportField
+		i     uint
+		index int64
+		raw   uint64
+		usage uint32
+		ok    bool
+		value int64
+	)
+
+	if len(report) < f.LengthInBytes() {
+		e = ErrHIDReportTooShort
+
+		return
+	}
+
+	for _, field = range f.Fields {
+		if field.Flags.Constant || field.Size == 0 {
+			continue
+		}
+
+		// Decode no more values than the report holds.
+
+		count = field.Count
+
+		if uint(8*len(report)) < field.OffsetInBits+count*field.Size {
+			count = (uint(8*len(report)) - field.OffsetInBits) / field.Size
+		}
+
+		for i = 0; i < count; i++ {
+			raw, e = hidReadBits(report, field.OffsetInBits+i*field.Size,
+				field.Size,
+			)
+			if e != nil {
+				return
+			}
+
+			value = int64(raw)
+
+			if field.Signed() && field.Size > 0 && raw>>(field.Size-1) == 1 {
+				value -= 1 << field.Size
+			}
+
+			if field.Flags.Variable {
+				usage, ok = field.usage(i)
+				if !ok {
+					continue
+				}
+
+				values = append(values,
+					HIDUsageValue{
+						UsagePage: uint16(usage >> 16),
+						Usage:     uint16(usage),
+						Value:     value,
+					},
+				)
+
+				continue
+			}
+
+			// An array field holds indices into its usages;
+			// values outside the logical range report no control.
+
+			if value < int64(field.LogicalMinimum) ||
+				value > int64(field.LogicalMaximum) {
+				continue
+			}
+
+			index = value - int64(field.LogicalMinimum)
+
+			usage, ok = field.usage(uint(index))
+			if !ok || uint16(usage) == 0 {
+				continue
+			}
+
+			values = append(values,
+				HIDUsageValue{
+					UsagePage: uint16(usage >> 16),
+					Usage:     uint16(usage),
+					Value:     1,
+				},
+			)
+		}
+	}
+
+	return
+}
+
+// HIDReportRunFormat reads up to eight bytes of a report
+// as a little-endian value.
+type HIDReportRunFormat struct {
+	HIDReportRunFormatWord0 `word:"64,order=hgfedcba"`
+}
+
+type HIDReportRunFormatWord0 struct {
+	Bits uint64 `bitfield:"64"`
+}
+
+// hidReadBits reads a field of a report packed LSB-first,
+// of up to 57 bits, from the run of bytes it spans.
+func hidReadBits(report []byte, offset, length uint) (
+	value uint64, e error,
+) {
+	var (
+		bytes [8]byte
+		run   HIDReportRunFormat
+	)
+
+	copy(bytes[:], report[offset/8:])
+
+	e = binary.Unmarshal(bytes[:], &run)
+	if e != nil {
+		return
+	}
+
+	value = run.Bits >> (offset % 8)
+
+	if length < 64 {
+		value &= 1<<length - 1
+	}
+
+	return
+}
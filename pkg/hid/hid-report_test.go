@@ -0,0 +1,266 @@
+package hid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHIDReportDescriptor(t *testing.T) {
+	var (
+		d      HIDReportDescriptor
+		e      error
+		format HIDReportFormat
+		ok     bool
+	)
+
+	d, e = ParseHIDReportDescriptor(mouseReportDescriptor)
+
+	assert.Nil(t, e)
+
+	assert.True(t, d.Numbered)
+
+	format, ok = d.Format(HIDReportTypeInput, mouseReportID)
+
+	assert.True(t, ok)
+
+	assert.Equal(t,
+		uint(24), format.LengthInBits,
+	)
+
+	assert.Len(t, format.Fields, 3)
+
+	assert.Equal(t,
+		uint(8), format.Fields[2].OffsetInBits,
+	)
+
+	assert.True(t, format.Fields[2].Signed())
+
+	assert.True(t, format.Fields[1].Flags.Constant)
+}
+
+func TestShouldReturnErrorGivenHIDReportFieldEmpty(t *testing.T) {
+	var (
+		e error
+	)
+
+	_, e = ParseHIDReportDescriptor(
+		[]byte{
+			0x75, 0x00, // Report Size (0)
+			0x97, 0xff, 0xff, 0xff, 0xff, // Report Count (4294967295)
+			0x81, 0x02, // Input (Data, Variable, Absolute)
+		},
+	)
+
+	assert.Equal(t,
+		ErrHIDReportFieldEmpty, e,
+	)
+}
+
+func TestParseHIDReportDescriptorGivenBufferedBytes(t *testing.T) {
+	var (
+		d      HIDReportDescriptor
+		e      error
+		format HIDReportFormat
+		ok     bool
+	)
+
+	d, e = ParseHIDReportDescriptor(
+		[]byte{
+			0x75, 0x08, // Report Size (8)
+			0x95, 0x04, // Report Count (4)
+			0x82, 0x02, 0x01, // Input (Data, Variable, Buffered Bytes)
+		},
+	)
+
+	assert.Nil(t, e)
+
+	format, ok = d.Format(HIDReportTypeInput, 0)
+
+	assert.True(t, ok)
+
+	assert.True(t, format.Fields[0].Flags.BufferedBytes)
+
+	assert.True(t, format.Fields[0].Flags.Variable)
+}
+
+func TestShouldReturnErrorGivenHIDLongItemTruncated(t *testing.T) {
+	var (
+		e error
+	)
+
+	_, e = ParseHIDReportDescriptor(
+		[]byte{
+			0xfe, 0x04, 0xf0, // Long item of 4 bytes of data
+			0x00, 0x00,
+		},
+	)
+
+	assert.Equal(t,
+		ErrHIDItemTruncated, e,
+	)
+}
+
+func TestDecodeHIDReport(t *testing.T) {
+	var (
+		d      HIDReportDescriptor
+		e      error
+		values []HIDUsageValue
+	)
+
+	d, e = ParseHIDReportDescriptor(mouseReportDescriptor)
+
+	assert.Nil(t, e)
+
+	values, e = d.DecodeReport(HIDReportTypeInput,
+		[]byte{mouseReportID, 0b00000101, 0x03, 0xfe},
+	)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]HIDUsageValue{
+			{UsagePage: 0x09, Usage: 1, Value: 1},
+			{UsagePage: 0x09, Usage: 2, Value: 0},
+			{UsagePage: 0x09, Usage: 3, Value: 1},
+			{UsagePage: 0x01, Usage: 0x30, Value: 3},
+			{UsagePage: 0x01, Usage: 0x31, Value: -2},
+		},
+		values,
+	)
+
+	_, e = d.DecodeReport(HIDReportTypeInput,
+		[]byte{mouseReportID, 0},
+	)
+
+	assert.Equal(t,
+		ErrHIDReportTooShort, e,
+	)
+
+	_, e = d.DecodeReport(HIDReportTypeFeature,
+		[]byte{mouseReportID, 0, 0, 0},
+	)
+
+	assert.Equal(t,
+		ErrHIDReportFormatNotFound, e,
+	)
+}
+
+func TestDecodeHIDReportGivenUnalignedField(t *testing.T) {
+	var (
+		d      HIDReportDescriptor
+		e      error
+		values []HIDUsageValue
+	)
+
+	d, e = ParseHIDReportDescriptor(
+		[]byte{
+			0x05, 0x01, // Usage Page (Generic Desktop)
+			0x15, 0x00, // Logical Minimum (0)
+			0x27, 0xff, 0xff, 0xff, 0x7f, // Logical Maximum (2147483647)
+			0x95, 0x01, // Report Count (1)
+			0x09, 0x30, // Usage (X)
+			0x75, 0x01, // Report Size (1)
+			0x81, 0x02, // Input (Data, Variable, Absolute)
+			0x09, 0x31, // Usage (Y)
+			0x75, 0x20, // Report Size (32)
+			0x81, 0x02, // Input (Data, Variable, Absolute)
+		},
+	)
+
+	assert.Nil(t, e)
+
+	// A 32-bit field at bit offset 1 spans five bytes.
+
+	values, e = d.DecodeReport(HIDReportTypeInput,
+		[]byte{0xf1, 0xac, 0x68, 0x24, 0x00},
+	)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]HIDUsageValue{
+			{UsagePage: 0x01, Usage: 0x30, Value: 1},
+			{UsagePage: 0x01, Usage: 0x31, Value: 0x12345678},
+		},
+		values,
+	)
+}
+
+func TestDecodeHIDArrayReport(t *testing.T) {
+	var (
+		d      HIDReportDescriptor
+		e      error
+		values []HIDUsageValue
+	)
+
+	d, e = ParseHIDReportDescriptor(keyboardReportDescriptor)
+
+	assert.Nil(t, e)
+
+	assert.False(t, d.Numbered)
+
+	values, e = d.DecodeReport(HIDReportTypeInput,
+		[]byte{0x04, 0x00, 0x00},
+	)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]HIDUsageValue{
+			{UsagePage: 0x07, Usage: 0x04, Value: 1},
+		},
+		values,
+	)
+}
+
+const (
+	mouseReportID = 2
+)
+
+var (
+	mouseReportDescriptor = []byte{
+		0x05, 0x01, // Usage Page (Generic Desktop)
+		0x09, 0x02, // Usage (Mouse)
+		0xa1, 0x01, // Collection (Application)
+		0x85, 0x02, //   Report ID (2)
+		0x09, 0x01, //   Usage (Pointer)
+		0xa1, 0x00, //   Collection (Physical)
+		0x05, 0x09, //     Usage Page (Button)
+		0x19, 0x01, //     Usage Minimum (1)
+		0x29, 0x03, //     Usage Maximum (3)
+		0x15, 0x00, //     Logical Minimum (0)
+		0x25, 0x01, //     Logical Maximum (1)
+		0x95, 0x03, //     Report Count (3)
+		0x75, 0x01, //     Report Size (1)
+		0x81, 0x02, //     Input (Data, Variable, Absolute)
+		0x95, 0x01, //     Report Count (1)
+		0x75, 0x05, //     Report Size (5)
+		0x81, 0x03, //     Input (Constant)
+		0x05, 0x01, //     Usage Page (Generic Desktop)
+		0x09, 0x30, //     Usage (X)
+		0x09, 0x31, //     Usage (Y)
+		0x15, 0x81, //     Logical Minimum (-127)
+		0x25, 0x7f, //     Logical Maximum (127)
+		0x75, 0x08, //     Report Size (8)
+		0x95, 0x02, //     Report Count (2)
+		0x81, 0x06, //     Input (Data, Variable, Relative)
+		0xc0, //         End Collection
+		0xc0, //       End Collection
+	}
+
+	keyboardReportDescriptor = []byte{
+		0x05, 0x01, // Usage Page (Generic Desktop)
+		0x09, 0x06, // Usage (Keyboard)
+		0xa1, 0x01, // Collection (Application)
+		0x05, 0x07, //   Usage Page (Keyboard/Keypad)
+		0x19, 0x00, //   Usage Minimum (0)
+		0x2a, 0xff, 0x00, // Usage Maximum (255)
+		0x15, 0x00, //   Logical Minimum (0)
+		0x26, 0xff, 0x00, // Logical Maximum (255)
+		0x75, 0x08, //   Report Size (8)
+		0x95, 0x03, //   Report Count (3)
+		0x81, 0x00, //   Input (Data, Array, Absolute)
+		0xc0, //       End Collection
+	}
+)
@@ -0,0 +1,88 @@
+package ieee8023
+
+type IEEE8023EthernetHeaderFormat struct {
+	// Reference: Clause 3.1.1 "Packet format" of
+	// IEEE Std 802.3-2018 Standard for Ethernet
+
+	// > Figure 3-1 shows the eight fields of a packet: the Preamble, Start
+	// > Frame Delimiter (SFD), the addresses of the MAC frame's destination
+	// > and source, a length or type field to indicate the length or protocol
+	// > type of the following field that contains the MAC client data, a
+	// > field that contains padding if required, and the frame check sequence
+	// > field containing a cyclic redundancy check value to detect errors in
+	// > a received MAC frame.
+	// >
+	// >   +----------------------+
+	// >   | DESTINATION ADDRESS  |  6 octets
+	// >   +----------------------+
+	// >   | SOURCE ADDRESS       |  6 octets
+	// >   +----------------------+
+	// >   | LENGTH/TYPE          |  2 octets
+	// >   +----------------------+
+	// >   | MAC CLIENT DATA      |
+	// >   +----------------------+  46-1500/1504/1982 octets
+	// >   | PAD                  |
+	// >   +----------------------+
+	// >   | FRAME CHECK SEQUENCE |  4 octets
+	// >   +----------------------+
+	//
+	// The preamble, SFD and frame check sequence are usually stripped by
+	// the network interface, leaving the 14-octet header below.
+
+	IEEE8023EthernetHeaderFormatWord0 `word:"48"`
+	IEEE8023EthernetHeaderFormatWord1 `word:"48"`
+	IEEE8023EthernetHeaderFormatWord2 `word:"16"`
+}
+
+type IEEE8023EthernetHeaderFormatWord0 struct {
+	DestinationAddressOctet0 uint8 `bitfield:"8"`
+	DestinationAddressOctet1 uint8 `bitfield:"8"`
+	DestinationAddressOctet2 uint8 `bitfield:"8"`
+	DestinationAddressOctet3 uint8 `bitfield:"8"`
+	DestinationAddressOctet4 uint8 `bitfield:"8"`
+	DestinationAddressOctet5 uint8 `bitfield:"8"`
+}
+
+type IEEE8023EthernetHeaderFormatWord1 struct {
+	SourceAddressOctet0 uint8 `bitfield:"8"`
+	SourceAddressOctet1 uint8 `bitfield:"8"`
+	SourceAddressOctet2 uint8 `bitfield:"8"`
+	SourceAddressOctet3 uint8 `bitfield:"8"`
+	SourceAddressOctet4 uint8 `bitfield:"8"`
+	SourceAddressOctet5 uint8 `bitfield:"8"`
+}
+
+type IEEE8023EthernetHeaderFormatWord2 struct {
+	LengthOrType uint16 `bitfield:"16"`
+	// > Length/Type field
+	// >
+	// > This two-octet field takes one of two meanings, depending on its
+	// > numeric value. ...
+	// >
+	// > a) If the value of this field is less than or equal to 1500 decimal
+	// >    (05DC hexadecimal), then the Length/Type field indicates the
+	// >    number of MAC client data octets contained in the subsequent MAC
+	// >    Client Data field of the basic frame (Length interpretation).
+	// > b) If the value of this field is greater than or equal to 1536
+	// >    decimal (0600 hexadecimal), then the Length/Type field indicates
+	// >    the Ethertype of the MAC client protocol (Type interpretation).
+}
+
+const (
+	IEEE8023EthernetHeaderLengthInBytes = 14
+)
+
+const (
+	IEEE8023EthernetHeaderLengthMaximum = 0x05dc
+	IEEE8023EthernetHeaderTypeMinimum   = 0x0600
+)
+
+const (
+	IEEE8023EthernetHeaderTypeIPv4                        = 0x0800
+	IEEE8023EthernetHeaderTypeARP                         = 0x0806
+	IEEE8023EthernetHeaderTypeTransparentEthernetBridging = 0x6558
+	IEEE8023EthernetHeaderTypeVLAN                        = 0x8100
+	IEEE8023EthernetHeaderTypeIPv6                        = 0x86dd
+	IEEE8023EthernetHeaderTypeMPLS                        = 0x8847
+	IEEE8023EthernetHeaderTypeLLDP                        = 0x88cc
+)
@@ -0,0 +1,160 @@
+package ieee8023
+
+import (
+	"testing"
+
+	"github.com/encodingx/binary"
+	"github.com/encodingx/binary/pkg/rfc7348"
+	"github.com/encodingx/binary/pkg/rfc791"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncapsulateAndDecapsulateIEEE8023(t *testing.T) {
+	const (
+		vni = 0xabcdef
+
+		udpHeaderLengthInBytes = 8
+	)
+
+	var (
+		e                   error
+		ethernetHeader      IEEE8023EthernetHeaderFormat
+		ethernetHeaderBytes []byte
+		frame               []byte
+		innerEthernetHeader IEEE8023EthernetHeaderFormat
+		innerFrame          []byte
+		internetHeader      rfc791.RFC791InternetHeaderFormatWithoutOptions
+		internetHeaderBytes []byte
+		udpHeaderBytes      []byte
+		vxlanHeader         rfc7348.RFC7348VXLANHeaderFormat
+		vxlanPacket         []byte
+	)
+
+	// Encapsulate an inner frame in VXLAN over UDP over IPv4 over Ethernet.
+
+	vxlanPacket, e = rfc7348.EncapsulateRFC7348(vni, innerFrameBytes)
+
+	assert.Nil(t, e)
+
+	udpHeaderBytes = []byte{
+		0xc0, 0x00, // source port
+		0x12, 0xb5, // destination port, rfc7348.RFC7348VXLANUDPDestinationPort
+		0x00, byte(udpHeaderLengthInBytes + len(vxlanPacket)), // length
+		0x00, 0x00, // checksum
+	}
+
+	internetHeader.Version = 4
+	internetHeader.IHL = 5
+	internetHeader.TotalLength = uint16(
+		20 + len(udpHeaderBytes) + len(vxlanPacket),
+	)
+	internetHeader.TimeToLive = 64
+	internetHeader.Protocol = rfc791.RFC791InternetHeaderProtocolUserDatagram
+	internetHeader.SourceAddressOctet0 = 192
+	internetHeader.SourceAddressOctet3 = 1
+	internetHeader.DestinationAddressOctet0 = 192
+	internetHeader.DestinationAddressOctet3 = 2
+
+	internetHeaderBytes, e = binary.Marshal(&internetHeader)
+
+	assert.Nil(t, e)
+
+	ethernetHeader.DestinationAddressOctet0 = 0x02
+	ethernetHeader.DestinationAddressOctet5 = 0x0a
+	ethernetHeader.SourceAddressOctet0 = 0x02
+	ethernetHeader.SourceAddressOctet5 = 0x0b
+	ethernetHeader.LengthOrType = IEEE8023EthernetHeaderTypeIPv4
+
+	ethernetHeaderBytes, e = binary.Marshal(&ethernetHeader)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]byte{
+			0x02, 0x00, 0x00, 0x00, 0x00, 0x0a, // destination
+			0x02, 0x00, 0x00, 0x00, 0x00, 0x0b, // source
+			0x08, 0x00, // IPv4
+		},
+		ethernetHeaderBytes,
+	)
+
+	frame = append(frame, ethernetHeaderBytes...)
+	frame = append(frame, internetHeaderBytes...)
+	frame = append(frame, udpHeaderBytes...)
+	frame = append(frame, vxlanPacket...)
+
+	// Decapsulate it again.
+
+	ethernetHeader = IEEE8023EthernetHeaderFormat{}
+
+	e = binary.Unmarshal(frame[:IEEE8023EthernetHeaderLengthInBytes],
+		&ethernetHeader,
+	)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint16(IEEE8023EthernetHeaderTypeIPv4),
+		ethernetHeader.LengthOrType,
+	)
+
+	frame = frame[IEEE8023EthernetHeaderLengthInBytes:]
+
+	internetHeader = rfc791.RFC791InternetHeaderFormatWithoutOptions{}
+
+	e = binary.Unmarshal(frame[:4*5], &internetHeader)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint8(rfc791.RFC791InternetHeaderProtocolUserDatagram),
+		internetHeader.Protocol,
+	)
+
+	assert.Equal(t,
+		int(internetHeader.TotalLength), len(frame),
+	)
+
+	frame = frame[4*internetHeader.IHL:]
+
+	assert.Equal(t,
+		uint16(rfc7348.RFC7348VXLANUDPDestinationPort),
+		uint16(frame[2])<<8|uint16(frame[3]),
+	)
+
+	frame = frame[udpHeaderLengthInBytes:]
+
+	vxlanHeader, innerFrame, e = rfc7348.DecapsulateRFC7348(frame)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint32(vni), vxlanHeader.VNI,
+	)
+
+	e = binary.Unmarshal(innerFrame[:IEEE8023EthernetHeaderLengthInBytes],
+		&innerEthernetHeader,
+	)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint16(IEEE8023EthernetHeaderTypeIPv4),
+		innerEthernetHeader.LengthOrType,
+	)
+
+	assert.Equal(t,
+		innerFrameBytes, innerFrame,
+	)
+}
+
+var (
+	innerFrameBytes = []byte{
+		0x02, 0x00, 0x00, 0x00, 0x00, 0x01, // destination
+		0x02, 0x00, 0x00, 0x00, 0x00, 0x02, // source
+		0x08, 0x00, // IPv4
+		0x45, 0x00, 0x00, 0x14, 0x00, 0x00, 0x40, 0x00,
+		0x40, 0x06, 0x00, 0x00, 0x0a, 0x00, 0x00, 0x01,
+		0x0a, 0x00, 0x00, 0x02,
+	}
+)
@@ -0,0 +1,106 @@
+package mpegts
+
+import (
+	"errors"
+)
+
+// MPEGTSContinuityChecker tracks the continuity_counter of each PID.
+// Reference: Section 2.4.3.3 "Semantic definition of fields in
+// transport stream packet layer"
+//
+// > In transport streams, duplicate packets may be sent as two,
+// > consecutive transport stream packets of the same PID. The duplicate
+// > packets shall have the same continuity_counter value as the original
+// > packet and the adaptation_field_control field shall be equal to '01'
+// > or '11'.
+type MPEGTSContinuityChecker struct {
+	states map[uint16]mpegtsContinuityState
+}
+
+type mpegtsContinuityState struct {
+	counter   uint8
+	duplicate bool
+}
+
+const (
+	mpegtsContinuityCounterModulus = 16
+)
+
+var (
+	ErrMPEGTSContinuityCounterDiscontinuous = errors.New(
+		"The continuity_counter of an MPEG-TS packet should increment " +
+			"by one with each packet of the same PID that has a payload, " +
+			"unless a discontinuity is indicated. " +
+			"The continuity_counter skips or repeats a value.",
+	)
+)
+
+func NewMPEGTSContinuityChecker() (c *MPEGTSContinuityChecker) {
+	c = &MPEGTSContinuityChecker{
+		states: make(map[uint16]mpegtsContinuityState),
+	}
+
+	return
+}
+
+// Check compares the continuity_counter of a packet with
+// that of the last packet of the same PID.
+// A single repetition of a packet with a payload is reported as a duplicate,
+// which the caller may discard.
+// On a discontinuity, the checker resynchronises to the packet.
+// Null packets are not checked.
+func (c *MPEGTSContinuityChecker) Check(packet *MPEGTSPacket) (
+	duplicate bool, e error,
+) {
+	var (
+		expected uint8
+		seen     bool
+		state    mpegtsContinuityState
+	)
+
+	if packet.PID == MPEGTSPIDNull {
+		return
+	}
+
+	state, seen = c.states[packet.PID]
+
+	c.states[packet.PID] = mpegtsContinuityState{
+		counter: packet.ContinuityCounter,
+	}
+
+	if !seen || packet.AdaptationField.DiscontinuityIndicator {
+		return
+	}
+
+	if !packet.HasPayload() {
+		expected = state.counter
+	} else {
+		expected = (state.counter + 1) % mpegtsContinuityCounterModulus
+	}
+
+	if packet.ContinuityCounter == expected {
+		return
+	}
+
+	if packet.HasPayload() && packet.ContinuityCounter == state.counter &&
+		!state.duplicate {
+		duplicate = true
+
+		c.states[packet.PID] = mpegtsContinuityState{
+			counter:   packet.ContinuityCounter,
+			duplicate: true,
+		}
+
+		return
+	}
+
+	e = ErrMPEGTSContinuityCounterDiscontinuous
+
+	return
+}
+
+func (c *MPEGTSContinuityChecker) Reset() {
+	c.states = make(map[uint16]mpegtsContinuityState)
+
+	return
+}
@@ -0,0 +1,51 @@
+package mpegts
+
+const (
+	mpegtsCRC32Polynomial = 0x04c11db7
+)
+
+var (
+	mpegtsCRC32Table = newMPEGTSCRC32Table()
+)
+
+// MPEGTSCRC32 computes the CRC-32/MPEG-2 of bytes:
+// polynomial 0x04C11DB7, initial value 0xFFFFFFFF, unreflected,
+// and without a final XOR.
+// Over a PSI section including its CRC_32 field, the result is zero.
+func MPEGTSCRC32(bytes []byte) (crc uint32) {
+	var (
+		b uint8
+	)
+
+	crc = 0xffffffff
+
+	for _, b = range bytes {
+		crc = crc<<8 ^ mpegtsCRC32Table[uint8(crc>>24)^b]
+	}
+
+	return
+}
+
+func newMPEGTSCRC32Table() (table [256]uint32) {
+	var (
+		crc uint32
+		i   int
+		j   int
+	)
+
+	for i = range table {
+		crc = uint32(i) << 24
+
+		for j = 0; j < 8; j++ {
+			if crc&0x80000000 != 0 {
+				crc = crc<<1 ^ mpegtsCRC32Polynomial
+			} else {
+				crc = crc << 1
+			}
+		}
+
+		table[i] = crc
+	}
+
+	return
+}
@@ -0,0 +1,455 @@
+package mpegts
+
+import (
+	"errors"
+
+	"github.com/encodingx/binary"
+)
+
+type MPEGTSPacketHeaderFormat struct {
+	// Reference: Section 2.4.3.2 "Transport stream packet layer" of
+	// ITU-T H.222.0 | ISO/IEC 13818-1 Generic coding of moving pictures
+	// and associated audio information: Systems
+
+	// > Table 2-2 – Transport packet of this Recommendation |
+	// > International Standard
+	// >
+	// >   transport_packet(){
+	// >     sync_byte                                    8  bslbf
+	// >     transport_error_indicator                    1  bslbf
+	// >     payload_unit_start_indicator                 1  bslbf
+	// >     transport_priority                           1  bslbf
+	// >     PID                                         13  uimsbf
+	// >     transport_scrambling_control                 2  bslbf
+	// >     adaptation_field_control                     2  bslbf
+	// >     continuity_counter                           4  uimsbf
+	// >     if(adaptation_field_control = = '10' ||
+	// >        adaptation_field_control = = '11'){
+	// >       adaptation_field()
+	// >     }
+	// >     if(adaptation_field_control = = '01' ||
+	// >        adaptation_field_control = = '11') {
+	// >       for (i = 0; i < N; i++){
+	// >         data_byte                                8  bslbf
+	// >       }
+	// >     }
+	// >   }
+
+	MPEGTSPacketHeaderFormatWord0 `word:"32"`
+}
+
+type MPEGTSPacketHeaderFormatWord0 struct {
+	SyncByte uint8 `bitfield:"8"`
+	// > sync_byte – The sync_byte is a fixed 8-bit field whose value is
+	// > '0100 0111' (0x47).
+
+	TransportErrorIndicator   bool `bitfield:"1"`
+	PayloadUnitStartIndicator bool `bitfield:"1"`
+	TransportPriority         bool `bitfield:"1"`
+
+	PID uint16 `bitfield:"13"`
+	// > PID – The PID is a 13-bit field, indicating the type of the data
+	// > stored in the packet payload.
+
+	TransportScramblingControl uint8 `bitfield:"2"`
+	AdaptationFieldControl     uint8 `bitfield:"2"`
+
+	ContinuityCounter uint8 `bitfield:"4"`
+	// > continuity_counter – The continuity_counter is a 4-bit field
+	// > incrementing with each transport stream packet with the same PID.
+	// > The continuity_counter wraps around to 0 after its maximum value.
+	// > The continuity_counter shall not be incremented when the
+	// > adaptation_field_control of the packet equals '00' or '10'.
+}
+
+const (
+	MPEGTSPacketLengthInBytes       = 188
+	MPEGTSPacketHeaderLengthInBytes = 4
+	MPEGTSSyncByte                  = 0x47
+)
+
+const (
+	MPEGTSAdaptationFieldControlReserved                = 0b00
+	MPEGTSAdaptationFieldControlPayloadOnly             = 0b01
+	MPEGTSAdaptationFieldControlAdaptationFieldOnly     = 0b10
+	MPEGTSAdaptationFieldControlAdaptationFieldThenData = 0b11
+)
+
+const (
+	MPEGTSPIDProgramAssociationTable  = 0x0000
+	MPEGTSPIDConditionalAccessTable   = 0x0001
+	MPEGTSPIDTransportStreamDescTable = 0x0002
+	MPEGTSPIDNull                     = 0x1fff
+)
+
+type MPEGTSAdaptationFieldHeaderFormat struct {
+	// Reference: Section 2.4.3.4 "Adaptation field"
+
+	// > Table 2-6 – Transport stream adaptation field
+	// >
+	// >   adaptation_field() {
+	// >     adaptation_field_length                      8  uimsbf
+	// >     if (adaptation_field_length > 0) {
+	// >       discontinuity_indicator                    1  bslbf
+	// >       random_access_indicator                    1  bslbf
+	// >       elementary_stream_priority_indicator       1  bslbf
+	// >       PCR_flag                                   1  bslbf
+	// >       OPCR_flag                                  1  bslbf
+	// >       splicing_point_flag                        1  bslbf
+	// >       transport_private_data_flag                1  bslbf
+	// >       adaptation_field_extension_flag            1  bslbf
+	// >       if (PCR_flag = = '1') {
+	// >         program_clock_reference_base            33  uimsbf
+	// >         reserved                                 6  bslbf
+	// >         program_clock_reference_extension        9  uimsbf
+	// >       }
+	// >       if (OPCR_flag = = '1') {
+	// >         original_program_clock_reference_base   33  uimsbf
+	// >         reserved                                 6  bslbf
+	// >         original_program_clock_reference_extension 9 uimsbf
+	// >       }
+	// >       if (splicing_point_flag = = '1') {
+	// >         splice_countdown                         8  tcimsbf
+	// >       }
+	// >       ...
+	// >     }
+	// >   }
+
+	MPEGTSAdaptationFieldHeaderFormatWord0 `word:"16"`
+}
+
+type MPEGTSAdaptationFieldHeaderFormatWord0 struct {
+	AdaptationFieldLength             uint8 `bitfield:"8"`
+	DiscontinuityIndicator            bool  `bitfield:"1"`
+	RandomAccessIndicator             bool  `bitfield:"1"`
+	ElementaryStreamPriorityIndicator bool  `bitfield:"1"`
+	PCRFlag                           bool  `bitfield:"1"`
+	OPCRFlag                          bool  `bitfield:"1"`
+	SplicingPointFlag                 bool  `bitfield:"1"`
+	TransportPrivateDataFlag          bool  `bitfield:"1"`
+	AdaptationFieldExtensionFlag      bool  `bitfield:"1"`
+}
+
+type MPEGTSProgramClockReferenceFormat struct {
+	// The 42-bit program clock reference spans six bytes,
+	// with a 33-bit base in units of 90 kHz
+	// and a 9-bit extension in units of 27 MHz.
+
+	MPEGTSProgramClockReferenceFormatWord0 `word:"48"`
+}
+
+type MPEGTSProgramClockReferenceFormatWord0 struct {
+	Base      uint64 `bitfield:"33"`
+	Reserved  uint8  `bitfield:"6"`
+	Extension uint16 `bitfield:"9"`
+}
+
+const (
+	MPEGTSProgramClockReferenceExtensionModulus = 300
+)
+
+// Value returns the program clock reference in units of 27 MHz.
+func (f MPEGTSProgramClockReferenceFormat) Value() uint64 {
+	return f.Base*MPEGTSProgramClockReferenceExtensionModulus +
+		uint64(f.Extension)
+}
+
+func (f *MPEGTSProgramClockReferenceFormat) SetValue(value uint64) {
+	f.Base = value / MPEGTSProgramClockReferenceExtensionModulus
+	f.Extension = uint16(value % MPEGTSProgramClockReferenceExtensionModulus)
+	f.Reserved = 1<<6 - 1
+
+	return
+}
+
+type MPEGTSAdaptationField struct {
+	MPEGTSAdaptationFieldHeaderFormat
+
+	PCR             MPEGTSProgramClockReferenceFormat
+	OPCR            MPEGTSProgramClockReferenceFormat
+	SpliceCountdown int8
+}
+
+type MPEGTSPacket struct {
+	MPEGTSPacketHeaderFormat
+
+	AdaptationField MPEGTSAdaptationField
+	Payload         []byte
+}
+
+var (
+	ErrMPEGTSAdaptationFieldTooLong = errors.New(
+		"The adaptation field of an MPEG-TS packet should fit " +
+			"within the 184 bytes following the packet header, " +
+			"alongside any payload. " +
+			"The adaptation field does not fit in the packet.",
+	)
+
+	ErrMPEGTSPacketLengthInvalid = errors.New(
+		"An MPEG-TS packet should be 188 bytes long. " +
+			"The packet is not 188 bytes long.",
+	)
+
+	ErrMPEGTSSyncByteNotFound = errors.New(
+		"An MPEG-TS packet should begin with sync byte 0x47. " +
+			"The packet does not begin with the sync byte.",
+	)
+)
+
+func (p MPEGTSPacket) HasAdaptationField() bool {
+	return p.AdaptationFieldControl&0b10 != 0
+}
+
+func (p MPEGTSPacket) HasPayload() bool {
+	return p.AdaptationFieldControl&0b01 != 0
+}
+
+func (p *MPEGTSPacket) UnmarshalBinary(bytes []byte) (e error) {
+	var (
+		i int
+	)
+
+	if len(bytes) != MPEGTSPacketLengthInBytes {
+		e = ErrMPEGTSPacketLengthInvalid
+
+		return
+	}
+
+	e = binary.Unmarshal(bytes[:MPEGTSPacketHeaderLengthInBytes],
+		&p.MPEGTSPacketHeaderFormat,
+	)
+	if e != nil {
+		return
+	}
+
+	if p.SyncByte != MPEGTSSyncByte {
+		e = ErrMPEGTSSyncByteNotFound
+
+		return
+	}
+
+	i = MPEGTSPacketHeaderLengthInBytes
+
+	p.AdaptationField = MPEGTSAdaptationField{}
+	p.Payload = nil
+
+	if p.HasAdaptationField() {
+		i, e = p.AdaptationField.unmarshal(bytes, i)
+		if e != nil {
+			return
+		}
+	}
+
+	if p.HasPayload() {
+		p.Payload = bytes[i:]
+	}
+
+	return
+}
+
+func (f *MPEGTSAdaptationField) unmarshal(bytes []byte, i int) (
+	j int, e error,
+) {
+	var (
+		end int
+	)
+
+	end = i + 1 + int(bytes[i])
+
+	if end > len(bytes) {
+		e = ErrMPEGTSAdaptationFieldTooLong
+
+		return
+	}
+
+	if bytes[i] == 0 {
+		j = end
+
+		return
+	}
+
+	e = binary.Unmarshal(bytes[i:i+2], &f.MPEGTSAdaptationFieldHeaderFormat)
+	if e != nil {
+		return
+	}
+
+	j = i + 2
+
+	if f.PCRFlag {
+		if j+6 > end {
+			e = ErrMPEGTSAdaptationFieldTooLong
+
+			return
+		}
+
+		e = binary.Unmarshal(bytes[j:j+6], &f.PCR)
+		if e != nil {
+			return
+		}
+
+		j += 6
+	}
+
+	if f.OPCRFlag {
+		if j+6 > end {
+			e = ErrMPEGTSAdaptationFieldTooLong
+
+			return
+		}
+
+		e = binary.Unmarshal(bytes[j:j+6], &f.OPCR)
+		if e != nil {
+			return
+		}
+
+		j += 6
+	}
+
+	if f.SplicingPointFlag {
+		if j+1 > end {
+			e = ErrMPEGTSAdaptationFieldTooLong
+
+			return
+		}
+
+		f.SpliceCountdown = int8(bytes[j])
+	}
+
+	// Private data and extensions are skipped along with stuffing bytes.
+
+	j = end
+
+	return
+}
+
+// MarshalBinary encodes the packet, padding the adaptation field
+// with stuffing bytes so that the packet is 188 bytes long.
+// Only the PCR, OPCR and splice countdown of the adaptation field are
+// encoded; the private data and extension flags are cleared.
+func (p *MPEGTSPacket) MarshalBinary() (bytes []byte, e error) {
+	var (
+		field       []byte
+		headerBytes []byte
+		i           int
+		stuffing    int
+	)
+
+	stuffing = MPEGTSPacketLengthInBytes - MPEGTSPacketHeaderLengthInBytes -
+		len(p.Payload)
+
+	if stuffing < 0 {
+		e = ErrMPEGTSPacketLengthInvalid
+
+		return
+	}
+
+	p.SyncByte = MPEGTSSyncByte
+
+	p.AdaptationFieldControl = MPEGTSAdaptationFieldControlPayloadOnly
+
+	if p.AdaptationField.flagged() || stuffing > 0 {
+		field, e = p.AdaptationField.marshal(stuffing)
+		if e != nil {
+			return
+		}
+
+		p.AdaptationFieldControl =
+			MPEGTSAdaptationFieldControlAdaptationFieldThenData
+
+		if len(p.Payload) == 0 {
+			p.AdaptationFieldControl =
+				MPEGTSAdaptationFieldControlAdaptationFieldOnly
+		}
+	}
+
+	headerBytes, e = binary.Marshal(&p.MPEGTSPacketHeaderFormat)
+	if e != nil {
+		return
+	}
+
+	bytes = make([]byte, 0, MPEGTSPacketLengthInBytes)
+
+	bytes = append(bytes, headerBytes...)
+	bytes = append(bytes, field...)
+
+	for i = len(bytes); i < MPEGTSPacketHeaderLengthInBytes+stuffing; i++ {
+		bytes = append(bytes, 0xff)
+	}
+
+	bytes = append(bytes, p.Payload...)
+
+	return
+}
+
+func (f *MPEGTSAdaptationField) flagged() bool {
+	return f.DiscontinuityIndicator ||
+		f.RandomAccessIndicator ||
+		f.ElementaryStreamPriorityIndicator ||
+		f.PCRFlag ||
+		f.OPCRFlag ||
+		f.SplicingPointFlag
+}
+
+func (f *MPEGTSAdaptationField) marshal(length int) (bytes []byte, e error) {
+	// The adaptation field absorbs all stuffing,
+	// so its length is fixed by the payload rather than its contents.
+	// A one-byte adaptation field is a lone length byte of zero.
+
+	var (
+		wordBytes []byte
+	)
+
+	f.TransportPrivateDataFlag = false
+	f.AdaptationFieldExtensionFlag = false
+
+	if length == 1 && !f.flagged() {
+		bytes = []byte{0}
+
+		return
+	}
+
+	if length < 2 {
+		e = ErrMPEGTSAdaptationFieldTooLong
+
+		return
+	}
+
+	f.AdaptationFieldLength = uint8(length - 1)
+
+	wordBytes, e = binary.Marshal(&f.MPEGTSAdaptationFieldHeaderFormat)
+	if e != nil {
+		return
+	}
+
+	bytes = append(bytes, wordBytes...)
+
+	if f.PCRFlag {
+		wordBytes, e = binary.Marshal(&f.PCR)
+		if e != nil {
+			return
+		}
+
+		bytes = append(bytes, wordBytes...)
+	}
+
+	if f.OPCRFlag {
+		wordBytes, e = binary.Marshal(&f.OPCR)
+		if e != nil {
+			return
+		}
+
+		bytes = append(bytes, wordBytes...)
+	}
+
+	if f.SplicingPointFlag {
+		bytes = append(bytes, uint8(f.SpliceCountdown))
+	}
+
+	if len(bytes) > length {
+		e = ErrMPEGTSAdaptationFieldTooLong
+
+		bytes = nil
+	}
+
+	return
+}
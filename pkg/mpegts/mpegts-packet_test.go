@@ -0,0 +1,207 @@
+package mpegts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMPEGTSPacketRoundTrip(t *testing.T) {
+	const (
+		pcr = 0x1ffffffff*MPEGTSProgramClockReferenceExtensionModulus + 299
+	)
+
+	var (
+		bytes   []byte
+		e       error
+		packet  MPEGTSPacket
+		packet1 MPEGTSPacket
+	)
+
+	packet.PayloadUnitStartIndicator = true
+	packet.PID = 0x0100
+	packet.ContinuityCounter = 0xa
+	packet.AdaptationField.RandomAccessIndicator = true
+	packet.AdaptationField.PCRFlag = true
+	packet.AdaptationField.PCR.SetValue(pcr)
+	packet.Payload = []byte{0x00, 0x00, 0x01, 0xe0}
+
+	bytes, e = packet.MarshalBinary()
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		MPEGTSPacketLengthInBytes, len(bytes),
+	)
+
+	assert.Equal(t,
+		[]byte{
+			0x47, 0x41, 0x00, 0x3a,
+			179, 0b01010000,
+			0xff, 0xff, 0xff, 0xff, 0xff, 0x2b,
+			0xff, 0xff,
+		},
+		bytes[:14],
+	)
+
+	assert.Equal(t,
+		[]byte{0x00, 0x00, 0x01, 0xe0},
+		bytes[184:],
+	)
+
+	e = packet1.UnmarshalBinary(bytes)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		packet, packet1,
+	)
+
+	assert.Equal(t,
+		uint64(pcr), packet1.AdaptationField.PCR.Value(),
+	)
+}
+
+func TestMPEGTSPacketStuffingByte(t *testing.T) {
+	var (
+		bytes  []byte
+		e      error
+		packet MPEGTSPacket
+	)
+
+	packet.PID = 0x0100
+	packet.Payload = make([]byte, 183)
+
+	bytes, e = packet.MarshalBinary()
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]byte{0x47, 0x01, 0x00, 0x30, 0x00},
+		bytes[:5],
+	)
+
+	e = packet.UnmarshalBinary(bytes)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		183, len(packet.Payload),
+	)
+}
+
+func TestMPEGTSPacketErrors(t *testing.T) {
+	var (
+		bytes  []byte
+		e      error
+		packet MPEGTSPacket
+	)
+
+	e = packet.UnmarshalBinary(make([]byte, 187))
+
+	assert.Equal(t,
+		ErrMPEGTSPacketLengthInvalid, e,
+	)
+
+	bytes = make([]byte, MPEGTSPacketLengthInBytes)
+
+	e = packet.UnmarshalBinary(bytes)
+
+	assert.Equal(t,
+		ErrMPEGTSSyncByteNotFound, e,
+	)
+
+	bytes[0] = MPEGTSSyncByte
+	bytes[3] = MPEGTSAdaptationFieldControlAdaptationFieldOnly << 4
+	bytes[4] = 184
+
+	e = packet.UnmarshalBinary(bytes)
+
+	assert.Equal(t,
+		ErrMPEGTSAdaptationFieldTooLong, e,
+	)
+
+	packet = MPEGTSPacket{}
+	packet.AdaptationField.PCRFlag = true
+	packet.Payload = make([]byte, 180)
+
+	_, e = packet.MarshalBinary()
+
+	assert.Equal(t,
+		ErrMPEGTSAdaptationFieldTooLong, e,
+	)
+}
+
+func TestMPEGTSContinuityChecker(t *testing.T) {
+	var (
+		checker   *MPEGTSContinuityChecker
+		duplicate bool
+		e         error
+		packet    MPEGTSPacket
+	)
+
+	checker = NewMPEGTSContinuityChecker()
+
+	packet.PID = 0x0100
+	packet.AdaptationFieldControl = MPEGTSAdaptationFieldControlPayloadOnly
+	packet.ContinuityCounter = 15
+
+	duplicate, e = checker.Check(&packet)
+
+	assert.False(t, duplicate)
+	assert.Nil(t, e)
+
+	packet.ContinuityCounter = 0
+
+	duplicate, e = checker.Check(&packet)
+
+	assert.False(t, duplicate)
+	assert.Nil(t, e)
+
+	duplicate, e = checker.Check(&packet)
+
+	assert.True(t, duplicate)
+	assert.Nil(t, e)
+
+	duplicate, e = checker.Check(&packet)
+
+	assert.False(t, duplicate)
+	assert.Equal(t,
+		ErrMPEGTSContinuityCounterDiscontinuous, e,
+	)
+
+	packet.AdaptationFieldControl =
+		MPEGTSAdaptationFieldControlAdaptationFieldOnly
+
+	duplicate, e = checker.Check(&packet)
+
+	assert.False(t, duplicate)
+	assert.Nil(t, e)
+
+	packet.AdaptationFieldControl = MPEGTSAdaptationFieldControlPayloadOnly
+	packet.ContinuityCounter = 5
+
+	_, e = checker.Check(&packet)
+
+	assert.Equal(t,
+		ErrMPEGTSContinuityCounterDiscontinuous, e,
+	)
+
+	packet.ContinuityCounter = 9
+	packet.AdaptationField.DiscontinuityIndicator = true
+
+	_, e = checker.Check(&packet)
+
+	assert.Nil(t, e)
+
+	packet.PID = MPEGTSPIDNull
+	packet.AdaptationField.DiscontinuityIndicator = false
+
+	_, e = checker.Check(&packet)
+
+	assert.Nil(t, e)
+
+	_, e = checker.Check(&packet)
+
+	assert.Nil(t, e)
+}
@@ -0,0 +1,634 @@
+package mpegts
+
+import (
+	"errors"
+
+	"github.com/encodingx/binary"
+)
+
+type MPEGTSPSISectionHeaderFormat struct {
+	// Reference: Section 2.4.4 "Program specific information" of
+	// ITU-T H.222.0 | ISO/IEC 13818-1
+
+	// > Table 2-30 – Program association section
+	// >
+	// >   program_association_section() {
+	// >     table_id                                     8  uimsbf
+	// >     section_syntax_indicator                     1  bslbf
+	// >     '0'                                          1  bslbf
+	// >     reserved                                     2  bslbf
+	// >     section_length                              12  uimsbf
+	// >     transport_stream_id                         16  uimsbf
+	// >     reserved                                     2  bslbf
+	// >     version_number                               5  uimsbf
+	// >     current_next_indicator                       1  bslbf
+	// >     section_number                               8  uimsbf
+	// >     last_section_number                          8  uimsbf
+	// >     ...
+	// >     CRC_32                                      32  rpchof
+	// >   }
+	//
+	// The first three bytes are common to all PSI sections.
+	// The remaining fields before the section data are common to
+	// sections with section_syntax_indicator set (see
+	// MPEGTSPSISectionSyntaxFormat); table_id_extension takes the place of
+	// transport_stream_id in the PAT and program_number in the PMT.
+
+	MPEGTSPSISectionHeaderFormatWord0 `word:"24"`
+}
+
+type MPEGTSPSISectionHeaderFormatWord0 struct {
+	TableID                uint8 `bitfield:"8"`
+	SectionSyntaxIndicator bool  `bitfield:"1"`
+	PrivateIndicator       bool  `bitfield:"1"`
+	Reserved               uint8 `bitfield:"2"`
+
+	SectionLength uint16 `bitfield:"12"`
+	// > section_length – This is a 12-bit field, the first two bits of which
+	// > shall be '00'. The remaining 10 bits specify the number of bytes of
+	// > the section, starting immediately following the section_length
+	// > field, and including the CRC.
+}
+
+type MPEGTSPSISectionSyntaxFormat struct {
+	MPEGTSPSISectionSyntaxFormatWord0 `word:"40"`
+}
+
+type MPEGTSPSISectionSyntaxFormatWord0 struct {
+	TableIDExtension     uint16 `bitfield:"16"`
+	Reserved             uint8  `bitfield:"2"`
+	VersionNumber        uint8  `bitfield:"5"`
+	CurrentNextIndicator bool   `bitfield:"1"`
+	SectionNumber        uint8  `bitfield:"8"`
+	LastSectionNumber    uint8  `bitfield:"8"`
+}
+
+const (
+	MPEGTSPSISectionHeaderLengthInBytes = 3
+	MPEGTSPSISectionSyntaxLengthInBytes = 5
+	MPEGTSPSISectionCRCLengthInBytes    = 4
+	MPEGTSPSISectionMaxLength           = 1021
+)
+
+const (
+	MPEGTSTableIDProgramAssociation = 0x00
+	MPEGTSTableIDConditionalAccess  = 0x01
+	MPEGTSTableIDProgramMap         = 0x02
+	MPEGTSTableIDStuffing           = 0xff
+)
+
+type MPEGTSPSISection struct {
+	MPEGTSPSISectionHeaderFormat
+	MPEGTSPSISectionSyntaxFormat
+
+	Data  []byte
+	CRC32 uint32
+}
+
+var (
+	ErrMPEGTSPSISectionCRCMismatch = errors.New(
+		"The CRC_32 field of a PSI section should give a zero output " +
+			"of the CRC-32/MPEG-2 decoder over the entire section. " +
+			"The CRC does not match the section.",
+	)
+
+	ErrMPEGTSPSISectionSyntaxIndicatorNotSet = errors.New(
+		"A PAT or PMT section should have its " +
+			"section_syntax_indicator set to 1. " +
+			"The section_syntax_indicator of the section is 0.",
+	)
+
+	ErrMPEGTSPSISectionTableIDMismatch = errors.New(
+		"The table_id of a PAT section should be 0x00, " +
+			"and that of a PMT section 0x02. " +
+			"The section has a table_id that does not match its table.",
+	)
+
+	ErrMPEGTSPSISectionTooLong = errors.New(
+		"The section_length of a PSI section should not exceed 1021. " +
+			"The section_length of the section is too large.",
+	)
+
+	ErrMPEGTSPSISectionTruncated = errors.New(
+		"A PSI section should be as long as its section_length " +
+			"indicates. The section ends before it should.",
+	)
+
+	ErrMPEGTSPointerFieldOutOfRange = errors.New(
+		"The pointer_field at the start of a payload should point " +
+			"to a byte within that payload. " +
+			"The pointer_field points past the end of the payload.",
+	)
+)
+
+// ParseMPEGTSPSISection parses the section at the start of bytes,
+// returning the number of bytes it occupies.
+// The CRC is verified for sections with section_syntax_indicator set,
+// whose Data excludes the syntax fields and the CRC.
+func ParseMPEGTSPSISection(bytes []byte) (
+	section MPEGTSPSISection, n int, e error,
+) {
+	var (
+		dataStart int
+	)
+
+	if len(bytes) < MPEGTSPSISectionHeaderLengthInBytes {
+		e = ErrMPEGTSPSISectionTruncated
+
+		return
+	}
+
+	e = binary.Unmarshal(bytes[:MPEGTSPSISectionHeaderLengthInBytes],
+		&section.MPEGTSPSISectionHeaderFormat,
+	)
+	if e != nil {
+		return
+	}
+
+	if section.SectionLength > MPEGTSPSISectionMaxLength {
+		e = ErrMPEGTSPSISectionTooLong
+
+		return
+	}
+
+	n = MPEGTSPSISectionHeaderLengthInBytes + int(section.SectionLength)
+
+	if len(bytes) < n {
+		e = ErrMPEGTSPSISectionTruncated
+
+		return
+	}
+
+	if !section.SectionSyntaxIndicator {
+		section.Data = bytes[MPEGTSPSISectionHeaderLengthInBytes:n]
+
+		return
+	}
+
+	dataStart = MPEGTSPSISectionHeaderLengthInBytes +
+		MPEGTSPSISectionSyntaxLengthInBytes
+
+	if n < dataStart+MPEGTSPSISectionCRCLengthInBytes {
+		e = ErrMPEGTSPSISectionTruncated
+
+		return
+	}
+
+	if MPEGTSCRC32(bytes[:n]) != 0 {
+		e = ErrMPEGTSPSISectionCRCMismatch
+
+		return
+	}
+
+	e = binary.Unmarshal(bytes[MPEGTSPSISectionHeaderLengthInBytes:dataStart],
+		&section.MPEGTSPSISectionSyntaxFormat,
+	)
+	if e != nil {
+		return
+	}
+
+	section.Data = bytes[dataStart : n-MPEGTSPSISectionCRCLengthInBytes]
+
+	section.CRC32 =
+		binary.BigEndian.Uint32(bytes[n-MPEGTSPSISectionCRCLengthInBytes:])
+
+	return
+}
+
+// MarshalBinary sets the section_length and CRC_32 fields
+// before encoding the section.
+func (s *MPEGTSPSISection) MarshalBinary() (bytes []byte, e error) {
+	var (
+		wordBytes []byte
+	)
+
+	s.SectionLength = uint16(len(s.Data))
+
+	if s.SectionSyntaxIndicator {
+		s.SectionLength += MPEGTSPSISectionSyntaxLengthInBytes +
+			MPEGTSPSISectionCRCLengthInBytes
+	}
+
+	if s.SectionLength > MPEGTSPSISectionMaxLength {
+		e = ErrMPEGTSPSISectionTooLong
+
+		return
+	}
+
+	wordBytes, e = binary.Marshal(&s.MPEGTSPSISectionHeaderFormat)
+	if e != nil {
+		return
+	}
+
+	bytes = append(bytes, wordBytes...)
+
+	if s.SectionSyntaxIndicator {
+		wordBytes, e = binary.Marshal(&s.MPEGTSPSISectionSyntaxFormat)
+		if e != nil {
+			return
+		}
+
+		bytes = append(bytes, wordBytes...)
+	}
+
+	bytes = append(bytes, s.Data...)
+
+	if s.SectionSyntaxIndicator {
+		s.CRC32 = MPEGTSCRC32(bytes)
+
+		bytes = append(bytes, make([]byte, MPEGTSPSISectionCRCLengthInBytes)...)
+
+		binary.BigEndian.PutUint32(
+			bytes[len(bytes)-MPEGTSPSISectionCRCLengthInBytes:],
+			s.CRC32,
+		)
+	}
+
+	return
+}
+
+type MPEGTSPATProgramFormat struct {
+	// Reference: Section 2.4.4.3 "Program association Table"
+
+	// >     for (i = 0; i < N; i++) {
+	// >       program_number                            16  uimsbf
+	// >       reserved                                   3  bslbf
+	// >       if (program_number = = '0') {
+	// >         network_PID                             13  uimsbf
+	// >       }
+	// >       else {
+	// >         program_map_PID                         13  uimsbf
+	// >       }
+	// >     }
+
+	MPEGTSPATProgramFormatWord0 `word:"32"`
+}
+
+type MPEGTSPATProgramFormatWord0 struct {
+	ProgramNumber uint16 `bitfield:"16"`
+	Reserved      uint8  `bitfield:"3"`
+
+	PID uint16 `bitfield:"13"`
+	// > network_PID – The network_PID is a 13-bit field, which is used only
+	// > in conjunction with the value of the program_number set to 0x0000,
+	// > specifies the PID of the transport stream packets which shall
+	// > contain the Network Information Table.
+	// >
+	// > program_map_PID – The program_map_PID is a 13-bit field specifying
+	// > the PID of the transport stream packets which shall contain the
+	// > program_map_section applicable for the program as specified by the
+	// > program_number.
+}
+
+const (
+	MPEGTSPATProgramLengthInBytes = 4
+)
+
+type MPEGTSProgramAssociationSection struct {
+	MPEGTSPSISection
+
+	Programs []MPEGTSPATProgramFormat
+}
+
+func ParseMPEGTSProgramAssociationSection(bytes []byte) (
+	pat MPEGTSProgramAssociationSection, e error,
+) {
+	var (
+		i       int
+		program MPEGTSPATProgramFormat
+	)
+
+	pat.MPEGTSPSISection, e = parseMPEGTSPSISectionWithTableID(bytes,
+		MPEGTSTableIDProgramAssociation,
+	)
+	if e != nil {
+		return
+	}
+
+	if len(pat.Data)%MPEGTSPATProgramLengthInBytes != 0 {
+		e = ErrMPEGTSPSISectionTruncated
+
+		return
+	}
+
+	for i = 0; i < len(pat.Data); i += MPEGTSPATProgramLengthInBytes {
+		e = binary.Unmarshal(pat.Data[i:i+MPEGTSPATProgramLengthInBytes],
+			&program,
+		)
+		if e != nil {
+			return
+		}
+
+		pat.Programs = append(pat.Programs, program)
+	}
+
+	return
+}
+
+// TransportStreamID returns the table_id_extension of the section.
+func (s MPEGTSProgramAssociationSection) TransportStreamID() uint16 {
+	return s.TableIDExtension
+}
+
+type MPEGTSPMTHeaderFormat struct {
+	// Reference: Section 2.4.4.8 "Program Map Table"
+
+	// > Table 2-33 – Transport stream program map section
+	// >
+	// >   TS_program_map_section() {
+	// >     ...
+	// >     reserved                                     3  bslbf
+	// >     PCR_PID                                     13  uimsbf
+	// >     reserved                                     4  bslbf
+	// >     program_info_length                         12  uimsbf
+	// >     for (i = 0; i < N; i++) {
+	// >       descriptor()
+	// >     }
+	// >     for (i = 0; i < N1; i++) {
+	// >       stream_type                                8  uimsbf
+	// >       reserved                                   3  bslbf
+	// >       elementary_PID                            13  uimsbf
+	// >       reserved                                   4  bslbf
+	// >       ES_info_length                            12  uimsbf
+	// >       for (i = 0; i < N2; i++) {
+	// >         descriptor()
+	// >       }
+	// >     }
+	// >     CRC_32                                      32  rpchof
+	// >   }
+
+	MPEGTSPMTHeaderFormatWord0 `word:"32"`
+}
+
+type MPEGTSPMTHeaderFormatWord0 struct {
+	Reserved0         uint8  `bitfield:"3"`
+	PCRPID            uint16 `bitfield:"13"`
+	Reserved1         uint8  `bitfield:"4"`
+	ProgramInfoLength uint16 `bitfield:"12"`
+}
+
+type MPEGTSPMTStreamFormat struct {
+	MPEGTSPMTStreamFormatWord0 `word:"40"`
+}
+
+type MPEGTSPMTStreamFormatWord0 struct {
+	StreamType    uint8  `bitfield:"8"`
+	Reserved0     uint8  `bitfield:"3"`
+	ElementaryPID uint16 `bitfield:"13"`
+	Reserved1     uint8  `bitfield:"4"`
+	ESInfoLength  uint16 `bitfield:"12"`
+}
+
+const (
+	MPEGTSPMTHeaderLengthInBytes = 4
+	MPEGTSPMTStreamLengthInBytes = 5
+)
+
+const (
+	MPEGTSStreamTypeMPEG1Video = 0x01
+	MPEGTSStreamTypeMPEG2Video = 0x02
+	MPEGTSStreamTypeMPEG1Audio = 0x03
+	MPEGTSStreamTypeMPEG2Audio = 0x04
+	MPEGTSStreamTypePrivatePES = 0x06
+	MPEGTSStreamTypeADTSAudio  = 0x0f
+	MPEGTSStreamTypeH264Video  = 0x1b
+	MPEGTSStreamTypeH265Video  = 0x24
+)
+
+type MPEGTSPMTStream struct {
+	MPEGTSPMTStreamFormat
+
+	Descriptors []byte
+}
+
+type MPEGTSProgramMapSection struct {
+	MPEGTSPSISection
+	MPEGTSPMTHeaderFormat
+
+	ProgramInfo []byte
+	Streams     []MPEGTSPMTStream
+}
+
+func ParseMPEGTSProgramMapSection(bytes []byte) (
+	pmt MPEGTSProgramMapSection, e error,
+) {
+	var (
+		i      int
+		stream MPEGTSPMTStream
+	)
+
+	pmt.MPEGTSPSISection, e = parseMPEGTSPSISectionWithTableID(bytes,
+		MPEGTSTableIDProgramMap,
+	)
+	if e != nil {
+		return
+	}
+
+	if len(pmt.Data) < MPEGTSPMTHeaderLengthInBytes {
+		e = ErrMPEGTSPSISectionTruncated
+
+		return
+	}
+
+	e = binary.Unmarshal(pmt.Data[:MPEGTSPMTHeaderLengthInBytes],
+		&pmt.MPEGTSPMTHeaderFormat,
+	)
+	if e != nil {
+		return
+	}
+
+	i = MPEGTSPMTHeaderLengthInBytes + int(pmt.ProgramInfoLength)
+
+	if i > len(pmt.Data) {
+		e = ErrMPEGTSPSISectionTruncated
+
+		return
+	}
+
+	pmt.ProgramInfo = pmt.Data[MPEGTSPMTHeaderLengthInBytes:i]
+
+	for i < len(pmt.Data) {
+		if i+MPEGTSPMTStreamLengthInBytes > len(pmt.Data) {
+			e = ErrMPEGTSPSISectionTruncated
+
+			return
+		}
+
+		e = binary.Unmarshal(pmt.Data[i:i+MPEGTSPMTStreamLengthInBytes],
+			&stream.MPEGTSPMTStreamFormat,
+		)
+		if e != nil {
+			return
+		}
+
+		i += MPEGTSPMTStreamLengthInBytes
+
+		if i+int(stream.ESInfoLength) > len(pmt.Data) {
+			e = ErrMPEGTSPSISectionTruncated
+
+			return
+		}
+
+		stream.Descriptors = pmt.Data[i : i+int(stream.ESInfoLength)]
+
+		i += int(stream.ESInfoLength)
+
+		pmt.Streams = append(pmt.Streams, stream)
+	}
+
+	return
+}
+
+// ProgramNumber returns the table_id_extension of the section.
+func (s MPEGTSProgramMapSection) ProgramNumber() uint16 {
+	return s.TableIDExtension
+}
+
+func parseMPEGTSPSISectionWithTableID(bytes []byte, tableID uint8) (
+	section MPEGTSPSISection, e error,
+) {
+	section, _, e = ParseMPEGTSPSISection(bytes)
+	if e != nil {
+		return
+	}
+
+	if section.TableID != tableID {
+		e = ErrMPEGTSPSISectionTableIDMismatch
+
+		return
+	}
+
+	if !section.SectionSyntaxIndicator {
+		e = ErrMPEGTSPSISectionSyntaxIndicatorNotSet
+
+		return
+	}
+
+	return
+}
+
+// MPEGTSSectionAssembler reassembles PSI sections
+// from the payloads of transport stream packets,
+// which may split sections or carry several of them.
+type MPEGTSSectionAssembler struct {
+	pending map[uint16][]byte
+}
+
+func NewMPEGTSSectionAssembler() (a *MPEGTSSectionAssembler) {
+	a = &MPEGTSSectionAssembler{
+		pending: make(map[uint16][]byte),
+	}
+
+	return
+}
+
+// Push returns the sections completed by the payload of a packet.
+// Payload bytes that precede the first section start on a PID are dropped.
+func (a *MPEGTSSectionAssembler) Push(packet *MPEGTSPacket) (
+	sections [][]byte, e error,
+) {
+	var (
+		more    [][]byte
+		pending []byte
+		pointer int
+		started bool
+	)
+
+	if !packet.HasPayload() || len(packet.Payload) == 0 {
+		return
+	}
+
+	pending, started = a.pending[packet.PID]
+
+	if !packet.PayloadUnitStartIndicator {
+		if started {
+			sections, pending = mpegtsCutSections(
+				append(pending, packet.Payload...),
+			)
+
+			a.store(packet.PID, pending)
+		}
+
+		return
+	}
+
+	pointer = int(packet.Payload[0])
+
+	if 1+pointer > len(packet.Payload) {
+		delete(a.pending, packet.PID)
+
+		e = ErrMPEGTSPointerFieldOutOfRange
+
+		return
+	}
+
+	if started {
+		sections, _ = mpegtsCutSections(
+			append(pending, packet.Payload[1:1+pointer]...),
+		)
+	}
+
+	// Copy the remainder so that later appends do not alias the packet.
+
+	pending = append([]byte(nil), packet.Payload[1+pointer:]...)
+
+	more, pending = mpegtsCutSections(pending)
+
+	sections = append(sections, more...)
+
+	a.store(packet.PID, pending)
+
+	return
+}
+
+func (a *MPEGTSSectionAssembler) store(pid uint16, pending []byte) {
+	if pending == nil {
+		delete(a.pending, pid)
+
+		return
+	}
+
+	a.pending[pid] = pending
+
+	return
+}
+
+// mpegtsCutSections splits complete sections off the front of bytes.
+// The remainder is nil once stuffing is reached,
+// since no further section can start in the same payload unit.
+func mpegtsCutSections(bytes []byte) (sections [][]byte, remainder []byte) {
+	var (
+		length int
+	)
+
+	remainder = bytes
+
+	for len(remainder) > 0 {
+		if remainder[0] == MPEGTSTableIDStuffing {
+			remainder = nil
+
+			return
+		}
+
+		if len(remainder) < MPEGTSPSISectionHeaderLengthInBytes {
+			return
+		}
+
+		length = MPEGTSPSISectionHeaderLengthInBytes +
+			int(binary.BigEndian.Uint16(remainder[1:])&0x0fff)
+
+		if len(remainder) < length {
+			return
+		}
+
+		sections = append(sections, remainder[:length:length])
+
+		remainder = remainder[length:]
+	}
+
+	if len(remainder) == 0 {
+		remainder = nil
+	}
+
+	return
+}
@@ -0,0 +1,227 @@
+package mpegts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	patSectionBytes = []byte{
+		0x00, 0xb0, 0x0d, 0x00, 0x01, 0xc1, 0x00, 0x00,
+		0x00, 0x01, 0xf0, 0x00,
+		0x2a, 0xb1, 0x04, 0xb2,
+	}
+
+	pmtSectionBytes = []byte{
+		0x02, 0xb0, 0x17, 0x00, 0x01, 0xc1, 0x00, 0x00,
+		0xe1, 0x00, 0xf0, 0x00,
+		0x1b, 0xe1, 0x00, 0xf0, 0x00,
+		0x0f, 0xe1, 0x01, 0xf0, 0x00,
+		0x2f, 0x44, 0xb9, 0x9b,
+	}
+)
+
+func TestMPEGTSCRC32(t *testing.T) {
+	assert.Equal(t,
+		uint32(0x0376e6e7), MPEGTSCRC32([]byte("123456789")),
+	)
+}
+
+func TestParseMPEGTSProgramAssociationSection(t *testing.T) {
+	var (
+		bytes []byte
+		e     error
+		pat   MPEGTSProgramAssociationSection
+	)
+
+	pat, e = ParseMPEGTSProgramAssociationSection(patSectionBytes)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint16(1), pat.TransportStreamID(),
+	)
+
+	assert.True(t, pat.CurrentNextIndicator)
+
+	assert.Equal(t,
+		1, len(pat.Programs),
+	)
+
+	assert.Equal(t,
+		uint16(1), pat.Programs[0].ProgramNumber,
+	)
+
+	assert.Equal(t,
+		uint16(0x1000), pat.Programs[0].PID,
+	)
+
+	assert.Equal(t,
+		uint32(0x2ab104b2), pat.CRC32,
+	)
+
+	bytes, e = pat.MarshalBinary()
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		patSectionBytes, bytes,
+	)
+
+	bytes = append([]byte(nil), patSectionBytes...)
+	bytes[9] = 0x02
+
+	_, e = ParseMPEGTSProgramAssociationSection(bytes)
+
+	assert.Equal(t,
+		ErrMPEGTSPSISectionCRCMismatch, e,
+	)
+
+	_, e = ParseMPEGTSProgramAssociationSection(patSectionBytes[:10])
+
+	assert.Equal(t,
+		ErrMPEGTSPSISectionTruncated, e,
+	)
+
+	_, e = ParseMPEGTSProgramMapSection(patSectionBytes)
+
+	assert.Equal(t,
+		ErrMPEGTSPSISectionTableIDMismatch, e,
+	)
+}
+
+func TestParseMPEGTSProgramMapSection(t *testing.T) {
+	var (
+		e   error
+		pmt MPEGTSProgramMapSection
+	)
+
+	pmt, e = ParseMPEGTSProgramMapSection(pmtSectionBytes)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint16(1), pmt.ProgramNumber(),
+	)
+
+	assert.Equal(t,
+		uint16(0x0100), pmt.PCRPID,
+	)
+
+	assert.Equal(t,
+		0, len(pmt.ProgramInfo),
+	)
+
+	assert.Equal(t,
+		2, len(pmt.Streams),
+	)
+
+	assert.Equal(t,
+		uint8(MPEGTSStreamTypeH264Video), pmt.Streams[0].StreamType,
+	)
+
+	assert.Equal(t,
+		uint16(0x0100), pmt.Streams[0].ElementaryPID,
+	)
+
+	assert.Equal(t,
+		uint8(MPEGTSStreamTypeADTSAudio), pmt.Streams[1].StreamType,
+	)
+
+	assert.Equal(t,
+		uint16(0x0101), pmt.Streams[1].ElementaryPID,
+	)
+}
+
+func TestMPEGTSSectionAssembler(t *testing.T) {
+	var (
+		assembler *MPEGTSSectionAssembler
+		e         error
+		packet    MPEGTSPacket
+		sections  [][]byte
+	)
+
+	assembler = NewMPEGTSSectionAssembler()
+
+	packet.PID = 0x1000
+	packet.AdaptationFieldControl = MPEGTSAdaptationFieldControlPayloadOnly
+
+	// A continuation before any section start is dropped.
+
+	packet.Payload = pmtSectionBytes[10:]
+
+	sections, e = assembler.Push(&packet)
+
+	assert.Nil(t, e)
+	assert.Nil(t, sections)
+
+	// The PAT and the first part of the PMT share a payload unit.
+
+	packet.PayloadUnitStartIndicator = true
+	packet.Payload = append([]byte{0x00}, patSectionBytes...)
+	packet.Payload = append(packet.Payload, pmtSectionBytes[:10]...)
+
+	sections, e = assembler.Push(&packet)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[][]byte{patSectionBytes}, sections,
+	)
+
+	packet.PayloadUnitStartIndicator = false
+	packet.Payload = append([]byte(nil), pmtSectionBytes[10:]...)
+	packet.Payload = append(packet.Payload, 0xff, 0xff, 0xff)
+
+	sections, e = assembler.Push(&packet)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[][]byte{pmtSectionBytes}, sections,
+	)
+
+	// Bytes before the pointer_field target are dropped
+	// unless they complete a pending section.
+
+	packet.PayloadUnitStartIndicator = true
+	packet.Payload = []byte{6}
+	packet.Payload = append(packet.Payload, pmtSectionBytes[20:]...)
+	packet.Payload = append(packet.Payload, patSectionBytes...)
+
+	sections, e = assembler.Push(&packet)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[][]byte{patSectionBytes}, sections,
+	)
+
+	packet.Payload = []byte{0x00}
+	packet.Payload = append(packet.Payload, pmtSectionBytes[:20]...)
+
+	_, e = assembler.Push(&packet)
+
+	assert.Nil(t, e)
+
+	packet.Payload = []byte{6}
+	packet.Payload = append(packet.Payload, pmtSectionBytes[20:]...)
+	packet.Payload = append(packet.Payload, patSectionBytes...)
+
+	sections, e = assembler.Push(&packet)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[][]byte{pmtSectionBytes, patSectionBytes}, sections,
+	)
+
+	packet.Payload = []byte{6}
+
+	_, e = assembler.Push(&packet)
+
+	assert.Equal(t,
+		ErrMPEGTSPointerFieldOutOfRange, e,
+	)
+}
@@ -0,0 +1,376 @@
+package rfc2131
+
+import (
+	"errors"
+	"net"
+
+	"github.com/encodingx/binary"
+	"github.com/encodingx/binary/pkg/tlv"
+)
+
+type RFC2131MessageFormat struct {
+	// Reference: Section 2 "Protocol Summary" of
+	// RFC 2131 Dynamic Host Configuration Protocol
+	// https://datatracker.ietf.org/doc/html/rfc2131#section-2
+
+	// >    0                   1                   2                   3
+	// >    0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+	// >    +---------------+---------------+---------------+---------------+
+	// >    |     op (1)    |   htype (1)   |   hlen (1)    |   hops (1)    |
+	// >    +---------------+---------------+---------------+---------------+
+	// >    |                            xid (4)                            |
+	// >    +-------------------------------+-------------------------------+
+	// >    |           secs (2)            |           flags (2)           |
+	// >    +-------------------------------+-------------------------------+
+	// >    |                          ciaddr  (4)                          |
+	// >    +---------------------------------------------------------------+
+	// >    |                          yiaddr  (4)                          |
+	// >    +---------------------------------------------------------------+
+	// >    |                          siaddr  (4)                          |
+	// >    +---------------------------------------------------------------+
+	// >    |                          giaddr  (4)                          |
+	// >    +---------------------------------------------------------------+
+	// >    |                                                               |
+	// >    |                          chaddr  (16)                         |
+	// >    |                                                               |
+	// >    |                                                               |
+	// >    +---------------------------------------------------------------+
+	// >    |                                                               |
+	// >    |                          sname   (64)                         |
+	// >    +---------------------------------------------------------------+
+	// >    |                                                               |
+	// >    |                          file    (128)                        |
+	// >    +---------------------------------------------------------------+
+	// >    |                                                               |
+	// >    |                          options (variable)                   |
+	// >    +---------------------------------------------------------------+
+	//
+	// The fields chaddr, sname and file are byte strings too long for words,
+	// and are held by RFC2131Message alongside this format-struct.
+
+	RFC2131MessageFormatWord0 `word:"32"`
+	RFC2131MessageFormatWord1 `word:"32"`
+	RFC2131MessageFormatWord2 `word:"32"`
+	RFC2131MessageFormatWord3 `word:"32"`
+	RFC2131MessageFormatWord4 `word:"32"`
+	RFC2131MessageFormatWord5 `word:"32"`
+	RFC2131MessageFormatWord6 `word:"32"`
+}
+
+type RFC2131MessageFormatWord0 struct {
+	Op uint8 `bitfield:"8"`
+	// > op            1  Message op code / message type.
+	// >                  1 = BOOTREQUEST, 2 = BOOTREPLY
+
+	HType uint8 `bitfield:"8"`
+	// > htype         1  Hardware address type, see ARP section in "Assigned
+	// >                  Numbers" RFC; e.g., '1' = 10mb ethernet.
+
+	HLen uint8 `bitfield:"8"`
+	// > hlen          1  Hardware address length (e.g.  '6' for 10mb
+	// >                  ethernet).
+
+	Hops uint8 `bitfield:"8"`
+	// > hops          1  Client sets to zero, optionally used by relay agents
+	// >                  when booting via a relay agent.
+}
+
+type RFC2131MessageFormatWord1 struct {
+	XID uint32 `bitfield:"32"`
+	// > xid           4  Transaction ID, a random number chosen by the
+	// >                  client, used by the client and server to associate
+	// >                  messages and responses between a client and a
+	// >                  server.
+}
+
+type RFC2131MessageFormatWord2 struct {
+	Secs uint16 `bitfield:"16"`
+	// > secs          2  Filled in by client, seconds elapsed since client
+	// >                  began address acquisition or renewal process.
+
+	Broadcast bool   `bitfield:"1"`
+	MBZ       uint16 `bitfield:"15"`
+	// >                     1 1 1 1 1 1
+	// > 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5
+	// > +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	// > |B|             MBZ             |
+	// > +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	// >
+	// > B:  BROADCAST flag
+	// >
+	// > MBZ:  MUST BE ZERO (reserved for future use)
+}
+
+type RFC2131MessageFormatWord3 struct {
+	CIAddr uint32 `bitfield:"32"`
+	// > ciaddr        4  Client IP address; only filled in if client is in
+	// >                  BOUND, RENEW or REBINDING state and can respond
+	// >                  to ARP requests.
+}
+
+type RFC2131MessageFormatWord4 struct {
+	YIAddr uint32 `bitfield:"32"`
+	// > yiaddr        4  'your' (client) IP address.
+}
+
+type RFC2131MessageFormatWord5 struct {
+	SIAddr uint32 `bitfield:"32"`
+	// > siaddr        4  IP address of next server to use in bootstrap;
+	// >                  returned in DHCPOFFER, DHCPACK by server.
+}
+
+type RFC2131MessageFormatWord6 struct {
+	GIAddr uint32 `bitfield:"32"`
+	// > giaddr        4  Relay agent IP address, used in booting via a
+	// >                  relay agent.
+}
+
+type RFC2131MagicCookieFormat struct {
+	// Reference: Section 3 "The Client-Server Protocol"
+
+	// > The first four octets of the 'options' field of the DHCP message
+	// > contain the (decimal) values 99, 130, 83 and 99, respectively (this
+	// > is the same magic cookie as is defined in RFC 1497 [17]).
+
+	RFC2131MagicCookieFormatWord0 `word:"32"`
+}
+
+type RFC2131MagicCookieFormatWord0 struct {
+	MagicCookie uint32 `bitfield:"32"`
+}
+
+const (
+	RFC2131OpBOOTREQUEST = 1
+	RFC2131OpBOOTREPLY   = 2
+
+	RFC2131HTypeEthernet = 1
+
+	RFC2131MagicCookie = 0x63825363
+)
+
+const (
+	rfc2131FixedLengthInBytes       = 28
+	rfc2131CHAddrLengthInBytes      = 16
+	rfc2131SNameLengthInBytes       = 64
+	rfc2131FileLengthInBytes        = 128
+	rfc2131MagicCookieLengthInBytes = 4
+
+	rfc2131OptionsOffset = rfc2131FixedLengthInBytes +
+		rfc2131CHAddrLengthInBytes +
+		rfc2131SNameLengthInBytes +
+		rfc2131FileLengthInBytes +
+		rfc2131MagicCookieLengthInBytes
+
+	// RFC 1542 Section 2.1 requires BOOTP messages of at least 300 octets,
+	// which relay agents may enforce.
+	rfc2131MinimumMessageLengthInBytes = 300
+)
+
+var (
+	ErrRFC2131MagicCookieInvalid = errors.New(
+		"The options field of a DHCP message should begin with " +
+			"the magic cookie 99.130.83.99. " +
+			"The message has a different magic cookie.",
+	)
+
+	ErrRFC2131MessageTruncated = errors.New(
+		"A DHCP message should be at least as long as its fixed fields " +
+			"and magic cookie. " +
+			"The message is truncated.",
+	)
+
+	ErrRFC2131OptionLengthInvalid = errors.New(
+		"The value of a DHCP option should be of the length " +
+			"defined for its code. " +
+			"The option value is of an invalid length.",
+	)
+)
+
+type RFC2131Message struct {
+	RFC2131MessageFormat
+
+	CHAddr [rfc2131CHAddrLengthInBytes]byte
+	// > chaddr       16  Client hardware address.
+
+	SName [rfc2131SNameLengthInBytes]byte
+	// > sname        64  Optional server host name, null terminated string.
+
+	File [rfc2131FileLengthInBytes]byte
+	// > file        128  Boot file name, null terminated string; "generic"
+	// >                  name or null in DHCPDISCOVER, fully qualified
+	// >                  directory-path name in DHCPOFFER.
+
+	// Options excludes the pad and end options,
+	// which are skipped when unmarshalling and added when marshalling.
+	// The values of all options are held in the Raw field of each TLV.
+	Options tlv.TLVList
+}
+
+var (
+	rfc2131OptionCodec *tlv.TLVCodec
+)
+
+func init() {
+	var (
+		e error
+	)
+
+	rfc2131OptionCodec, e = tlv.NewTLVCodec(&tlv.TLVHeader8x8Format{})
+	if e != nil {
+		panic(e)
+	}
+
+	return
+}
+
+func (m *RFC2131Message) UnmarshalBinary(bytes []byte) (e error) {
+	var (
+		cookie RFC2131MagicCookieFormat
+		n      int
+		offset int
+		option tlv.TLV
+	)
+
+	if len(bytes) < rfc2131OptionsOffset {
+		e = ErrRFC2131MessageTruncated
+
+		return
+	}
+
+	e = binary.Unmarshal(bytes[:rfc2131FixedLengthInBytes],
+		&m.RFC2131MessageFormat,
+	)
+	if e != nil {
+		return
+	}
+
+	offset = rfc2131FixedLengthInBytes
+
+	offset += copy(m.CHAddr[:], bytes[offset:])
+	offset += copy(m.SName[:], bytes[offset:])
+	offset += copy(m.File[:], bytes[offset:])
+
+	e = binary.Unmarshal(bytes[offset:rfc2131OptionsOffset], &cookie)
+	if e != nil {
+		return
+	}
+
+	if cookie.MagicCookie != RFC2131MagicCookie {
+		e = ErrRFC2131MagicCookieInvalid
+
+		return
+	}
+
+	m.Options = nil
+
+	// Reference: Section 3 "Options" of
+	// RFC 2132 DHCP Options and BOOTP Vendor Extensions
+	// https://datatracker.ietf.org/doc/html/rfc2132#section-3.1
+
+	// > The pad option can be used to cause subsequent fields to align on
+	// > word boundaries.
+	// >
+	// > The end option marks the end of valid information in the vendor
+	// > field.  Subsequent octets should be filled with pad options.
+
+	for offset = rfc2131OptionsOffset; offset < len(bytes); offset += n {
+		switch bytes[offset] {
+		case RFC2132OptionPad:
+			n = 1
+
+			continue
+
+		case RFC2132OptionEnd:
+			return
+		}
+
+		option, n, e = rfc2131OptionCodec.UnmarshalTLV(bytes[offset:])
+		if e != nil {
+			return
+		}
+
+		m.Options = append(m.Options, option)
+	}
+
+	return
+}
+
+func (m *RFC2131Message) MarshalBinary() (bytes []byte, e error) {
+	var (
+		cookie       RFC2131MagicCookieFormat
+		cookieBytes  []byte
+		optionsBytes []byte
+	)
+
+	bytes, e = binary.Marshal(&m.RFC2131MessageFormat)
+	if e != nil {
+		return
+	}
+
+	cookie.MagicCookie = RFC2131MagicCookie
+
+	cookieBytes, e = binary.Marshal(&cookie)
+	if e != nil {
+		return
+	}
+
+	optionsBytes, e = rfc2131OptionCodec.Marshal(m.Options)
+	if e != nil {
+		return
+	}
+
+	bytes = append(bytes, m.CHAddr[:]...)
+	bytes = append(bytes, m.SName[:]...)
+	bytes = append(bytes, m.File[:]...)
+	bytes = append(bytes, cookieBytes...)
+	bytes = append(bytes, optionsBytes...)
+	bytes = append(bytes, RFC2132OptionEnd)
+
+	for len(bytes) < rfc2131MinimumMessageLengthInBytes {
+		bytes = append(bytes, RFC2132OptionPad)
+	}
+
+	return
+}
+
+// HardwareAddress returns the first hlen bytes of chaddr.
+func (m *RFC2131Message) HardwareAddress() net.HardwareAddr {
+	if int(m.HLen) > len(m.CHAddr) {
+		return net.HardwareAddr(m.CHAddr[:])
+	}
+
+	return net.HardwareAddr(m.CHAddr[:m.HLen])
+}
+
+// SetHardwareAddress sets chaddr and hlen.
+// Addresses longer than chaddr are truncated.
+func (m *RFC2131Message) SetHardwareAddress(address net.HardwareAddr) {
+	m.CHAddr = [rfc2131CHAddrLengthInBytes]byte{}
+	m.HLen = uint8(copy(m.CHAddr[:], address))
+
+	return
+}
+
+// RFC2131IPv4 converts an address field of a message to a net.IP.
+// From Go 1.18, RFC2131Addr converts it to a netip.Addr instead.
+func RFC2131IPv4(address uint32) net.IP {
+	return net.IPv4(
+		byte(address>>24), byte(address>>16), byte(address>>8), byte(address),
+	)
+}
+
+// RFC2131IPv4Uint32 converts a net.IP to an address field of a message.
+// It returns false if ip is not an IPv4 address.
+func RFC2131IPv4Uint32(ip net.IP) (address uint32, ok bool) {
+	ip = ip.To4()
+	if ip == nil {
+		return
+	}
+
+	address = uint32(ip[0])<<24 | uint32(ip[1])<<16 |
+		uint32(ip[2])<<8 | uint32(ip[3])
+
+	ok = true
+
+	return
+}
@@ -0,0 +1,194 @@
+package rfc2131
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRFC2131MessageRoundTrip(t *testing.T) {
+	var (
+		bytes       []byte
+		e           error
+		ips         []net.IP
+		ip          net.IP
+		message     RFC2131Message
+		message1    RFC2131Message
+		messageType uint8
+		ok          bool
+		seconds     uint32
+	)
+
+	message.Op = RFC2131OpBOOTREQUEST
+	message.HType = RFC2131HTypeEthernet
+	message.XID = 0x3903f326
+	message.Broadcast = true
+
+	message.SetHardwareAddress(
+		net.HardwareAddr{0x00, 0x0b, 0x82, 0x01, 0xfc, 0x42},
+	)
+
+	assert.Nil(t,
+		message.SetDHCPMessageType(RFC2132DHCPDISCOVER),
+	)
+
+	assert.Nil(t,
+		message.SetRequestedIPAddress(net.IPv4(192, 168, 0, 10)),
+	)
+
+	bytes, e = message.MarshalBinary()
+
+	assert.Nil(t, e)
+
+	assert.Len(t, bytes, rfc2131MinimumMessageLengthInBytes)
+
+	assert.Equal(t,
+		[]byte{
+			0x01, 0x01, 0x06, 0x00,
+			0x39, 0x03, 0xf3, 0x26,
+			0x00, 0x00, 0x80, 0x00,
+		},
+		bytes[:12],
+	)
+
+	assert.Equal(t,
+		[]byte{
+			0x63, 0x82, 0x53, 0x63,
+			0x35, 0x01, 0x01,
+			0x32, 0x04, 0xc0, 0xa8, 0x00, 0x0a,
+			0xff,
+			0x00,
+		},
+		bytes[rfc2131OptionsOffset-4:rfc2131OptionsOffset+11],
+	)
+
+	e = message1.UnmarshalBinary(bytes)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		message, message1,
+	)
+
+	assert.Equal(t,
+		net.HardwareAddr{0x00, 0x0b, 0x82, 0x01, 0xfc, 0x42},
+		message1.HardwareAddress(),
+	)
+
+	messageType, ok, e = message1.DHCPMessageType()
+
+	assert.True(t, ok)
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint8(RFC2132DHCPDISCOVER), messageType,
+	)
+
+	ip, ok, e = message1.RequestedIPAddress()
+
+	assert.True(t, ok)
+	assert.Nil(t, e)
+
+	assert.True(t,
+		net.IPv4(192, 168, 0, 10).Equal(ip),
+	)
+
+	_, ok, e = message1.IPAddressLeaseTime()
+
+	assert.False(t, ok)
+	assert.Nil(t, e)
+
+	assert.Nil(t,
+		message1.SetIPAddressLeaseTime(86400),
+	)
+
+	assert.Nil(t,
+		message1.SetRouter([]net.IP{net.IPv4(192, 168, 0, 1)}),
+	)
+
+	assert.Nil(t,
+		message1.SetDomainNameServer(
+			[]net.IP{net.IPv4(192, 168, 0, 1), net.IPv4(9, 9, 9, 9)},
+		),
+	)
+
+	seconds, ok, e = message1.IPAddressLeaseTime()
+
+	assert.True(t, ok)
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint32(86400), seconds,
+	)
+
+	ips, ok, e = message1.DomainNameServer()
+
+	assert.True(t, ok)
+	assert.Nil(t, e)
+
+	assert.Len(t, ips, 2)
+
+	assert.True(t,
+		net.IPv4(9, 9, 9, 9).Equal(ips[1]),
+	)
+
+	assert.Equal(t,
+		ErrRFC2131OptionLengthInvalid,
+		message1.SetRouter([]net.IP{net.ParseIP("2001:db8::1")}),
+	)
+}
+
+func TestRFC2131MessageOptions(t *testing.T) {
+	var (
+		bytes   []byte
+		e       error
+		message RFC2131Message
+		value   []byte
+		ok      bool
+	)
+
+	bytes = make([]byte, rfc2131OptionsOffset)
+
+	copy(bytes[rfc2131OptionsOffset-4:], []byte{0x63, 0x82, 0x53, 0x63})
+
+	// Pad options are skipped, and octets after the end option ignored.
+
+	bytes = append(bytes,
+		0x00, 0x00, 0x0c, 0x02, 'h', 'i', 0x00, 0xff, 0x0c, 0x01,
+	)
+
+	e = message.UnmarshalBinary(bytes)
+
+	assert.Nil(t, e)
+
+	assert.Len(t, message.Options, 1)
+
+	value, ok = message.Option(RFC2132OptionHostName)
+
+	assert.True(t, ok)
+
+	assert.Equal(t,
+		[]byte("hi"), value,
+	)
+
+	message.SetOption(RFC2132OptionDHCPMessageType, []byte{0x01, 0x02})
+
+	_, _, e = message.DHCPMessageType()
+
+	assert.Equal(t,
+		ErrRFC2131OptionLengthInvalid, e,
+	)
+
+	bytes[rfc2131OptionsOffset-1] = 0x00
+
+	assert.Equal(t,
+		ErrRFC2131MagicCookieInvalid,
+		message.UnmarshalBinary(bytes),
+	)
+
+	assert.Equal(t,
+		ErrRFC2131MessageTruncated,
+		message.UnmarshalBinary(bytes[:rfc2131OptionsOffset-1]),
+	)
+}
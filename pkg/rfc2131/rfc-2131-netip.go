@@ -0,0 +1,157 @@
+//go:build go1.18
+// +build go1.18
+
+package rfc2131
+
+import (
+	"net"
+	"net/netip"
+)
+
+// RFC2131Addr converts an address field of a message to a netip.Addr.
+func RFC2131Addr(address uint32) netip.Addr {
+	return netip.AddrFrom4(
+		[4]byte{
+			byte(address >> 24), byte(address >> 16),
+			byte(address >> 8), byte(address),
+		},
+	)
+}
+
+// RFC2131AddrUint32 converts a netip.Addr to an address field of a message.
+// IPv4-mapped IPv6 addresses are unmapped.
+// It returns false if addr is not an IPv4 address.
+func RFC2131AddrUint32(addr netip.Addr) (address uint32, ok bool) {
+	var (
+		octets [4]byte
+	)
+
+	addr = addr.Unmap()
+	if !addr.Is4() {
+		return
+	}
+
+	octets = addr.As4()
+
+	address = uint32(octets[0])<<24 | uint32(octets[1])<<16 |
+		uint32(octets[2])<<8 | uint32(octets[3])
+
+	ok = true
+
+	return
+}
+
+func (m *RFC2131Message) RequestedAddr() (addr netip.Addr, ok bool, e error) {
+	var (
+		ip net.IP
+	)
+
+	ip, ok, e = m.RequestedIPAddress()
+	if !ok || e != nil {
+		return
+	}
+
+	addr, _ = netip.AddrFromSlice(ip.To4())
+
+	return
+}
+
+func (m *RFC2131Message) SetRequestedAddr(addr netip.Addr) (e error) {
+	e = m.SetRequestedIPAddress(rfc2131AddrIP(addr))
+
+	return
+}
+
+func (m *RFC2131Message) RouterAddrs() (addrs []netip.Addr, ok bool,
+	e error,
+) {
+	var (
+		ips []net.IP
+	)
+
+	ips, ok, e = m.Router()
+	if !ok || e != nil {
+		return
+	}
+
+	addrs = rfc2131IPAddrs(ips)
+
+	return
+}
+
+func (m *RFC2131Message) SetRouterAddrs(addrs []netip.Addr) (e error) {
+	e = m.SetRouter(rfc2131AddrIPs(addrs))
+
+	return
+}
+
+func (m *RFC2131Message) DomainNameServerAddrs() (addrs []netip.Addr,
+	ok bool, e error,
+) {
+	var (
+		ips []net.IP
+	)
+
+	ips, ok, e = m.DomainNameServer()
+	if !ok || e != nil {
+		return
+	}
+
+	addrs = rfc2131IPAddrs(ips)
+
+	return
+}
+
+func (m *RFC2131Message) SetDomainNameServerAddrs(addrs []netip.Addr) (
+	e error,
+) {
+	e = m.SetDomainNameServer(rfc2131AddrIPs(addrs))
+
+	return
+}
+
+// rfc2131AddrIP returns nil for addresses other than IPv4,
+// which the setters of options reject.
+func rfc2131AddrIP(addr netip.Addr) (ip net.IP) {
+	var (
+		octets [4]byte
+	)
+
+	addr = addr.Unmap()
+	if !addr.Is4() {
+		return
+	}
+
+	octets = addr.As4()
+
+	ip = net.IP(octets[:])
+
+	return
+}
+
+func rfc2131AddrIPs(addrs []netip.Addr) (ips []net.IP) {
+	var (
+		addr netip.Addr
+	)
+
+	for _, addr = range addrs {
+		ips = append(ips, rfc2131AddrIP(addr))
+	}
+
+	return
+}
+
+func rfc2131IPAddrs(ips []net.IP) (addrs []netip.Addr) {
+	var (
+		addr netip.Addr
+		ip   net.IP
+	)
+
+	for _, ip = range ips {
+		addr, _ = netip.AddrFromSlice(ip.To4())
+
+		addrs = append(addrs, addr)
+	}
+
+	return
+}
@@ -0,0 +1,80 @@
+//go:build go1.18
+// +build go1.18
+
+package rfc2131
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRFC2131NetIP(t *testing.T) {
+	var (
+		addr    netip.Addr
+		addrs   []netip.Addr
+		address uint32
+		e       error
+		message RFC2131Message
+		ok      bool
+	)
+
+	address, ok = RFC2131AddrUint32(netip.MustParseAddr("::ffff:10.0.0.1"))
+
+	assert.True(t, ok)
+
+	assert.Equal(t,
+		uint32(0x0a000001), address,
+	)
+
+	assert.Equal(t,
+		netip.MustParseAddr("10.0.0.1"), RFC2131Addr(address),
+	)
+
+	_, ok = RFC2131AddrUint32(netip.MustParseAddr("2001:db8::1"))
+
+	assert.False(t, ok)
+
+	assert.Nil(t,
+		message.SetRequestedAddr(netip.MustParseAddr("192.168.0.10")),
+	)
+
+	addr, ok, e = message.RequestedAddr()
+
+	assert.True(t, ok)
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		netip.MustParseAddr("192.168.0.10"), addr,
+	)
+
+	assert.Nil(t,
+		message.SetDomainNameServerAddrs(
+			[]netip.Addr{
+				netip.MustParseAddr("1.1.1.1"),
+				netip.MustParseAddr("8.8.8.8"),
+			},
+		),
+	)
+
+	addrs, ok, e = message.DomainNameServerAddrs()
+
+	assert.True(t, ok)
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]netip.Addr{
+			netip.MustParseAddr("1.1.1.1"),
+			netip.MustParseAddr("8.8.8.8"),
+		},
+		addrs,
+	)
+
+	assert.Equal(t,
+		ErrRFC2131OptionLengthInvalid,
+		message.SetRouterAddrs(
+			[]netip.Addr{netip.MustParseAddr("2001:db8::1")},
+		),
+	)
+}
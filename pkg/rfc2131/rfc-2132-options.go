@@ -0,0 +1,374 @@
+package rfc2131
+
+import (
+	"net"
+
+	"github.com/encodingx/binary"
+	"github.com/encodingx/binary/pkg/tlv"
+)
+
+// Reference: RFC 2132 DHCP Options and BOOTP Vendor Extensions
+// https://datatracker.ietf.org/doc/html/rfc2132
+
+const (
+	RFC2132OptionPad                  = 0
+	RFC2132OptionSubnetMask           = 1
+	RFC2132OptionRouter               = 3
+	RFC2132OptionDomainNameServer     = 6
+	RFC2132OptionHostName             = 12
+	RFC2132OptionDomainName           = 15
+	RFC2132OptionBroadcastAddress     = 28
+	RFC2132OptionRequestedIPAddress   = 50
+	RFC2132OptionIPAddressLeaseTime   = 51
+	RFC2132OptionOverload             = 52
+	RFC2132OptionDHCPMessageType      = 53
+	RFC2132OptionServerIdentifier     = 54
+	RFC2132OptionParameterRequestList = 55
+	RFC2132OptionMessage              = 56
+	RFC2132OptionMaximumMessageSize   = 57
+	RFC2132OptionRenewalTimeValue     = 58
+	RFC2132OptionRebindingTimeValue   = 59
+	RFC2132OptionClientIdentifier     = 61
+	RFC2132OptionEnd                  = 255
+)
+
+const (
+	RFC2132DHCPDISCOVER = 1
+	RFC2132DHCPOFFER    = 2
+	RFC2132DHCPREQUEST  = 3
+	RFC2132DHCPDECLINE  = 4
+	RFC2132DHCPACK      = 5
+	RFC2132DHCPNAK      = 6
+	RFC2132DHCPRELEASE  = 7
+	RFC2132DHCPINFORM   = 8
+)
+
+type RFC2132DHCPMessageTypeFormat struct {
+	// Reference: Section 9.6 "DHCP Message Type"
+
+	// >     Code   Len  Type
+	// >    +-----+-----+-----+
+	// >    |  53 |  1  | 1-9 |
+	// >    +-----+-----+-----+
+
+	RFC2132DHCPMessageTypeFormatWord0 `word:"8"`
+}
+
+type RFC2132DHCPMessageTypeFormatWord0 struct {
+	Type uint8 `bitfield:"8"`
+}
+
+type RFC2132IPAddressLeaseTimeFormat struct {
+	// Reference: Section 9.2 "IP Address Lease Time"
+
+	// > The time is in units of seconds, and is specified as a 32-bit
+	// > unsigned integer.
+
+	RFC2132IPAddressLeaseTimeFormatWord0 `word:"32"`
+}
+
+type RFC2132IPAddressLeaseTimeFormatWord0 struct {
+	Seconds uint32 `bitfield:"32"`
+}
+
+// Option returns the value of the first option with a code.
+func (m *RFC2131Message) Option(code uint8) (value []byte, ok bool) {
+	var (
+		option tlv.TLV
+	)
+
+	option, ok = m.Options.Find(uint64(code))
+	if !ok {
+		return
+	}
+
+	value = option.Raw
+
+	return
+}
+
+// SetOption replaces the value of the first option with a code,
+// or appends the option if it is absent.
+func (m *RFC2131Message) SetOption(code uint8, value []byte) {
+	var (
+		i int
+	)
+
+	for i = range m.Options {
+		if m.Options[i].Type == uint64(code) {
+			m.Options[i].Value = nil
+			m.Options[i].Raw = value
+
+			return
+		}
+	}
+
+	m.Options = append(m.Options,
+		tlv.TLV{
+			Type: uint64(code),
+			Raw:  value,
+		},
+	)
+
+	return
+}
+
+func (m *RFC2131Message) DHCPMessageType() (messageType uint8, ok bool,
+	e error,
+) {
+	var (
+		format RFC2132DHCPMessageTypeFormat
+	)
+
+	ok, e = m.unmarshalOption(RFC2132OptionDHCPMessageType, &format)
+	if !ok || e != nil {
+		return
+	}
+
+	messageType = format.Type
+
+	return
+}
+
+func (m *RFC2131Message) SetDHCPMessageType(messageType uint8) (e error) {
+	var (
+		format RFC2132DHCPMessageTypeFormat
+	)
+
+	format.Type = messageType
+
+	e = m.marshalOption(RFC2132OptionDHCPMessageType, &format)
+
+	return
+}
+
+func (m *RFC2131Message) IPAddressLeaseTime() (seconds uint32, ok bool,
+	e error,
+) {
+	var (
+		format RFC2132IPAddressLeaseTimeFormat
+	)
+
+	ok, e = m.unmarshalOption(RFC2132OptionIPAddressLeaseTime, &format)
+	if !ok || e != nil {
+		return
+	}
+
+	seconds = format.Seconds
+
+	return
+}
+
+func (m *RFC2131Message) SetIPAddressLeaseTime(seconds uint32) (e error) {
+	var (
+		format RFC2132IPAddressLeaseTimeFormat
+	)
+
+	format.Seconds = seconds
+
+	e = m.marshalOption(RFC2132OptionIPAddressLeaseTime, &format)
+
+	return
+}
+
+func (m *RFC2131Message) RequestedIPAddress() (ip net.IP, ok bool, e error) {
+	var (
+		ips []net.IP
+	)
+
+	ips, ok, e = m.ipv4Option(RFC2132OptionRequestedIPAddress)
+	if !ok || e != nil {
+		return
+	}
+
+	if len(ips) != 1 {
+		e = ErrRFC2131OptionLengthInvalid
+
+		return
+	}
+
+	ip = ips[0]
+
+	return
+}
+
+func (m *RFC2131Message) SetRequestedIPAddress(ip net.IP) (e error) {
+	e = m.setIPv4Option(RFC2132OptionRequestedIPAddress, []net.IP{ip})
+
+	return
+}
+
+func (m *RFC2131Message) ServerIdentifier() (ip net.IP, ok bool, e error) {
+	var (
+		ips []net.IP
+	)
+
+	ips, ok, e = m.ipv4Option(RFC2132OptionServerIdentifier)
+	if !ok || e != nil {
+		return
+	}
+
+	if len(ips) != 1 {
+		e = ErrRFC2131OptionLengthInvalid
+
+		return
+	}
+
+	ip = ips[0]
+
+	return
+}
+
+func (m *RFC2131Message) SetServerIdentifier(ip net.IP) (e error) {
+	e = m.setIPv4Option(RFC2132OptionServerIdentifier, []net.IP{ip})
+
+	return
+}
+
+func (m *RFC2131Message) SubnetMask() (mask net.IPMask, ok bool, e error) {
+	var (
+		ips []net.IP
+	)
+
+	ips, ok, e = m.ipv4Option(RFC2132OptionSubnetMask)
+	if !ok || e != nil {
+		return
+	}
+
+	if len(ips) != 1 {
+		e = ErrRFC2131OptionLengthInvalid
+
+		return
+	}
+
+	mask = net.IPMask(ips[0].To4())
+
+	return
+}
+
+func (m *RFC2131Message) SetSubnetMask(mask net.IPMask) (e error) {
+	e = m.setIPv4Option(RFC2132OptionSubnetMask, []net.IP{net.IP(mask)})
+
+	return
+}
+
+// Router returns the routers on the subnet of the client,
+// in order of preference.
+func (m *RFC2131Message) Router() (ips []net.IP, ok bool, e error) {
+	ips, ok, e = m.ipv4Option(RFC2132OptionRouter)
+
+	return
+}
+
+func (m *RFC2131Message) SetRouter(ips []net.IP) (e error) {
+	e = m.setIPv4Option(RFC2132OptionRouter, ips)
+
+	return
+}
+
+// DomainNameServer returns the DNS servers available to the client,
+// in order of preference.
+func (m *RFC2131Message) DomainNameServer() (ips []net.IP, ok bool,
+	e error,
+) {
+	ips, ok, e = m.ipv4Option(RFC2132OptionDomainNameServer)
+
+	return
+}
+
+func (m *RFC2131Message) SetDomainNameServer(ips []net.IP) (e error) {
+	e = m.setIPv4Option(RFC2132OptionDomainNameServer, ips)
+
+	return
+}
+
+func (m *RFC2131Message) unmarshalOption(code uint8, format interface{}) (
+	ok bool, e error,
+) {
+	var (
+		value []byte
+	)
+
+	value, ok = m.Option(code)
+	if !ok {
+		return
+	}
+
+	e = binary.Unmarshal(value, format)
+	if e != nil {
+		e = ErrRFC2131OptionLengthInvalid
+
+		return
+	}
+
+	return
+}
+
+func (m *RFC2131Message) marshalOption(code uint8, format interface{}) (
+	e error,
+) {
+	var (
+		value []byte
+	)
+
+	value, e = binary.Marshal(format)
+	if e != nil {
+		return
+	}
+
+	m.SetOption(code, value)
+
+	return
+}
+
+// ipv4Option decodes an option whose value is one or more IPv4 addresses.
+func (m *RFC2131Message) ipv4Option(code uint8) (ips []net.IP, ok bool,
+	e error,
+) {
+	var (
+		i     int
+		value []byte
+	)
+
+	value, ok = m.Option(code)
+	if !ok {
+		return
+	}
+
+	if len(value) == 0 || len(value)%net.IPv4len != 0 {
+		e = ErrRFC2131OptionLengthInvalid
+
+		return
+	}
+
+	for i = 0; i < len(value); i += net.IPv4len {
+		ips = append(ips,
+			net.IPv4(value[i], value[i+1], value[i+2], value[i+3]),
+		)
+	}
+
+	return
+}
+
+func (m *RFC2131Message) setIPv4Option(code uint8, ips []net.IP) (e error) {
+	var (
+		ip    net.IP
+		ip4   net.IP
+		value []byte
+	)
+
+	for _, ip = range ips {
+		ip4 = ip.To4()
+		if ip4 == nil {
+			e = ErrRFC2131OptionLengthInvalid
+
+			return
+		}
+
+		value = append(value, ip4...)
+	}
+
+	m.SetOption(code, value)
+
+	return
+}
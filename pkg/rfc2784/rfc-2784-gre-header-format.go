@@ -0,0 +1,328 @@
+package rfc2784
+
+import (
+	"errors"
+
+	"github.com/encodingx/binary"
+)
+
+type RFC2784GREHeaderFormat struct {
+	// Reference: Section 2 "Structure of a GRE Encapsulated Packet" of
+	// RFC 2784 Generic Routing Encapsulation (GRE)
+	// https://datatracker.ietf.org/doc/html/rfc2784#section-2
+	// and Section 2 "Extensions to GRE Header" of
+	// RFC 2890 Key and Sequence Number Extensions to GRE
+	// https://datatracker.ietf.org/doc/html/rfc2890#section-2
+
+	// >  0                   1                   2                   3
+	// >  0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+	// > +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	// > |C| |K|S| Reserved0       | Ver |         Protocol Type         |
+	// > +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	// > |      Checksum (optional)      |       Reserved1 (Optional)    |
+	// > +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	// > |                         Key (optional)                        |
+	// > +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	// > |                 Sequence Number (Optional)                    |
+	// > +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	//
+	// The optional words are the format-structs
+	// RFC2784GREChecksumFormat, RFC2890GREKeyFormat and
+	// RFC2890GRESequenceNumberFormat, present in that order
+	// when their flag bits are set.
+
+	RFC2784GREHeaderFormatWord0 `word:"32"`
+}
+
+type RFC2784GREHeaderFormatWord0 struct {
+	ChecksumPresent       bool   `bitfield:"1"`
+	Reserved0Bit1         bool   `bitfield:"1"`
+	KeyPresent            bool   `bitfield:"1"`
+	SequenceNumberPresent bool   `bitfield:"1"`
+	Reserved0             uint16 `bitfield:"9"`
+	// > Checksum Present (bit 0)
+	// >
+	// >   If the Checksum Present bit is set to one, then the Checksum and
+	// >   the Reserved1 fields are present and the Checksum field contains
+	// >   valid information.
+	// >
+	// > Key Present (bit 2)
+	// >
+	// >   If the Key Present bit is set to 1, then it indicates that the
+	// >   Key field is present in the GRE header.
+	// >
+	// > Sequence Number Present (bit 3)
+	// >
+	// >   If the Sequence Number Present bit is set to 1, then it indicates
+	// >   that the Sequence Number field is present.
+	// >
+	// > Reserved0 (bits 4-12)
+	// >
+	// >   A receiver MUST discard a packet where any of bits 1-5 are non-
+	// >   zero, unless that receiver implements RFC 1701. Bits 6-12 are
+	// >   reserved for future use. These bits MUST be sent as zero and MUST
+	// >   be ignored on receipt.
+
+	Version uint8 `bitfield:"3"`
+	// > Version Number (bits 13-15)
+	// >
+	// >   The Version Number field MUST contain the value zero.
+
+	ProtocolType uint16 `bitfield:"16"`
+	// > Protocol Type (2 octets)
+	// >
+	// >   The Protocol Type field contains the protocol type of the payload
+	// >   packet. These Protocol Types are defined in [RFC1700] as "ETHER
+	// >   TYPES" and in [ETYPES].
+}
+
+const (
+	RFC2784GREHeaderVersion = 0
+)
+
+const (
+	RFC2784GREHeaderProtocolTypeIPv4                        = 0x0800
+	RFC2784GREHeaderProtocolTypeTransparentEthernetBridging = 0x6558
+	RFC2784GREHeaderProtocolTypeIPv6                        = 0x86dd
+)
+
+type RFC2784GREChecksumFormat struct {
+	RFC2784GREChecksumFormatWord0 `word:"32"`
+}
+
+type RFC2784GREChecksumFormatWord0 struct {
+	Checksum uint16 `bitfield:"16"`
+	// > Checksum (2 octets)
+	// >
+	// >   The Checksum field contains the IP (one's complement) checksum sum
+	// >   of the all the 16 bit words in the GRE header and the payload
+	// >   packet. For purposes of computing the checksum, the value of the
+	// >   checksum field is zero. This field is present only if the Checksum
+	// >   Present bit is set to one.
+
+	Reserved1 uint16 `bitfield:"16"`
+}
+
+type RFC2890GREKeyFormat struct {
+	RFC2890GREKeyFormatWord0 `word:"32"`
+}
+
+type RFC2890GREKeyFormatWord0 struct {
+	Key uint32 `bitfield:"32"`
+	// > The Key field contains a four octet number which was inserted by
+	// > the encapsulator. The actual method by which this Key is obtained
+	// > is beyond the scope of the document. The Key field is intended to
+	// > be used for identifying an individual traffic flow within a tunnel.
+}
+
+type RFC2890GRESequenceNumberFormat struct {
+	RFC2890GRESequenceNumberFormatWord0 `word:"32"`
+}
+
+type RFC2890GRESequenceNumberFormatWord0 struct {
+	SequenceNumber uint32 `bitfield:"32"`
+	// > The Sequence Number MUST be used by the receiver to establish the
+	// > order in which packets have been transmitted from the encapsulator
+	// > to the receiver.
+}
+
+const (
+	rfc2784GREWordLengthInBytes = 4
+)
+
+var (
+	ErrRFC2784GREChecksumMismatch = errors.New(
+		"The Checksum field of a GRE header should contain " +
+			"the one's complement checksum of the header and payload. " +
+			"The checksum does not match the packet.",
+	)
+
+	ErrRFC2784GREPacketTooShort = errors.New(
+		"A GRE packet should be at least as long as " +
+			"the header words indicated by its flag bits. " +
+			"The packet ends before the header does.",
+	)
+
+	ErrRFC2784GREVersionNotZero = errors.New(
+		"The Version Number field of a GRE header MUST contain zero. " +
+			"The version number of the header is not zero.",
+	)
+)
+
+type RFC2784GREPacket struct {
+	RFC2784GREHeaderFormat
+	RFC2784GREChecksumFormat
+	RFC2890GREKeyFormat
+	RFC2890GRESequenceNumberFormat
+
+	Payload []byte
+}
+
+func (p *RFC2784GREPacket) MarshalBinary() (bytes []byte, e error) {
+	// The checksum is computed over the packet as marshalled,
+	// overwriting any value in the Checksum field.
+
+	var (
+		wordBytes []byte
+	)
+
+	wordBytes, e = binary.Marshal(&p.RFC2784GREHeaderFormat)
+	if e != nil {
+		return
+	}
+
+	bytes = append(bytes, wordBytes...)
+
+	if p.ChecksumPresent {
+		bytes = append(bytes, make([]byte, rfc2784GREWordLengthInBytes)...)
+	}
+
+	if p.KeyPresent {
+		wordBytes, e = binary.Marshal(&p.RFC2890GREKeyFormat)
+		if e != nil {
+			return
+		}
+
+		bytes = append(bytes, wordBytes...)
+	}
+
+	if p.SequenceNumberPresent {
+		wordBytes, e = binary.Marshal(&p.RFC2890GRESequenceNumberFormat)
+		if e != nil {
+			return
+		}
+
+		bytes = append(bytes, wordBytes...)
+	}
+
+	bytes = append(bytes, p.Payload...)
+
+	if p.ChecksumPresent {
+		p.Checksum = rfc2784Checksum(bytes)
+
+		binary.BigEndian.PutUint16(bytes[rfc2784GREWordLengthInBytes:],
+			p.Checksum,
+		)
+	}
+
+	return
+}
+
+func (p *RFC2784GREPacket) UnmarshalBinary(bytes []byte) (e error) {
+	var (
+		i int
+	)
+
+	if len(bytes) < rfc2784GREWordLengthInBytes {
+		e = ErrRFC2784GREPacketTooShort
+
+		return
+	}
+
+	e = binary.Unmarshal(bytes[:rfc2784GREWordLengthInBytes],
+		&p.RFC2784GREHeaderFormat,
+	)
+	if e != nil {
+		return
+	}
+
+	if p.Version != RFC2784GREHeaderVersion {
+		e = ErrRFC2784GREVersionNotZero
+
+		return
+	}
+
+	if len(bytes) < p.HeaderLengthInBytes() {
+		e = ErrRFC2784GREPacketTooShort
+
+		return
+	}
+
+	i = rfc2784GREWordLengthInBytes
+
+	p.RFC2784GREChecksumFormat = RFC2784GREChecksumFormat{}
+	p.RFC2890GREKeyFormat = RFC2890GREKeyFormat{}
+	p.RFC2890GRESequenceNumberFormat = RFC2890GRESequenceNumberFormat{}
+
+	if p.ChecksumPresent {
+		if rfc2784Checksum(bytes) != 0 {
+			e = ErrRFC2784GREChecksumMismatch
+
+			return
+		}
+
+		e = binary.Unmarshal(bytes[i:i+rfc2784GREWordLengthInBytes],
+			&p.RFC2784GREChecksumFormat,
+		)
+		if e != nil {
+			return
+		}
+
+		i += rfc2784GREWordLengthInBytes
+	}
+
+	if p.KeyPresent {
+		e = binary.Unmarshal(bytes[i:i+rfc2784GREWordLengthInBytes],
+			&p.RFC2890GREKeyFormat,
+		)
+		if e != nil {
+			return
+		}
+
+		i += rfc2784GREWordLengthInBytes
+	}
+
+	if p.SequenceNumberPresent {
+		e = binary.Unmarshal(bytes[i:i+rfc2784GREWordLengthInBytes],
+			&p.RFC2890GRESequenceNumberFormat,
+		)
+		if e != nil {
+			return
+		}
+
+		i += rfc2784GREWordLengthInBytes
+	}
+
+	p.Payload = bytes[i:]
+
+	return
+}
+
+func (p *RFC2784GREPacket) HeaderLengthInBytes() (length int) {
+	length = rfc2784GREWordLengthInBytes
+
+	if p.ChecksumPresent {
+		length += rfc2784GREWordLengthInBytes
+	}
+
+	if p.KeyPresent {
+		length += rfc2784GREWordLengthInBytes
+	}
+
+	if p.SequenceNumberPresent {
+		length += rfc2784GREWordLengthInBytes
+	}
+
+	return
+}
+
+func rfc2784Checksum(bytes []byte) uint16 {
+	var (
+		i   int
+		sum uint32
+	)
+
+	for i = 0; i+1 < len(bytes); i += 2 {
+		sum += uint32(bytes[i])<<8 | uint32(bytes[i+1])
+	}
+
+	if len(bytes)%2 == 1 {
+		sum += uint32(bytes[len(bytes)-1]) << 8
+	}
+
+	for sum>>16 != 0 {
+		sum = sum&0xffff + sum>>16
+	}
+
+	return ^uint16(sum)
+}
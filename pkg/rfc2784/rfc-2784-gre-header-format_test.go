@@ -0,0 +1,87 @@
+package rfc2784
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRFC2784GREPacketRoundTrip(t *testing.T) {
+	var (
+		bytes   []byte
+		e       error
+		packet  RFC2784GREPacket
+		packet1 RFC2784GREPacket
+	)
+
+	packet.ChecksumPresent = true
+	packet.KeyPresent = true
+	packet.SequenceNumberPresent = true
+	packet.ProtocolType = RFC2784GREHeaderProtocolTypeIPv4
+	packet.Key = 0x01020304
+	packet.SequenceNumber = 7
+	packet.Payload = []byte{0x45, 0x00, 0x00}
+
+	bytes, e = packet.MarshalBinary()
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]byte{
+			0b10110000, 0x00, 0x08, 0x00,
+			0xfe, 0xf1, 0x00, 0x00,
+			0x01, 0x02, 0x03, 0x04,
+			0x00, 0x00, 0x00, 0x07,
+			0x45, 0x00, 0x00,
+		},
+		bytes,
+	)
+
+	e = packet1.UnmarshalBinary(bytes)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		packet, packet1,
+	)
+
+	bytes[len(bytes)-1] = 1
+
+	e = packet1.UnmarshalBinary(bytes)
+
+	assert.Equal(t,
+		ErrRFC2784GREChecksumMismatch, e,
+	)
+}
+
+func TestRFC2784GREPacketWithoutOptionalWords(t *testing.T) {
+	var (
+		e      error
+		packet RFC2784GREPacket
+	)
+
+	e = packet.UnmarshalBinary([]byte{0x00, 0x00, 0x65, 0x58, 0xff})
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint16(RFC2784GREHeaderProtocolTypeTransparentEthernetBridging),
+		packet.ProtocolType,
+	)
+
+	assert.Equal(t,
+		[]byte{0xff}, packet.Payload,
+	)
+
+	e = packet.UnmarshalBinary([]byte{0b00100000, 0x00, 0x08, 0x00})
+
+	assert.Equal(t,
+		ErrRFC2784GREPacketTooShort, e,
+	)
+
+	e = packet.UnmarshalBinary([]byte{0x00, 0x01, 0x08, 0x00})
+
+	assert.Equal(t,
+		ErrRFC2784GREVersionNotZero, e,
+	)
+}
@@ -0,0 +1,489 @@
+package rfc4271
+
+import (
+	"errors"
+
+	"github.com/encodingx/binary"
+	"github.com/encodingx/binary/pkg/tlv"
+)
+
+type RFC4271MessageHeaderFormat struct {
+	// Reference: Section 4.1 "Message Header Format" of
+	// RFC 4271 A Border Gateway Protocol 4 (BGP-4)
+	// https://datatracker.ietf.org/doc/html/rfc4271#section-4.1
+
+	// >    0                   1                   2                   3
+	// >    0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+	// >    +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	// >    |                                                               |
+	// >    +                                                               +
+	// >    |                                                               |
+	// >    +                                                               +
+	// >    |                           Marker                              |
+	// >    +                                                               +
+	// >    |                                                               |
+	// >    +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	// >    |          Length               |      Type     |
+	// >    +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+
+	RFC4271MessageHeaderFormatWord0 `word:"64"`
+	RFC4271MessageHeaderFormatWord1 `word:"64"`
+	RFC4271MessageHeaderFormatWord2 `word:"24"`
+}
+
+type RFC4271MessageHeaderFormatWord0 struct {
+	Marker0 uint64 `bitfield:"64"`
+	// > Marker:
+	// >
+	// >    This 16-octet field is included for compatibility; it MUST be
+	// >    set to all ones.
+}
+
+type RFC4271MessageHeaderFormatWord1 struct {
+	Marker1 uint64 `bitfield:"64"`
+}
+
+type RFC4271MessageHeaderFormatWord2 struct {
+	Length uint16 `bitfield:"16"`
+	// > Length:
+	// >
+	// >    This 2-octet unsigned integer indicates the total length of the
+	// >    message, including the header in octets.  [...] The value of
+	// >    the Length field MUST always be at least 19 and no greater than
+	// >    4096, and MAY be further constrained, depending on the message
+	// >    type.
+
+	Type uint8 `bitfield:"8"`
+	// > Type:
+	// >
+	// >    This 1-octet unsigned integer indicates the type code of the
+	// >    message.
+}
+
+const (
+	RFC4271MessageHeaderLengthInBytes  = 19
+	RFC4271MaximumMessageLengthInBytes = 4096
+
+	RFC4271Marker = 0xffffffffffffffff
+)
+
+const (
+	RFC4271MessageTypeOPEN         = 1
+	RFC4271MessageTypeUPDATE       = 2
+	RFC4271MessageTypeNOTIFICATION = 3
+	RFC4271MessageTypeKEEPALIVE    = 4
+)
+
+var (
+	ErrRFC4271MarkerInvalid = errors.New(
+		"The marker of a BGP message should be all ones. " +
+			"The marker has bits that are not set.",
+	)
+
+	ErrRFC4271MessageLengthInvalid = errors.New(
+		"The length of a BGP message should be between the minimum " +
+			"for its type and 4096. " +
+			"The message is of an invalid length.",
+	)
+
+	ErrRFC4271MessageTruncated = errors.New(
+		"The bytes of a BGP message should be as many as its length. " +
+			"The bytes end before the message.",
+	)
+
+	ErrRFC4271MessageTypeUnknown = errors.New(
+		"The type of a BGP message should be one defined by RFC 4271. " +
+			"The message is of an unknown type.",
+	)
+)
+
+// RFC4271Message is implemented by the body of each message type.
+type RFC4271Message interface {
+	RFC4271MessageType() uint8
+	MarshalRFC4271Body() ([]byte, error)
+	UnmarshalRFC4271Body([]byte) error
+}
+
+// ParseRFC4271Message decodes the message at the start of bytes,
+// returning the number of bytes it occupies.
+func ParseRFC4271Message(bytes []byte) (message RFC4271Message, n int,
+	e error,
+) {
+	var (
+		header RFC4271MessageHeaderFormat
+		min    int
+	)
+
+	if len(bytes) < RFC4271MessageHeaderLengthInBytes {
+		e = ErrRFC4271MessageTruncated
+
+		return
+	}
+
+	e = binary.Unmarshal(bytes[:RFC4271MessageHeaderLengthInBytes], &header)
+	if e != nil {
+		return
+	}
+
+	if header.Marker0 != RFC4271Marker || header.Marker1 != RFC4271Marker {
+		e = ErrRFC4271MarkerInvalid
+
+		return
+	}
+
+	switch header.Type {
+	case RFC4271MessageTypeOPEN:
+		message = &RFC4271OpenMessage{}
+		min = RFC4271MessageHeaderLengthInBytes + rfc4271OpenLengthInBytes
+
+	case RFC4271MessageTypeUPDATE:
+		message = &RFC4271UpdateMessage{}
+		min = RFC4271MessageHeaderLengthInBytes + 4
+
+	case RFC4271MessageTypeNOTIFICATION:
+		message = &RFC4271NotificationMessage{}
+		min = RFC4271MessageHeaderLengthInBytes +
+			rfc4271NotificationLengthInBytes
+
+	case RFC4271MessageTypeKEEPALIVE:
+		message = &RFC4271KeepaliveMessage{}
+		min = RFC4271MessageHeaderLengthInBytes
+
+	default:
+		e = ErrRFC4271MessageTypeUnknown
+
+		return
+	}
+
+	n = int(header.Length)
+
+	if n < min || n > RFC4271MaximumMessageLengthInBytes ||
+		header.Type == RFC4271MessageTypeKEEPALIVE && n != min {
+		message = nil
+		n = 0
+		e = ErrRFC4271MessageLengthInvalid
+
+		return
+	}
+
+	if len(bytes) < n {
+		message = nil
+		n = 0
+		e = ErrRFC4271MessageTruncated
+
+		return
+	}
+
+	e = message.UnmarshalRFC4271Body(bytes[RFC4271MessageHeaderLengthInBytes:n])
+	if e != nil {
+		message = nil
+		n = 0
+
+		return
+	}
+
+	return
+}
+
+// MarshalRFC4271Message encodes a message with its header.
+func MarshalRFC4271Message(message RFC4271Message) (bytes []byte, e error) {
+	var (
+		body   []byte
+		header RFC4271MessageHeaderFormat
+	)
+
+	body, e = message.MarshalRFC4271Body()
+	if e != nil {
+		return
+	}
+
+	if RFC4271MessageHeaderLengthInBytes+len(body) >
+		RFC4271MaximumMessageLengthInBytes {
+		e = ErrRFC4271MessageLengthInvalid
+
+		return
+	}
+
+	header.Marker0 = RFC4271Marker
+	header.Marker1 = RFC4271Marker
+	header.Length = uint16(RFC4271MessageHeaderLengthInBytes + len(body))
+	header.Type = message.RFC4271MessageType()
+
+	bytes, e = binary.Marshal(&header)
+	if e != nil {
+		return
+	}
+
+	bytes = append(bytes, body...)
+
+	return
+}
+
+type RFC4271OpenMessageFormat struct {
+	// Reference: Section 4.2 "OPEN Message Format"
+
+	// >     0                   1                   2                   3
+	// >     0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+	// >     +-+-+-+-+-+-+-+-+
+	// >     |    Version    |
+	// >     +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	// >     |     My Autonomous System      |
+	// >     +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	// >     |           Hold Time           |
+	// >     +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	// >     |                         BGP Identifier                        |
+	// >     +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	// >     | Opt Parm Len  |
+	// >     +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	// >     |                                                               |
+	// >     |             Optional Parameters (variable)                    |
+	// >     |                                                               |
+	// >     +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+
+	RFC4271OpenMessageFormatWord0 `word:"8"`
+	RFC4271OpenMessageFormatWord1 `word:"16"`
+	RFC4271OpenMessageFormatWord2 `word:"16"`
+	RFC4271OpenMessageFormatWord3 `word:"32"`
+	RFC4271OpenMessageFormatWord4 `word:"8"`
+}
+
+type RFC4271OpenMessageFormatWord0 struct {
+	Version uint8 `bitfield:"8"`
+	// > Version:
+	// >
+	// >    This 1-octet unsigned integer indicates the protocol version
+	// >    number of the message.  The current BGP version number is 4.
+}
+
+type RFC4271OpenMessageFormatWord1 struct {
+	MyAutonomousSystem uint16 `bitfield:"16"`
+	// > My Autonomous System:
+	// >
+	// >    This 2-octet unsigned integer indicates the Autonomous System
+	// >    number of the sender.
+}
+
+type RFC4271OpenMessageFormatWord2 struct {
+	HoldTime uint16 `bitfield:"16"`
+	// > Hold Time:
+	// >
+	// >    This 2-octet unsigned integer indicates the number of seconds
+	// >    the sender proposes for the value of the Hold Timer.
+}
+
+type RFC4271OpenMessageFormatWord3 struct {
+	BGPIdentifier uint32 `bitfield:"32"`
+	// > BGP Identifier:
+	// >
+	// >    This 4-octet unsigned integer indicates the BGP Identifier of
+	// >    the sender.
+}
+
+type RFC4271OpenMessageFormatWord4 struct {
+	OptParmLen uint8 `bitfield:"8"`
+	// > Optional Parameters Length:
+	// >
+	// >    This 1-octet unsigned integer indicates the total length of the
+	// >    Optional Parameters field in octets.
+}
+
+const (
+	RFC4271Version = 4
+
+	// Reference: RFC 5492 Capabilities Advertisement with BGP-4
+	RFC4271OptionalParameterCapabilities = 2
+
+	rfc4271OpenLengthInBytes = 10
+)
+
+var (
+	rfc4271OptionalParameterCodec *tlv.TLVCodec
+)
+
+func init() {
+	var (
+		e error
+	)
+
+	// > Optional Parameters:
+	// >
+	// >    This field contains a list of optional parameters, in which
+	// >    each parameter is encoded as a <Parameter Type, Parameter
+	// >    Length, Parameter Value> triplet.
+
+	rfc4271OptionalParameterCodec, e = tlv.NewTLVCodec(
+		&tlv.TLVHeader8x8Format{},
+	)
+	if e != nil {
+		panic(e)
+	}
+
+	return
+}
+
+type RFC4271OpenMessage struct {
+	RFC4271OpenMessageFormat
+
+	// The values of all optional parameters are held
+	// in the Raw field of each TLV.
+	OptionalParameters tlv.TLVList
+}
+
+func (m *RFC4271OpenMessage) RFC4271MessageType() uint8 {
+	return RFC4271MessageTypeOPEN
+}
+
+// MarshalRFC4271Body sets the Optional Parameters Length.
+func (m *RFC4271OpenMessage) MarshalRFC4271Body() (bytes []byte, e error) {
+	var (
+		parameters []byte
+	)
+
+	parameters, e = rfc4271OptionalParameterCodec.Marshal(m.OptionalParameters)
+	if e != nil {
+		return
+	}
+
+	if len(parameters) > 0xff {
+		e = ErrRFC4271MessageLengthInvalid
+
+		return
+	}
+
+	m.OptParmLen = uint8(len(parameters))
+
+	bytes, e = binary.Marshal(&m.RFC4271OpenMessageFormat)
+	if e != nil {
+		return
+	}
+
+	bytes = append(bytes, parameters...)
+
+	return
+}
+
+func (m *RFC4271OpenMessage) UnmarshalRFC4271Body(bytes []byte) (e error) {
+	if len(bytes) < rfc4271OpenLengthInBytes {
+		e = ErrRFC4271MessageLengthInvalid
+
+		return
+	}
+
+	e = binary.Unmarshal(bytes[:rfc4271OpenLengthInBytes],
+		&m.RFC4271OpenMessageFormat,
+	)
+	if e != nil {
+		return
+	}
+
+	bytes = bytes[rfc4271OpenLengthInBytes:]
+
+	if len(bytes) != int(m.OptParmLen) {
+		e = ErrRFC4271MessageLengthInvalid
+
+		return
+	}
+
+	m.OptionalParameters, e = rfc4271OptionalParameterCodec.Unmarshal(bytes)
+	if e != nil {
+		return
+	}
+
+	return
+}
+
+type RFC4271NotificationMessageFormat struct {
+	// Reference: Section 4.5 "NOTIFICATION Message Format"
+
+	// >     0                   1                   2                   3
+	// >     0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+	// >     +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	// >     | Error code    | Error subcode |   Data (variable)             |
+	// >     +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+
+	RFC4271NotificationMessageFormatWord0 `word:"16"`
+}
+
+type RFC4271NotificationMessageFormatWord0 struct {
+	ErrorCode    uint8 `bitfield:"8"`
+	ErrorSubcode uint8 `bitfield:"8"`
+}
+
+const (
+	RFC4271ErrorCodeMessageHeaderError      = 1
+	RFC4271ErrorCodeOPENMessageError        = 2
+	RFC4271ErrorCodeUPDATEMessageError      = 3
+	RFC4271ErrorCodeHoldTimerExpired        = 4
+	RFC4271ErrorCodeFiniteStateMachineError = 5
+	RFC4271ErrorCodeCease                   = 6
+
+	rfc4271NotificationLengthInBytes = 2
+)
+
+type RFC4271NotificationMessage struct {
+	RFC4271NotificationMessageFormat
+
+	Data []byte
+}
+
+func (m *RFC4271NotificationMessage) RFC4271MessageType() uint8 {
+	return RFC4271MessageTypeNOTIFICATION
+}
+
+func (m *RFC4271NotificationMessage) MarshalRFC4271Body() (bytes []byte,
+	e error,
+) {
+	bytes, e = binary.Marshal(&m.RFC4271NotificationMessageFormat)
+	if e != nil {
+		return
+	}
+
+	bytes = append(bytes, m.Data...)
+
+	return
+}
+
+func (m *RFC4271NotificationMessage) UnmarshalRFC4271Body(bytes []byte) (
+	e error,
+) {
+	if len(bytes) < rfc4271NotificationLengthInBytes {
+		e = ErrRFC4271MessageLengthInvalid
+
+		return
+	}
+
+	e = binary.Unmarshal(bytes[:rfc4271NotificationLengthInBytes],
+		&m.RFC4271NotificationMessageFormat,
+	)
+	if e != nil {
+		return
+	}
+
+	m.Data = bytes[rfc4271NotificationLengthInBytes:]
+
+	return
+}
+
+// RFC4271KeepaliveMessage has no body.
+// Reference: Section 4.4 "KEEPALIVE Message Format"
+type RFC4271KeepaliveMessage struct{}
+
+func (m *RFC4271KeepaliveMessage) RFC4271MessageType() uint8 {
+	return RFC4271MessageTypeKEEPALIVE
+}
+
+func (m *RFC4271KeepaliveMessage) MarshalRFC4271Body() (bytes []byte,
+	e error,
+) {
+	return
+}
+
+func (m *RFC4271KeepaliveMessage) UnmarshalRFC4271Body(bytes []byte) (
+	e error,
+) {
+	if len(bytes) != 0 {
+		e = ErrRFC4271MessageLengthInvalid
+	}
+
+	return
+}
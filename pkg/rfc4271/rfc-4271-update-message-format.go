@@ -0,0 +1,596 @@
+package rfc4271
+
+import (
+	"errors"
+	"net"
+
+	"github.com/encodingx/binary"
+)
+
+// Reference: Section 4.3 "UPDATE Message Format"
+
+// >    +-----------------------------------------------------+
+// >    |   Withdrawn Routes Length (2 octets)                |
+// >    +-----------------------------------------------------+
+// >    |   Withdrawn Routes (variable)                       |
+// >    +-----------------------------------------------------+
+// >    |   Total Path Attribute Length (2 octets)            |
+// >    +-----------------------------------------------------+
+// >    |   Path Attributes (variable)                        |
+// >    +-----------------------------------------------------+
+// >    |   Network Layer Reachability Information (variable) |
+// >    +-----------------------------------------------------+
+
+type RFC4271UpdateMessage struct {
+	WithdrawnRoutes []net.IPNet
+	PathAttributes  []RFC4271PathAttribute
+	NLRI            []net.IPNet
+}
+
+type RFC4271PathAttributeHeaderFormat struct {
+	// > Each path attribute is a triple <attribute type, attribute length,
+	// > attribute value> of variable length.
+	// >
+	// > Attribute Type is a two-octet field that consists of the
+	// > Attribute Flags octet, followed by the Attribute Type Code
+	// > octet.
+	// >
+	// >       0                   1
+	// >       0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5
+	// >       +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	// >       |  Attr. Flags  |Attr. Type Code|
+	// >       +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+
+	RFC4271PathAttributeHeaderFormatWord0 `word:"16"`
+}
+
+type RFC4271PathAttributeHeaderFormatWord0 struct {
+	Optional bool `bitfield:"1"`
+	// > The high-order bit (bit 0) of the Attribute Flags octet is the
+	// > Optional bit.  It defines whether the attribute is optional (if
+	// > set to 1) or well-known (if set to 0).
+
+	Transitive bool `bitfield:"1"`
+	// > The second high-order bit (bit 1) of the Attribute Flags octet
+	// > is the Transitive bit.  It defines whether an optional
+	// > attribute is transitive (if set to 1) or non-transitive (if set
+	// > to 0).
+
+	Partial bool `bitfield:"1"`
+	// > The third high-order bit (bit 2) of the Attribute Flags octet
+	// > is the Partial bit.  It defines whether the information
+	// > contained in the optional transitive attribute is partial (if
+	// > set to 1) or complete (if set to 0).
+
+	ExtendedLength bool `bitfield:"1"`
+	// > The fourth high-order bit (bit 3) of the Attribute Flags octet
+	// > is the Extended Length bit.  It defines whether the Attribute
+	// > Length is one octet (if set to 0) or two octets (if set to 1).
+
+	Unused uint8 `bitfield:"4"`
+	// > The lower-order four bits of the Attribute Flags octet are
+	// > unused.  They MUST be zero when sent and MUST be ignored when
+	// > received.
+
+	TypeCode uint8 `bitfield:"8"`
+}
+
+type RFC4271PathAttribute struct {
+	RFC4271PathAttributeHeaderFormat
+
+	// ExtendedLength is set when marshalling values longer than 255 bytes.
+	Value []byte
+}
+
+const (
+	RFC4271AttributeTypeORIGIN          = 1
+	RFC4271AttributeTypeASPATH          = 2
+	RFC4271AttributeTypeNEXTHOP         = 3
+	RFC4271AttributeTypeMULTIEXITDISC   = 4
+	RFC4271AttributeTypeLOCALPREF       = 5
+	RFC4271AttributeTypeATOMICAGGREGATE = 6
+	RFC4271AttributeTypeAGGREGATOR      = 7
+)
+
+const (
+	RFC4271OriginIGP        = 0
+	RFC4271OriginEGP        = 1
+	RFC4271OriginINCOMPLETE = 2
+
+	RFC4271ASSet      = 1
+	RFC4271ASSequence = 2
+)
+
+type RFC4271OriginFormat struct {
+	RFC4271OriginFormatWord0 `word:"8"`
+}
+
+type RFC4271OriginFormatWord0 struct {
+	Origin uint8 `bitfield:"8"`
+}
+
+type RFC4271NextHopFormat struct {
+	RFC4271NextHopFormatWord0 `word:"32"`
+}
+
+type RFC4271NextHopFormatWord0 struct {
+	NextHop uint32 `bitfield:"32"`
+}
+
+type RFC4271MultiExitDiscFormat struct {
+	RFC4271MultiExitDiscFormatWord0 `word:"32"`
+}
+
+type RFC4271MultiExitDiscFormatWord0 struct {
+	MultiExitDisc uint32 `bitfield:"32"`
+}
+
+type RFC4271LocalPrefFormat struct {
+	RFC4271LocalPrefFormatWord0 `word:"32"`
+}
+
+type RFC4271LocalPrefFormatWord0 struct {
+	LocalPref uint32 `bitfield:"32"`
+}
+
+type RFC4271AggregatorFormat struct {
+	// > AGGREGATOR is an optional transitive attribute of length 6.
+	// > The attribute contains the last AS number that formed the
+	// > aggregate route (encoded as 2 octets), followed by the IP
+	// > address of the BGP speaker that formed the aggregate route
+	// > (encoded as 4 octets).
+
+	RFC4271AggregatorFormatWord0 `word:"16"`
+	RFC4271AggregatorFormatWord1 `word:"32"`
+}
+
+type RFC4271AggregatorFormatWord0 struct {
+	AutonomousSystem uint16 `bitfield:"16"`
+}
+
+type RFC4271AggregatorFormatWord1 struct {
+	Address uint32 `bitfield:"32"`
+}
+
+type RFC4271ASPathSegmentHeaderFormat struct {
+	// > Each AS path segment is represented by a triple <path segment
+	// > type, path segment length, path segment value>.
+	// >
+	// > The path segment length is a 1-octet length field,
+	// > containing the number of ASes (not the number of octets) in
+	// > the path segment value field.
+
+	RFC4271ASPathSegmentHeaderFormatWord0 `word:"16"`
+}
+
+type RFC4271ASPathSegmentHeaderFormatWord0 struct {
+	Type   uint8 `bitfield:"8"`
+	Length uint8 `bitfield:"8"`
+}
+
+type RFC4271ASPathSegment struct {
+	Type uint8
+
+	// > The path segment value field contains one or more AS
+	// > numbers, each encoded as a 2-octet length field.
+	AutonomousSystems []uint16
+}
+
+const (
+	rfc4271LengthFieldLengthInBytes         = 2
+	rfc4271PathAttributeHeaderLengthInBytes = 2
+	rfc4271ASPathSegmentHeaderLengthInBytes = 2
+)
+
+var (
+	ErrRFC4271PathAttributeTruncated = errors.New(
+		"A BGP path attribute should be as long as its header " +
+			"and the length it declares. " +
+			"The bytes end before the attribute.",
+	)
+
+	ErrRFC4271PrefixInvalid = errors.New(
+		"An NLRI prefix should be an IPv4 prefix of length " +
+			"no greater than 32, followed by enough octets to hold it. " +
+			"The prefix is invalid or truncated.",
+	)
+)
+
+func (m *RFC4271UpdateMessage) RFC4271MessageType() uint8 {
+	return RFC4271MessageTypeUPDATE
+}
+
+func (m *RFC4271UpdateMessage) MarshalRFC4271Body() (bytes []byte, e error) {
+	var (
+		attributes []byte
+		withdrawn  []byte
+	)
+
+	withdrawn, e = rfc4271AppendPrefixes(nil, m.WithdrawnRoutes)
+	if e != nil {
+		return
+	}
+
+	attributes, e = rfc4271AppendPathAttributes(nil, m.PathAttributes)
+	if e != nil {
+		return
+	}
+
+	bytes = rfc4271AppendUint16(bytes, uint16(len(withdrawn)))
+	bytes = append(bytes, withdrawn...)
+	bytes = rfc4271AppendUint16(bytes, uint16(len(attributes)))
+	bytes = append(bytes, attributes...)
+
+	bytes, e = rfc4271AppendPrefixes(bytes, m.NLRI)
+	if e != nil {
+		return
+	}
+
+	return
+}
+
+func (m *RFC4271UpdateMessage) UnmarshalRFC4271Body(bytes []byte) (e error) {
+	// > The minimum length of the UPDATE message is 23 octets -- 19 octets
+	// > for the fixed header + 2 octets for the Withdrawn Routes Length + 2
+	// > octets for the Total Path Attribute Length (the value of Withdrawn
+	// > Routes Length is 0 and the value of Total Path Attribute Length is
+	// > 0).
+
+	var (
+		length int
+	)
+
+	length, bytes, e = rfc4271ReadLengthField(bytes)
+	if e != nil {
+		return
+	}
+
+	m.WithdrawnRoutes, e = ParseRFC4271Prefixes(bytes[:length])
+	if e != nil {
+		return
+	}
+
+	length, bytes, e = rfc4271ReadLengthField(bytes[length:])
+	if e != nil {
+		return
+	}
+
+	m.PathAttributes, e = ParseRFC4271PathAttributes(bytes[:length])
+	if e != nil {
+		return
+	}
+
+	m.NLRI, e = ParseRFC4271Prefixes(bytes[length:])
+	if e != nil {
+		return
+	}
+
+	return
+}
+
+// PathAttribute returns the first path attribute with a type code.
+func (m *RFC4271UpdateMessage) PathAttribute(typeCode uint8) (
+	attribute RFC4271PathAttribute, ok bool,
+) {
+	for _, attribute = range m.PathAttributes {
+		if attribute.TypeCode == typeCode {
+			ok = true
+
+			return
+		}
+	}
+
+	attribute = RFC4271PathAttribute{}
+
+	return
+}
+
+func ParseRFC4271PathAttributes(bytes []byte) (
+	attributes []RFC4271PathAttribute, e error,
+) {
+	var (
+		attribute RFC4271PathAttribute
+		length    int
+		n         int
+	)
+
+	for len(bytes) > 0 {
+		if len(bytes) < rfc4271PathAttributeHeaderLengthInBytes+1 {
+			e = ErrRFC4271PathAttributeTruncated
+
+			return
+		}
+
+		e = binary.Unmarshal(bytes[:rfc4271PathAttributeHeaderLengthInBytes],
+			&attribute.RFC4271PathAttributeHeaderFormat,
+		)
+		if e != nil {
+			return
+		}
+
+		bytes = bytes[rfc4271PathAttributeHeaderLengthInBytes:]
+
+		if attribute.ExtendedLength {
+			length, bytes, e = rfc4271ReadLengthField(bytes)
+			if e != nil {
+				e = ErrRFC4271PathAttributeTruncated
+
+				return
+			}
+
+			n = length
+
+		} else {
+			n = int(bytes[0])
+
+			bytes = bytes[1:]
+		}
+
+		if len(bytes) < n {
+			e = ErrRFC4271PathAttributeTruncated
+
+			return
+		}
+
+		attribute.Value = bytes[:n]
+
+		attributes = append(attributes, attribute)
+
+		bytes = bytes[n:]
+	}
+
+	return
+}
+
+func rfc4271AppendPathAttributes(bytes []byte,
+	attributes []RFC4271PathAttribute,
+) (
+	result []byte, e error,
+) {
+	var (
+		attribute RFC4271PathAttribute
+		header    []byte
+	)
+
+	result = bytes
+
+	for _, attribute = range attributes {
+		if len(attribute.Value) > 0xffff {
+			e = ErrRFC4271MessageLengthInvalid
+
+			return
+		}
+
+		if len(attribute.Value) > 0xff {
+			attribute.ExtendedLength = true
+		}
+
+		header, e = binary.Marshal(&attribute.RFC4271PathAttributeHeaderFormat)
+		if e != nil {
+			return
+		}
+
+		result = append(result, header...)
+
+		if attribute.ExtendedLength {
+			result = rfc4271AppendUint16(result, uint16(len(attribute.Value)))
+
+		} else {
+			result = append(result, byte(len(attribute.Value)))
+		}
+
+		result = append(result, attribute.Value...)
+	}
+
+	return
+}
+
+// ParseRFC4271Prefixes decodes IPv4 address prefixes
+// encoded as in the Withdrawn Routes and NLRI fields.
+func ParseRFC4271Prefixes(bytes []byte) (prefixes []net.IPNet, e error) {
+	// > Each IP address prefix is encoded as a 2-tuple of the form
+	// > <length, prefix>, whose fields are described below:
+	// >
+	// >          +---------------------------+
+	// >          |   Length (1 octet)        |
+	// >          +---------------------------+
+	// >          |   Prefix (variable)       |
+	// >          +---------------------------+
+	// >
+	// > b) Prefix:
+	// >
+	// >    The Prefix field contains an IP address prefix, followed by
+	// >    enough trailing bits to make the end of the field fall on an
+	// >    octet boundary.  Note that the value of trailing bits is
+	// >    irrelevant.
+
+	var (
+		length int
+		n      int
+		prefix net.IPNet
+	)
+
+	for len(bytes) > 0 {
+		length = int(bytes[0])
+		n = (length + 7) / 8
+
+		if length > 8*net.IPv4len || len(bytes) < 1+n {
+			e = ErrRFC4271PrefixInvalid
+
+			return
+		}
+
+		prefix.Mask = net.CIDRMask(length, 8*net.IPv4len)
+		prefix.IP = make(net.IP, net.IPv4len)
+
+		copy(prefix.IP, bytes[1:1+n])
+
+		prefix.IP = prefix.IP.Mask(prefix.Mask)
+
+		prefixes = append(prefixes, prefix)
+
+		bytes = bytes[1+n:]
+	}
+
+	return
+}
+
+// AppendRFC4271Prefix appends an IPv4 address prefix
+// encoded as in the Withdrawn Routes and NLRI fields.
+func AppendRFC4271Prefix(bytes []byte, prefix net.IPNet) (result []byte,
+	e error,
+) {
+	var (
+		bits   int
+		ip     net.IP
+		length int
+	)
+
+	ip = prefix.IP.To4()
+	length, bits = prefix.Mask.Size()
+
+	if ip == nil || bits != 8*net.IPv4len {
+		e = ErrRFC4271PrefixInvalid
+
+		return
+	}
+
+	ip = ip.Mask(prefix.Mask)
+
+	result = append(bytes, byte(length))
+	result = append(result, ip[:(length+7)/8]...)
+
+	return
+}
+
+func rfc4271AppendPrefixes(bytes []byte, prefixes []net.IPNet) (
+	result []byte, e error,
+) {
+	var (
+		prefix net.IPNet
+	)
+
+	result = bytes
+
+	for _, prefix = range prefixes {
+		result, e = AppendRFC4271Prefix(result, prefix)
+		if e != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// ParseRFC4271ASPath decodes the value of an AS_PATH attribute.
+func ParseRFC4271ASPath(bytes []byte) (segments []RFC4271ASPathSegment,
+	e error,
+) {
+	var (
+		header  RFC4271ASPathSegmentHeaderFormat
+		i       int
+		n       int
+		segment RFC4271ASPathSegment
+	)
+
+	for len(bytes) > 0 {
+		if len(bytes) < rfc4271ASPathSegmentHeaderLengthInBytes {
+			e = ErrRFC4271PathAttributeTruncated
+
+			return
+		}
+
+		e = binary.Unmarshal(bytes[:rfc4271ASPathSegmentHeaderLengthInBytes],
+			&header,
+		)
+		if e != nil {
+			return
+		}
+
+		bytes = bytes[rfc4271ASPathSegmentHeaderLengthInBytes:]
+
+		n = 2 * int(header.Length)
+
+		if len(bytes) < n {
+			e = ErrRFC4271PathAttributeTruncated
+
+			return
+		}
+
+		segment = RFC4271ASPathSegment{
+			Type:              header.Type,
+			AutonomousSystems: make([]uint16, header.Length),
+		}
+
+		for i = range segment.AutonomousSystems {
+			segment.AutonomousSystems[i] = binary.BigEndian.Uint16(bytes[2*i:])
+		}
+
+		segments = append(segments, segment)
+
+		bytes = bytes[n:]
+	}
+
+	return
+}
+
+// MarshalRFC4271ASPath encodes the value of an AS_PATH attribute.
+func MarshalRFC4271ASPath(segments []RFC4271ASPathSegment) (bytes []byte,
+	e error,
+) {
+	var (
+		as      uint16
+		header  RFC4271ASPathSegmentHeaderFormat
+		segment RFC4271ASPathSegment
+		word    []byte
+	)
+
+	for _, segment = range segments {
+		if len(segment.AutonomousSystems) > 0xff {
+			e = ErrRFC4271MessageLengthInvalid
+
+			return
+		}
+
+		header.Type = segment.Type
+		header.Length = uint8(len(segment.AutonomousSystems))
+
+		word, e = binary.Marshal(&header)
+		if e != nil {
+			return
+		}
+
+		bytes = append(bytes, word...)
+
+		for _, as = range segment.AutonomousSystems {
+			bytes = rfc4271AppendUint16(bytes, as)
+		}
+	}
+
+	return
+}
+
+func rfc4271ReadLengthField(bytes []byte) (length int, rest []byte,
+	e error,
+) {
+	if len(bytes) < rfc4271LengthFieldLengthInBytes {
+		e = ErrRFC4271MessageLengthInvalid
+
+		return
+	}
+
+	length = int(binary.BigEndian.Uint16(bytes))
+	rest = bytes[rfc4271LengthFieldLengthInBytes:]
+
+	if len(rest) < length {
+		e = ErrRFC4271MessageLengthInvalid
+
+		return
+	}
+
+	return
+}
+
+func rfc4271AppendUint16(bytes []byte, value uint16) []byte {
+	return append(bytes, byte(value>>8), byte(value))
+}
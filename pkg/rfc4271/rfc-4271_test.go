@@ -0,0 +1,308 @@
+package rfc4271
+
+import (
+	"net"
+	"testing"
+
+	"github.com/encodingx/binary"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	rfc4271TestMarker = []byte{
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	}
+)
+
+func rfc4271TestMessage(length uint16, messageType uint8, body ...byte) (
+	bytes []byte,
+) {
+	bytes = append(bytes, rfc4271TestMarker...)
+	bytes = append(bytes, byte(length>>8), byte(length), messageType)
+	bytes = append(bytes, body...)
+
+	return
+}
+
+func TestRFC4271Open(t *testing.T) {
+	var (
+		bytes   []byte
+		e       error
+		encoded []byte
+		message RFC4271Message
+		n       int
+		open    *RFC4271OpenMessage
+	)
+
+	bytes = rfc4271TestMessage(0x21, RFC4271MessageTypeOPEN,
+		0x04, 0xfd, 0xe8, 0x00, 0xb4, 0xc0, 0xa8, 0x00, 0x01,
+		0x04, 0x02, 0x02, 0x02, 0x00,
+	)
+
+	message, n, e = ParseRFC4271Message(bytes)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		len(bytes), n,
+	)
+
+	open = message.(*RFC4271OpenMessage)
+
+	assert.Equal(t,
+		uint16(65000), open.MyAutonomousSystem,
+	)
+
+	assert.Equal(t,
+		uint16(180), open.HoldTime,
+	)
+
+	assert.Equal(t,
+		uint32(0xc0a80001), open.BGPIdentifier,
+	)
+
+	assert.Equal(t,
+		uint64(RFC4271OptionalParameterCapabilities),
+		open.OptionalParameters[0].Type,
+	)
+
+	encoded, e = MarshalRFC4271Message(open)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		bytes, encoded,
+	)
+}
+
+func TestRFC4271Update(t *testing.T) {
+	var (
+		bytes     []byte
+		e         error
+		encoded   []byte
+		message   RFC4271Message
+		nextHop   RFC4271NextHopFormat
+		attribute RFC4271PathAttribute
+		ok        bool
+		segments  []RFC4271ASPathSegment
+		update    *RFC4271UpdateMessage
+	)
+
+	bytes = rfc4271TestMessage(0x31, RFC4271MessageTypeUPDATE,
+		// Withdrawn Routes: 172.16.0.0/12
+		0x00, 0x03, 0x0c, 0xac, 0x10,
+
+		// Path Attributes: ORIGIN IGP, AS_PATH 65000, NEXT_HOP 192.168.0.1
+		0x00, 0x12,
+		0x40, 0x01, 0x01, 0x00,
+		0x40, 0x02, 0x04, 0x02, 0x01, 0xfd, 0xe8,
+		0x40, 0x03, 0x04, 0xc0, 0xa8, 0x00, 0x01,
+
+		// NLRI: 10.1.2.0/24, 0.0.0.0/0
+		0x18, 0x0a, 0x01, 0x02, 0x00,
+	)
+
+	message, _, e = ParseRFC4271Message(bytes)
+
+	assert.Nil(t, e)
+
+	update = message.(*RFC4271UpdateMessage)
+
+	assert.Equal(t,
+		[]net.IPNet{
+			{
+				IP:   net.IP{172, 16, 0, 0},
+				Mask: net.CIDRMask(12, 32),
+			},
+		},
+		update.WithdrawnRoutes,
+	)
+
+	assert.Equal(t,
+		[]net.IPNet{
+			{
+				IP:   net.IP{10, 1, 2, 0},
+				Mask: net.CIDRMask(24, 32),
+			},
+			{
+				IP:   net.IP{0, 0, 0, 0},
+				Mask: net.CIDRMask(0, 32),
+			},
+		},
+		update.NLRI,
+	)
+
+	assert.Len(t, update.PathAttributes, 3)
+
+	attribute, ok = update.PathAttribute(RFC4271AttributeTypeASPATH)
+
+	assert.True(t, ok)
+	assert.False(t, attribute.Optional)
+	assert.True(t, attribute.Transitive)
+
+	segments, e = ParseRFC4271ASPath(attribute.Value)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]RFC4271ASPathSegment{
+			{
+				Type:              RFC4271ASSequence,
+				AutonomousSystems: []uint16{65000},
+			},
+		},
+		segments,
+	)
+
+	attribute.Value, e = MarshalRFC4271ASPath(segments)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]byte{0x02, 0x01, 0xfd, 0xe8}, attribute.Value,
+	)
+
+	attribute, _ = update.PathAttribute(RFC4271AttributeTypeNEXTHOP)
+
+	assert.Nil(t,
+		binary.Unmarshal(attribute.Value, &nextHop),
+	)
+
+	assert.Equal(t,
+		uint32(0xc0a80001), nextHop.NextHop,
+	)
+
+	encoded, e = MarshalRFC4271Message(update)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		bytes, encoded,
+	)
+}
+
+func TestRFC4271PathAttributeExtendedLength(t *testing.T) {
+	var (
+		attributes []RFC4271PathAttribute
+		bytes      []byte
+		e          error
+	)
+
+	attributes = []RFC4271PathAttribute{
+		{
+			Value: make([]byte, 0x100),
+		},
+	}
+
+	attributes[0].Optional = true
+	attributes[0].Transitive = true
+	attributes[0].TypeCode = RFC4271AttributeTypeAGGREGATOR
+
+	bytes, e = rfc4271AppendPathAttributes(nil, attributes)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]byte{0xd0, 0x07, 0x01, 0x00}, bytes[:4],
+	)
+
+	attributes, e = ParseRFC4271PathAttributes(bytes)
+
+	assert.Nil(t, e)
+
+	assert.True(t, attributes[0].ExtendedLength)
+
+	assert.Len(t, attributes[0].Value, 0x100)
+
+	_, e = ParseRFC4271PathAttributes(bytes[:0x100])
+
+	assert.Equal(t,
+		ErrRFC4271PathAttributeTruncated, e,
+	)
+}
+
+func TestRFC4271NotificationKeepaliveAndErrors(t *testing.T) {
+	var (
+		bytes        []byte
+		e            error
+		message      RFC4271Message
+		notification *RFC4271NotificationMessage
+	)
+
+	bytes = rfc4271TestMessage(0x13, RFC4271MessageTypeKEEPALIVE)
+
+	message, _, e = ParseRFC4271Message(bytes)
+
+	assert.Nil(t, e)
+
+	assert.IsType(t,
+		&RFC4271KeepaliveMessage{}, message,
+	)
+
+	bytes = rfc4271TestMessage(0x15, RFC4271MessageTypeNOTIFICATION,
+		RFC4271ErrorCodeCease, 0x02,
+	)
+
+	message, _, e = ParseRFC4271Message(bytes)
+
+	assert.Nil(t, e)
+
+	notification = message.(*RFC4271NotificationMessage)
+
+	assert.Equal(t,
+		uint8(RFC4271ErrorCodeCease), notification.ErrorCode,
+	)
+
+	assert.Empty(t, notification.Data)
+
+	_, _, e = ParseRFC4271Message(
+		rfc4271TestMessage(0x14, RFC4271MessageTypeKEEPALIVE, 0x00),
+	)
+
+	assert.Equal(t,
+		ErrRFC4271MessageLengthInvalid, e,
+	)
+
+	_, _, e = ParseRFC4271Message(
+		rfc4271TestMessage(0x18, RFC4271MessageTypeUPDATE, 0x00, 0x00),
+	)
+
+	assert.Equal(t,
+		ErrRFC4271MessageTruncated, e,
+	)
+
+	_, _, e = ParseRFC4271Message(
+		rfc4271TestMessage(0x13, 0x05),
+	)
+
+	assert.Equal(t,
+		ErrRFC4271MessageTypeUnknown, e,
+	)
+
+	bytes = rfc4271TestMessage(0x13, RFC4271MessageTypeKEEPALIVE)
+	bytes[0] = 0x00
+
+	_, _, e = ParseRFC4271Message(bytes)
+
+	assert.Equal(t,
+		ErrRFC4271MarkerInvalid, e,
+	)
+
+	_, e = AppendRFC4271Prefix(nil,
+		net.IPNet{
+			IP:   net.ParseIP("2001:db8::"),
+			Mask: net.CIDRMask(32, 128),
+		},
+	)
+
+	assert.Equal(t,
+		ErrRFC4271PrefixInvalid, e,
+	)
+
+	_, e = ParseRFC4271Prefixes([]byte{0x21, 0x0a, 0x00, 0x00, 0x00, 0x00})
+
+	assert.Equal(t,
+		ErrRFC4271PrefixInvalid, e,
+	)
+}
@@ -0,0 +1,570 @@
+package rfc6455
+
+import (
+	"errors"
+	"unicode/utf8"
+
+	"github.com/encodingx/binary"
+)
+
+type RFC6455FrameHeaderFormat struct {
+	// Reference: Section 5.2 "Base Framing Protocol" of
+	// RFC 6455 The WebSocket Protocol
+	// https://datatracker.ietf.org/doc/html/rfc6455#section-5.2
+
+	// >  0                   1                   2                   3
+	// >  0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+	// > +-+-+-+-+-------+-+-------------+-------------------------------+
+	// > |F|R|R|R| opcode|M| Payload len |    Extended payload length    |
+	// > |I|S|S|S|  (4)  |A|     (7)     |             (16/64)           |
+	// > |N|V|V|V|       |S|             |   (if payload len==126/127)   |
+	// > | |1|2|3|       |K|             |                               |
+	// > +-+-+-+-+-------+-+-------------+ - - - - - - - - - - - - - - - +
+	// > |     Extended payload length continued, if payload len == 127  |
+	// > + - - - - - - - - - - - - - - - +-------------------------------+
+	// > |                               |Masking-key, if MASK set to 1  |
+	// > +-------------------------------+-------------------------------+
+	// > | Masking-key (continued)       |          Payload Data         |
+	// > +-------------------------------- - - - - - - - - - - - - - - - +
+	// > :                     Payload Data continued ...                :
+	// > + - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - +
+	// > |                     Payload Data continued ...                |
+	// > +---------------------------------------------------------------+
+	//
+	// The extended payload length and masking key are the format-structs
+	// RFC6455ExtendedPayloadLength16Format,
+	// RFC6455ExtendedPayloadLength64Format and RFC6455MaskingKeyFormat.
+
+	RFC6455FrameHeaderFormatWord0 `word:"16"`
+}
+
+type RFC6455FrameHeaderFormatWord0 struct {
+	FIN bool `bitfield:"1"`
+	// > FIN:  1 bit
+	// >
+	// >    Indicates that this is the final fragment in a message.  The
+	// >    first fragment MAY also be the final fragment.
+
+	RSV1 bool `bitfield:"1"`
+	RSV2 bool `bitfield:"1"`
+	RSV3 bool `bitfield:"1"`
+	// > RSV1, RSV2, RSV3:  1 bit each
+	// >
+	// >    MUST be 0 unless an extension is negotiated that defines meanings
+	// >    for non-zero values.  If a nonzero value is received and none of
+	// >    the negotiated extensions defines the meaning of such a nonzero
+	// >    value, the receiving endpoint MUST _Fail the WebSocket
+	// >    Connection_.
+
+	Opcode uint8 `bitfield:"4"`
+	// > Opcode:  4 bits
+	// >
+	// >    Defines the interpretation of the "Payload data".  If an unknown
+	// >    opcode is received, the receiving endpoint MUST _Fail the
+	// >    WebSocket Connection_.
+
+	MASK bool `bitfield:"1"`
+	// > Mask:  1 bit
+	// >
+	// >    Defines whether the "Payload data" is masked.  If set to 1, a
+	// >    masking key is present in masking-key, and this is used to unmask
+	// >    the "Payload data" as per Section 5.3.  All frames sent from
+	// >    client to server have this bit set to 1.
+
+	PayloadLen uint8 `bitfield:"7"`
+	// > Payload length:  7 bits, 7+16 bits, or 7+64 bits
+	// >
+	// >    The length of the "Payload data", in bytes: if 0-125, that is the
+	// >    payload length.  If 126, the following 2 bytes interpreted as a
+	// >    16-bit unsigned integer are the payload length.  If 127, the
+	// >    following 8 bytes interpreted as a 64-bit unsigned integer (the
+	// >    most significant bit MUST be 0) are the payload length.
+	// >    Multibyte length quantities are expressed in network byte order.
+	// >    Note that in all cases, the minimal number of bytes MUST be used
+	// >    to encode the length.
+}
+
+type RFC6455ExtendedPayloadLength16Format struct {
+	RFC6455ExtendedPayloadLength16FormatWord0 `word:"16"`
+}
+
+type RFC6455ExtendedPayloadLength16FormatWord0 struct {
+	ExtendedPayloadLength uint16 `bitfield:"16"`
+}
+
+type RFC6455ExtendedPayloadLength64Format struct {
+	RFC6455ExtendedPayloadLength64FormatWord0 `word:"64"`
+}
+
+type RFC6455ExtendedPayloadLength64FormatWord0 struct {
+	MostSignificantBit    bool   `bitfield:"1"`
+	ExtendedPayloadLength uint64 `bitfield:"63"`
+}
+
+type RFC6455MaskingKeyFormat struct {
+	RFC6455MaskingKeyFormatWord0 `word:"32"`
+}
+
+type RFC6455MaskingKeyFormatWord0 struct {
+	MaskingKey uint32 `bitfield:"32"`
+	// > Masking-key:  0 or 4 bytes
+	// >
+	// >    All frames sent from the client to the server are masked by a
+	// >    32-bit value that is contained within the frame.  This field is
+	// >    present if the mask bit is set to 1 and is absent if the mask bit
+	// >    is set to 0.
+}
+
+const (
+	RFC6455OpcodeContinuation = 0x0
+	RFC6455OpcodeText         = 0x1
+	RFC6455OpcodeBinary       = 0x2
+	RFC6455OpcodeClose        = 0x8
+	RFC6455OpcodePing         = 0x9
+	RFC6455OpcodePong         = 0xa
+)
+
+const (
+	RFC6455FrameHeaderLengthInBytes = 2
+	RFC6455MaskingKeyLengthInBytes  = 4
+
+	RFC6455PayloadLen16                 = 126
+	RFC6455PayloadLen64                 = 127
+	RFC6455ControlFrameMaxPayloadLength = 125
+)
+
+const (
+	RFC6455CloseCodeNormalClosure           = 1000
+	RFC6455CloseCodeGoingAway               = 1001
+	RFC6455CloseCodeProtocolError           = 1002
+	RFC6455CloseCodeUnsupportedData         = 1003
+	RFC6455CloseCodeReserved                = 1004
+	RFC6455CloseCodeNoStatusReceived        = 1005
+	RFC6455CloseCodeAbnormalClosure         = 1006
+	RFC6455CloseCodeInvalidFramePayloadData = 1007
+	RFC6455CloseCodePolicyViolation         = 1008
+	RFC6455CloseCodeMessageTooBig           = 1009
+	RFC6455CloseCodeMandatoryExtension      = 1010
+	RFC6455CloseCodeInternalServerError     = 1011
+	RFC6455CloseCodeTLSHandshake            = 1015
+)
+
+var (
+	ErrRFC6455ClosePayloadInvalid = errors.New(
+		"The payload of a WebSocket Close frame should be empty, " +
+			"or a valid status code followed by a UTF-8 reason. " +
+			"The Close frame payload is invalid.",
+	)
+
+	ErrRFC6455ControlFrameFragmented = errors.New(
+		"WebSocket control frames MUST NOT be fragmented. " +
+			"The control frame does not have its FIN bit set.",
+	)
+
+	ErrRFC6455ControlFramePayloadTooLong = errors.New(
+		"WebSocket control frames MUST have a payload length " +
+			"of 125 bytes or less. " +
+			"The control frame payload is longer than 125 bytes.",
+	)
+
+	ErrRFC6455FrameTruncated = errors.New(
+		"A WebSocket frame should be as long as its header indicates. " +
+			"The bytes end before the frame does.",
+	)
+
+	ErrRFC6455OpcodeReserved = errors.New(
+		"The opcode of a WebSocket frame should be one defined " +
+			"by RFC 6455. The opcode is reserved.",
+	)
+
+	ErrRFC6455PayloadLengthNotMinimal = errors.New(
+		"The payload length of a WebSocket frame MUST be encoded " +
+			"in the minimal number of bytes. " +
+			"The payload length is encoded in more bytes than needed.",
+	)
+
+	ErrRFC6455PayloadLengthTooLarge = errors.New(
+		"The most significant bit of a 64-bit WebSocket payload length " +
+			"MUST be 0, and the length should not exceed the limit set. " +
+			"The payload length is too large.",
+	)
+
+	ErrRFC6455ReservedBitsSet = errors.New(
+		"The RSV1, RSV2 and RSV3 bits of a WebSocket frame MUST be 0 " +
+			"unless an extension defines them. " +
+			"A reserved bit of the frame is set.",
+	)
+)
+
+type RFC6455Frame struct {
+	RFC6455FrameHeaderFormat
+	RFC6455MaskingKeyFormat
+
+	// Payload is unmasked, regardless of the MASK bit.
+	Payload []byte
+}
+
+func (f *RFC6455Frame) IsControl() bool {
+	return f.Opcode&0x8 != 0
+}
+
+// Validate checks the opcode and reserved bits of a frame,
+// and the rules for control frames.
+// Reserved bits are permitted where set in allowedRSV,
+// whose bits 2, 1 and 0 stand for RSV1, RSV2 and RSV3.
+func (f *RFC6455Frame) Validate(allowedRSV uint8) (e error) {
+	var (
+		rsv uint8
+	)
+
+	switch f.Opcode {
+	case RFC6455OpcodeContinuation,
+		RFC6455OpcodeText,
+		RFC6455OpcodeBinary,
+		RFC6455OpcodeClose,
+		RFC6455OpcodePing,
+		RFC6455OpcodePong:
+
+	default:
+		e = ErrRFC6455OpcodeReserved
+
+		return
+	}
+
+	if f.RSV1 {
+		rsv |= 0b100
+	}
+
+	if f.RSV2 {
+		rsv |= 0b010
+	}
+
+	if f.RSV3 {
+		rsv |= 0b001
+	}
+
+	if rsv&^allowedRSV != 0 {
+		e = ErrRFC6455ReservedBitsSet
+
+		return
+	}
+
+	if !f.IsControl() {
+		return
+	}
+
+	// > All control frames MUST have a payload length of 125 bytes or less
+	// > and MUST NOT be fragmented.
+
+	if len(f.Payload) > RFC6455ControlFrameMaxPayloadLength {
+		e = ErrRFC6455ControlFramePayloadTooLong
+
+		return
+	}
+
+	if !f.FIN {
+		e = ErrRFC6455ControlFrameFragmented
+
+		return
+	}
+
+	if f.Opcode == RFC6455OpcodeClose {
+		_, _, e = ParseRFC6455ClosePayload(f.Payload)
+		if e != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// MarshalBinary encodes the frame with the minimal payload length encoding,
+// masking the payload with the masking key if MASK is set.
+// The Payload of the frame is left unmasked.
+func (f *RFC6455Frame) MarshalBinary() (bytes []byte, e error) {
+	var (
+		length    uint64
+		length16  RFC6455ExtendedPayloadLength16Format
+		length64  RFC6455ExtendedPayloadLength64Format
+		offset    int
+		wordBytes []byte
+	)
+
+	length = uint64(len(f.Payload))
+
+	switch {
+	case length < RFC6455PayloadLen16:
+		f.PayloadLen = uint8(length)
+
+	case length <= 1<<16-1:
+		f.PayloadLen = RFC6455PayloadLen16
+
+		length16.ExtendedPayloadLength = uint16(length)
+
+	default:
+		f.PayloadLen = RFC6455PayloadLen64
+
+		length64.ExtendedPayloadLength = length
+	}
+
+	bytes, e = binary.Marshal(&f.RFC6455FrameHeaderFormat)
+	if e != nil {
+		return
+	}
+
+	switch f.PayloadLen {
+	case RFC6455PayloadLen16:
+		wordBytes, e = binary.Marshal(&length16)
+
+	case RFC6455PayloadLen64:
+		wordBytes, e = binary.Marshal(&length64)
+
+	default:
+		wordBytes = nil
+	}
+
+	if e != nil {
+		return
+	}
+
+	bytes = append(bytes, wordBytes...)
+
+	if f.MASK {
+		wordBytes, e = binary.Marshal(&f.RFC6455MaskingKeyFormat)
+		if e != nil {
+			return
+		}
+
+		bytes = append(bytes, wordBytes...)
+	}
+
+	offset = len(bytes)
+
+	bytes = append(bytes, f.Payload...)
+
+	if f.MASK {
+		RFC6455Mask(f.MaskingKey, bytes[offset:])
+	}
+
+	return
+}
+
+// ParseRFC6455Frame decodes the frame at the start of bytes,
+// returning the number of bytes it occupies.
+// The payload is copied and unmasked.
+// The frame is not validated; see Validate.
+func ParseRFC6455Frame(bytes []byte) (frame RFC6455Frame, n int, e error) {
+	var (
+		i      int
+		length uint64
+	)
+
+	length, i, e = frame.unmarshalHeader(bytes)
+	if e != nil {
+		return
+	}
+
+	if length > uint64(len(bytes)-i) {
+		e = ErrRFC6455FrameTruncated
+
+		return
+	}
+
+	n = i + int(length)
+
+	frame.Payload = append([]byte(nil), bytes[i:n]...)
+
+	if frame.MASK {
+		RFC6455Mask(frame.MaskingKey, frame.Payload)
+	}
+
+	return
+}
+
+// unmarshalHeader decodes the header, extended payload length and
+// masking key at the start of bytes,
+// returning the payload length and the offset of the payload.
+func (f *RFC6455Frame) unmarshalHeader(bytes []byte) (
+	length uint64, i int, e error,
+) {
+	var (
+		length16 RFC6455ExtendedPayloadLength16Format
+		length64 RFC6455ExtendedPayloadLength64Format
+	)
+
+	if len(bytes) < RFC6455FrameHeaderLengthInBytes {
+		e = ErrRFC6455FrameTruncated
+
+		return
+	}
+
+	e = binary.Unmarshal(bytes[:RFC6455FrameHeaderLengthInBytes],
+		&f.RFC6455FrameHeaderFormat,
+	)
+	if e != nil {
+		return
+	}
+
+	i = RFC6455FrameHeaderLengthInBytes
+
+	if len(bytes) < i+f.extensionLengthInBytes() {
+		e = ErrRFC6455FrameTruncated
+
+		return
+	}
+
+	switch f.PayloadLen {
+	case RFC6455PayloadLen16:
+		e = binary.Unmarshal(bytes[i:i+2], &length16)
+		if e != nil {
+			return
+		}
+
+		i += 2
+
+		length = uint64(length16.ExtendedPayloadLength)
+
+		if length < RFC6455PayloadLen16 {
+			e = ErrRFC6455PayloadLengthNotMinimal
+
+			return
+		}
+
+	case RFC6455PayloadLen64:
+		e = binary.Unmarshal(bytes[i:i+8], &length64)
+		if e != nil {
+			return
+		}
+
+		i += 8
+
+		if length64.MostSignificantBit {
+			e = ErrRFC6455PayloadLengthTooLarge
+
+			return
+		}
+
+		length = length64.ExtendedPayloadLength
+
+		if length <= 1<<16-1 {
+			e = ErrRFC6455PayloadLengthNotMinimal
+
+			return
+		}
+
+	default:
+		length = uint64(f.PayloadLen)
+	}
+
+	f.RFC6455MaskingKeyFormat = RFC6455MaskingKeyFormat{}
+
+	if f.MASK {
+		e = binary.Unmarshal(bytes[i:i+RFC6455MaskingKeyLengthInBytes],
+			&f.RFC6455MaskingKeyFormat,
+		)
+		if e != nil {
+			return
+		}
+
+		i += RFC6455MaskingKeyLengthInBytes
+	}
+
+	return
+}
+
+// extensionLengthInBytes returns the length of the extended payload length
+// and masking key that follow the first two bytes of the header.
+func (f *RFC6455Frame) extensionLengthInBytes() (length int) {
+	switch f.PayloadLen {
+	case RFC6455PayloadLen16:
+		length = 2
+
+	case RFC6455PayloadLen64:
+		length = 8
+	}
+
+	if f.MASK {
+		length += RFC6455MaskingKeyLengthInBytes
+	}
+
+	return
+}
+
+// RFC6455Mask masks or unmasks payload in place.
+// Reference: Section 5.3 "Client-to-Server Masking"
+//
+// > Octet i of the transformed data ("transformed-octet-i") is the XOR of
+// > octet i of the original data ("original-octet-i") with octet at index
+// > i modulo 4 of the masking key ("masking-key-octet-j"):
+// >
+// >   j                   = i MOD 4
+// >   transformed-octet-i = original-octet-i XOR masking-key-octet-j
+func RFC6455Mask(maskingKey uint32, payload []byte) {
+	var (
+		i   int
+		key [RFC6455MaskingKeyLengthInBytes]byte
+	)
+
+	binary.BigEndian.PutUint32(key[:], maskingKey)
+
+	for i = range payload {
+		payload[i] ^= key[i%RFC6455MaskingKeyLengthInBytes]
+	}
+
+	return
+}
+
+// ParseRFC6455ClosePayload decodes the status code and reason
+// from the payload of a Close frame.
+// An empty payload has the code 1005, which is never sent.
+// Reference: Section 5.5.1 "Close" and Section 7.4 "Status Codes"
+func ParseRFC6455ClosePayload(payload []byte) (
+	code uint16, reason string, e error,
+) {
+	if len(payload) == 0 {
+		code = RFC6455CloseCodeNoStatusReceived
+
+		return
+	}
+
+	if len(payload) < 2 || !utf8.Valid(payload[2:]) {
+		e = ErrRFC6455ClosePayloadInvalid
+
+		return
+	}
+
+	code = binary.BigEndian.Uint16(payload)
+
+	// > 1005 ... 1006 ... 1015 ... MUST NOT be set as a status code in a
+	// > Close control frame by an endpoint.
+	//
+	// Codes 1000-2999 not defined by RFC 6455 are rejected too,
+	// along with codes outside of the ranges in Section 7.4.2.
+
+	switch {
+	case code < 1000,
+		code == RFC6455CloseCodeReserved,
+		code == RFC6455CloseCodeNoStatusReceived,
+		code == RFC6455CloseCodeAbnormalClosure,
+		code == RFC6455CloseCodeTLSHandshake,
+		code > 1011 && code < 3000,
+		code >= 5000:
+		e = ErrRFC6455ClosePayloadInvalid
+
+		return
+	}
+
+	reason = string(payload[2:])
+
+	return
+}
+
+func NewRFC6455ClosePayload(code uint16, reason string) (payload []byte) {
+	payload = make([]byte, 2, 2+len(reason))
+
+	binary.BigEndian.PutUint16(payload, code)
+
+	payload = append(payload, reason...)
+
+	return
+}
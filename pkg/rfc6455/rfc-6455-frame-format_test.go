@@ -0,0 +1,367 @@
+package rfc6455
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	rfc6455TestMaskingKey = 0x37fa213d
+)
+
+func TestRFC6455FrameExamples(t *testing.T) {
+	// Reference: Section 5.7 "Examples"
+
+	var (
+		e       error
+		encoded []byte
+		frame   RFC6455Frame
+		frame1  RFC6455Frame
+		n       int
+		vectors map[string][]byte
+	)
+
+	vectors = map[string][]byte{
+		// > A single-frame unmasked text message
+		"unmasked": {0x81, 0x05, 0x48, 0x65, 0x6c, 0x6c, 0x6f},
+
+		// > A single-frame masked text message
+		"masked": {
+			0x81, 0x85, 0x37, 0xfa, 0x21, 0x3d,
+			0x7f, 0x9f, 0x4d, 0x51, 0x58,
+		},
+	}
+
+	frame.FIN = true
+	frame.Opcode = RFC6455OpcodeText
+	frame.Payload = []byte("Hello")
+
+	encoded, e = frame.MarshalBinary()
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		vectors["unmasked"], encoded,
+	)
+
+	frame.MASK = true
+	frame.MaskingKey = rfc6455TestMaskingKey
+
+	encoded, e = frame.MarshalBinary()
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		vectors["masked"], encoded,
+	)
+
+	assert.Equal(t,
+		[]byte("Hello"), frame.Payload,
+	)
+
+	frame1, n, e = ParseRFC6455Frame(vectors["masked"])
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		len(vectors["masked"]), n,
+	)
+
+	assert.Equal(t,
+		frame, frame1,
+	)
+
+	assert.Nil(t,
+		frame1.Validate(0),
+	)
+}
+
+func TestRFC6455FrameExtendedPayloadLength(t *testing.T) {
+	// > 256 bytes binary message in a single unmasked frame
+	// >    0x82 0x7E 0x0100 [256 bytes of binary data]
+	// >
+	// > 64KiB binary message in a single unmasked frame
+	// >    0x82 0x7F 0x0000000000010000 [65536 bytes of binary data]
+
+	var (
+		e       error
+		encoded []byte
+		frame   RFC6455Frame
+		frame1  RFC6455Frame
+	)
+
+	frame.FIN = true
+	frame.Opcode = RFC6455OpcodeBinary
+	frame.Payload = make([]byte, 256)
+
+	encoded, e = frame.MarshalBinary()
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]byte{0x82, 0x7e, 0x01, 0x00}, encoded[:4],
+	)
+
+	assert.Equal(t,
+		4+256, len(encoded),
+	)
+
+	frame.Payload = make([]byte, 65536)
+
+	encoded, e = frame.MarshalBinary()
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]byte{0x82, 0x7f, 0, 0, 0, 0, 0, 0x01, 0x00, 0x00}, encoded[:10],
+	)
+
+	frame1, _, e = ParseRFC6455Frame(encoded)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		65536, len(frame1.Payload),
+	)
+
+	_, _, e = ParseRFC6455Frame([]byte{0x82, 0x7e, 0x00, 0x7d})
+
+	assert.Equal(t,
+		ErrRFC6455PayloadLengthNotMinimal, e,
+	)
+
+	_, _, e = ParseRFC6455Frame([]byte{0x82, 0x7f, 0x80, 0, 0, 0, 0, 0, 0, 0})
+
+	assert.Equal(t,
+		ErrRFC6455PayloadLengthTooLarge, e,
+	)
+
+	_, _, e = ParseRFC6455Frame([]byte{0x82, 0x7e, 0x01, 0x00, 0x00})
+
+	assert.Equal(t,
+		ErrRFC6455FrameTruncated, e,
+	)
+}
+
+func TestRFC6455FrameValidate(t *testing.T) {
+	var (
+		frame RFC6455Frame
+	)
+
+	frame.Opcode = RFC6455OpcodePing
+	frame.FIN = true
+	frame.Payload = make([]byte, 126)
+
+	assert.Equal(t,
+		ErrRFC6455ControlFramePayloadTooLong, frame.Validate(0),
+	)
+
+	frame.Payload = nil
+	frame.FIN = false
+
+	assert.Equal(t,
+		ErrRFC6455ControlFrameFragmented, frame.Validate(0),
+	)
+
+	frame.Opcode = 0x3
+
+	assert.Equal(t,
+		ErrRFC6455OpcodeReserved, frame.Validate(0),
+	)
+
+	frame.Opcode = RFC6455OpcodeText
+	frame.RSV1 = true
+
+	assert.Equal(t,
+		ErrRFC6455ReservedBitsSet, frame.Validate(0),
+	)
+
+	assert.Nil(t,
+		frame.Validate(0b100),
+	)
+
+	frame = RFC6455Frame{}
+	frame.Opcode = RFC6455OpcodeClose
+	frame.FIN = true
+	frame.Payload = NewRFC6455ClosePayload(RFC6455CloseCodeGoingAway, "bye")
+
+	assert.Nil(t,
+		frame.Validate(0),
+	)
+
+	frame.Payload = NewRFC6455ClosePayload(RFC6455CloseCodeNoStatusReceived,
+		"",
+	)
+
+	assert.Equal(t,
+		ErrRFC6455ClosePayloadInvalid, frame.Validate(0),
+	)
+
+	frame.Payload = []byte{0x03, 0xe8, 0xff}
+
+	assert.Equal(t,
+		ErrRFC6455ClosePayloadInvalid, frame.Validate(0),
+	)
+}
+
+func TestParseRFC6455ClosePayload(t *testing.T) {
+	var (
+		code   uint16
+		e      error
+		reason string
+	)
+
+	code, reason, e = ParseRFC6455ClosePayload(
+		NewRFC6455ClosePayload(RFC6455CloseCodeNormalClosure, "done"),
+	)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint16(RFC6455CloseCodeNormalClosure), code,
+	)
+
+	assert.Equal(t,
+		"done", reason,
+	)
+
+	code, _, e = ParseRFC6455ClosePayload(nil)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint16(RFC6455CloseCodeNoStatusReceived), code,
+	)
+
+	_, _, e = ParseRFC6455ClosePayload([]byte{0x03})
+
+	assert.Equal(t,
+		ErrRFC6455ClosePayloadInvalid, e,
+	)
+}
+
+func TestRFC6455Reader(t *testing.T) {
+	// > A fragmented unmasked text message
+	// >
+	// >    0x01 0x03 0x48 0x65 0x6c (contains "Hel")
+	// >    0x80 0x02 0x6c 0x6f (contains "lo")
+	// >
+	// > Unmasked Ping request and masked Ping response
+	// >
+	// >    0x89 0x05 0x48 0x65 0x6c 0x6c 0x6f (contains a body of "Hello",
+	// >    but the contents of the body are arbitrary)
+
+	var (
+		e      error
+		frame  RFC6455Frame
+		r      *RFC6455Reader
+		stream []byte
+	)
+
+	stream = []byte{
+		0x01, 0x03, 0x48, 0x65, 0x6c,
+		0x89, 0x05, 0x48, 0x65, 0x6c, 0x6c, 0x6f,
+		0x80, 0x02, 0x6c, 0x6f,
+		0x80, 0x00,
+	}
+
+	r = NewRFC6455Reader(bytes.NewReader(stream), false, 0)
+
+	frame, e = r.ReadFrame()
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]byte("Hel"), frame.Payload,
+	)
+
+	assert.False(t, frame.FIN)
+
+	frame, e = r.ReadFrame()
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint8(RFC6455OpcodePing), frame.Opcode,
+	)
+
+	frame, e = r.ReadFrame()
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]byte("lo"), frame.Payload,
+	)
+
+	assert.True(t, frame.FIN)
+
+	_, e = r.ReadFrame()
+
+	assert.Equal(t,
+		ErrRFC6455ContinuationUnexpected, e,
+	)
+
+	r = NewRFC6455Reader(bytes.NewReader(stream[5:12]), true, 0)
+
+	_, e = r.ReadFrame()
+
+	assert.Equal(t,
+		ErrRFC6455MaskMismatch, e,
+	)
+
+	r = NewRFC6455Reader(bytes.NewReader([]byte{
+		0x8a, 0x85, 0x37, 0xfa, 0x21, 0x3d, 0x7f, 0x9f, 0x4d, 0x51, 0x58,
+	}), true, 0)
+
+	frame, e = r.ReadFrame()
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]byte("Hello"), frame.Payload,
+	)
+
+	r = NewRFC6455Reader(bytes.NewReader([]byte{
+		0x01, 0x00,
+		0x02, 0x00,
+	}), false, 0)
+
+	_, e = r.ReadFrame()
+
+	assert.Nil(t, e)
+
+	_, e = r.ReadFrame()
+
+	assert.Equal(t,
+		ErrRFC6455ContinuationExpected, e,
+	)
+
+	r = NewRFC6455Reader(bytes.NewReader([]byte{0x82, 0x7e, 0x01, 0x00}),
+		false, 255,
+	)
+
+	_, e = r.ReadFrame()
+
+	assert.Equal(t,
+		ErrRFC6455PayloadLengthTooLarge, e,
+	)
+
+	r = NewRFC6455Reader(bytes.NewReader([]byte{0x82, 0x05, 0x00}), false, 0)
+
+	_, e = r.ReadFrame()
+
+	assert.Equal(t,
+		io.ErrUnexpectedEOF, e,
+	)
+
+	r = NewRFC6455Reader(bytes.NewReader(nil), false, 0)
+
+	_, e = r.ReadFrame()
+
+	assert.Equal(t,
+		io.EOF, e,
+	)
+}
@@ -0,0 +1,180 @@
+package rfc6455
+
+import (
+	"errors"
+	"io"
+)
+
+// RFC6455Reader reads WebSocket frames from a stream,
+// validating each frame and the sequence of fragments.
+// Control frames may be interleaved with the fragments of a message.
+type RFC6455Reader struct {
+	reader           io.Reader
+	masked           bool
+	maxPayloadLength uint64
+	allowedRSV       uint8
+	fragmented       bool
+	header           [RFC6455FrameHeaderLengthInBytes + 8 +
+		RFC6455MaskingKeyLengthInBytes]byte
+}
+
+const (
+	RFC6455ReaderDefaultMaxPayloadLength = 1 << 24
+)
+
+var (
+	ErrRFC6455ContinuationExpected = errors.New(
+		"A WebSocket data frame should follow a fragment without FIN set " +
+			"only as a continuation frame. " +
+			"A text or binary frame interrupts a fragmented message.",
+	)
+
+	ErrRFC6455ContinuationUnexpected = errors.New(
+		"A WebSocket continuation frame should follow " +
+			"a fragment without FIN set. " +
+			"The continuation frame does not continue a message.",
+	)
+
+	ErrRFC6455MaskMismatch = errors.New(
+		"Frames sent by a WebSocket client MUST be masked, " +
+			"and frames sent by a server MUST NOT be masked. " +
+			"The MASK bit of the frame does not match the sender.",
+	)
+)
+
+// NewRFC6455Reader returns a reader of frames that should be masked
+// if masked is true, as are those read by a server.
+// Frames with payloads longer than maxPayloadLength are rejected
+// before their payloads are read;
+// RFC6455ReaderDefaultMaxPayloadLength applies if it is zero.
+func NewRFC6455Reader(reader io.Reader, masked bool,
+	maxPayloadLength uint64,
+) (
+	r *RFC6455Reader,
+) {
+	if maxPayloadLength == 0 {
+		maxPayloadLength = RFC6455ReaderDefaultMaxPayloadLength
+	}
+
+	r = &RFC6455Reader{
+		reader:           reader,
+		masked:           masked,
+		maxPayloadLength: maxPayloadLength,
+	}
+
+	return
+}
+
+// AllowRSV permits reserved bits negotiated by extensions.
+// Bits 2, 1 and 0 of rsv stand for RSV1, RSV2 and RSV3.
+func (r *RFC6455Reader) AllowRSV(rsv uint8) {
+	r.allowedRSV = rsv
+
+	return
+}
+
+// ReadFrame reads and validates the next frame, unmasking its payload.
+// At the end of the stream it returns io.EOF,
+// or io.ErrUnexpectedEOF if the stream ends within a frame.
+func (r *RFC6455Reader) ReadFrame() (frame RFC6455Frame, e error) {
+	var (
+		length uint64
+		n      int
+	)
+
+	_, e = io.ReadFull(r.reader, r.header[:RFC6455FrameHeaderLengthInBytes])
+	if e != nil {
+		return
+	}
+
+	// The first two bytes determine how much more of the header to read.
+
+	_, _, e = frame.unmarshalHeader(r.header[:RFC6455FrameHeaderLengthInBytes])
+	if e != nil && e != ErrRFC6455FrameTruncated {
+		return
+	}
+
+	n = RFC6455FrameHeaderLengthInBytes + frame.extensionLengthInBytes()
+
+	_, e = io.ReadFull(r.reader, r.header[RFC6455FrameHeaderLengthInBytes:n])
+	if e != nil {
+		e = rfc6455UnexpectedEOF(e)
+
+		return
+	}
+
+	length, _, e = frame.unmarshalHeader(r.header[:n])
+	if e != nil {
+		return
+	}
+
+	if frame.MASK != r.masked {
+		e = ErrRFC6455MaskMismatch
+
+		return
+	}
+
+	if frame.IsControl() && length > RFC6455ControlFrameMaxPayloadLength {
+		e = ErrRFC6455ControlFramePayloadTooLong
+
+		return
+	}
+
+	if length > r.maxPayloadLength {
+		e = ErrRFC6455PayloadLengthTooLarge
+
+		return
+	}
+
+	frame.Payload = make([]byte, length)
+
+	_, e = io.ReadFull(r.reader, frame.Payload)
+	if e != nil {
+		e = rfc6455UnexpectedEOF(e)
+
+		return
+	}
+
+	if frame.MASK {
+		RFC6455Mask(frame.MaskingKey, frame.Payload)
+	}
+
+	e = frame.Validate(r.allowedRSV)
+	if e != nil {
+		return
+	}
+
+	e = r.sequence(&frame)
+	if e != nil {
+		return
+	}
+
+	return
+}
+
+func (r *RFC6455Reader) sequence(frame *RFC6455Frame) (e error) {
+	if frame.IsControl() {
+		return
+	}
+
+	switch {
+	case frame.Opcode == RFC6455OpcodeContinuation && !r.fragmented:
+		e = ErrRFC6455ContinuationUnexpected
+
+	case frame.Opcode != RFC6455OpcodeContinuation && r.fragmented:
+		e = ErrRFC6455ContinuationExpected
+
+	default:
+		r.fragmented = !frame.FIN
+	}
+
+	return
+}
+
+func rfc6455UnexpectedEOF(e error) error {
+	if e == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return e
+}
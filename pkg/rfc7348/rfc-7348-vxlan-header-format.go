@@ -0,0 +1,118 @@
+package rfc7348
+
+import (
+	"errors"
+
+	"github.com/encodingx/binary"
+)
+
+type RFC7348VXLANHeaderFormat struct {
+	// Reference: Section 5 "VXLAN Frame Format" of
+	// RFC 7348 Virtual eXtensible Local Area Network (VXLAN)
+	// https://datatracker.ietf.org/doc/html/rfc7348#section-5
+
+	// > VXLAN Header:
+	// > +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	// > |R|R|R|R|I|R|R|R|            Reserved                           |
+	// > +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	// > |                VXLAN Network Identifier (VNI) |   Reserved    |
+	// > +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+
+	RFC7348VXLANHeaderFormatWord0 `word:"32"`
+	RFC7348VXLANHeaderFormatWord1 `word:"32"`
+}
+
+type RFC7348VXLANHeaderFormatWord0 struct {
+	FlagsReserved0 uint8  `bitfield:"4"`
+	FlagsI         bool   `bitfield:"1"`
+	FlagsReserved1 uint8  `bitfield:"3"`
+	Reserved0      uint32 `bitfield:"24"`
+	// > Flags (8 bits): where the I flag MUST be set to 1 for a valid
+	// > VXLAN Network ID (VNI).  The other 7 bits (designated "R") are
+	// > reserved fields and MUST be set to zero on transmission and
+	// > ignored on receipt.
+	// >
+	// > Reserved fields (24 bits and 8 bits): MUST be set to zero on
+	// > transmission and ignored on receipt.
+}
+
+type RFC7348VXLANHeaderFormatWord1 struct {
+	VNI       uint32 `bitfield:"24"`
+	Reserved1 uint8  `bitfield:"8"`
+	// > VXLAN Network Identifier (VNI) / VXLAN Segment ID: this is a
+	// > 24-bit value used to designate the individual VXLAN overlay
+	// > network on which the communicating VMs are situated.
+}
+
+const (
+	RFC7348VXLANHeaderLengthInBytes = 8
+)
+
+const (
+	RFC7348VXLANUDPDestinationPort = 4789
+)
+
+var (
+	ErrRFC7348VXLANPacketTooShort = errors.New(
+		"A VXLAN packet should begin with an 8-octet VXLAN header. " +
+			"The packet is shorter than the header.",
+	)
+
+	ErrRFC7348VXLANFlagINotSet = errors.New(
+		"The I flag of a VXLAN header MUST be set to 1 for a valid VNI. " +
+			"The I flag of the header is not set.",
+	)
+)
+
+// EncapsulateRFC7348 prefixes an inner Ethernet frame with a VXLAN header.
+// The result is the payload of a UDP datagram.
+func EncapsulateRFC7348(vni uint32, innerFrame []byte) (
+	packet []byte, e error,
+) {
+	var (
+		header      RFC7348VXLANHeaderFormat
+		headerBytes []byte
+	)
+
+	header.FlagsI = true
+	header.VNI = vni
+
+	headerBytes, e = binary.Marshal(&header)
+	if e != nil {
+		return
+	}
+
+	packet = make([]byte, 0, len(headerBytes)+len(innerFrame))
+
+	packet = append(packet, headerBytes...)
+	packet = append(packet, innerFrame...)
+
+	return
+}
+
+// DecapsulateRFC7348 splits a UDP payload
+// into its VXLAN header and inner Ethernet frame.
+func DecapsulateRFC7348(packet []byte) (
+	header RFC7348VXLANHeaderFormat, innerFrame []byte, e error,
+) {
+	if len(packet) < RFC7348VXLANHeaderLengthInBytes {
+		e = ErrRFC7348VXLANPacketTooShort
+
+		return
+	}
+
+	e = binary.Unmarshal(packet[:RFC7348VXLANHeaderLengthInBytes], &header)
+	if e != nil {
+		return
+	}
+
+	if !header.FlagsI {
+		e = ErrRFC7348VXLANFlagINotSet
+
+		return
+	}
+
+	innerFrame = packet[RFC7348VXLANHeaderLengthInBytes:]
+
+	return
+}
@@ -0,0 +1,85 @@
+package rfc7348
+
+import (
+	"testing"
+
+	"github.com/encodingx/binary"
+	"github.com/encodingx/binary/pkg/ieee8023"
+	"github.com/encodingx/binary/pkg/rfc791"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncapsulateAndDecapsulateRFC7348(t *testing.T) {
+	const (
+		vni = 0xabcdef
+	)
+
+	var (
+		e              error
+		ethernetHeader ieee8023.IEEE8023EthernetHeaderFormat
+		header         RFC7348VXLANHeaderFormat
+		innerFrame     []byte
+		internetHeader rfc791.RFC791InternetHeaderFormatWithoutOptions
+		packet         []byte
+	)
+
+	packet, e = EncapsulateRFC7348(vni, innerFrameBytes)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]byte{0x08, 0x00, 0x00, 0x00, 0xab, 0xcd, 0xef, 0x00},
+		packet[:RFC7348VXLANHeaderLengthInBytes],
+	)
+
+	header, innerFrame, e = DecapsulateRFC7348(packet)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint32(vni), header.VNI,
+	)
+
+	e = binary.Unmarshal(
+		innerFrame[:ieee8023.IEEE8023EthernetHeaderLengthInBytes],
+		&ethernetHeader,
+	)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint16(ieee8023.IEEE8023EthernetHeaderTypeIPv4),
+		ethernetHeader.LengthOrType,
+	)
+
+	e = binary.Unmarshal(
+		innerFrame[ieee8023.IEEE8023EthernetHeaderLengthInBytes:],
+		&internetHeader,
+	)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint8(rfc791.RFC791InternetHeaderProtocolTCP),
+		internetHeader.Protocol,
+	)
+
+	packet[0] = 0
+
+	_, _, e = DecapsulateRFC7348(packet)
+
+	assert.Equal(t,
+		ErrRFC7348VXLANFlagINotSet, e,
+	)
+}
+
+var (
+	innerFrameBytes = []byte{
+		0x02, 0x00, 0x00, 0x00, 0x00, 0x01, // destination
+		0x02, 0x00, 0x00, 0x00, 0x00, 0x02, // source
+		0x08, 0x00, // IPv4
+		0x45, 0x00, 0x00, 0x14, 0x00, 0x00, 0x40, 0x00,
+		0x40, 0x06, 0x00, 0x00, 0x0a, 0x00, 0x00, 0x01,
+		0x0a, 0x00, 0x00, 0x02,
+	}
+)
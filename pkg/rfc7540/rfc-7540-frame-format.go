@@ -0,0 +1,973 @@
+package rfc7540
+
+import (
+	"errors"
+
+	"github.com/encodingx/binary"
+)
+
+type RFC7540FrameHeaderFormat struct {
+	// Reference: Section 4.1 "Frame Format" of
+	// RFC 7540 Hypertext Transfer Protocol Version 2 (HTTP/2)
+	// https://datatracker.ietf.org/doc/html/rfc7540#section-4.1
+
+	// > All frames begin with a fixed 9-octet header followed by a variable-
+	// > length payload.
+	// >
+	// >  +-----------------------------------------------+
+	// >  |                 Length (24)                   |
+	// >  +---------------+---------------+---------------+
+	// >  |   Type (8)    |   Flags (8)   |
+	// >  +-+-------------+---------------+-------------------------------+
+	// >  |R|                 Stream Identifier (31)                      |
+	// >  +=+=============================================================+
+	// >  |                   Frame Payload (0...)                      ...
+	// >  +---------------------------------------------------------------+
+
+	RFC7540FrameHeaderFormatWord0 `word:"24"`
+	RFC7540FrameHeaderFormatWord1 `word:"8"`
+	RFC7540FrameHeaderFormatWord2 `word:"8"`
+	RFC7540FrameHeaderFormatWord3 `word:"32"`
+}
+
+type RFC7540FrameHeaderFormatWord0 struct {
+	Length uint32 `bitfield:"24"`
+	// > Length:  The length of the frame payload expressed as an unsigned
+	// >    24-bit integer.  Values greater than 2^14 (16,384) MUST NOT be
+	// >    sent unless the receiver has set a larger value for
+	// >    SETTINGS_MAX_FRAME_SIZE.
+}
+
+type RFC7540FrameHeaderFormatWord1 struct {
+	Type uint8 `bitfield:"8"`
+	// > Type:  The 8-bit type of the frame.  The frame type determines the
+	// >    format and semantics of the frame.  Implementations MUST ignore
+	// >    and discard any frame that has a type that is unknown.
+}
+
+type RFC7540FrameHeaderFormatWord2 struct {
+	Flags uint8 `bitfield:"8"`
+	// > Flags:  An 8-bit field reserved for boolean flags specific to the
+	// >    frame type.
+}
+
+type RFC7540FrameHeaderFormatWord3 struct {
+	R bool `bitfield:"1"`
+	// > R: A reserved 1-bit field.  The semantics of this bit are undefined,
+	// >    and the bit MUST remain unset (0x0) when sending and MUST be
+	// >    ignored when receiving.
+
+	StreamIdentifier uint32 `bitfield:"31"`
+	// > Stream Identifier:  A stream identifier (see Section 5.1.1)
+	// >    expressed as an unsigned 31-bit integer.  The value 0x0 is
+	// >    reserved for frames that are associated with the connection as a
+	// >    whole as opposed to an individual stream.
+}
+
+func (h RFC7540FrameHeaderFormat) HasFlag(flag uint8) bool {
+	return h.Flags&flag != 0
+}
+
+const (
+	RFC7540FrameHeaderLengthInBytes = 9
+)
+
+const (
+	RFC7540FrameTypeDATA         = 0x0
+	RFC7540FrameTypeHEADERS      = 0x1
+	RFC7540FrameTypePRIORITY     = 0x2
+	RFC7540FrameTypeRSTSTREAM    = 0x3
+	RFC7540FrameTypeSETTINGS     = 0x4
+	RFC7540FrameTypePUSHPROMISE  = 0x5
+	RFC7540FrameTypePING         = 0x6
+	RFC7540FrameTypeGOAWAY       = 0x7
+	RFC7540FrameTypeWINDOWUPDATE = 0x8
+	RFC7540FrameTypeCONTINUATION = 0x9
+)
+
+const (
+	RFC7540FlagENDSTREAM  = 0x01
+	RFC7540FlagACK        = 0x01
+	RFC7540FlagENDHEADERS = 0x04
+	RFC7540FlagPADDED     = 0x08
+	RFC7540FlagPRIORITY   = 0x20
+)
+
+const (
+	RFC7540SettingsHeaderTableSize      = 0x1
+	RFC7540SettingsEnablePush           = 0x2
+	RFC7540SettingsMaxConcurrentStreams = 0x3
+	RFC7540SettingsInitialWindowSize    = 0x4
+	RFC7540SettingsMaxFrameSize         = 0x5
+	RFC7540SettingsMaxHeaderListSize    = 0x6
+)
+
+const (
+	RFC7540ErrorCodeNoError            = 0x0
+	RFC7540ErrorCodeProtocolError      = 0x1
+	RFC7540ErrorCodeInternalError      = 0x2
+	RFC7540ErrorCodeFlowControlError   = 0x3
+	RFC7540ErrorCodeSettingsTimeout    = 0x4
+	RFC7540ErrorCodeStreamClosed       = 0x5
+	RFC7540ErrorCodeFrameSizeError     = 0x6
+	RFC7540ErrorCodeRefusedStream      = 0x7
+	RFC7540ErrorCodeCancel             = 0x8
+	RFC7540ErrorCodeCompressionError   = 0x9
+	RFC7540ErrorCodeConnectError       = 0xa
+	RFC7540ErrorCodeEnhanceYourCalm    = 0xb
+	RFC7540ErrorCodeInadequateSecurity = 0xc
+	RFC7540ErrorCodeHTTP11Required     = 0xd
+)
+
+const (
+	RFC7540ClientConnectionPreface = "PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n"
+
+	RFC7540DefaultMaxFrameSize = 1 << 14
+	RFC7540MaxMaxFrameSize     = 1<<24 - 1
+)
+
+type RFC7540PadLengthFormat struct {
+	RFC7540PadLengthFormatWord0 `word:"8"`
+}
+
+type RFC7540PadLengthFormatWord0 struct {
+	PadLength uint8 `bitfield:"8"`
+	// > Pad Length:  An 8-bit field containing the length of the frame
+	// >    padding in units of octets.  This field is conditional (as
+	// >    signified by a "?" in the diagram) and is only present if the
+	// >    PADDED flag is set.
+}
+
+type RFC7540PriorityFormat struct {
+	// Reference: Section 6.3 "PRIORITY"
+
+	// >  +-+-------------------------------------------------------------+
+	// >  |E|                  Stream Dependency (31)                     |
+	// >  +-+-------------+-----------------------------------------------+
+	// >  |   Weight (8)  |
+	// >  +-+-------------+
+	//
+	// The same fields are present in HEADERS frames with the PRIORITY flag.
+
+	RFC7540PriorityFormatWord0 `word:"32"`
+	RFC7540PriorityFormatWord1 `word:"8"`
+}
+
+type RFC7540PriorityFormatWord0 struct {
+	E bool `bitfield:"1"`
+	// > E: A single-bit flag indicating that the stream dependency is
+	// >    exclusive (see Section 5.3).
+
+	StreamDependency uint32 `bitfield:"31"`
+	// > Stream Dependency:  A 31-bit stream identifier for the stream that
+	// >    this stream depends on (see Section 5.3).
+}
+
+type RFC7540PriorityFormatWord1 struct {
+	Weight uint8 `bitfield:"8"`
+	// > Weight:  An unsigned 8-bit integer representing a priority weight
+	// >    for the stream (see Section 5.3).  Add one to the value to obtain
+	// >    a weight between 1 and 256.
+}
+
+type RFC7540RSTStreamFormat struct {
+	// Reference: Section 6.4 "RST_STREAM"
+
+	RFC7540RSTStreamFormatWord0 `word:"32"`
+}
+
+type RFC7540RSTStreamFormatWord0 struct {
+	ErrorCode uint32 `bitfield:"32"`
+}
+
+type RFC7540SettingFormat struct {
+	// Reference: Section 6.5.1 "SETTINGS Format"
+
+	// >  +-------------------------------+
+	// >  |       Identifier (16)         |
+	// >  +-------------------------------+-------------------------------+
+	// >  |                        Value (32)                             |
+	// >  +---------------------------------------------------------------+
+
+	RFC7540SettingFormatWord0 `word:"16"`
+	RFC7540SettingFormatWord1 `word:"32"`
+}
+
+type RFC7540SettingFormatWord0 struct {
+	Identifier uint16 `bitfield:"16"`
+}
+
+type RFC7540SettingFormatWord1 struct {
+	Value uint32 `bitfield:"32"`
+}
+
+type RFC7540PushPromiseFormat struct {
+	// Reference: Section 6.6 "PUSH_PROMISE"
+
+	// >  +---------------+
+	// >  |Pad Length? (8)|
+	// >  +-+-------------+-----------------------------------------------+
+	// >  |R|                  Promised Stream ID (31)                    |
+	// >  +-+-----------------------------+-------------------------------+
+	// >  |                   Header Block Fragment (*)                 ...
+	// >  +---------------------------------------------------------------+
+	// >  |                           Padding (*)                       ...
+	// >  +---------------------------------------------------------------+
+
+	RFC7540PushPromiseFormatWord0 `word:"32"`
+}
+
+type RFC7540PushPromiseFormatWord0 struct {
+	R                bool   `bitfield:"1"`
+	PromisedStreamID uint32 `bitfield:"31"`
+}
+
+type RFC7540PingFormat struct {
+	// Reference: Section 6.7 "PING"
+
+	RFC7540PingFormatWord0 `word:"64"`
+}
+
+type RFC7540PingFormatWord0 struct {
+	OpaqueData uint64 `bitfield:"64"`
+}
+
+type RFC7540GoAwayFormat struct {
+	// Reference: Section 6.8 "GOAWAY"
+
+	// >  +-+-------------------------------------------------------------+
+	// >  |R|                  Last-Stream-ID (31)                        |
+	// >  +-+-------------------------------------------------------------+
+	// >  |                      Error Code (32)                          |
+	// >  +---------------------------------------------------------------+
+	// >  |                  Additional Debug Data (*)                    |
+	// >  +---------------------------------------------------------------+
+
+	RFC7540GoAwayFormatWord0 `word:"32"`
+	RFC7540GoAwayFormatWord1 `word:"32"`
+}
+
+type RFC7540GoAwayFormatWord0 struct {
+	R            bool   `bitfield:"1"`
+	LastStreamID uint32 `bitfield:"31"`
+}
+
+type RFC7540GoAwayFormatWord1 struct {
+	ErrorCode uint32 `bitfield:"32"`
+}
+
+type RFC7540WindowUpdateFormat struct {
+	// Reference: Section 6.9 "WINDOW_UPDATE"
+
+	// >  +-+-------------------------------------------------------------+
+	// >  |R|              Window Size Increment (31)                     |
+	// >  +-+-------------------------------------------------------------+
+
+	RFC7540WindowUpdateFormatWord0 `word:"32"`
+}
+
+type RFC7540WindowUpdateFormatWord0 struct {
+	R                   bool   `bitfield:"1"`
+	WindowSizeIncrement uint32 `bitfield:"31"`
+}
+
+const (
+	rfc7540PadLengthLengthInBytes    = 1
+	rfc7540PriorityLengthInBytes     = 5
+	rfc7540RSTStreamLengthInBytes    = 4
+	rfc7540SettingLengthInBytes      = 6
+	rfc7540PushPromiseLengthInBytes  = 4
+	rfc7540PingLengthInBytes         = 8
+	rfc7540GoAwayLengthInBytes       = 8
+	rfc7540WindowUpdateLengthInBytes = 4
+)
+
+var (
+	ErrRFC7540FrameSizeInvalid = errors.New(
+		"The payload of an HTTP/2 frame should be of the size " +
+			"defined for its type. " +
+			"The frame payload is of an invalid size.",
+	)
+
+	ErrRFC7540FrameTypeUnknown = errors.New(
+		"The type of an HTTP/2 frame should be one defined by RFC 7540 " +
+			"to be decoded. Frames of unknown types MUST be ignored. " +
+			"The frame is of an unknown type.",
+	)
+
+	ErrRFC7540PaddingTooLong = errors.New(
+		"The padding of an HTTP/2 frame should be shorter than " +
+			"the frame payload. " +
+			"The Pad Length exceeds the remaining payload.",
+	)
+
+	ErrRFC7540StreamIdentifierInvalid = errors.New(
+		"The stream identifier of an HTTP/2 frame should be non-zero " +
+			"for stream frames, and zero for connection frames. " +
+			"The stream identifier does not match the frame type.",
+	)
+)
+
+// RFC7540FramePayload is implemented by the payload of each frame type.
+// Marshalling a payload sets the Type and Length of a frame header,
+// and reads the flags that gate optional fields.
+type RFC7540FramePayload interface {
+	RFC7540FrameType() uint8
+	MarshalRFC7540Payload(header *RFC7540FrameHeaderFormat) ([]byte, error)
+	UnmarshalRFC7540Payload(header RFC7540FrameHeaderFormat, payload []byte) error
+}
+
+type RFC7540Frame struct {
+	RFC7540FrameHeaderFormat
+
+	Payload []byte
+}
+
+// DecodePayload decodes the payload of the frame according to its type,
+// validating its size and stream identifier.
+func (f RFC7540Frame) DecodePayload() (payload RFC7540FramePayload, e error) {
+	switch f.Type {
+	case RFC7540FrameTypeDATA:
+		payload = &RFC7540DataPayload{}
+
+	case RFC7540FrameTypeHEADERS:
+		payload = &RFC7540HeadersPayload{}
+
+	case RFC7540FrameTypePRIORITY:
+		payload = &RFC7540PriorityPayload{}
+
+	case RFC7540FrameTypeRSTSTREAM:
+		payload = &RFC7540RSTStreamPayload{}
+
+	case RFC7540FrameTypeSETTINGS:
+		payload = &RFC7540SettingsPayload{}
+
+	case RFC7540FrameTypePUSHPROMISE:
+		payload = &RFC7540PushPromisePayload{}
+
+	case RFC7540FrameTypePING:
+		payload = &RFC7540PingPayload{}
+
+	case RFC7540FrameTypeGOAWAY:
+		payload = &RFC7540GoAwayPayload{}
+
+	case RFC7540FrameTypeWINDOWUPDATE:
+		payload = &RFC7540WindowUpdatePayload{}
+
+	case RFC7540FrameTypeCONTINUATION:
+		payload = &RFC7540ContinuationPayload{}
+
+	default:
+		e = ErrRFC7540FrameTypeUnknown
+
+		return
+	}
+
+	e = rfc7540ValidateStreamIdentifier(f.RFC7540FrameHeaderFormat)
+	if e != nil {
+		payload = nil
+
+		return
+	}
+
+	e = payload.UnmarshalRFC7540Payload(f.RFC7540FrameHeaderFormat, f.Payload)
+	if e != nil {
+		payload = nil
+
+		return
+	}
+
+	return
+}
+
+// MarshalRFC7540Frame encodes a frame from a header and payload,
+// setting the Type and Length of the header.
+func MarshalRFC7540Frame(header *RFC7540FrameHeaderFormat,
+	payload RFC7540FramePayload,
+) (
+	bytes []byte, e error,
+) {
+	var (
+		payloadBytes []byte
+	)
+
+	payloadBytes, e = payload.MarshalRFC7540Payload(header)
+	if e != nil {
+		return
+	}
+
+	header.Type = payload.RFC7540FrameType()
+	header.Length = uint32(len(payloadBytes))
+
+	bytes, e = binary.Marshal(header)
+	if e != nil {
+		return
+	}
+
+	bytes = append(bytes, payloadBytes...)
+
+	return
+}
+
+func rfc7540ValidateStreamIdentifier(header RFC7540FrameHeaderFormat) (
+	e error,
+) {
+	switch header.Type {
+	case RFC7540FrameTypeSETTINGS,
+		RFC7540FrameTypePING,
+		RFC7540FrameTypeGOAWAY:
+		if header.StreamIdentifier != 0 {
+			e = ErrRFC7540StreamIdentifierInvalid
+		}
+
+	case RFC7540FrameTypeWINDOWUPDATE:
+		// WINDOW_UPDATE frames apply to either streams or the connection.
+
+	default:
+		if header.StreamIdentifier == 0 {
+			e = ErrRFC7540StreamIdentifierInvalid
+		}
+	}
+
+	return
+}
+
+// rfc7540Unpad strips the Pad Length field and padding from a payload
+// if the PADDED flag is set.
+func rfc7540Unpad(header RFC7540FrameHeaderFormat, payload []byte) (
+	padLength uint8, unpadded []byte, e error,
+) {
+	var (
+		format RFC7540PadLengthFormat
+	)
+
+	if !header.HasFlag(RFC7540FlagPADDED) {
+		unpadded = payload
+
+		return
+	}
+
+	if len(payload) < rfc7540PadLengthLengthInBytes {
+		e = ErrRFC7540FrameSizeInvalid
+
+		return
+	}
+
+	e = binary.Unmarshal(payload[:rfc7540PadLengthLengthInBytes], &format)
+	if e != nil {
+		return
+	}
+
+	padLength = format.PadLength
+
+	// > If the length of the padding is the length of the frame payload or
+	// > greater, the recipient MUST treat this as a connection error
+	// > (Section 5.4.1) of type PROTOCOL_ERROR.
+
+	if int(padLength) >= len(payload) {
+		e = ErrRFC7540PaddingTooLong
+
+		return
+	}
+
+	unpadded = payload[rfc7540PadLengthLengthInBytes : len(payload)-int(padLength)]
+
+	return
+}
+
+func rfc7540Pad(header *RFC7540FrameHeaderFormat, padLength uint8,
+	unpadded []byte,
+) (
+	payload []byte, e error,
+) {
+	var (
+		format RFC7540PadLengthFormat
+	)
+
+	if !header.HasFlag(RFC7540FlagPADDED) {
+		payload = unpadded
+
+		return
+	}
+
+	format.PadLength = padLength
+
+	payload, e = binary.Marshal(&format)
+	if e != nil {
+		return
+	}
+
+	payload = append(payload, unpadded...)
+	payload = append(payload, make([]byte, padLength)...)
+
+	return
+}
+
+type RFC7540DataPayload struct {
+	// Reference: Section 6.1 "DATA"
+
+	// >  +---------------+
+	// >  |Pad Length? (8)|
+	// >  +---------------+-----------------------------------------------+
+	// >  |                            Data (*)                         ...
+	// >  +---------------------------------------------------------------+
+	// >  |                           Padding (*)                       ...
+	// >  +---------------------------------------------------------------+
+	//
+	// PadLength is significant only if the PADDED flag is set.
+
+	PadLength uint8
+	Data      []byte
+}
+
+func (p *RFC7540DataPayload) RFC7540FrameType() uint8 {
+	return RFC7540FrameTypeDATA
+}
+
+func (p *RFC7540DataPayload) MarshalRFC7540Payload(
+	header *RFC7540FrameHeaderFormat,
+) (
+	bytes []byte, e error,
+) {
+	bytes, e = rfc7540Pad(header, p.PadLength, p.Data)
+
+	return
+}
+
+func (p *RFC7540DataPayload) UnmarshalRFC7540Payload(
+	header RFC7540FrameHeaderFormat, payload []byte,
+) (
+	e error,
+) {
+	p.PadLength, p.Data, e = rfc7540Unpad(header, payload)
+
+	return
+}
+
+type RFC7540HeadersPayload struct {
+	// Reference: Section 6.2 "HEADERS"
+
+	// >  +---------------+
+	// >  |Pad Length? (8)|
+	// >  +-+-------------+-----------------------------------------------+
+	// >  |E|                 Stream Dependency? (31)                     |
+	// >  +-+-------------+-----------------------------------------------+
+	// >  |  Weight? (8)  |
+	// >  +-+-------------+-----------------------------------------------+
+	// >  |                   Header Block Fragment (*)                 ...
+	// >  +---------------------------------------------------------------+
+	// >  |                           Padding (*)                       ...
+	// >  +---------------------------------------------------------------+
+	//
+	// The priority fields are significant only if the PRIORITY flag is set.
+
+	PadLength uint8
+
+	RFC7540PriorityFormat
+
+	HeaderBlockFragment []byte
+}
+
+func (p *RFC7540HeadersPayload) RFC7540FrameType() uint8 {
+	return RFC7540FrameTypeHEADERS
+}
+
+func (p *RFC7540HeadersPayload) MarshalRFC7540Payload(
+	header *RFC7540FrameHeaderFormat,
+) (
+	bytes []byte, e error,
+) {
+	if header.HasFlag(RFC7540FlagPRIORITY) {
+		bytes, e = binary.Marshal(&p.RFC7540PriorityFormat)
+		if e != nil {
+			return
+		}
+	}
+
+	bytes = append(bytes, p.HeaderBlockFragment...)
+
+	bytes, e = rfc7540Pad(header, p.PadLength, bytes)
+
+	return
+}
+
+func (p *RFC7540HeadersPayload) UnmarshalRFC7540Payload(
+	header RFC7540FrameHeaderFormat, payload []byte,
+) (
+	e error,
+) {
+	p.PadLength, payload, e = rfc7540Unpad(header, payload)
+	if e != nil {
+		return
+	}
+
+	p.RFC7540PriorityFormat = RFC7540PriorityFormat{}
+
+	if header.HasFlag(RFC7540FlagPRIORITY) {
+		if len(payload) < rfc7540PriorityLengthInBytes {
+			e = ErrRFC7540FrameSizeInvalid
+
+			return
+		}
+
+		e = binary.Unmarshal(payload[:rfc7540PriorityLengthInBytes],
+			&p.RFC7540PriorityFormat,
+		)
+		if e != nil {
+			return
+		}
+
+		payload = payload[rfc7540PriorityLengthInBytes:]
+	}
+
+	p.HeaderBlockFragment = payload
+
+	return
+}
+
+type RFC7540PriorityPayload struct {
+	RFC7540PriorityFormat
+}
+
+func (p *RFC7540PriorityPayload) RFC7540FrameType() uint8 {
+	return RFC7540FrameTypePRIORITY
+}
+
+func (p *RFC7540PriorityPayload) MarshalRFC7540Payload(
+	header *RFC7540FrameHeaderFormat,
+) (
+	bytes []byte, e error,
+) {
+	bytes, e = binary.Marshal(&p.RFC7540PriorityFormat)
+
+	return
+}
+
+func (p *RFC7540PriorityPayload) UnmarshalRFC7540Payload(
+	header RFC7540FrameHeaderFormat, payload []byte,
+) (
+	e error,
+) {
+	if len(payload) != rfc7540PriorityLengthInBytes {
+		e = ErrRFC7540FrameSizeInvalid
+
+		return
+	}
+
+	e = binary.Unmarshal(payload, &p.RFC7540PriorityFormat)
+
+	return
+}
+
+type RFC7540RSTStreamPayload struct {
+	RFC7540RSTStreamFormat
+}
+
+func (p *RFC7540RSTStreamPayload) RFC7540FrameType() uint8 {
+	return RFC7540FrameTypeRSTSTREAM
+}
+
+func (p *RFC7540RSTStreamPayload) MarshalRFC7540Payload(
+	header *RFC7540FrameHeaderFormat,
+) (
+	bytes []byte, e error,
+) {
+	bytes, e = binary.Marshal(&p.RFC7540RSTStreamFormat)
+
+	return
+}
+
+func (p *RFC7540RSTStreamPayload) UnmarshalRFC7540Payload(
+	header RFC7540FrameHeaderFormat, payload []byte,
+) (
+	e error,
+) {
+	if len(payload) != rfc7540RSTStreamLengthInBytes {
+		e = ErrRFC7540FrameSizeInvalid
+
+		return
+	}
+
+	e = binary.Unmarshal(payload, &p.RFC7540RSTStreamFormat)
+
+	return
+}
+
+type RFC7540SettingsPayload struct {
+	// Settings should be empty if the ACK flag is set.
+	Settings []RFC7540SettingFormat
+}
+
+func (p *RFC7540SettingsPayload) RFC7540FrameType() uint8 {
+	return RFC7540FrameTypeSETTINGS
+}
+
+func (p *RFC7540SettingsPayload) MarshalRFC7540Payload(
+	header *RFC7540FrameHeaderFormat,
+) (
+	bytes []byte, e error,
+) {
+	var (
+		i            int
+		settingBytes []byte
+	)
+
+	if header.HasFlag(RFC7540FlagACK) && len(p.Settings) > 0 {
+		e = ErrRFC7540FrameSizeInvalid
+
+		return
+	}
+
+	for i = range p.Settings {
+		settingBytes, e = binary.Marshal(&p.Settings[i])
+		if e != nil {
+			return
+		}
+
+		bytes = append(bytes, settingBytes...)
+	}
+
+	return
+}
+
+func (p *RFC7540SettingsPayload) UnmarshalRFC7540Payload(
+	header RFC7540FrameHeaderFormat, payload []byte,
+) (
+	e error,
+) {
+	// > Receipt of a SETTINGS frame with the ACK flag set and a length
+	// > field value other than 0 MUST be treated as a connection error
+	// > (Section 5.4.1) of type FRAME_SIZE_ERROR.
+	// >
+	// > A SETTINGS frame with a length other than a multiple of 6 octets
+	// > MUST be treated as a connection error (Section 5.4.1) of type
+	// > FRAME_SIZE_ERROR.
+
+	var (
+		i       int
+		setting RFC7540SettingFormat
+	)
+
+	if header.HasFlag(RFC7540FlagACK) && len(payload) > 0 ||
+		len(payload)%rfc7540SettingLengthInBytes != 0 {
+		e = ErrRFC7540FrameSizeInvalid
+
+		return
+	}
+
+	p.Settings = nil
+
+	for i = 0; i < len(payload); i += rfc7540SettingLengthInBytes {
+		e = binary.Unmarshal(payload[i:i+rfc7540SettingLengthInBytes],
+			&setting,
+		)
+		if e != nil {
+			return
+		}
+
+		p.Settings = append(p.Settings, setting)
+	}
+
+	return
+}
+
+type RFC7540PushPromisePayload struct {
+	PadLength uint8
+
+	RFC7540PushPromiseFormat
+
+	HeaderBlockFragment []byte
+}
+
+func (p *RFC7540PushPromisePayload) RFC7540FrameType() uint8 {
+	return RFC7540FrameTypePUSHPROMISE
+}
+
+func (p *RFC7540PushPromisePayload) MarshalRFC7540Payload(
+	header *RFC7540FrameHeaderFormat,
+) (
+	bytes []byte, e error,
+) {
+	bytes, e = binary.Marshal(&p.RFC7540PushPromiseFormat)
+	if e != nil {
+		return
+	}
+
+	bytes = append(bytes, p.HeaderBlockFragment...)
+
+	bytes, e = rfc7540Pad(header, p.PadLength, bytes)
+
+	return
+}
+
+func (p *RFC7540PushPromisePayload) UnmarshalRFC7540Payload(
+	header RFC7540FrameHeaderFormat, payload []byte,
+) (
+	e error,
+) {
+	p.PadLength, payload, e = rfc7540Unpad(header, payload)
+	if e != nil {
+		return
+	}
+
+	if len(payload) < rfc7540PushPromiseLengthInBytes {
+		e = ErrRFC7540FrameSizeInvalid
+
+		return
+	}
+
+	e = binary.Unmarshal(payload[:rfc7540PushPromiseLengthInBytes],
+		&p.RFC7540PushPromiseFormat,
+	)
+	if e != nil {
+		return
+	}
+
+	p.HeaderBlockFragment = payload[rfc7540PushPromiseLengthInBytes:]
+
+	return
+}
+
+type RFC7540PingPayload struct {
+	RFC7540PingFormat
+}
+
+func (p *RFC7540PingPayload) RFC7540FrameType() uint8 {
+	return RFC7540FrameTypePING
+}
+
+func (p *RFC7540PingPayload) MarshalRFC7540Payload(
+	header *RFC7540FrameHeaderFormat,
+) (
+	bytes []byte, e error,
+) {
+	bytes, e = binary.Marshal(&p.RFC7540PingFormat)
+
+	return
+}
+
+func (p *RFC7540PingPayload) UnmarshalRFC7540Payload(
+	header RFC7540FrameHeaderFormat, payload []byte,
+) (
+	e error,
+) {
+	if len(payload) != rfc7540PingLengthInBytes {
+		e = ErrRFC7540FrameSizeInvalid
+
+		return
+	}
+
+	e = binary.Unmarshal(payload, &p.RFC7540PingFormat)
+
+	return
+}
+
+type RFC7540GoAwayPayload struct {
+	RFC7540GoAwayFormat
+
+	AdditionalDebugData []byte
+}
+
+func (p *RFC7540GoAwayPayload) RFC7540FrameType() uint8 {
+	return RFC7540FrameTypeGOAWAY
+}
+
+func (p *RFC7540GoAwayPayload) MarshalRFC7540Payload(
+	header *RFC7540FrameHeaderFormat,
+) (
+	bytes []byte, e error,
+) {
+	bytes, e = binary.Marshal(&p.RFC7540GoAwayFormat)
+	if e != nil {
+		return
+	}
+
+	bytes = append(bytes, p.AdditionalDebugData...)
+
+	return
+}
+
+func (p *RFC7540GoAwayPayload) UnmarshalRFC7540Payload(
+	header RFC7540FrameHeaderFormat, payload []byte,
+) (
+	e error,
+) {
+	if len(payload) < rfc7540GoAwayLengthInBytes {
+		e = ErrRFC7540FrameSizeInvalid
+
+		return
+	}
+
+	e = binary.Unmarshal(payload[:rfc7540GoAwayLengthInBytes],
+		&p.RFC7540GoAwayFormat,
+	)
+	if e != nil {
+		return
+	}
+
+	p.AdditionalDebugData = payload[rfc7540GoAwayLengthInBytes:]
+
+	return
+}
+
+type RFC7540WindowUpdatePayload struct {
+	RFC7540WindowUpdateFormat
+}
+
+func (p *RFC7540WindowUpdatePayload) RFC7540FrameType() uint8 {
+	return RFC7540FrameTypeWINDOWUPDATE
+}
+
+func (p *RFC7540WindowUpdatePayload) MarshalRFC7540Payload(
+	header *RFC7540FrameHeaderFormat,
+) (
+	bytes []byte, e error,
+) {
+	bytes, e = binary.Marshal(&p.RFC7540WindowUpdateFormat)
+
+	return
+}
+
+func (p *RFC7540WindowUpdatePayload) UnmarshalRFC7540Payload(
+	header RFC7540FrameHeaderFormat, payload []byte,
+) (
+	e error,
+) {
+	if len(payload) != rfc7540WindowUpdateLengthInBytes {
+		e = ErrRFC7540FrameSizeInvalid
+
+		return
+	}
+
+	e = binary.Unmarshal(payload, &p.RFC7540WindowUpdateFormat)
+
+	return
+}
+
+type RFC7540ContinuationPayload struct {
+	HeaderBlockFragment []byte
+}
+
+func (p *RFC7540ContinuationPayload) RFC7540FrameType() uint8 {
+	return RFC7540FrameTypeCONTINUATION
+}
+
+func (p *RFC7540ContinuationPayload) MarshalRFC7540Payload(
+	header *RFC7540FrameHeaderFormat,
+) (
+	bytes []byte, e error,
+) {
+	bytes = p.HeaderBlockFragment
+
+	return
+}
+
+func (p *RFC7540ContinuationPayload) UnmarshalRFC7540Payload(
+	header RFC7540FrameHeaderFormat, payload []byte,
+) (
+	e error,
+) {
+	p.HeaderBlockFragment = payload
+
+	return
+}
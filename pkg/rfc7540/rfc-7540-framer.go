@@ -0,0 +1,184 @@
+package rfc7540
+
+import (
+	"errors"
+	"io"
+
+	"github.com/encodingx/binary"
+)
+
+// RFC7540Framer reads HTTP/2 frames from a stream and writes them to another,
+// enforcing SETTINGS_MAX_FRAME_SIZE in each direction.
+type RFC7540Framer struct {
+	reader            io.Reader
+	writer            io.Writer
+	maxReadFrameSize  uint32
+	maxWriteFrameSize uint32
+	header            [RFC7540FrameHeaderLengthInBytes]byte
+}
+
+var (
+	ErrRFC7540FrameSizeExceeded = errors.New(
+		"The length of an HTTP/2 frame payload should not exceed " +
+			"SETTINGS_MAX_FRAME_SIZE. " +
+			"The frame is too large.",
+	)
+
+	ErrRFC7540MaxFrameSizeInvalid = errors.New(
+		"SETTINGS_MAX_FRAME_SIZE should be between 2^14 and 2^24-1. " +
+			"The maximum frame size is out of range.",
+	)
+)
+
+// NewRFC7540Framer returns a framer over reader and writer,
+// either of which may be nil if frames are only written or only read.
+// Both maximum frame sizes are initially RFC7540DefaultMaxFrameSize.
+func NewRFC7540Framer(reader io.Reader, writer io.Writer) (
+	f *RFC7540Framer,
+) {
+	f = &RFC7540Framer{
+		reader:            reader,
+		writer:            writer,
+		maxReadFrameSize:  RFC7540DefaultMaxFrameSize,
+		maxWriteFrameSize: RFC7540DefaultMaxFrameSize,
+	}
+
+	return
+}
+
+// SetMaxReadFrameSize sets the largest frame payload accepted by ReadFrame,
+// as advertised in SETTINGS_MAX_FRAME_SIZE to the peer.
+func (f *RFC7540Framer) SetMaxReadFrameSize(size uint32) (e error) {
+	e = rfc7540ValidateMaxFrameSize(size)
+	if e != nil {
+		return
+	}
+
+	f.maxReadFrameSize = size
+
+	return
+}
+
+// SetMaxWriteFrameSize sets the largest frame payload accepted by WriteFrame,
+// as advertised in SETTINGS_MAX_FRAME_SIZE by the peer.
+func (f *RFC7540Framer) SetMaxWriteFrameSize(size uint32) (e error) {
+	e = rfc7540ValidateMaxFrameSize(size)
+	if e != nil {
+		return
+	}
+
+	f.maxWriteFrameSize = size
+
+	return
+}
+
+// ReadFrame reads the next frame without decoding its payload.
+// Frames larger than the maximum read frame size are rejected
+// before their payloads are read.
+// At the end of the stream it returns io.EOF,
+// or io.ErrUnexpectedEOF if the stream ends within a frame.
+func (f *RFC7540Framer) ReadFrame() (frame RFC7540Frame, e error) {
+	_, e = io.ReadFull(f.reader, f.header[:])
+	if e != nil {
+		return
+	}
+
+	e = binary.Unmarshal(f.header[:], &frame.RFC7540FrameHeaderFormat)
+	if e != nil {
+		return
+	}
+
+	// > An endpoint MUST send an error code of FRAME_SIZE_ERROR if a frame
+	// > exceeds the size defined in SETTINGS_MAX_FRAME_SIZE, exceeds any
+	// > limit defined for the frame type, or is too small to contain
+	// > mandatory frame data.
+
+	if frame.Length > f.maxReadFrameSize {
+		e = ErrRFC7540FrameSizeExceeded
+
+		return
+	}
+
+	frame.Payload = make([]byte, frame.Length)
+
+	_, e = io.ReadFull(f.reader, frame.Payload)
+	if e != nil {
+		if e == io.EOF {
+			e = io.ErrUnexpectedEOF
+		}
+
+		return
+	}
+
+	return
+}
+
+// WriteFrame encodes and writes a frame, setting the Type and Length
+// of the header from the payload.
+func (f *RFC7540Framer) WriteFrame(header RFC7540FrameHeaderFormat,
+	payload RFC7540FramePayload,
+) (
+	e error,
+) {
+	var (
+		bytes []byte
+	)
+
+	bytes, e = MarshalRFC7540Frame(&header, payload)
+	if e != nil {
+		return
+	}
+
+	if header.Length > f.maxWriteFrameSize {
+		e = ErrRFC7540FrameSizeExceeded
+
+		return
+	}
+
+	_, e = f.writer.Write(bytes)
+	if e != nil {
+		return
+	}
+
+	return
+}
+
+// WriteRawFrame writes a frame with an already encoded payload,
+// such as one of an extension type, setting the Length of its header.
+func (f *RFC7540Framer) WriteRawFrame(frame RFC7540Frame) (e error) {
+	var (
+		bytes []byte
+	)
+
+	if len(frame.Payload) > int(f.maxWriteFrameSize) {
+		e = ErrRFC7540FrameSizeExceeded
+
+		return
+	}
+
+	frame.Length = uint32(len(frame.Payload))
+
+	bytes, e = binary.Marshal(&frame.RFC7540FrameHeaderFormat)
+	if e != nil {
+		return
+	}
+
+	_, e = f.writer.Write(append(bytes, frame.Payload...))
+	if e != nil {
+		return
+	}
+
+	return
+}
+
+func rfc7540ValidateMaxFrameSize(size uint32) (e error) {
+	// > The initial value is 2^14 (16,384) octets.  The value advertised
+	// > by an endpoint MUST be between this initial value and the maximum
+	// > allowed frame size (2^24-1 or 16,777,215 octets), inclusive.
+
+	if size < RFC7540DefaultMaxFrameSize || size > RFC7540MaxMaxFrameSize {
+		e = ErrRFC7540MaxFrameSizeInvalid
+	}
+
+	return
+}
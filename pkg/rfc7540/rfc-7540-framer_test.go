@@ -0,0 +1,243 @@
+package rfc7540
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRFC7540FramerSettings(t *testing.T) {
+	var (
+		buffer  bytes.Buffer
+		e       error
+		framer  *RFC7540Framer
+		frame   RFC7540Frame
+		header  RFC7540FrameHeaderFormat
+		payload RFC7540FramePayload
+	)
+
+	framer = NewRFC7540Framer(&buffer, &buffer)
+
+	e = framer.WriteFrame(header,
+		&RFC7540SettingsPayload{
+			Settings: []RFC7540SettingFormat{
+				{
+					RFC7540SettingFormatWord0{RFC7540SettingsMaxFrameSize},
+					RFC7540SettingFormatWord1{1 << 15},
+				},
+			},
+		},
+	)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]byte{
+			0x00, 0x00, 0x06, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0x00, 0x05, 0x00, 0x00, 0x80, 0x00,
+		},
+		buffer.Bytes(),
+	)
+
+	frame, e = framer.ReadFrame()
+
+	assert.Nil(t, e)
+
+	payload, e = frame.DecodePayload()
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint32(1<<15),
+		payload.(*RFC7540SettingsPayload).Settings[0].Value,
+	)
+
+	_, e = framer.ReadFrame()
+
+	assert.Equal(t,
+		io.EOF, e,
+	)
+
+	header.Flags = RFC7540FlagACK
+	header.StreamIdentifier = 1
+
+	e = framer.WriteFrame(header, &RFC7540SettingsPayload{})
+
+	assert.Nil(t, e)
+
+	frame, e = framer.ReadFrame()
+
+	assert.Nil(t, e)
+
+	_, e = frame.DecodePayload()
+
+	assert.Equal(t,
+		ErrRFC7540StreamIdentifierInvalid, e,
+	)
+}
+
+func TestRFC7540FramerHeadersPaddedPriority(t *testing.T) {
+	var (
+		buffer  bytes.Buffer
+		e       error
+		framer  *RFC7540Framer
+		frame   RFC7540Frame
+		header  RFC7540FrameHeaderFormat
+		headers *RFC7540HeadersPayload
+		payload RFC7540FramePayload
+	)
+
+	framer = NewRFC7540Framer(&buffer, &buffer)
+
+	header.Flags = RFC7540FlagENDHEADERS | RFC7540FlagPADDED |
+		RFC7540FlagPRIORITY
+	header.StreamIdentifier = 3
+
+	headers = &RFC7540HeadersPayload{
+		PadLength:           2,
+		HeaderBlockFragment: []byte{0x82, 0x86},
+	}
+
+	headers.E = true
+	headers.StreamDependency = 1
+	headers.Weight = 15
+
+	e = framer.WriteFrame(header, headers)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]byte{
+			0x00, 0x00, 0x0a, 0x01, 0x2c, 0x00, 0x00, 0x00, 0x03,
+			0x02,
+			0x80, 0x00, 0x00, 0x01, 0x0f,
+			0x82, 0x86,
+			0x00, 0x00,
+		},
+		buffer.Bytes(),
+	)
+
+	frame, e = framer.ReadFrame()
+
+	assert.Nil(t, e)
+
+	payload, e = frame.DecodePayload()
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		headers, payload,
+	)
+
+	// A Pad Length equal to the payload length is a PROTOCOL_ERROR.
+
+	frame.Payload = []byte{0x02, 0x00}
+	frame.Flags = RFC7540FlagPADDED
+
+	_, e = frame.DecodePayload()
+
+	assert.Equal(t,
+		ErrRFC7540PaddingTooLong, e,
+	)
+}
+
+func TestRFC7540FramerFixedSizeFrames(t *testing.T) {
+	var (
+		e       error
+		frame   RFC7540Frame
+		payload RFC7540FramePayload
+	)
+
+	frame.Type = RFC7540FrameTypeWINDOWUPDATE
+	frame.Payload = []byte{0x80, 0x00, 0x10, 0x00}
+
+	payload, e = frame.DecodePayload()
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint32(0x1000),
+		payload.(*RFC7540WindowUpdatePayload).WindowSizeIncrement,
+	)
+
+	frame.Type = RFC7540FrameTypePING
+	frame.Payload = []byte{0x00}
+
+	_, e = frame.DecodePayload()
+
+	assert.Equal(t,
+		ErrRFC7540FrameSizeInvalid, e,
+	)
+
+	frame.Type = 0xfa
+
+	_, e = frame.DecodePayload()
+
+	assert.Equal(t,
+		ErrRFC7540FrameTypeUnknown, e,
+	)
+}
+
+func TestRFC7540FramerMaxFrameSize(t *testing.T) {
+	var (
+		buffer bytes.Buffer
+		e      error
+		framer *RFC7540Framer
+	)
+
+	framer = NewRFC7540Framer(&buffer, &buffer)
+
+	assert.Equal(t,
+		ErrRFC7540MaxFrameSizeInvalid,
+		framer.SetMaxReadFrameSize(RFC7540DefaultMaxFrameSize-1),
+	)
+
+	assert.Equal(t,
+		ErrRFC7540MaxFrameSizeInvalid,
+		framer.SetMaxWriteFrameSize(RFC7540MaxMaxFrameSize+1),
+	)
+
+	e = framer.WriteFrame(
+		RFC7540FrameHeaderFormat{
+			RFC7540FrameHeaderFormatWord3: RFC7540FrameHeaderFormatWord3{
+				StreamIdentifier: 1,
+			},
+		},
+		&RFC7540DataPayload{
+			Data: make([]byte, RFC7540DefaultMaxFrameSize+1),
+		},
+	)
+
+	assert.Equal(t,
+		ErrRFC7540FrameSizeExceeded, e,
+	)
+
+	assert.Nil(t,
+		framer.SetMaxWriteFrameSize(RFC7540DefaultMaxFrameSize+1),
+	)
+
+	e = framer.WriteRawFrame(
+		RFC7540Frame{
+			Payload: make([]byte, RFC7540DefaultMaxFrameSize+1),
+		},
+	)
+
+	assert.Nil(t, e)
+
+	_, e = framer.ReadFrame()
+
+	assert.Equal(t,
+		ErrRFC7540FrameSizeExceeded, e,
+	)
+
+	buffer.Reset()
+	buffer.Write([]byte{0x00, 0x00, 0x04, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00})
+
+	_, e = framer.ReadFrame()
+
+	assert.Equal(t,
+		io.ErrUnexpectedEOF, e,
+	)
+}
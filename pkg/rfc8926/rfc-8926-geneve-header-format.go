@@ -0,0 +1,296 @@
+package rfc8926
+
+import (
+	"errors"
+
+	"github.com/encodingx/binary"
+	"github.com/encodingx/binary/pkg/tlv"
+)
+
+type RFC8926GeneveHeaderFormat struct {
+	// Reference: Section 3.4 "Tunnel Header Fields" of
+	// RFC 8926 Geneve: Generic Network Virtualization Encapsulation
+	// https://datatracker.ietf.org/doc/html/rfc8926#section-3.4
+
+	// > Geneve Header:
+	// >  0                   1                   2                   3
+	// >  0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+	// > +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	// > |Ver|  Opt Len  |O|C|    Rsvd.  |          Protocol Type        |
+	// > +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	// > |        Virtual Network Identifier (VNI)       |    Reserved   |
+	// > +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	// > |                                                               |
+	// > ~                    Variable-Length Options                    ~
+	// > |                                                               |
+	// > +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+
+	RFC8926GeneveHeaderFormatWord0 `word:"32"`
+	RFC8926GeneveHeaderFormatWord1 `word:"32"`
+}
+
+type RFC8926GeneveHeaderFormatWord0 struct {
+	Version uint8 `bitfield:"2"`
+	// > Version (Ver) (2 bits):  The current version number is 0.
+
+	OptLen uint8 `bitfield:"6"`
+	// > Opt Len (6 bits):  The length of the option fields, expressed in
+	// >    4-byte multiples, not including the 8-byte fixed tunnel header.
+
+	O bool `bitfield:"1"`
+	// > O (1 bit):  Control packet.  This packet contains a control message.
+
+	C bool `bitfield:"1"`
+	// > C (1 bit):  Critical options present.  One or more options has the
+	// >    critical bit set (see Section 3.5).
+
+	Reserved0 uint8 `bitfield:"6"`
+
+	ProtocolType uint16 `bitfield:"16"`
+	// > Protocol Type (16 bits):  The type of protocol data unit appearing
+	// >    after the Geneve header.  This follows the Ethertype [ETYPES]
+	// >    convention, with Ethernet itself being represented by the value
+	// >    0x6558.
+}
+
+type RFC8926GeneveHeaderFormatWord1 struct {
+	VNI uint32 `bitfield:"24"`
+	// > Virtual Network Identifier (VNI) (24 bits):  An identifier for a
+	// >    unique element of a virtual network.
+
+	Reserved1 uint8 `bitfield:"8"`
+}
+
+const (
+	RFC8926GeneveHeaderVersion = 0
+)
+
+const (
+	RFC8926GeneveHeaderLengthInBytes   = 8
+	RFC8926GeneveOptionUnitInBytes     = 4
+	RFC8926GeneveOptionsMaxLengthUnits = 1<<6 - 1
+)
+
+const (
+	RFC8926GeneveProtocolTypeEthernet = 0x6558
+	RFC8926GeneveProtocolTypeIPv4     = 0x0800
+	RFC8926GeneveProtocolTypeIPv6     = 0x86dd
+)
+
+const (
+	RFC8926GeneveUDPDestinationPort = 6081
+)
+
+type RFC8926GeneveOptionHeaderFormat struct {
+	// Reference: Section 3.5 "Tunnel Options"
+
+	// >  0                   1                   2                   3
+	// >  0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+	// > +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	// > |          Option Class         |      Type     |R|R|R| Length  |
+	// > +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	// > |                      Variable-Length Option Data              |
+	// > +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+	//
+	// As a TLV header, the type of an option is its Option Class
+	// and Type concatenated (see RFC8926GeneveOptionType).
+
+	RFC8926GeneveOptionHeaderFormatWord0 `word:"32"`
+}
+
+type RFC8926GeneveOptionHeaderFormatWord0 struct {
+	OptionClass uint16 `bitfield:"16"`
+	// > Option Class (16 bits):  Namespace for the Type field.
+
+	Type uint8 `bitfield:"8"`
+	// > Type (8 bits):  Type indicating the format of the data contained in
+	// >    this option.  Options are primarily designed to encourage future
+	// >    extensibility and interoperability.  The high-order bit of the
+	// >    Type field indicates that the option is critical.
+
+	Reserved uint8 `bitfield:"3"`
+
+	Length uint8 `bitfield:"5"`
+	// > Length (5 bits):  Length of the option, expressed in 4-byte
+	// >    multiples excluding the Option Header.
+}
+
+const (
+	RFC8926GeneveOptionTypeCritical = 0x80
+)
+
+func RFC8926GeneveOptionType(optionClass uint16, optionType uint8) uint64 {
+	return uint64(optionClass)<<8 | uint64(optionType)
+}
+
+func (h *RFC8926GeneveOptionHeaderFormat) TLVType() uint64 {
+	return RFC8926GeneveOptionType(h.OptionClass, h.Type)
+}
+
+func (h *RFC8926GeneveOptionHeaderFormat) TLVLength() int {
+	return int(h.Length) * RFC8926GeneveOptionUnitInBytes
+}
+
+func (h *RFC8926GeneveOptionHeaderFormat) SetTLVType(tlvType uint64) {
+	h.OptionClass = uint16(tlvType >> 8)
+	h.Type = uint8(tlvType)
+
+	return
+}
+
+func (h *RFC8926GeneveOptionHeaderFormat) SetTLVLength(length int) {
+	// Lengths that are not multiples of four are left unrepresentable,
+	// for the TLV codec to reject.
+
+	h.Length = uint8(length / RFC8926GeneveOptionUnitInBytes)
+
+	if length%RFC8926GeneveOptionUnitInBytes != 0 {
+		h.Length = ^uint8(0)
+	}
+
+	return
+}
+
+var (
+	ErrRFC8926GeneveOptionsTooLong = errors.New(
+		"The options of a Geneve header should be at most 252 bytes long, " +
+			"as expressed by the 6-bit Opt Len field. " +
+			"The options are too long.",
+	)
+
+	ErrRFC8926GenevePacketTooShort = errors.New(
+		"A Geneve packet should be at least as long as its fixed header " +
+			"and the options length indicated by it. " +
+			"The packet ends before the options do.",
+	)
+
+	ErrRFC8926GeneveVersionUnsupported = errors.New(
+		"The version number of a Geneve header should be 0. " +
+			"The header has a version number that is not supported.",
+	)
+)
+
+func NewRFC8926GeneveOptionCodec() (c *tlv.TLVCodec, e error) {
+	c, e = tlv.NewTLVCodec(&RFC8926GeneveOptionHeaderFormat{})
+
+	return
+}
+
+type RFC8926GenevePacket struct {
+	RFC8926GeneveHeaderFormat
+
+	Options tlv.TLVList
+	Payload []byte
+}
+
+// Marshal sets the Opt Len and C fields of the header
+// from the options before marshalling the packet.
+// Options are encoded by the codec, which may be nil
+// if none of the options hold format-structs.
+func (p *RFC8926GenevePacket) Marshal(codec *tlv.TLVCodec) (
+	bytes []byte, e error,
+) {
+	var (
+		headerBytes  []byte
+		option       tlv.TLV
+		optionsBytes []byte
+	)
+
+	if codec == nil {
+		codec, e = NewRFC8926GeneveOptionCodec()
+		if e != nil {
+			return
+		}
+	}
+
+	optionsBytes, e = codec.Marshal(p.Options)
+	if e != nil {
+		return
+	}
+
+	if len(optionsBytes) >
+		RFC8926GeneveOptionsMaxLengthUnits*RFC8926GeneveOptionUnitInBytes {
+		e = ErrRFC8926GeneveOptionsTooLong
+
+		return
+	}
+
+	p.OptLen = uint8(len(optionsBytes) / RFC8926GeneveOptionUnitInBytes)
+
+	p.C = false
+
+	for _, option = range p.Options {
+		if uint8(option.Type)&RFC8926GeneveOptionTypeCritical != 0 {
+			p.C = true
+		}
+	}
+
+	headerBytes, e = binary.Marshal(&p.RFC8926GeneveHeaderFormat)
+	if e != nil {
+		return
+	}
+
+	bytes = make([]byte, 0,
+		len(headerBytes)+len(optionsBytes)+len(p.Payload),
+	)
+
+	bytes = append(bytes, headerBytes...)
+	bytes = append(bytes, optionsBytes...)
+	bytes = append(bytes, p.Payload...)
+
+	return
+}
+
+func (p *RFC8926GenevePacket) Unmarshal(bytes []byte, codec *tlv.TLVCodec) (
+	e error,
+) {
+	var (
+		optionsEnd int
+	)
+
+	if len(bytes) < RFC8926GeneveHeaderLengthInBytes {
+		e = ErrRFC8926GenevePacketTooShort
+
+		return
+	}
+
+	e = binary.Unmarshal(bytes[:RFC8926GeneveHeaderLengthInBytes],
+		&p.RFC8926GeneveHeaderFormat,
+	)
+	if e != nil {
+		return
+	}
+
+	if p.Version != RFC8926GeneveHeaderVersion {
+		e = ErrRFC8926GeneveVersionUnsupported
+
+		return
+	}
+
+	optionsEnd = RFC8926GeneveHeaderLengthInBytes +
+		int(p.OptLen)*RFC8926GeneveOptionUnitInBytes
+
+	if len(bytes) < optionsEnd {
+		e = ErrRFC8926GenevePacketTooShort
+
+		return
+	}
+
+	if codec == nil {
+		codec, e = NewRFC8926GeneveOptionCodec()
+		if e != nil {
+			return
+		}
+	}
+
+	p.Options, e = codec.Unmarshal(
+		bytes[RFC8926GeneveHeaderLengthInBytes:optionsEnd],
+	)
+	if e != nil {
+		return
+	}
+
+	p.Payload = bytes[optionsEnd:]
+
+	return
+}
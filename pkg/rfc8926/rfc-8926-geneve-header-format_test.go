@@ -0,0 +1,94 @@
+package rfc8926
+
+import (
+	"testing"
+
+	"github.com/encodingx/binary/pkg/tlv"
+	"github.com/stretchr/testify/assert"
+)
+
+type testOptionFormat struct {
+	testOptionFormatWord0 `word:"32"`
+}
+
+type testOptionFormatWord0 struct {
+	Value uint32 `bitfield:"32"`
+}
+
+func TestRFC8926GenevePacketRoundTrip(t *testing.T) {
+	const (
+		optionClass = 0x0102
+		optionType  = RFC8926GeneveOptionTypeCritical | 0x03
+	)
+
+	var (
+		bytes   []byte
+		codec   *tlv.TLVCodec
+		e       error
+		packet  RFC8926GenevePacket
+		packet1 RFC8926GenevePacket
+	)
+
+	codec, e = NewRFC8926GeneveOptionCodec()
+
+	assert.Nil(t, e)
+
+	e = codec.Register(RFC8926GeneveOptionType(optionClass, optionType),
+		(*testOptionFormat)(nil),
+	)
+
+	assert.Nil(t, e)
+
+	packet.ProtocolType = RFC8926GeneveProtocolTypeEthernet
+	packet.VNI = 0x123456
+	packet.Options = tlv.TLVList{
+		{
+			Type:  RFC8926GeneveOptionType(optionClass, optionType),
+			Value: &testOptionFormat{testOptionFormatWord0{0xdeadbeef}},
+		},
+		{
+			Type: RFC8926GeneveOptionType(optionClass, 0x04),
+			Raw:  []byte{1, 2, 3, 4, 5, 6, 7, 8},
+		},
+	}
+	packet.Payload = []byte{0xff}
+
+	bytes, e = packet.Marshal(codec)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]byte{
+			0b00000101, 0b01000000, 0x65, 0x58,
+			0x12, 0x34, 0x56, 0x00,
+			0x01, 0x02, 0x83, 0x01,
+			0xde, 0xad, 0xbe, 0xef,
+			0x01, 0x02, 0x04, 0x02,
+			1, 2, 3, 4, 5, 6, 7, 8,
+			0xff,
+		},
+		bytes,
+	)
+
+	e = packet1.Unmarshal(bytes, codec)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		packet, packet1,
+	)
+
+	packet.Options[1].Raw = []byte{1, 2, 3}
+
+	_, e = packet.Marshal(codec)
+
+	assert.Equal(t,
+		tlv.ErrTLVHeaderOverflow, e,
+	)
+
+	e = packet1.Unmarshal(bytes[:12], codec)
+
+	assert.Equal(t,
+		ErrRFC8926GenevePacketTooShort, e,
+	)
+}
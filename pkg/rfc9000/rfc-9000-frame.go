@@ -0,0 +1,802 @@
+package rfc9000
+
+import (
+	"errors"
+
+	"github.com/encodingx/binary"
+)
+
+// Reference: Section 12.4 "Frames and Frame Types" and
+// Section 19 "Frame Types and Formats" of RFC 9000
+
+// > The payload of QUIC packets, after removing packet protection,
+// > consists of a sequence of complete frames, as shown in Figure 11.
+// > Version Negotiation, Stateless Reset, and Retry packets do not contain
+// > frames.
+// >
+// > Frame {
+// >   Frame Type (i),
+// >   Type-Dependent Fields (..),
+// > }
+
+const (
+	RFC9000FrameTypePadding            = 0x00
+	RFC9000FrameTypePing               = 0x01
+	RFC9000FrameTypeACK                = 0x02
+	RFC9000FrameTypeACKECN             = 0x03
+	RFC9000FrameTypeResetStream        = 0x04
+	RFC9000FrameTypeStopSending        = 0x05
+	RFC9000FrameTypeCrypto             = 0x06
+	RFC9000FrameTypeNewToken           = 0x07
+	RFC9000FrameTypeStream             = 0x08
+	RFC9000FrameTypeStreamMax          = 0x0f
+	RFC9000FrameTypeMaxData            = 0x10
+	RFC9000FrameTypeMaxStreamData      = 0x11
+	RFC9000FrameTypeMaxStreamsBidi     = 0x12
+	RFC9000FrameTypeMaxStreamsUni      = 0x13
+	RFC9000FrameTypeDataBlocked        = 0x14
+	RFC9000FrameTypeStreamDataBlocked  = 0x15
+	RFC9000FrameTypeStreamsBlockedBidi = 0x16
+	RFC9000FrameTypeStreamsBlockedUni  = 0x17
+	RFC9000FrameTypeNewConnectionID    = 0x18
+	RFC9000FrameTypeRetireConnectionID = 0x19
+	RFC9000FrameTypePathChallenge      = 0x1a
+	RFC9000FrameTypePathResponse       = 0x1b
+	RFC9000FrameTypeConnectionClose    = 0x1c
+	RFC9000FrameTypeConnectionCloseApp = 0x1d
+	RFC9000FrameTypeHandshakeDone      = 0x1e
+)
+
+const (
+	rfc9000StatelessResetTokenLength = 16
+	rfc9000PathChallengeDataLength   = 8
+)
+
+var (
+	ErrRFC9000FrameTruncated = errors.New(
+		"A QUIC frame should be complete within the packet payload. " +
+			"The payload ends before the frame does.",
+	)
+
+	ErrRFC9000FrameTypeUnknown = errors.New(
+		"The Frame Type of a QUIC frame should be one defined by RFC 9000. " +
+			"The frame is of an unknown type.",
+	)
+)
+
+type RFC9000Frame interface {
+	RFC9000FrameType() uint64
+}
+
+type RFC9000PaddingFrame struct {
+	// Length is the number of consecutive PADDING frames,
+	// which are collected into one.
+	Length int
+}
+
+type RFC9000PingFrame struct {
+}
+
+type RFC9000ACKFrame struct {
+	LargestAcknowledged uint64
+	ACKDelay            uint64
+	FirstACKRange       uint64
+	ACKRanges           []RFC9000ACKRange
+
+	// ECN is set for ACK frames of type 0x03,
+	// which carry ECN counts.
+	ECN   bool
+	ECT0  uint64
+	ECT1  uint64
+	ECNCE uint64
+}
+
+type RFC9000ACKRange struct {
+	Gap            uint64
+	ACKRangeLength uint64
+}
+
+type RFC9000ResetStreamFrame struct {
+	StreamID                     uint64
+	ApplicationProtocolErrorCode uint64
+	FinalSize                    uint64
+}
+
+type RFC9000StopSendingFrame struct {
+	StreamID                     uint64
+	ApplicationProtocolErrorCode uint64
+}
+
+type RFC9000CryptoFrame struct {
+	Offset uint64
+	Data   []byte
+}
+
+type RFC9000NewTokenFrame struct {
+	Token []byte
+}
+
+type RFC9000StreamFrameTypeFormat struct {
+	// Reference: Section 19.8 "STREAM Frames"
+
+	// > The Type field in the STREAM frame takes the form 0b00001XXX (or
+	// > the set of values from 0x08 to 0x0f).  The three low-order bits of
+	// > the frame type determine the fields that are present in the frame:
+	// >
+	// > *  The OFF bit (0x04) in the frame type is set to indicate that
+	// >    there is an Offset field present.
+	// > *  The LEN bit (0x02) in the frame type is set to indicate that
+	// >    there is a Length field present.
+	// > *  The FIN bit (0x01) indicates that the frame marks the end of the
+	// >    stream.
+
+	RFC9000StreamFrameTypeFormatWord0 `word:"8"`
+}
+
+type RFC9000StreamFrameTypeFormatWord0 struct {
+	Prefix uint8 `bitfield:"5"`
+	OFF    bool  `bitfield:"1"`
+	LEN    bool  `bitfield:"1"`
+	FIN    bool  `bitfield:"1"`
+}
+
+type RFC9000StreamFrame struct {
+	RFC9000StreamFrameTypeFormat
+
+	StreamID uint64
+	Offset   uint64
+	Data     []byte
+}
+
+type RFC9000MaxDataFrame struct {
+	MaximumData uint64
+}
+
+type RFC9000MaxStreamDataFrame struct {
+	StreamID          uint64
+	MaximumStreamData uint64
+}
+
+type RFC9000MaxStreamsFrame struct {
+	Bidirectional  bool
+	MaximumStreams uint64
+}
+
+type RFC9000DataBlockedFrame struct {
+	MaximumData uint64
+}
+
+type RFC9000StreamDataBlockedFrame struct {
+	StreamID          uint64
+	MaximumStreamData uint64
+}
+
+type RFC9000StreamsBlockedFrame struct {
+	Bidirectional  bool
+	MaximumStreams uint64
+}
+
+type RFC9000NewConnectionIDFrame struct {
+	SequenceNumber      uint64
+	RetirePriorTo       uint64
+	ConnectionID        []byte
+	StatelessResetToken []byte
+}
+
+type RFC9000RetireConnectionIDFrame struct {
+	SequenceNumber uint64
+}
+
+type RFC9000PathChallengeFrame struct {
+	Data []byte
+}
+
+type RFC9000PathResponseFrame struct {
+	Data []byte
+}
+
+type RFC9000ConnectionCloseFrame struct {
+	// Application is set for CONNECTION_CLOSE frames of type 0x1d,
+	// which signal errors at the application layer
+	// and have no Frame Type field.
+	Application  bool
+	ErrorCode    uint64
+	FrameType    uint64
+	ReasonPhrase []byte
+}
+
+type RFC9000HandshakeDoneFrame struct {
+}
+
+func (RFC9000PaddingFrame) RFC9000FrameType() uint64 {
+	return RFC9000FrameTypePadding
+}
+
+func (RFC9000PingFrame) RFC9000FrameType() uint64 {
+	return RFC9000FrameTypePing
+}
+
+func (f RFC9000ACKFrame) RFC9000FrameType() uint64 {
+	if f.ECN {
+		return RFC9000FrameTypeACKECN
+	}
+
+	return RFC9000FrameTypeACK
+}
+
+func (RFC9000ResetStreamFrame) RFC9000FrameType() uint64 {
+	return RFC9000FrameTypeResetStream
+}
+
+func (RFC9000StopSendingFrame) RFC9000FrameType() uint64 {
+	return RFC9000FrameTypeStopSending
+}
+
+func (RFC9000CryptoFrame) RFC9000FrameType() uint64 {
+	return RFC9000FrameTypeCrypto
+}
+
+func (RFC9000NewTokenFrame) RFC9000FrameType() uint64 {
+	return RFC9000FrameTypeNewToken
+}
+
+func (f RFC9000StreamFrame) RFC9000FrameType() uint64 {
+	var (
+		frameType uint64
+	)
+
+	frameType = RFC9000FrameTypeStream
+
+	if f.OFF {
+		frameType |= 0x04
+	}
+
+	if f.LEN {
+		frameType |= 0x02
+	}
+
+	if f.FIN {
+		frameType |= 0x01
+	}
+
+	return frameType
+}
+
+func (RFC9000MaxDataFrame) RFC9000FrameType() uint64 {
+	return RFC9000FrameTypeMaxData
+}
+
+func (RFC9000MaxStreamDataFrame) RFC9000FrameType() uint64 {
+	return RFC9000FrameTypeMaxStreamData
+}
+
+func (f RFC9000MaxStreamsFrame) RFC9000FrameType() uint64 {
+	if f.Bidirectional {
+		return RFC9000FrameTypeMaxStreamsBidi
+	}
+
+	return RFC9000FrameTypeMaxStreamsUni
+}
+
+func (RFC9000DataBlockedFrame) RFC9000FrameType() uint64 {
+	return RFC9000FrameTypeDataBlocked
+}
+
+func (RFC9000StreamDataBlockedFrame) RFC9000FrameType() uint64 {
+	return RFC9000FrameTypeStreamDataBlocked
+}
+
+func (f RFC9000StreamsBlockedFrame) RFC9000FrameType() uint64 {
+	if f.Bidirectional {
+		return RFC9000FrameTypeStreamsBlockedBidi
+	}
+
+	return RFC9000FrameTypeStreamsBlockedUni
+}
+
+func (RFC9000NewConnectionIDFrame) RFC9000FrameType() uint64 {
+	return RFC9000FrameTypeNewConnectionID
+}
+
+func (RFC9000RetireConnectionIDFrame) RFC9000FrameType() uint64 {
+	return RFC9000FrameTypeRetireConnectionID
+}
+
+func (RFC9000PathChallengeFrame) RFC9000FrameType() uint64 {
+	return RFC9000FrameTypePathChallenge
+}
+
+func (RFC9000PathResponseFrame) RFC9000FrameType() uint64 {
+	return RFC9000FrameTypePathResponse
+}
+
+func (f RFC9000ConnectionCloseFrame) RFC9000FrameType() uint64 {
+	if f.Application {
+		return RFC9000FrameTypeConnectionCloseApp
+	}
+
+	return RFC9000FrameTypeConnectionClose
+}
+
+func (RFC9000HandshakeDoneFrame) RFC9000FrameType() uint64 {
+	return RFC9000FrameTypeHandshakeDone
+}
+
+// ParseRFC9000Frames parses the frames of a packet payload
+// from which packet protection has been removed.
+// Frames of the payload share memory with it.
+func ParseRFC9000Frames(payload []byte) (frames []RFC9000Frame, e error) {
+	var (
+		frame RFC9000Frame
+		i     int
+		n     int
+	)
+
+	for i < len(payload) {
+		frame, n, e = ParseRFC9000Frame(payload[i:])
+		if e != nil {
+			return
+		}
+
+		frames = append(frames, frame)
+
+		i += n
+	}
+
+	return
+}
+
+// ParseRFC9000Frame parses the frame at the start of bytes,
+// returning the number of bytes it occupies.
+func ParseRFC9000Frame(bytes []byte) (frame RFC9000Frame, n int, e error) {
+	var (
+		frameType uint64
+		r         rfc9000FrameReader
+	)
+
+	r.bytes = bytes
+
+	frameType, e = r.varInt()
+	if e != nil {
+		return
+	}
+
+	switch {
+	case frameType == RFC9000FrameTypePadding:
+		frame = r.padding()
+
+	case frameType == RFC9000FrameTypePing:
+		frame = RFC9000PingFrame{}
+
+	case frameType == RFC9000FrameTypeACK ||
+		frameType == RFC9000FrameTypeACKECN:
+		frame, e = r.ack(frameType == RFC9000FrameTypeACKECN)
+
+	case frameType == RFC9000FrameTypeResetStream:
+		frame, e = r.resetStream()
+
+	case frameType == RFC9000FrameTypeStopSending:
+		frame, e = r.stopSending()
+
+	case frameType == RFC9000FrameTypeCrypto:
+		frame, e = r.crypto()
+
+	case frameType == RFC9000FrameTypeNewToken:
+		frame, e = r.newToken()
+
+	case frameType >= RFC9000FrameTypeStream &&
+		frameType <= RFC9000FrameTypeStreamMax:
+		frame, e = r.stream(uint8(frameType))
+
+	case frameType == RFC9000FrameTypeMaxData:
+		frame, e = r.maxData()
+
+	case frameType == RFC9000FrameTypeMaxStreamData:
+		frame, e = r.maxStreamData()
+
+	case frameType == RFC9000FrameTypeMaxStreamsBidi ||
+		frameType == RFC9000FrameTypeMaxStreamsUni:
+		frame, e = r.maxStreams(frameType == RFC9000FrameTypeMaxStreamsBidi)
+
+	case frameType == RFC9000FrameTypeDataBlocked:
+		frame, e = r.dataBlocked()
+
+	case frameType == RFC9000FrameTypeStreamDataBlocked:
+		frame, e = r.streamDataBlocked()
+
+	case frameType == RFC9000FrameTypeStreamsBlockedBidi ||
+		frameType == RFC9000FrameTypeStreamsBlockedUni:
+		frame, e = r.streamsBlocked(
+			frameType == RFC9000FrameTypeStreamsBlockedBidi,
+		)
+
+	case frameType == RFC9000FrameTypeNewConnectionID:
+		frame, e = r.newConnectionID()
+
+	case frameType == RFC9000FrameTypeRetireConnectionID:
+		frame, e = r.retireConnectionID()
+
+	case frameType == RFC9000FrameTypePathChallenge:
+		frame, e = r.pathChallenge()
+
+	case frameType == RFC9000FrameTypePathResponse:
+		frame, e = r.pathResponse()
+
+	case frameType == RFC9000FrameTypeConnectionClose ||
+		frameType == RFC9000FrameTypeConnectionCloseApp:
+		frame, e = r.connectionClose(
+			frameType == RFC9000FrameTypeConnectionCloseApp,
+		)
+
+	case frameType == RFC9000FrameTypeHandshakeDone:
+		frame = RFC9000HandshakeDoneFrame{}
+
+	default:
+		e = ErrRFC9000FrameTypeUnknown
+	}
+
+	if e != nil {
+		frame = nil
+
+		return
+	}
+
+	n = r.i
+
+	return
+}
+
+type rfc9000FrameReader struct {
+	bytes []byte
+	i     int
+}
+
+func (r *rfc9000FrameReader) varInt() (value uint64, e error) {
+	var (
+		n int
+	)
+
+	value, n, e = ReadRFC9000VarInt(r.bytes[r.i:])
+	if e != nil {
+		e = ErrRFC9000FrameTruncated
+
+		return
+	}
+
+	r.i += n
+
+	return
+}
+
+func (r *rfc9000FrameReader) next(n uint64) (bytes []byte, e error) {
+	if n > uint64(len(r.bytes)-r.i) {
+		e = ErrRFC9000FrameTruncated
+
+		return
+	}
+
+	bytes = r.bytes[r.i : r.i+int(n)]
+
+	r.i += int(n)
+
+	return
+}
+
+func (r *rfc9000FrameReader) lengthPrefixed() (bytes []byte, e error) {
+	var (
+		length uint64
+	)
+
+	length, e = r.varInt()
+	if e != nil {
+		return
+	}
+
+	bytes, e = r.next(length)
+
+	return
+}
+
+func (r *rfc9000FrameReader) varInts(values ...*uint64) (e error) {
+	var (
+		value *uint64
+	)
+
+	for _, value = range values {
+		*value, e = r.varInt()
+		if e != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (r *rfc9000FrameReader) padding() (frame RFC9000PaddingFrame) {
+	frame.Length = 1
+
+	for r.i < len(r.bytes) && r.bytes[r.i] == RFC9000FrameTypePadding {
+		frame.Length++
+
+		r.i++
+	}
+
+	return
+}
+
+func (r *rfc9000FrameReader) ack(ecn bool) (frame RFC9000ACKFrame, e error) {
+	// > ACK Frame {
+	// >   Type (i) = 0x02..0x03,
+	// >   Largest Acknowledged (i),
+	// >   ACK Delay (i),
+	// >   ACK Range Count (i),
+	// >   First ACK Range (i),
+	// >   ACK Range (..) ...,
+	// >   [ECN Counts (..)],
+	// > }
+
+	var (
+		count    uint64
+		i        uint64
+		ackRange RFC9000ACKRange
+	)
+
+	e = r.varInts(&frame.LargestAcknowledged, &frame.ACKDelay, &count,
+		&frame.FirstACKRange,
+	)
+	if e != nil {
+		return
+	}
+
+	// Each range takes at least two bytes,
+	// which bounds the count before allocating.
+
+	if count > uint64(len(r.bytes)-r.i)/2 {
+		e = ErrRFC9000FrameTruncated
+
+		return
+	}
+
+	for i = 0; i < count; i++ {
+		e = r.varInts(&ackRange.Gap, &ackRange.ACKRangeLength)
+		if e != nil {
+			return
+		}
+
+		frame.ACKRanges = append(frame.ACKRanges, ackRange)
+	}
+
+	frame.ECN = ecn
+
+	if ecn {
+		e = r.varInts(&frame.ECT0, &frame.ECT1, &frame.ECNCE)
+		if e != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (r *rfc9000FrameReader) resetStream() (
+	frame RFC9000ResetStreamFrame, e error,
+) {
+	e = r.varInts(&frame.StreamID, &frame.ApplicationProtocolErrorCode,
+		&frame.FinalSize,
+	)
+
+	return
+}
+
+func (r *rfc9000FrameReader) stopSending() (
+	frame RFC9000StopSendingFrame, e error,
+) {
+	e = r.varInts(&frame.StreamID, &frame.ApplicationProtocolErrorCode)
+
+	return
+}
+
+func (r *rfc9000FrameReader) crypto() (frame RFC9000CryptoFrame, e error) {
+	e = r.varInts(&frame.Offset)
+	if e != nil {
+		return
+	}
+
+	frame.Data, e = r.lengthPrefixed()
+
+	return
+}
+
+func (r *rfc9000FrameReader) newToken() (frame RFC9000NewTokenFrame, e error) {
+	frame.Token, e = r.lengthPrefixed()
+
+	return
+}
+
+func (r *rfc9000FrameReader) stream(frameType uint8) (
+	frame RFC9000StreamFrame, e error,
+) {
+	// > STREAM Frame {
+	// >   Type (i) = 0x08..0x0f,
+	// >   Stream ID (i),
+	// >   [Offset (i)],
+	// >   [Length (i)],
+	// >   Stream Data (..),
+	// > }
+
+	e = binary.Unmarshal([]byte{frameType}, &frame.RFC9000StreamFrameTypeFormat)
+	if e != nil {
+		return
+	}
+
+	e = r.varInts(&frame.StreamID)
+	if e != nil {
+		return
+	}
+
+	if frame.OFF {
+		e = r.varInts(&frame.Offset)
+		if e != nil {
+			return
+		}
+	}
+
+	if frame.LEN {
+		frame.Data, e = r.lengthPrefixed()
+
+		return
+	}
+
+	// > When the LEN bit is set to 0, the Stream Data field consumes all
+	// > the remaining bytes in the packet.
+
+	frame.Data, e = r.next(uint64(len(r.bytes) - r.i))
+
+	return
+}
+
+func (r *rfc9000FrameReader) maxData() (frame RFC9000MaxDataFrame, e error) {
+	e = r.varInts(&frame.MaximumData)
+
+	return
+}
+
+func (r *rfc9000FrameReader) maxStreamData() (
+	frame RFC9000MaxStreamDataFrame, e error,
+) {
+	e = r.varInts(&frame.StreamID, &frame.MaximumStreamData)
+
+	return
+}
+
+func (r *rfc9000FrameReader) maxStreams(bidirectional bool) (
+	frame RFC9000MaxStreamsFrame, e error,
+) {
+	frame.Bidirectional = bidirectional
+
+	e = r.varInts(&frame.MaximumStreams)
+
+	return
+}
+
+func (r *rfc9000FrameReader) dataBlocked() (
+	frame RFC9000DataBlockedFrame, e error,
+) {
+	e = r.varInts(&frame.MaximumData)
+
+	return
+}
+
+func (r *rfc9000FrameReader) streamDataBlocked() (
+	frame RFC9000StreamDataBlockedFrame, e error,
+) {
+	e = r.varInts(&frame.StreamID, &frame.MaximumStreamData)
+
+	return
+}
+
+func (r *rfc9000FrameReader) streamsBlocked(bidirectional bool) (
+	frame RFC9000StreamsBlockedFrame, e error,
+) {
+	frame.Bidirectional = bidirectional
+
+	e = r.varInts(&frame.MaximumStreams)
+
+	return
+}
+
+func (r *rfc9000FrameReader) newConnectionID() (
+	frame RFC9000NewConnectionIDFrame, e error,
+) {
+	// > NEW_CONNECTION_ID Frame {
+	// >   Type (i) = 0x18,
+	// >   Sequence Number (i),
+	// >   Retire Prior To (i),
+	// >   Length (8),
+	// >   Connection ID (8..160),
+	// >   Stateless Reset Token (128),
+	// > }
+
+	var (
+		length []byte
+	)
+
+	e = r.varInts(&frame.SequenceNumber, &frame.RetirePriorTo)
+	if e != nil {
+		return
+	}
+
+	length, e = r.next(1)
+	if e != nil {
+		return
+	}
+
+	if length[0] > RFC9000MaxConnectionIDLength {
+		e = ErrRFC9000ConnectionIDTooLong
+
+		return
+	}
+
+	frame.ConnectionID, e = r.next(uint64(length[0]))
+	if e != nil {
+		return
+	}
+
+	frame.StatelessResetToken, e = r.next(rfc9000StatelessResetTokenLength)
+
+	return
+}
+
+func (r *rfc9000FrameReader) retireConnectionID() (
+	frame RFC9000RetireConnectionIDFrame, e error,
+) {
+	e = r.varInts(&frame.SequenceNumber)
+
+	return
+}
+
+func (r *rfc9000FrameReader) pathChallenge() (
+	frame RFC9000PathChallengeFrame, e error,
+) {
+	frame.Data, e = r.next(rfc9000PathChallengeDataLength)
+
+	return
+}
+
+func (r *rfc9000FrameReader) pathResponse() (
+	frame RFC9000PathResponseFrame, e error,
+) {
+	frame.Data, e = r.next(rfc9000PathChallengeDataLength)
+
+	return
+}
+
+func (r *rfc9000FrameReader) connectionClose(application bool) (
+	frame RFC9000ConnectionCloseFrame, e error,
+) {
+	// > CONNECTION_CLOSE Frame {
+	// >   Type (i) = 0x1c..0x1d,
+	// >   Error Code (i),
+	// >   [Frame Type (i)],
+	// >   Reason Phrase Length (i),
+	// >   Reason Phrase (..),
+	// > }
+
+	frame.Application = application
+
+	e = r.varInts(&frame.ErrorCode)
+	if e != nil {
+		return
+	}
+
+	if !application {
+		e = r.varInts(&frame.FrameType)
+		if e != nil {
+			return
+		}
+	}
+
+	frame.ReasonPhrase, e = r.lengthPrefixed()
+
+	return
+}
@@ -0,0 +1,756 @@
+package rfc9000
+
+import (
+	"errors"
+
+	"github.com/encodingx/binary"
+)
+
+type RFC9000LongHeaderFormat struct {
+	// Reference: Section 17.2 "Long Header Packets" of
+	// RFC 9000 QUIC: A UDP-Based Multiplexed and Secure Transport
+	// https://datatracker.ietf.org/doc/html/rfc9000#section-17.2
+
+	// > Long Header Packet {
+	// >   Header Form (1) = 1,
+	// >   Fixed Bit (1) = 1,
+	// >   Long Packet Type (2),
+	// >   Type-Specific Bits (4),
+	// >   Version (32),
+	// >   Destination Connection ID Length (8),
+	// >   Destination Connection ID (0..160),
+	// >   Source Connection ID Length (8),
+	// >   Source Connection ID (0..160),
+	// >   Type-Specific Payload (..),
+	// > }
+	//
+	// The connection IDs and type-specific payload are variable in length
+	// and are held by RFC9000LongHeader.
+
+	RFC9000LongHeaderFormatWord0 `word:"8"`
+	RFC9000LongHeaderFormatWord1 `word:"32"`
+}
+
+type RFC9000LongHeaderFormatWord0 struct {
+	HeaderForm bool `bitfield:"1"`
+	// > Header Form:  The most significant bit (0x80) of byte 0 (the first
+	// >    byte) is set to 1 for long headers.
+
+	FixedBit bool `bitfield:"1"`
+	// > Fixed Bit:  The next bit (0x40) of byte 0 is set to 1, unless the
+	// >    packet is a Version Negotiation packet.  Packets containing a
+	// >    zero value for this bit are not valid packets in this version and
+	// >    MUST be discarded.
+
+	LongPacketType uint8 `bitfield:"2"`
+	// > Long Packet Type:  The next two bits (those with a mask of 0x30) of
+	// >    byte 0 contain a packet type.
+
+	ReservedBits uint8 `bitfield:"2"`
+	// > Reserved Bits:  Two bits (those with a mask of 0x0c) of byte 0 are
+	// >    reserved across multiple packet types.  These bits are protected
+	// >    using header protection; see Section 5.4 of [QUIC-TLS].  The value
+	// >    included prior to protection MUST be set to 0.
+
+	PacketNumberLength uint8 `bitfield:"2"`
+	// > Packet Number Length:  In packet types that contain a Packet Number
+	// >    field, the least significant two bits (those with a mask of 0x03)
+	// >    of byte 0 contain the length of the Packet Number field, encoded
+	// >    as an unsigned two-bit integer that is one less than the length
+	// >    of the Packet Number field in bytes.
+}
+
+type RFC9000LongHeaderFormatWord1 struct {
+	Version uint32 `bitfield:"32"`
+	// > Version:  The QUIC Version is a 32-bit field that follows the first
+	// >    byte.  This field indicates the version of QUIC that is in use and
+	// >    determines how the rest of the protocol fields are interpreted.
+}
+
+const (
+	RFC9000LongPacketTypeInitial   = 0x00
+	RFC9000LongPacketType0RTT      = 0x01
+	RFC9000LongPacketTypeHandshake = 0x02
+	RFC9000LongPacketTypeRetry     = 0x03
+)
+
+const (
+	RFC9000VersionNegotiation = 0x00000000
+	RFC9000Version1           = 0x00000001
+)
+
+const (
+	RFC9000MaxConnectionIDLength   = 20
+	RFC9000RetryIntegrityTagLength = 16
+)
+
+type RFC9000ShortHeaderFormat struct {
+	// Reference: Section 17.3.1 "1-RTT Packet"
+
+	// > 1-RTT Packet {
+	// >   Header Form (1) = 0,
+	// >   Fixed Bit (1) = 1,
+	// >   Spin Bit (1),
+	// >   Reserved Bits (2),
+	// >   Key Phase (1),
+	// >   Packet Number Length (2),
+	// >   Destination Connection ID (0..160),
+	// >   Packet Number (8..32),
+	// >   Packet Payload (8..),
+	// > }
+
+	RFC9000ShortHeaderFormatWord0 `word:"8"`
+}
+
+type RFC9000ShortHeaderFormatWord0 struct {
+	HeaderForm bool `bitfield:"1"`
+	FixedBit   bool `bitfield:"1"`
+
+	SpinBit bool `bitfield:"1"`
+	// > Spin Bit:  The third most significant bit (0x20) of byte 0 is the
+	// >    latency spin bit, set as described in Section 17.4.
+
+	ReservedBits uint8 `bitfield:"2"`
+
+	KeyPhase bool `bitfield:"1"`
+	// > Key Phase:  The next bit (0x04) of byte 0 indicates the key phase,
+	// >    which allows a recipient of a packet to identify the packet
+	// >    protection keys that are used to protect the packet.
+
+	PacketNumberLength uint8 `bitfield:"2"`
+}
+
+// RFC9000HeaderProtector computes header protection masks.
+// Reference: Section 5.4 "Header Protection" of
+// RFC 9001 Using TLS to Secure QUIC
+//
+// > Header protection is applied after packet protection is applied (see
+// > Section 5.3).  The ciphertext of the packet is sampled and used as
+// > input to an encryption algorithm.
+//
+// Implementations hold the header protection key,
+// which this package does not derive.
+type RFC9000HeaderProtector interface {
+	// RFC9000HeaderProtectionMask returns a mask of at least five bytes
+	// from a 16-byte sample of the packet ciphertext.
+	RFC9000HeaderProtectionMask(sample []byte) (mask []byte, e error)
+}
+
+const (
+	rfc9000HeaderProtectionSampleOffset = 4
+	rfc9000HeaderProtectionSampleLength = 16
+	rfc9000HeaderProtectionMaskLength   = 5
+	rfc9000LongHeaderProtectedBits      = 0x0f
+	rfc9000ShortHeaderProtectedBits     = 0x1f
+)
+
+const (
+	rfc9000LongHeaderFormatLengthInBytes  = 5
+	rfc9000ShortHeaderFormatLengthInBytes = 1
+)
+
+var (
+	ErrRFC9000ConnectionIDTooLong = errors.New(
+		"A QUIC version 1 connection ID should be at most 20 bytes long. " +
+			"The connection ID length exceeds 20.",
+	)
+
+	ErrRFC9000FixedBitNotSet = errors.New(
+		"The Fixed Bit of a QUIC packet should be set to 1, " +
+			"unless the packet is a Version Negotiation packet. " +
+			"The Fixed Bit of the packet is 0.",
+	)
+
+	ErrRFC9000HeaderFormMismatch = errors.New(
+		"The Header Form bit of a QUIC packet should be 1 for long headers " +
+			"and 0 for short headers. " +
+			"The Header Form of the packet does not match the header parsed.",
+	)
+
+	ErrRFC9000HeaderProtectionMaskTooShort = errors.New(
+		"A QUIC header protection mask should be at least 5 bytes long. " +
+			"The mask returned by the header protector is too short.",
+	)
+
+	ErrRFC9000PacketTooShort = errors.New(
+		"A QUIC packet should be at least as long as its header, " +
+			"and long enough to sample for header protection. " +
+			"The packet ends too early.",
+	)
+)
+
+type RFC9000LongHeader struct {
+	RFC9000LongHeaderFormat
+
+	DestinationConnectionID []byte
+	SourceConnectionID      []byte
+
+	// SupportedVersions is the payload of a Version Negotiation packet.
+	SupportedVersions []uint32
+
+	// Token is the Token of an Initial packet,
+	// or the Retry Token of a Retry packet.
+	Token []byte
+
+	// RetryIntegrityTag is that of a Retry packet.
+	RetryIntegrityTag []byte
+
+	// Length is the length of the packet number and payload of
+	// Initial, 0-RTT and Handshake packets.
+	Length uint64
+
+	// PacketNumber is truncated to the length of its encoding.
+	PacketNumber uint32
+
+	// PacketNumberOffset is the offset of the Packet Number field, and
+	// HeaderLength the offset of the packet payload.
+	PacketNumberOffset int
+	HeaderLength       int
+}
+
+func (h *RFC9000LongHeader) IsVersionNegotiation() bool {
+	return h.Version == RFC9000VersionNegotiation
+}
+
+func (h *RFC9000LongHeader) hasPacketNumber() bool {
+	return !h.IsVersionNegotiation() &&
+		h.LongPacketType != RFC9000LongPacketTypeRetry
+}
+
+// PacketLength returns the length of the packet, which may be followed by
+// other packets coalesced in the same datagram.
+// Version Negotiation and Retry packets extend to the end of the datagram,
+// and the length returned is zero.
+func (h *RFC9000LongHeader) PacketLength() int {
+	if !h.hasPacketNumber() {
+		return 0
+	}
+
+	return h.PacketNumberOffset + int(h.Length)
+}
+
+// ParseRFC9000LongHeader parses the long header at the start of packet.
+// If hp is not nil, header protection is removed in place,
+// so that packet holds the unprotected header needed
+// as associated data for packet protection.
+func ParseRFC9000LongHeader(packet []byte, hp RFC9000HeaderProtector) (
+	header RFC9000LongHeader, e error,
+) {
+	var (
+		i int
+		n int
+	)
+
+	if len(packet) < rfc9000LongHeaderFormatLengthInBytes {
+		e = ErrRFC9000PacketTooShort
+
+		return
+	}
+
+	e = binary.Unmarshal(packet[:rfc9000LongHeaderFormatLengthInBytes],
+		&header.RFC9000LongHeaderFormat,
+	)
+	if e != nil {
+		return
+	}
+
+	if !header.HeaderForm {
+		e = ErrRFC9000HeaderFormMismatch
+
+		return
+	}
+
+	if !header.FixedBit && !header.IsVersionNegotiation() {
+		e = ErrRFC9000FixedBitNotSet
+
+		return
+	}
+
+	i = rfc9000LongHeaderFormatLengthInBytes
+
+	header.DestinationConnectionID, i, e = rfc9000ReadConnectionID(packet, i)
+	if e != nil {
+		return
+	}
+
+	header.SourceConnectionID, i, e = rfc9000ReadConnectionID(packet, i)
+	if e != nil {
+		return
+	}
+
+	if header.IsVersionNegotiation() {
+		for ; i+4 <= len(packet); i += 4 {
+			header.SupportedVersions = append(header.SupportedVersions,
+				binary.BigEndian.Uint32(packet[i:]),
+			)
+		}
+
+		header.HeaderLength = i
+
+		return
+	}
+
+	if header.LongPacketType == RFC9000LongPacketTypeRetry {
+		if len(packet)-i < RFC9000RetryIntegrityTagLength {
+			e = ErrRFC9000PacketTooShort
+
+			return
+		}
+
+		header.Token = packet[i : len(packet)-RFC9000RetryIntegrityTagLength]
+
+		header.RetryIntegrityTag =
+			packet[len(packet)-RFC9000RetryIntegrityTagLength:]
+
+		header.HeaderLength = len(packet)
+
+		return
+	}
+
+	if header.LongPacketType == RFC9000LongPacketTypeInitial {
+		header.Token, i, e = rfc9000ReadLengthPrefixedBytes(packet, i)
+		if e != nil {
+			return
+		}
+	}
+
+	header.Length, n, e = ReadRFC9000VarInt(packet[i:])
+	if e != nil {
+		return
+	}
+
+	header.PacketNumberOffset = i + n
+
+	if hp != nil {
+		e = rfc9000RemoveHeaderProtection(packet, header.PacketNumberOffset,
+			rfc9000LongHeaderProtectedBits, hp,
+		)
+		if e != nil {
+			return
+		}
+
+		e = binary.Unmarshal(packet[:rfc9000LongHeaderFormatLengthInBytes],
+			&header.RFC9000LongHeaderFormat,
+		)
+		if e != nil {
+			return
+		}
+	}
+
+	header.PacketNumber, header.HeaderLength, e = rfc9000ReadPacketNumber(
+		packet, header.PacketNumberOffset, header.PacketNumberLength,
+	)
+	if e != nil {
+		return
+	}
+
+	return
+}
+
+// MarshalBinary encodes the header of an Initial, 0-RTT or Handshake
+// packet up to and including the packet number,
+// whose encoded length is set by the Packet Number Length field.
+// Length should include the packet number and the protected payload.
+// Header protection is not applied; see ApplyRFC9000HeaderProtection.
+func (h *RFC9000LongHeader) MarshalBinary() (bytes []byte, e error) {
+	var (
+		i int
+	)
+
+	h.HeaderForm = true
+	h.FixedBit = true
+
+	bytes, e = binary.Marshal(&h.RFC9000LongHeaderFormat)
+	if e != nil {
+		return
+	}
+
+	bytes, e = rfc9000AppendConnectionID(bytes, h.DestinationConnectionID)
+	if e != nil {
+		return
+	}
+
+	bytes, e = rfc9000AppendConnectionID(bytes, h.SourceConnectionID)
+	if e != nil {
+		return
+	}
+
+	if h.LongPacketType == RFC9000LongPacketTypeInitial {
+		bytes, e = AppendRFC9000VarInt(bytes, uint64(len(h.Token)))
+		if e != nil {
+			return
+		}
+
+		bytes = append(bytes, h.Token...)
+	}
+
+	bytes, e = AppendRFC9000VarInt(bytes, h.Length)
+	if e != nil {
+		return
+	}
+
+	h.PacketNumberOffset = len(bytes)
+
+	for i = int(h.PacketNumberLength); i >= 0; i-- {
+		bytes = append(bytes, uint8(h.PacketNumber>>(8*i)))
+	}
+
+	h.HeaderLength = len(bytes)
+
+	return
+}
+
+type RFC9000ShortHeader struct {
+	RFC9000ShortHeaderFormat
+
+	DestinationConnectionID []byte
+
+	// PacketNumber is truncated to the length of its encoding.
+	PacketNumber uint32
+
+	PacketNumberOffset int
+	HeaderLength       int
+}
+
+// ParseRFC9000ShortHeader parses the short header at the start of packet.
+// Short headers do not encode the length of the destination connection ID,
+// which the endpoint should know from having chosen it.
+// If hp is not nil, header protection is removed in place.
+func ParseRFC9000ShortHeader(packet []byte, connectionIDLength int,
+	hp RFC9000HeaderProtector,
+) (
+	header RFC9000ShortHeader, e error,
+) {
+	if connectionIDLength > RFC9000MaxConnectionIDLength {
+		e = ErrRFC9000ConnectionIDTooLong
+
+		return
+	}
+
+	header.PacketNumberOffset =
+		rfc9000ShortHeaderFormatLengthInBytes + connectionIDLength
+
+	if len(packet) < header.PacketNumberOffset {
+		e = ErrRFC9000PacketTooShort
+
+		return
+	}
+
+	e = binary.Unmarshal(packet[:rfc9000ShortHeaderFormatLengthInBytes],
+		&header.RFC9000ShortHeaderFormat,
+	)
+	if e != nil {
+		return
+	}
+
+	if header.HeaderForm {
+		e = ErrRFC9000HeaderFormMismatch
+
+		return
+	}
+
+	if !header.FixedBit {
+		e = ErrRFC9000FixedBitNotSet
+
+		return
+	}
+
+	header.DestinationConnectionID =
+		packet[rfc9000ShortHeaderFormatLengthInBytes:header.PacketNumberOffset]
+
+	if hp != nil {
+		e = rfc9000RemoveHeaderProtection(packet, header.PacketNumberOffset,
+			rfc9000ShortHeaderProtectedBits, hp,
+		)
+		if e != nil {
+			return
+		}
+
+		e = binary.Unmarshal(packet[:rfc9000ShortHeaderFormatLengthInBytes],
+			&header.RFC9000ShortHeaderFormat,
+		)
+		if e != nil {
+			return
+		}
+	}
+
+	header.PacketNumber, header.HeaderLength, e = rfc9000ReadPacketNumber(
+		packet, header.PacketNumberOffset, header.PacketNumberLength,
+	)
+	if e != nil {
+		return
+	}
+
+	return
+}
+
+func (h *RFC9000ShortHeader) MarshalBinary() (bytes []byte, e error) {
+	var (
+		i int
+	)
+
+	h.HeaderForm = false
+	h.FixedBit = true
+
+	if len(h.DestinationConnectionID) > RFC9000MaxConnectionIDLength {
+		e = ErrRFC9000ConnectionIDTooLong
+
+		return
+	}
+
+	bytes, e = binary.Marshal(&h.RFC9000ShortHeaderFormat)
+	if e != nil {
+		return
+	}
+
+	bytes = append(bytes, h.DestinationConnectionID...)
+
+	h.PacketNumberOffset = len(bytes)
+
+	for i = int(h.PacketNumberLength); i >= 0; i-- {
+		bytes = append(bytes, uint8(h.PacketNumber>>(8*i)))
+	}
+
+	h.HeaderLength = len(bytes)
+
+	return
+}
+
+// ApplyRFC9000HeaderProtection protects the first byte and packet number
+// of a packet in place, after packet protection has been applied
+// to the payload.
+func ApplyRFC9000HeaderProtection(packet []byte, packetNumberOffset int,
+	hp RFC9000HeaderProtector,
+) (
+	e error,
+) {
+	var (
+		bits               uint8
+		i                  int
+		mask               []byte
+		packetNumberLength int
+	)
+
+	if len(packet) == 0 {
+		e = ErrRFC9000PacketTooShort
+
+		return
+	}
+
+	bits = rfc9000ShortHeaderProtectedBits
+
+	if packet[0]&0x80 != 0 {
+		bits = rfc9000LongHeaderProtectedBits
+	}
+
+	packetNumberLength = int(packet[0]&0x03) + 1
+
+	mask, e = rfc9000HeaderProtectionMask(packet, packetNumberOffset, hp)
+	if e != nil {
+		return
+	}
+
+	packet[0] ^= mask[0] & bits
+
+	for i = 0; i < packetNumberLength; i++ {
+		packet[packetNumberOffset+i] ^= mask[1+i]
+	}
+
+	return
+}
+
+func rfc9000RemoveHeaderProtection(packet []byte, packetNumberOffset int,
+	bits uint8, hp RFC9000HeaderProtector,
+) (
+	e error,
+) {
+	// > The same number of bytes are always sampled, but an allowance needs
+	// > to be made for the endpoint removing protection, which will not
+	// > know the length of the Packet Number field.  The sample is taken
+	// > assuming the Packet Number field is 4 bytes long.
+
+	var (
+		i                  int
+		mask               []byte
+		packetNumberLength int
+	)
+
+	mask, e = rfc9000HeaderProtectionMask(packet, packetNumberOffset, hp)
+	if e != nil {
+		return
+	}
+
+	packet[0] ^= mask[0] & bits
+
+	packetNumberLength = int(packet[0]&0x03) + 1
+
+	for i = 0; i < packetNumberLength; i++ {
+		packet[packetNumberOffset+i] ^= mask[1+i]
+	}
+
+	return
+}
+
+func rfc9000HeaderProtectionMask(packet []byte, packetNumberOffset int,
+	hp RFC9000HeaderProtector,
+) (
+	mask []byte, e error,
+) {
+	var (
+		sampleOffset int
+	)
+
+	sampleOffset = packetNumberOffset + rfc9000HeaderProtectionSampleOffset
+
+	if len(packet) < sampleOffset+rfc9000HeaderProtectionSampleLength {
+		e = ErrRFC9000PacketTooShort
+
+		return
+	}
+
+	mask, e = hp.RFC9000HeaderProtectionMask(
+		packet[sampleOffset : sampleOffset+rfc9000HeaderProtectionSampleLength],
+	)
+	if e != nil {
+		return
+	}
+
+	if len(mask) < rfc9000HeaderProtectionMaskLength {
+		e = ErrRFC9000HeaderProtectionMaskTooShort
+
+		return
+	}
+
+	return
+}
+
+func rfc9000ReadPacketNumber(packet []byte, offset int, lengthMinus1 uint8) (
+	packetNumber uint32, end int, e error,
+) {
+	var (
+		i int
+	)
+
+	end = offset + int(lengthMinus1) + 1
+
+	if len(packet) < end {
+		e = ErrRFC9000PacketTooShort
+
+		return
+	}
+
+	for i = offset; i < end; i++ {
+		packetNumber = packetNumber<<8 | uint32(packet[i])
+	}
+
+	return
+}
+
+func rfc9000ReadConnectionID(packet []byte, i int) (
+	connectionID []byte, j int, e error,
+) {
+	if i >= len(packet) {
+		e = ErrRFC9000PacketTooShort
+
+		return
+	}
+
+	if packet[i] > RFC9000MaxConnectionIDLength {
+		e = ErrRFC9000ConnectionIDTooLong
+
+		return
+	}
+
+	j = i + 1 + int(packet[i])
+
+	if j > len(packet) {
+		e = ErrRFC9000PacketTooShort
+
+		return
+	}
+
+	connectionID = packet[i+1 : j]
+
+	return
+}
+
+func rfc9000AppendConnectionID(bytes, connectionID []byte) (
+	appended []byte, e error,
+) {
+	if len(connectionID) > RFC9000MaxConnectionIDLength {
+		e = ErrRFC9000ConnectionIDTooLong
+
+		return
+	}
+
+	appended = append(bytes, uint8(len(connectionID)))
+	appended = append(appended, connectionID...)
+
+	return
+}
+
+func rfc9000ReadLengthPrefixedBytes(bytes []byte, i int) (
+	value []byte, j int, e error,
+) {
+	var (
+		length uint64
+		n      int
+	)
+
+	length, n, e = ReadRFC9000VarInt(bytes[i:])
+	if e != nil {
+		return
+	}
+
+	i += n
+
+	if length > uint64(len(bytes)-i) {
+		e = ErrRFC9000PacketTooShort
+
+		return
+	}
+
+	j = i + int(length)
+
+	value = bytes[i:j]
+
+	return
+}
+
+// DecodeRFC9000PacketNumber recovers a full packet number
+// from its truncated encoding of lengthInBytes,
+// given the largest packet number received so far in the same space.
+func DecodeRFC9000PacketNumber(largest uint64, truncated uint32,
+	lengthInBytes int,
+) (
+	packetNumber uint64,
+) {
+	// Reference: Appendix A.3 "Sample Packet Number Decoding Algorithm"
+
+	var (
+		expected uint64
+		halfWin  uint64
+		win      uint64
+		winMask  uint64
+	)
+
+	expected = largest + 1
+	win = 1 << (8 * lengthInBytes)
+	halfWin = win / 2
+	winMask = win - 1
+
+	// > candidate_pn = (expected_pn & ~pn_mask) | truncated_pn
+
+	packetNumber = expected&^winMask | uint64(truncated)
+
+	switch {
+	case packetNumber+halfWin <= expected &&
+		packetNumber < 1<<62-win:
+		packetNumber += win
+
+	case packetNumber > expected+halfWin && packetNumber >= win:
+		packetNumber -= win
+	}
+
+	return
+}
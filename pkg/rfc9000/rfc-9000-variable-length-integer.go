@@ -0,0 +1,135 @@
+package rfc9000
+
+import (
+	"errors"
+
+	"github.com/encodingx/binary"
+)
+
+// Reference: Section 16 "Variable-Length Integer Encoding" of
+// RFC 9000 QUIC: A UDP-Based Multiplexed and Secure Transport
+// https://datatracker.ietf.org/doc/html/rfc9000#section-16
+
+// > QUIC packets and frames commonly use a variable-length encoding for
+// > non-negative integer values.  This encoding ensures that smaller
+// > integer values need fewer bytes to encode.
+// >
+// > The QUIC variable-length integer encoding reserves the two most
+// > significant bits of the first byte to encode the base-2 logarithm of
+// > the integer encoding length in bytes.  The integer value is encoded
+// > on the remaining bits, in network byte order.
+// >
+// >        +======+========+=============+=======================+
+// >        | 2MSB | Length | Usable Bits | Range                 |
+// >        +======+========+=============+=======================+
+// >        | 00   | 1      | 6           | 0-63                  |
+// >        +------+--------+-------------+-----------------------+
+// >        | 01   | 2      | 14          | 0-16383               |
+// >        +------+--------+-------------+-----------------------+
+// >        | 10   | 4      | 30          | 0-1073741823          |
+// >        +------+--------+-------------+-----------------------+
+// >        | 11   | 8      | 62          | 0-4611686018427387903 |
+// >        +------+--------+-------------+-----------------------+
+
+const (
+	RFC9000VarIntMax = 1<<62 - 1
+)
+
+var (
+	ErrRFC9000VarIntTooLarge = errors.New(
+		"A QUIC variable-length integer should be at most 2^62-1. " +
+			"The value is too large to be encoded.",
+	)
+
+	ErrRFC9000VarIntTruncated = errors.New(
+		"A QUIC variable-length integer should be as long as " +
+			"the two most significant bits of its first byte indicate. " +
+			"The bytes end before the integer does.",
+	)
+)
+
+// ReadRFC9000VarInt decodes the variable-length integer at the start of
+// bytes, returning the number of bytes it occupies.
+func ReadRFC9000VarInt(bytes []byte) (value uint64, n int, e error) {
+	var (
+		i int
+	)
+
+	if len(bytes) == 0 {
+		e = ErrRFC9000VarIntTruncated
+
+		return
+	}
+
+	n = 1 << (bytes[0] >> 6)
+
+	if len(bytes) < n {
+		e = ErrRFC9000VarIntTruncated
+
+		n = 0
+
+		return
+	}
+
+	value = uint64(bytes[0] & 0x3f)
+
+	for i = 1; i < n; i++ {
+		value = value<<8 | uint64(bytes[i])
+	}
+
+	return
+}
+
+// AppendRFC9000VarInt appends the shortest encoding of value to bytes.
+func AppendRFC9000VarInt(bytes []byte, value uint64) (
+	appended []byte, e error,
+) {
+	var (
+		encoding [8]byte
+	)
+
+	if value > RFC9000VarIntMax {
+		appended = bytes
+
+		e = ErrRFC9000VarIntTooLarge
+
+		return
+	}
+
+	binary.BigEndian.PutUint64(encoding[:], value)
+
+	switch RFC9000VarIntLength(value) {
+	case 1:
+		appended = append(bytes, encoding[7])
+
+	case 2:
+		appended = append(bytes, encoding[6]|0b01<<6, encoding[7])
+
+	case 4:
+		appended = append(bytes, encoding[4]|0b10<<6)
+		appended = append(appended, encoding[5:]...)
+
+	case 8:
+		appended = append(bytes, encoding[0]|0b11<<6)
+		appended = append(appended, encoding[1:]...)
+	}
+
+	return
+}
+
+// RFC9000VarIntLength returns the length in bytes
+// of the shortest encoding of value.
+func RFC9000VarIntLength(value uint64) int {
+	switch {
+	case value <= 1<<6-1:
+		return 1
+
+	case value <= 1<<14-1:
+		return 2
+
+	case value <= 1<<30-1:
+		return 4
+	}
+
+	return 8
+}
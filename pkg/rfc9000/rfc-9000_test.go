@@ -0,0 +1,492 @@
+package rfc9000
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRFC9000VarInt(t *testing.T) {
+	// Reference: Appendix A.1 "Sample Variable-Length Integer Decoding"
+
+	var (
+		e        error
+		encoding string
+		n        int
+		value    uint64
+		value1   uint64
+		vectors  map[string]uint64
+	)
+
+	vectors = map[string]uint64{
+		"c2197c5eff14e88c": 151288809941952652,
+		"9d7f3e7d":         494878333,
+		"7bbd":             15293,
+		"25":               37,
+	}
+
+	for encoding, value = range vectors {
+		value1, n, e = ReadRFC9000VarInt(mustDecodeHex(encoding))
+
+		assert.Nil(t, e)
+
+		assert.Equal(t,
+			value, value1,
+		)
+
+		assert.Equal(t,
+			len(encoding)/2, n,
+		)
+
+		assert.Equal(t,
+			n, RFC9000VarIntLength(value),
+		)
+
+		assert.Equal(t,
+			mustDecodeHex(encoding), mustAppendRFC9000VarInt(t, value),
+		)
+	}
+
+	value, n, e = ReadRFC9000VarInt([]byte{0x40, 0x25})
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint64(37), value,
+	)
+
+	assert.Equal(t,
+		2, n,
+	)
+
+	_, _, e = ReadRFC9000VarInt([]byte{0x9d, 0x7f})
+
+	assert.Equal(t,
+		ErrRFC9000VarIntTruncated, e,
+	)
+
+	_, e = AppendRFC9000VarInt(nil, RFC9000VarIntMax+1)
+
+	assert.Equal(t,
+		ErrRFC9000VarIntTooLarge, e,
+	)
+}
+
+type rfc9000TestHeaderProtector struct {
+	sample []byte
+	mask   []byte
+}
+
+func (hp rfc9000TestHeaderProtector) RFC9000HeaderProtectionMask(
+	sample []byte,
+) (
+	mask []byte, e error,
+) {
+	if bytes.Equal(sample, hp.sample) {
+		mask = hp.mask
+	}
+
+	return
+}
+
+func TestRFC9000LongHeaderProtection(t *testing.T) {
+	// Reference: Appendix A.2 "Client Initial" of RFC 9001
+
+	var (
+		e                 error
+		header            RFC9000LongHeader
+		header1           RFC9000LongHeader
+		hp                rfc9000TestHeaderProtector
+		packet            []byte
+		protectedHeader   []byte
+		unprotectedHeader []byte
+	)
+
+	unprotectedHeader = mustDecodeHex(
+		"c300000001088394c8f03e5157080000449e00000002",
+	)
+
+	protectedHeader = mustDecodeHex(
+		"c000000001088394c8f03e5157080000449e7b9aec34",
+	)
+
+	hp.sample = mustDecodeHex("d1b1c98dd7689fb8ec11d242b123dc9b")
+	hp.mask = mustDecodeHex("437b9aec36")
+
+	packet = append(append([]byte(nil), protectedHeader...), hp.sample...)
+
+	header, e = ParseRFC9000LongHeader(packet, hp)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		unprotectedHeader, packet[:len(unprotectedHeader)],
+	)
+
+	assert.Equal(t,
+		uint8(RFC9000LongPacketTypeInitial), header.LongPacketType,
+	)
+
+	assert.Equal(t,
+		uint32(RFC9000Version1), header.Version,
+	)
+
+	assert.Equal(t,
+		mustDecodeHex("8394c8f03e515708"), header.DestinationConnectionID,
+	)
+
+	assert.Equal(t,
+		0, len(header.SourceConnectionID),
+	)
+
+	assert.Equal(t,
+		uint64(1182), header.Length,
+	)
+
+	assert.Equal(t,
+		uint8(3), header.PacketNumberLength,
+	)
+
+	assert.Equal(t,
+		uint32(2), header.PacketNumber,
+	)
+
+	assert.Equal(t,
+		18, header.PacketNumberOffset,
+	)
+
+	assert.Equal(t,
+		22, header.HeaderLength,
+	)
+
+	assert.Equal(t,
+		18+1182, header.PacketLength(),
+	)
+
+	header1.LongPacketType = RFC9000LongPacketTypeInitial
+	header1.Version = RFC9000Version1
+	header1.PacketNumberLength = 3
+	header1.DestinationConnectionID = header.DestinationConnectionID
+	header1.Length = 1182
+	header1.PacketNumber = 2
+
+	packet, e = header1.MarshalBinary()
+
+	assert.Nil(t, e)
+
+	// The Length field is encoded in two bytes rather than four,
+	// so the header is the same as that of the RFC.
+
+	assert.Equal(t,
+		unprotectedHeader, packet,
+	)
+
+	packet = append(packet, hp.sample...)
+
+	e = ApplyRFC9000HeaderProtection(packet, header1.PacketNumberOffset, hp)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		protectedHeader, packet[:len(protectedHeader)],
+	)
+
+	_, e = ParseRFC9000LongHeader(protectedHeader, hp)
+
+	assert.Equal(t,
+		ErrRFC9000PacketTooShort, e,
+	)
+
+	hp.mask = hp.mask[:4]
+
+	_, e = ParseRFC9000LongHeader(packet, hp)
+
+	assert.Equal(t,
+		ErrRFC9000HeaderProtectionMaskTooShort, e,
+	)
+}
+
+func TestRFC9000LongHeaderVersionNegotiationAndRetry(t *testing.T) {
+	var (
+		e      error
+		header RFC9000LongHeader
+		packet []byte
+	)
+
+	packet = mustDecodeHex(
+		"80" + "00000000" + "0101" + "020203" + "00000001" + "6b3343cf",
+	)
+
+	header, e = ParseRFC9000LongHeader(packet, nil)
+
+	assert.Nil(t, e)
+
+	assert.True(t, header.IsVersionNegotiation())
+
+	assert.Equal(t,
+		[]uint32{RFC9000Version1, 0x6b3343cf}, header.SupportedVersions,
+	)
+
+	assert.Equal(t,
+		0, header.PacketLength(),
+	)
+
+	// Reference: Appendix A.4 "Retry" of RFC 9001
+
+	packet = mustDecodeHex(
+		"ff000000010008f067a5502a4262b5746f6b656e" +
+			"04a265ba2eff4d829058fb3f0f2496ba",
+	)
+
+	header, e = ParseRFC9000LongHeader(packet, nil)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint8(RFC9000LongPacketTypeRetry), header.LongPacketType,
+	)
+
+	assert.Equal(t,
+		mustDecodeHex("f067a5502a4262b5"), header.SourceConnectionID,
+	)
+
+	assert.Equal(t,
+		[]byte("token"), header.Token,
+	)
+
+	assert.Equal(t,
+		mustDecodeHex("04a265ba2eff4d829058fb3f0f2496ba"),
+		header.RetryIntegrityTag,
+	)
+
+	_, e = ParseRFC9000LongHeader(mustDecodeHex("8000000001"), nil)
+
+	assert.Equal(t,
+		ErrRFC9000FixedBitNotSet, e,
+	)
+
+	_, e = ParseRFC9000LongHeader(mustDecodeHex("c00000000115"), nil)
+
+	assert.Equal(t,
+		ErrRFC9000ConnectionIDTooLong, e,
+	)
+
+	_, e = ParseRFC9000LongHeader(mustDecodeHex("4000000001"), nil)
+
+	assert.Equal(t,
+		ErrRFC9000HeaderFormMismatch, e,
+	)
+}
+
+func TestRFC9000ShortHeader(t *testing.T) {
+	// Reference: Appendix A.5 "ChaCha20-Poly1305 Short Header Packet"
+	// of RFC 9001
+
+	var (
+		e       error
+		header  RFC9000ShortHeader
+		header1 RFC9000ShortHeader
+		hp      rfc9000TestHeaderProtector
+		packet  []byte
+	)
+
+	hp.sample = mustDecodeHex("5e5cd55c41f69080575d7999c25a5bfb")
+	hp.mask = mustDecodeHex("aefefe7d03")
+
+	packet = mustDecodeHex("4cfe4189655e5cd55c41f69080575d7999c25a5bfb")
+
+	header, e = ParseRFC9000ShortHeader(packet, 0, hp)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		mustDecodeHex("4200bff4"), packet[:4],
+	)
+
+	assert.Equal(t,
+		uint8(2), header.PacketNumberLength,
+	)
+
+	assert.Equal(t,
+		uint32(0x00bff4), header.PacketNumber,
+	)
+
+	assert.Equal(t,
+		uint64(654360564),
+		DecodeRFC9000PacketNumber(654360563, header.PacketNumber, 3),
+	)
+
+	header1.PacketNumberLength = 2
+	header1.PacketNumber = 0x00bff4
+
+	packet, e = header1.MarshalBinary()
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		mustDecodeHex("4200bff4"), packet,
+	)
+
+	_, e = ParseRFC9000ShortHeader(mustDecodeHex("c2"), 0, nil)
+
+	assert.Equal(t,
+		ErrRFC9000HeaderFormMismatch, e,
+	)
+}
+
+func TestDecodeRFC9000PacketNumber(t *testing.T) {
+	// Reference: Appendix A.3 "Sample Packet Number Decoding Algorithm"
+
+	assert.Equal(t,
+		uint64(0xa82f9b32),
+		DecodeRFC9000PacketNumber(0xa82f30ea, 0x9b32, 2),
+	)
+
+	assert.Equal(t,
+		uint64(0x100),
+		DecodeRFC9000PacketNumber(0xff, 0x00, 1),
+	)
+
+	assert.Equal(t,
+		uint64(0xff),
+		DecodeRFC9000PacketNumber(0x100, 0xff, 1),
+	)
+}
+
+func TestParseRFC9000Frames(t *testing.T) {
+	var (
+		e       error
+		frames  []RFC9000Frame
+		payload []byte
+	)
+
+	payload = mustDecodeHex(
+		// ACK with ECN counts and one additional range
+		"03" + "4064" + "05" + "01" + "02" + "01" + "03" + "0a" + "0b" + "0c" +
+			// CRYPTO
+			"06" + "00" + "03" + "010203" +
+			// STREAM with OFF and LEN
+			"0e" + "04" + "4100" + "02" + "6869" +
+			// MAX_STREAMS (unidirectional)
+			"13" + "0a" +
+			// CONNECTION_CLOSE
+			"1c" + "0a" + "06" + "03" + "626164" +
+			// PING
+			"01" +
+			// STREAM without LEN, extending to the end
+			"09" + "04" + "7a7a" +
+			"",
+	)
+
+	frames, e = ParseRFC9000Frames(payload)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]RFC9000Frame{
+			RFC9000ACKFrame{
+				LargestAcknowledged: 100,
+				ACKDelay:            5,
+				FirstACKRange:       2,
+				ACKRanges: []RFC9000ACKRange{
+					{Gap: 1, ACKRangeLength: 3},
+				},
+				ECN:   true,
+				ECT0:  10,
+				ECT1:  11,
+				ECNCE: 12,
+			},
+			RFC9000CryptoFrame{
+				Offset: 0,
+				Data:   []byte{1, 2, 3},
+			},
+			RFC9000StreamFrame{
+				RFC9000StreamFrameTypeFormat: RFC9000StreamFrameTypeFormat{
+					RFC9000StreamFrameTypeFormatWord0: RFC9000StreamFrameTypeFormatWord0{
+						Prefix: 0b00001,
+						OFF:    true,
+						LEN:    true,
+					},
+				},
+				StreamID: 4,
+				Offset:   256,
+				Data:     []byte("hi"),
+			},
+			RFC9000MaxStreamsFrame{
+				MaximumStreams: 10,
+			},
+			RFC9000ConnectionCloseFrame{
+				ErrorCode:    10,
+				FrameType:    RFC9000FrameTypeCrypto,
+				ReasonPhrase: []byte("bad"),
+			},
+			RFC9000PingFrame{},
+			RFC9000StreamFrame{
+				RFC9000StreamFrameTypeFormat: RFC9000StreamFrameTypeFormat{
+					RFC9000StreamFrameTypeFormatWord0: RFC9000StreamFrameTypeFormatWord0{
+						Prefix: 0b00001,
+						FIN:    true,
+					},
+				},
+				StreamID: 4,
+				Data:     []byte("zz"),
+			},
+		},
+		frames,
+	)
+
+	assert.Equal(t,
+		uint64(0x0e), frames[2].RFC9000FrameType(),
+	)
+
+	frames, e = ParseRFC9000Frames(mustDecodeHex("0000000001"))
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]RFC9000Frame{
+			RFC9000PaddingFrame{Length: 4},
+			RFC9000PingFrame{},
+		},
+		frames,
+	)
+
+	_, e = ParseRFC9000Frames(mustDecodeHex("0600050102"))
+
+	assert.Equal(t,
+		ErrRFC9000FrameTruncated, e,
+	)
+
+	_, e = ParseRFC9000Frames(mustDecodeHex("1f"))
+
+	assert.Equal(t,
+		ErrRFC9000FrameTypeUnknown, e,
+	)
+}
+
+func mustDecodeHex(s string) (bytes []byte) {
+	var (
+		e error
+	)
+
+	bytes, e = hex.DecodeString(s)
+	if e != nil {
+		panic(e)
+	}
+
+	return
+}
+
+func mustAppendRFC9000VarInt(t *testing.T, value uint64) (bytes []byte) {
+	var (
+		e error
+	)
+
+	bytes, e = AppendRFC9000VarInt(nil, value)
+
+	assert.Nil(t, e)
+
+	return
+}
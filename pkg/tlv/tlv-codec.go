@@ -0,0 +1,388 @@
+package tlv
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/encodingx/binary"
+)
+
+var (
+	ErrTLVHeaderNotPointerToStruct = errors.New(
+		"A TLV header should be a pointer to a format-struct. " +
+			"The header is not a pointer to a struct.",
+	)
+
+	ErrTLVFormatNotPointerToStruct = errors.New(
+		"A format registered for a TLV type should be given " +
+			"as a pointer to a format-struct. " +
+			"The argument is not a pointer to a struct.",
+	)
+
+	ErrTLVHeaderOverflow = errors.New(
+		"The type and length of a TLV should fit in its header. " +
+			"The type or length overflows its bit field in the header.",
+	)
+
+	ErrTLVTruncated = errors.New(
+		"A TLV should be as long as its header and the length it declares. " +
+			"The bytes end before the header or value of a TLV.",
+	)
+
+	ErrTLVValueTypeMismatch = errors.New(
+		"The value of a TLV should point to the format-struct " +
+			"registered for its type, or be nil. " +
+			"The value is of another type, or its type is not registered.",
+	)
+
+	ErrTLVListFieldWithoutCodec = errors.New(
+		"A TLV list field should be given a codec " +
+			"before it is marshalled or unmarshalled. " +
+			"The codec of the field is nil.",
+	)
+
+	ErrTLVValueLengthMismatch = errors.New(
+		"The value of a TLV of a registered type should be of length " +
+			"equal to the length of the format-struct registered for it. " +
+			"The TLV declares a different length.",
+	)
+)
+
+// TLVHeader is implemented by pointers to format-structs
+// describing the type and length fields that precede each value.
+// Lengths are in bytes of the value alone;
+// implementations convert to and from the units of their length fields.
+type TLVHeader interface {
+	TLVType() uint64
+	TLVLength() int
+	SetTLVType(uint64)
+	SetTLVLength(int)
+}
+
+type TLV struct {
+	Type uint64
+
+	// Value points to a format-struct registered for the type,
+	// or is nil for types not registered with the codec.
+	Value interface{}
+
+	// Raw holds the value bytes of a TLV of an unregistered type,
+	// to be marshalled as they are.
+	Raw []byte
+}
+
+// TLVList is a sequence of TLVs, marshalled by a TLVCodec
+// or as a TLVListField of a format-struct.
+type TLVList []TLV
+
+func (l TLVList) Find(tlvType uint64) (tlv TLV, ok bool) {
+	for _, tlv = range l {
+		if tlv.Type == tlvType {
+			ok = true
+
+			return
+		}
+	}
+
+	tlv = TLV{}
+
+	return
+}
+
+// TLVListField is a TLV list declared as the last field of a format-struct,
+// following its words to the end of the bytes (see binary.Trailer):
+//
+//	type MessageFormat struct {
+//		MessageFormatWord0 `word:"32"`
+//		Options            tlv.TLVListField
+//	}
+//
+// The field is parameterised by its header format through its codec,
+// which is set before the format-struct is marshalled or unmarshalled.
+type TLVListField struct {
+	Codec *TLVCodec
+	TLVList
+}
+
+func (f *TLVListField) MarshalTrailer() (bytes []byte, e error) {
+	if f.Codec == nil {
+		e = ErrTLVListFieldWithoutCodec
+
+		return
+	}
+
+	bytes, e = f.Codec.Marshal(f.TLVList)
+	if e != nil {
+		return
+	}
+
+	return
+}
+
+func (f *TLVListField) UnmarshalTrailer(bytes []byte) (e error) {
+	var (
+		list TLVList
+	)
+
+	if f.Codec == nil {
+		e = ErrTLVListFieldWithoutCodec
+
+		return
+	}
+
+	list, e = f.Codec.Unmarshal(bytes)
+	if e != nil {
+		return
+	}
+
+	f.TLVList = list
+
+	return
+}
+
+type TLVCodec struct {
+	header              reflect.Type
+	headerLengthInBytes int
+	formats             map[uint64]reflect.Type
+}
+
+func NewTLVCodec(header TLVHeader) (c *TLVCodec, e error) {
+	var (
+		headerBytes []byte
+		reflection  reflect.Type
+	)
+
+	reflection = reflect.TypeOf(header)
+
+	if !tlvIsPointerToStruct(reflection) {
+		e = ErrTLVHeaderNotPointerToStruct
+
+		return
+	}
+
+	headerBytes, e = binary.Marshal(header)
+	if e != nil {
+		return
+	}
+
+	c = &TLVCodec{
+		header:              reflection.Elem(),
+		headerLengthInBytes: len(headerBytes),
+		formats:             make(map[uint64]reflect.Type),
+	}
+
+	return
+}
+
+// Register associates a TLV type with a format-struct.
+// The argument is a pointer to a variable of that format-struct type,
+// and may be nil (e.g. (*Format)(nil)).
+func (c *TLVCodec) Register(tlvType uint64, iface interface{}) (e error) {
+	var (
+		reflection reflect.Type
+	)
+
+	reflection = reflect.TypeOf(iface)
+
+	if !tlvIsPointerToStruct(reflection) {
+		e = ErrTLVFormatNotPointerToStruct
+
+		return
+	}
+
+	// Marshal validates the format-struct.
+
+	_, e = binary.Marshal(
+		reflect.New(reflection.Elem()).Interface(),
+	)
+	if e != nil {
+		return
+	}
+
+	c.formats[tlvType] = reflection.Elem()
+
+	return
+}
+
+// tlvIsPointerToStruct returns false for the nil type of an untyped nil.
+func tlvIsPointerToStruct(reflection reflect.Type) bool {
+	return reflection != nil &&
+		reflection.Kind() == reflect.Ptr &&
+		reflection.Elem().Kind() == reflect.Struct
+}
+
+func (c *TLVCodec) HeaderLengthInBytes() int {
+	return c.headerLengthInBytes
+}
+
+func (c *TLVCodec) Marshal(list TLVList) (bytes []byte, e error) {
+	var (
+		tlv      TLV
+		tlvBytes []byte
+	)
+
+	for _, tlv = range list {
+		tlvBytes, e = c.MarshalTLV(tlv)
+		if e != nil {
+			return
+		}
+
+		bytes = append(bytes, tlvBytes...)
+	}
+
+	return
+}
+
+func (c *TLVCodec) MarshalTLV(tlv TLV) (bytes []byte, e error) {
+	var (
+		check       TLVHeader
+		format      reflect.Type
+		header      TLVHeader
+		headerBytes []byte
+		inMap       bool
+		value       []byte
+	)
+
+	value = tlv.Raw
+
+	if tlv.Value != nil {
+		format, inMap = c.formats[tlv.Type]
+
+		if !inMap || reflect.TypeOf(tlv.Value) != reflect.PtrTo(format) {
+			e = ErrTLVValueTypeMismatch
+
+			return
+		}
+
+		value, e = binary.Marshal(tlv.Value)
+		if e != nil {
+			return
+		}
+	}
+
+	header = reflect.New(c.header).Interface().(TLVHeader)
+
+	header.SetTLVType(tlv.Type)
+	header.SetTLVLength(len(value))
+
+	headerBytes, e = binary.Marshal(header)
+	if e != nil {
+		return
+	}
+
+	// Bit fields narrower than their struct fields are masked by Marshal,
+	// so read the header back to detect truncation.
+
+	check = reflect.New(c.header).Interface().(TLVHeader)
+
+	e = binary.Unmarshal(headerBytes, check)
+	if e != nil {
+		return
+	}
+
+	if check.TLVType() != tlv.Type || check.TLVLength() != len(value) {
+		e = ErrTLVHeaderOverflow
+
+		return
+	}
+
+	bytes = append(headerBytes, value...)
+
+	return
+}
+
+func (c *TLVCodec) Unmarshal(bytes []byte) (list TLVList, e error) {
+	var (
+		n   int
+		tlv TLV
+	)
+
+	for len(bytes) > 0 {
+		tlv, n, e = c.UnmarshalTLV(bytes)
+		if e != nil {
+			return
+		}
+
+		list = append(list, tlv)
+
+		bytes = bytes[n:]
+	}
+
+	return
+}
+
+func (c *TLVCodec) UnmarshalTLV(bytes []byte) (tlv TLV, n int, e error) {
+	var (
+		format reflect.Type
+		header TLVHeader
+		inMap  bool
+		length int
+		value  reflect.Value
+	)
+
+	if len(bytes) < c.headerLengthInBytes {
+		e = ErrTLVTruncated
+
+		return
+	}
+
+	header = reflect.New(c.header).Interface().(TLVHeader)
+
+	e = binary.Unmarshal(bytes[:c.headerLengthInBytes], header)
+	if e != nil {
+		return
+	}
+
+	length = header.TLVLength()
+
+	n = c.headerLengthInBytes + length
+
+	if len(bytes) < n {
+		e = ErrTLVTruncated
+
+		return
+	}
+
+	tlv.Type = header.TLVType()
+
+	format, inMap = c.formats[tlv.Type]
+
+	if !inMap {
+		tlv.Raw = append([]byte(nil), bytes[c.headerLengthInBytes:n]...)
+
+		return
+	}
+
+	value = reflect.New(format)
+
+	e = binary.Unmarshal(bytes[c.headerLengthInBytes:n], value.Interface())
+	if e != nil {
+		if length != c.formatLength(format) {
+			e = ErrTLVValueLengthMismatch
+		}
+
+		return
+	}
+
+	tlv.Value = value.Interface()
+
+	return
+}
+
+func (c *TLVCodec) formatLength(format reflect.Type) (length int) {
+	var (
+		bytes []byte
+		e     error
+	)
+
+	bytes, e = binary.Marshal(
+		reflect.New(format).Interface(),
+	)
+	if e != nil {
+		return
+	}
+
+	length = len(bytes)
+
+	return
+}
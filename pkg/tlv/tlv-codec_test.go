@@ -0,0 +1,238 @@
+package tlv
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/encodingx/binary"
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	lldpFrameFormat struct {
+		LLDPFrameFormatWord0 `word:"16"`
+		TLVs                 TLVListField
+	}
+
+	LLDPFrameFormatWord0 struct {
+		EtherType uint16 `bitfield:"16"`
+	}
+)
+
+func TestTLVCodecRoundTrip(t *testing.T) {
+	var (
+		bytes []byte
+		c     *TLVCodec
+		e     error
+		list  TLVList
+		tlv   TLV
+		ok    bool
+	)
+
+	c, e = NewTLVCodec(&LLDPTLVHeaderFormat{})
+
+	assert.Nil(t, e)
+
+	e = c.Register(LLDPTLVTypeTimeToLive, (*LLDPTimeToLiveFormat)(nil))
+
+	assert.Nil(t, e)
+
+	list, e = c.Unmarshal(lldpBytes)
+
+	assert.Nil(t, e)
+
+	assert.Len(t, list, 3)
+
+	assert.Equal(t,
+		[]byte{0x04, 0x00, 0x11, 0x22, 0x33, 0x44, 0x55}, list[0].Raw,
+	)
+
+	tlv, ok = list.Find(LLDPTLVTypeTimeToLive)
+
+	assert.True(t, ok)
+
+	assert.Equal(t,
+		uint16(120),
+		tlv.Value.(*LLDPTimeToLiveFormat).Seconds,
+	)
+
+	bytes, e = c.Marshal(list)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		lldpBytes, bytes,
+	)
+}
+
+func TestTLVCodecErrors(t *testing.T) {
+	var (
+		c *TLVCodec
+		e error
+	)
+
+	c, e = NewTLVCodec(&LLDPTLVHeaderFormat{})
+
+	assert.Nil(t, e)
+
+	e = c.Register(LLDPTLVTypeTimeToLive, (*LLDPTimeToLiveFormat)(nil))
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		ErrTLVFormatNotPointerToStruct,
+		c.Register(LLDPTLVTypeTimeToLive, nil),
+	)
+
+	assert.Equal(t,
+		ErrTLVFormatNotPointerToStruct,
+		c.Register(LLDPTLVTypeTimeToLive, LLDPTimeToLiveFormat{}),
+	)
+
+	_, e = NewTLVCodec(nil)
+
+	assert.Equal(t,
+		ErrTLVHeaderNotPointerToStruct, e,
+	)
+
+	_, e = c.Unmarshal(lldpBytes[:4])
+
+	assert.Equal(t,
+		ErrTLVTruncated, e,
+	)
+
+	_, e = c.Unmarshal([]byte{0x06, 0x01, 0x00})
+
+	assert.Equal(t,
+		ErrTLVValueLengthMismatch, e,
+	)
+
+	_, e = c.Marshal(
+		TLVList{
+			{Type: 128},
+		},
+	)
+
+	assert.Equal(t,
+		ErrTLVHeaderOverflow, e,
+	)
+
+	_, e = c.Marshal(
+		TLVList{
+			{Type: 1, Raw: make([]byte, 512)},
+		},
+	)
+
+	assert.Equal(t,
+		ErrTLVHeaderOverflow, e,
+	)
+
+	_, e = c.Marshal(
+		TLVList{
+			{Type: LLDPTLVTypeTimeToLive, Value: &LLDPTLVHeaderFormat{}},
+		},
+	)
+
+	assert.Equal(t,
+		ErrTLVValueTypeMismatch, e,
+	)
+
+	_, e = c.Marshal(
+		TLVList{
+			{Type: 1, Value: &LLDPTimeToLiveFormat{}},
+		},
+	)
+
+	assert.Equal(t,
+		ErrTLVValueTypeMismatch, e,
+	)
+}
+
+func TestTLVListField(t *testing.T) {
+	var (
+		bytes []byte
+		c     *TLVCodec
+		e     error
+		frame lldpFrameFormat
+		ok    bool
+		tlv   TLV
+	)
+
+	c, e = NewTLVCodec(&LLDPTLVHeaderFormat{})
+
+	assert.Nil(t, e)
+
+	e = c.Register(LLDPTLVTypeTimeToLive, (*LLDPTimeToLiveFormat)(nil))
+
+	assert.Nil(t, e)
+
+	frame.TLVs.Codec = c
+
+	e = binary.Unmarshal(
+		append([]byte{0x88, 0xcc}, lldpBytes...), &frame,
+	)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint16(0x88cc), frame.EtherType,
+	)
+
+	tlv, ok = frame.TLVs.Find(LLDPTLVTypeTimeToLive)
+
+	assert.True(t, ok)
+
+	assert.Equal(t,
+		uint16(120),
+		tlv.Value.(*LLDPTimeToLiveFormat).Seconds,
+	)
+
+	bytes, e = binary.Marshal(&frame)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		append([]byte{0x88, 0xcc}, lldpBytes...), bytes,
+	)
+
+	// A list failing to unmarshal leaves the format-struct untouched.
+
+	frame = lldpFrameFormat{}
+
+	frame.TLVs.Codec = c
+
+	e = binary.Unmarshal(
+		append([]byte{0x88, 0xcc}, lldpBytes[:4]...), &frame,
+	)
+
+	assert.True(t,
+		errors.Is(e, ErrTLVTruncated),
+	)
+
+	assert.Equal(t,
+		lldpFrameFormat{
+			TLVs: TLVListField{
+				Codec: c,
+			},
+		},
+		frame,
+	)
+
+	frame.TLVs.Codec = nil
+
+	_, e = binary.Marshal(&frame)
+
+	assert.True(t,
+		errors.Is(e, ErrTLVListFieldWithoutCodec),
+	)
+}
+
+var (
+	lldpBytes = []byte{
+		0b00000010, 0b00000111, // Chassis ID, 7 octets
+		0x04, 0x00, 0x11, 0x22, 0x33, 0x44, 0x55,
+		0b00000110, 0b00000010, // Time To Live, 2 octets
+		0x00, 0x78,
+		0b00000000, 0b00000000, // End of LLDPDU
+	}
+)
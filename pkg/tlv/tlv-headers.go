@@ -0,0 +1,127 @@
+package tlv
+
+type TLVHeader8x8Format struct {
+	// A one-byte type followed by a one-byte length of the value,
+	// as in DHCP options (RFC 2132) and IPv4 options (RFC 791).
+
+	TLVHeader8x8FormatWord0 `word:"16"`
+}
+
+type TLVHeader8x8FormatWord0 struct {
+	Type   uint8 `bitfield:"8"`
+	Length uint8 `bitfield:"8"`
+}
+
+func (h *TLVHeader8x8Format) TLVType() uint64 {
+	return uint64(h.Type)
+}
+
+func (h *TLVHeader8x8Format) TLVLength() int {
+	return int(h.Length)
+}
+
+func (h *TLVHeader8x8Format) SetTLVType(tlvType uint64) {
+	h.Type = uint8(tlvType)
+
+	return
+}
+
+func (h *TLVHeader8x8Format) SetTLVLength(length int) {
+	h.Length = uint8(length)
+
+	return
+}
+
+type TLVHeader16x16Format struct {
+	// A two-byte type followed by a two-byte length of the value,
+	// as in many vendor protocols and DHCPv6 options (RFC 8415).
+
+	TLVHeader16x16FormatWord0 `word:"32"`
+}
+
+type TLVHeader16x16FormatWord0 struct {
+	Type   uint16 `bitfield:"16"`
+	Length uint16 `bitfield:"16"`
+}
+
+func (h *TLVHeader16x16Format) TLVType() uint64 {
+	return uint64(h.Type)
+}
+
+func (h *TLVHeader16x16Format) TLVLength() int {
+	return int(h.Length)
+}
+
+func (h *TLVHeader16x16Format) SetTLVType(tlvType uint64) {
+	h.Type = uint16(tlvType)
+
+	return
+}
+
+func (h *TLVHeader16x16Format) SetTLVLength(length int) {
+	h.Length = uint16(length)
+
+	return
+}
+
+type LLDPTLVHeaderFormat struct {
+	// Reference: Section 8.4 "Basic TLV format" of
+	// IEEE Std 802.1AB-2016 Station and Media Access Control
+	// Connectivity Discovery
+
+	// > +----------------+--------------------+------------------------+
+	// > |    TLV type    | TLV information    |  TLV information       |
+	// > |                | string length      |  string                |
+	// > +----------------+--------------------+------------------------+
+	// > |    7 bits      |      9 bits        |  0 <= n <= 511 octets  |
+	// > +----------------+--------------------+------------------------+
+
+	LLDPTLVHeaderFormatWord0 `word:"16"`
+}
+
+type LLDPTLVHeaderFormatWord0 struct {
+	Type   uint8  `bitfield:"7"`
+	Length uint16 `bitfield:"9"`
+}
+
+func (h *LLDPTLVHeaderFormat) TLVType() uint64 {
+	return uint64(h.Type)
+}
+
+func (h *LLDPTLVHeaderFormat) TLVLength() int {
+	return int(h.Length)
+}
+
+func (h *LLDPTLVHeaderFormat) SetTLVType(tlvType uint64) {
+	h.Type = uint8(tlvType)
+
+	return
+}
+
+func (h *LLDPTLVHeaderFormat) SetTLVLength(length int) {
+	h.Length = uint16(length)
+
+	return
+}
+
+const (
+	LLDPTLVTypeEndOfLLDPDU = iota
+	LLDPTLVTypeChassisID
+	LLDPTLVTypePortID
+	LLDPTLVTypeTimeToLive
+	LLDPTLVTypePortDescription
+	LLDPTLVTypeSystemName
+	LLDPTLVTypeSystemDescription
+	LLDPTLVTypeSystemCapabilities
+	LLDPTLVTypeManagementAddress
+
+	LLDPTLVTypeOrganizationallySpecific = 127
+)
+
+type LLDPTimeToLiveFormat struct {
+	LLDPTimeToLiveFormatWord0 `word:"16"`
+}
+
+type LLDPTimeToLiveFormatWord0 struct {
+	Seconds uint16 `bitfield:"16"`
+}
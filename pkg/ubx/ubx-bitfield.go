@@ -0,0 +1,113 @@
+package ubx
+
+import (
+	"errors"
+
+	"github.com/encodingx/binary"
+)
+
+var (
+	ErrUBXBitfieldLengthMismatch = errors.New(
+		"A format-struct representing a UBX bitfield " +
+			"should be as long as the bitfield. " +
+			"The format-struct and the bitfield differ in length.",
+	)
+)
+
+// UBX bitfield types X1, X2 and X4 are little-endian on the wire.
+// Payloads hold them as unsigned integers,
+// which these functions convert to and from format-structs
+// whose words list bit fields from the most significant bit down.
+
+func UnmarshalUBXX1(x uint8, iface interface{}) (e error) {
+	e = binary.Unmarshal([]byte{x}, iface)
+
+	return
+}
+
+func UnmarshalUBXX2(x uint16, iface interface{}) (e error) {
+	var (
+		bytes []byte = make([]byte, 2)
+	)
+
+	binary.BigEndian.PutUint16(bytes, x)
+
+	e = binary.Unmarshal(bytes, iface)
+
+	return
+}
+
+func UnmarshalUBXX4(x uint32, iface interface{}) (e error) {
+	var (
+		bytes []byte = make([]byte, 4)
+	)
+
+	binary.BigEndian.PutUint32(bytes, x)
+
+	e = binary.Unmarshal(bytes, iface)
+
+	return
+}
+
+func MarshalUBXX1(iface interface{}) (x uint8, e error) {
+	var (
+		bytes []byte
+	)
+
+	bytes, e = binary.Marshal(iface)
+	if e != nil {
+		return
+	}
+
+	if len(bytes) != 1 {
+		e = ErrUBXBitfieldLengthMismatch
+
+		return
+	}
+
+	x = bytes[0]
+
+	return
+}
+
+func MarshalUBXX2(iface interface{}) (x uint16, e error) {
+	var (
+		bytes []byte
+	)
+
+	bytes, e = binary.Marshal(iface)
+	if e != nil {
+		return
+	}
+
+	if len(bytes) != 2 {
+		e = ErrUBXBitfieldLengthMismatch
+
+		return
+	}
+
+	x = binary.BigEndian.Uint16(bytes)
+
+	return
+}
+
+func MarshalUBXX4(iface interface{}) (x uint32, e error) {
+	var (
+		bytes []byte
+	)
+
+	bytes, e = binary.Marshal(iface)
+	if e != nil {
+		return
+	}
+
+	if len(bytes) != 4 {
+		e = ErrUBXBitfieldLengthMismatch
+
+		return
+	}
+
+	x = binary.BigEndian.Uint32(bytes)
+
+	return
+}
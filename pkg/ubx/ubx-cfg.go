@@ -0,0 +1,245 @@
+package ubx
+
+const (
+	UBXACKNAK = 0x00
+	UBXACKACK = 0x01
+)
+
+type UBXACKACKPayload struct {
+	// Reference: Section 32.9.2 "UBX-ACK-ACK (0x05 0x01)"
+
+	// > Output upon processing of an input message. A UBX-ACK-ACK is sent as
+	// > soon as possible but at least within one second.
+
+	ClsID uint8 // Class ID of the Acknowledged Message
+	MsgID uint8 // Message ID of the Acknowledged Message
+}
+
+func (UBXACKACKPayload) UBXClass() uint8 {
+	return UBXClassACK
+}
+
+func (UBXACKACKPayload) UBXID() uint8 {
+	return UBXACKACK
+}
+
+type UBXACKNAKPayload struct {
+	// Reference: Section 32.9.1 "UBX-ACK-NAK (0x05 0x00)"
+
+	// > Output upon processing of an input message. A UBX-ACK-NAK is sent
+	// > as soon as possible but at least within one second.
+
+	ClsID uint8 // Class ID of the Not-Acknowledged Message
+	MsgID uint8 // Message ID of the Not-Acknowledged Message
+}
+
+func (UBXACKNAKPayload) UBXClass() uint8 {
+	return UBXClassACK
+}
+
+func (UBXACKNAKPayload) UBXID() uint8 {
+	return UBXACKNAK
+}
+
+const (
+	UBXCFGPRT  = 0x00
+	UBXCFGMSG  = 0x01
+	UBXCFGRST  = 0x04
+	UBXCFGRATE = 0x08
+)
+
+type UBXCFGPRTUARTPayload struct {
+	// Reference: Section 32.10.25.5 "Port Configuration for UART Ports"
+
+	// > Several configurations can be concatenated to one input message.
+	// > In this case the payload length can be a multiple of the normal
+	// > length (see the other versions of CFG-PRT). Output messages from
+	// > the module contain only one configuration unit.
+
+	PortID       uint8
+	Reserved1    uint8
+	TxReady      uint16 // X2, see UBXCFGPRTTxReadyFormat
+	Mode         uint32 // X4, see UBXCFGPRTModeFormat
+	BaudRate     uint32
+	InProtoMask  uint16 // X2, see UBXCFGPRTProtoMaskFormat
+	OutProtoMask uint16 // X2, see UBXCFGPRTProtoMaskFormat
+	Flags        uint16 // X2, see UBXCFGPRTFlagsFormat
+	Reserved2    [2]uint8
+}
+
+func (UBXCFGPRTUARTPayload) UBXClass() uint8 {
+	return UBXClassCFG
+}
+
+func (UBXCFGPRTUARTPayload) UBXID() uint8 {
+	return UBXCFGPRT
+}
+
+const (
+	UBXCFGPRTPortIDI2C   = 0
+	UBXCFGPRTPortIDUART1 = 1
+	UBXCFGPRTPortIDUART2 = 2
+	UBXCFGPRTPortIDUSB   = 3
+	UBXCFGPRTPortIDSPI   = 4
+)
+
+type UBXCFGPRTTxReadyFormat struct {
+	UBXCFGPRTTxReadyFormatWord0 `word:"16"`
+}
+
+type UBXCFGPRTTxReadyFormatWord0 struct {
+	Thres uint16 `bitfield:"9"`
+	Pin   uint8  `bitfield:"5"`
+	Pol   bool   `bitfield:"1"`
+	En    bool   `bitfield:"1"`
+	// > en     Enable TX ready feature for this port
+	// > pol    Polarity
+	// > pin    PIO to be used (must not be in use by another function)
+	// > thres  Threshold, in units of 8 bytes
+}
+
+type UBXCFGPRTModeFormat struct {
+	UBXCFGPRTModeFormatWord0 `word:"32"`
+}
+
+type UBXCFGPRTModeFormatWord0 struct {
+	Reserved2 uint32 `bitfield:"18"`
+	NStopBits uint8  `bitfield:"2"`
+	Parity    uint8  `bitfield:"3"`
+	Reserved1 bool   `bitfield:"1"`
+	CharLen   uint8  `bitfield:"2"`
+	Reserved0 uint8  `bitfield:"6"`
+	// > charLen    Character length
+	// >              00 5bit (not supported)
+	// >              01 6bit (not supported)
+	// >              10 7bit (supported only with parity)
+	// >              11 8bit
+	// > parity     000 Even parity
+	// >            001 Odd parity
+	// >            10X No parity
+	// >            X1X Reserved
+	// > nStopBits  Number of Stop bits
+	// >              00 1 Stop bit
+	// >              01 1.5 Stop bit
+	// >              10 2 Stop bit
+	// >              11 0.5 Stop bit
+}
+
+const (
+	UBXCFGPRTModeCharLen7Bit = 0b10
+	UBXCFGPRTModeCharLen8Bit = 0b11
+)
+
+const (
+	UBXCFGPRTModeParityEven = 0b000
+	UBXCFGPRTModeParityOdd  = 0b001
+	UBXCFGPRTModeParityNone = 0b100
+)
+
+const (
+	UBXCFGPRTModeNStopBits1   = 0b00
+	UBXCFGPRTModeNStopBits1p5 = 0b01
+	UBXCFGPRTModeNStopBits2   = 0b10
+	UBXCFGPRTModeNStopBits0p5 = 0b11
+)
+
+type UBXCFGPRTProtoMaskFormat struct {
+	UBXCFGPRTProtoMaskFormatWord0 `word:"16"`
+}
+
+type UBXCFGPRTProtoMaskFormatWord0 struct {
+	Reserved1 uint16 `bitfield:"10"`
+	RTCM3     bool   `bitfield:"1"`
+	Reserved0 uint8  `bitfield:"2"`
+	RTCM      bool   `bitfield:"1"`
+	NMEA      bool   `bitfield:"1"`
+	UBX       bool   `bitfield:"1"`
+}
+
+type UBXCFGPRTFlagsFormat struct {
+	UBXCFGPRTFlagsFormatWord0 `word:"16"`
+}
+
+type UBXCFGPRTFlagsFormatWord0 struct {
+	Reserved1         uint16 `bitfield:"14"`
+	ExtendedTxTimeout bool   `bitfield:"1"`
+	Reserved0         bool   `bitfield:"1"`
+}
+
+type UBXCFGMSGPayload struct {
+	// Reference: Section 32.10.18.3 "Set Message Rate"
+
+	// > Set message rate configuration for the current port. See also
+	// > section How to change between protocols.
+
+	MsgClass uint8
+	MsgID    uint8
+	Rate     uint8 // Send rate on current port, in navigation solutions
+}
+
+func (UBXCFGMSGPayload) UBXClass() uint8 {
+	return UBXClassCFG
+}
+
+func (UBXCFGMSGPayload) UBXID() uint8 {
+	return UBXCFGMSG
+}
+
+type UBXCFGRSTPayload struct {
+	// Reference: Section 32.10.28 "UBX-CFG-RST (0x06 0x04)"
+
+	// > Reset Receiver / Clear Backup Data Structures
+
+	NavBbrMask uint16 // X2, BBR sections to clear
+	ResetMode  uint8
+	Reserved1  uint8
+}
+
+func (UBXCFGRSTPayload) UBXClass() uint8 {
+	return UBXClassCFG
+}
+
+func (UBXCFGRSTPayload) UBXID() uint8 {
+	return UBXCFGRST
+}
+
+const (
+	UBXCFGRSTNavBbrMaskHotStart  = 0x0000
+	UBXCFGRSTNavBbrMaskWarmStart = 0x0001
+	UBXCFGRSTNavBbrMaskColdStart = 0xffff
+)
+
+const (
+	UBXCFGRSTResetModeHardwareImmediately = 0x00
+	UBXCFGRSTResetModeSoftware            = 0x01
+	UBXCFGRSTResetModeSoftwareGNSSOnly    = 0x02
+	UBXCFGRSTResetModeHardwareAfterStop   = 0x04
+	UBXCFGRSTResetModeGNSSStop            = 0x08
+	UBXCFGRSTResetModeGNSSStart           = 0x09
+)
+
+type UBXCFGRATEPayload struct {
+	// Reference: Section 32.10.27 "UBX-CFG-RATE (0x06 0x08)"
+
+	// > Navigation/Measurement Rate Settings
+
+	MeasRate uint16 // The elapsed time between GNSS measurements, ms
+	NavRate  uint16 // The ratio between measurements and navigation solutions
+	TimeRef  uint16 // The time system to which measurements are aligned
+}
+
+func (UBXCFGRATEPayload) UBXClass() uint8 {
+	return UBXClassCFG
+}
+
+func (UBXCFGRATEPayload) UBXID() uint8 {
+	return UBXCFGRATE
+}
+
+const (
+	UBXCFGRATETimeRefUTC     = 0
+	UBXCFGRATETimeRefGPS     = 1
+	UBXCFGRATETimeRefGLONASS = 2
+	UBXCFGRATETimeRefBeiDou  = 3
+	UBXCFGRATETimeRefGalileo = 4
+)
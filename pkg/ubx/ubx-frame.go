@@ -0,0 +1,277 @@
+package ubx
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/encodingx/binary"
+)
+
+type UBXFrameHeaderFormat struct {
+	// Reference: Section 32.2 "UBX Frame Structure" of
+	// u-blox 8 / u-blox M8 Receiver Description
+	// Including Protocol Specification (UBX-13003221)
+
+	// > The structure of a basic UBX Frame is shown in the following diagram.
+	// >
+	// >   SYNC CHAR 1 | SYNC CHAR 2 | CLASS | ID | LENGTH | PAYLOAD | CK_A CK_B
+	// >   1 byte        1 byte        1 byte  1 byte  2 bytes  LENGTH    2 bytes
+	// >
+	// > o Every Frame starts with a 2-byte Preamble consisting of two
+	// >   synchronization characters: 0xB5 0x62.
+	// > o A 1-byte Message Class field follows. A Class is a group of
+	// >   messages that are related to each other.
+	// > o A 1-byte Message ID field defines the message that is to follow.
+	// > o A 2-byte Length field follows. The length is defined as being that
+	// >   of the payload only. It does not include the Preamble, Message
+	// >   Class, Message ID, Length, or CRC fields. The number format of the
+	// >   length field is a Little-Endian unsigned 16-bit integer.
+
+	UBXFrameHeaderFormatWord0 `word:"32"`
+}
+
+type UBXFrameHeaderFormatWord0 struct {
+	SyncChar1 uint8 `bitfield:"8"`
+	SyncChar2 uint8 `bitfield:"8"`
+	Class     uint8 `bitfield:"8"`
+	ID        uint8 `bitfield:"8"`
+}
+
+const (
+	UBXSyncChar1 = 0xb5
+	UBXSyncChar2 = 0x62
+)
+
+const (
+	UBXFrameHeaderLengthInBytes   = 6
+	UBXFrameChecksumLengthInBytes = 2
+	UBXFrameMaxPayloadLength      = 1<<16 - 1
+)
+
+const (
+	UBXClassNAV = 0x01
+	UBXClassRXM = 0x02
+	UBXClassINF = 0x04
+	UBXClassACK = 0x05
+	UBXClassCFG = 0x06
+	UBXClassUPD = 0x09
+	UBXClassMON = 0x0a
+	UBXClassAID = 0x0b
+	UBXClassTIM = 0x0d
+	UBXClassESF = 0x10
+	UBXClassMGA = 0x13
+	UBXClassLOG = 0x21
+	UBXClassSEC = 0x27
+	UBXClassHNR = 0x28
+)
+
+var (
+	ErrUBXChecksumMismatch = errors.New(
+		"A UBX frame should end with the 8-bit Fletcher checksum " +
+			"of its class, ID, length and payload. " +
+			"The checksum of the frame does not match its contents.",
+	)
+
+	ErrUBXFrameTruncated = errors.New(
+		"A UBX frame should be as long as its length field indicates. " +
+			"The frame is shorter than its header, payload and checksum.",
+	)
+
+	ErrUBXMessageMismatch = errors.New(
+		"A UBX payload should be decoded into the message " +
+			"identified by the class and ID of its frame. " +
+			"The frame carries a different message.",
+	)
+
+	ErrUBXPayloadLengthMismatch = errors.New(
+		"A UBX payload should be of length equal to " +
+			"the length of the message it is decoded into. " +
+			"The payload and the message differ in length.",
+	)
+
+	ErrUBXPayloadTooLong = errors.New(
+		"A UBX payload should be at most 65535 bytes long. " +
+			"The payload is too long to be framed.",
+	)
+
+	ErrUBXSyncCharsNotFound = errors.New(
+		"A UBX frame should begin with sync chars 0xB5 0x62. " +
+			"The frame does not begin with the sync chars.",
+	)
+)
+
+type UBXMessage interface {
+	UBXClass() uint8
+	UBXID() uint8
+}
+
+type UBXFrame struct {
+	Class   uint8
+	ID      uint8
+	Payload []byte
+}
+
+func NewUBXFrame(message UBXMessage) (frame UBXFrame, e error) {
+	var (
+		buffer bytes.Buffer
+	)
+
+	e = binary.Write(&buffer, binary.LittleEndian, message)
+	if e != nil {
+		return
+	}
+
+	frame = UBXFrame{
+		Class:   message.UBXClass(),
+		ID:      message.UBXID(),
+		Payload: buffer.Bytes(),
+	}
+
+	return
+}
+
+func ParseUBXFrame(bytes []byte) (frame UBXFrame, e error) {
+	var (
+		checksumA uint8
+		checksumB uint8
+		end       int
+		header    UBXFrameHeaderFormat
+		length    int
+	)
+
+	if len(bytes) < UBXFrameHeaderLengthInBytes+UBXFrameChecksumLengthInBytes {
+		e = ErrUBXFrameTruncated
+
+		return
+	}
+
+	e = binary.Unmarshal(bytes[:4], &header)
+	if e != nil {
+		return
+	}
+
+	if header.SyncChar1 != UBXSyncChar1 || header.SyncChar2 != UBXSyncChar2 {
+		e = ErrUBXSyncCharsNotFound
+
+		return
+	}
+
+	length = int(binary.LittleEndian.Uint16(bytes[4:6]))
+
+	if len(bytes) <
+		UBXFrameHeaderLengthInBytes+length+UBXFrameChecksumLengthInBytes {
+		e = ErrUBXFrameTruncated
+
+		return
+	}
+
+	end = UBXFrameHeaderLengthInBytes + length
+
+	checksumA, checksumB = UBXChecksum(bytes[2:end])
+
+	if bytes[end] != checksumA || bytes[end+1] != checksumB {
+		e = ErrUBXChecksumMismatch
+
+		return
+	}
+
+	frame = UBXFrame{
+		Class:   header.Class,
+		ID:      header.ID,
+		Payload: bytes[UBXFrameHeaderLengthInBytes:end],
+	}
+
+	return
+}
+
+func (f UBXFrame) MarshalBinary() (bytes []byte, e error) {
+	var (
+		checksumA   uint8
+		checksumB   uint8
+		header      UBXFrameHeaderFormat
+		headerBytes []byte
+	)
+
+	if len(f.Payload) > UBXFrameMaxPayloadLength {
+		e = ErrUBXPayloadTooLong
+
+		return
+	}
+
+	header = UBXFrameHeaderFormat{
+		UBXFrameHeaderFormatWord0{
+			SyncChar1: UBXSyncChar1,
+			SyncChar2: UBXSyncChar2,
+			Class:     f.Class,
+			ID:        f.ID,
+		},
+	}
+
+	headerBytes, e = binary.Marshal(&header)
+	if e != nil {
+		return
+	}
+
+	bytes = make([]byte,
+		UBXFrameHeaderLengthInBytes+len(f.Payload)+
+			UBXFrameChecksumLengthInBytes,
+	)
+
+	copy(bytes, headerBytes)
+
+	binary.LittleEndian.PutUint16(bytes[4:6],
+		uint16(len(f.Payload)),
+	)
+
+	copy(bytes[UBXFrameHeaderLengthInBytes:], f.Payload)
+
+	checksumA, checksumB = UBXChecksum(
+		bytes[2 : UBXFrameHeaderLengthInBytes+len(f.Payload)],
+	)
+
+	bytes[len(bytes)-2] = checksumA
+	bytes[len(bytes)-1] = checksumB
+
+	return
+}
+
+func (f UBXFrame) DecodePayload(message UBXMessage) (e error) {
+	if f.Class != message.UBXClass() || f.ID != message.UBXID() {
+		e = ErrUBXMessageMismatch
+
+		return
+	}
+
+	if len(f.Payload) != binary.Size(message) {
+		e = ErrUBXPayloadLengthMismatch
+
+		return
+	}
+
+	e = binary.Read(bytes.NewReader(f.Payload), binary.LittleEndian, message)
+	if e != nil {
+		return
+	}
+
+	return
+}
+
+func UBXChecksum(bytes []byte) (checksumA, checksumB uint8) {
+	// Reference: Section 32.4 "UBX Checksum"
+
+	// > The checksum is calculated over the Message, starting and including
+	// > the CLASS field, up until, but excluding, the Checksum Field.
+	// >
+	// > The checksum algorithm used is the 8-Bit Fletcher Algorithm.
+
+	var (
+		b byte
+	)
+
+	for _, b = range bytes {
+		checksumA += b
+		checksumB += checksumA
+	}
+
+	return
+}
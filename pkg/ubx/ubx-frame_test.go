@@ -0,0 +1,163 @@
+package ubx
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalUBXFrame(t *testing.T) {
+	var (
+		e     error
+		frame UBXFrame
+		raw   []byte
+	)
+
+	frame, e = NewUBXFrame(&cfgRatePayload)
+
+	assert.Nil(t, e)
+
+	raw, e = frame.MarshalBinary()
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		cfgRateBytes, raw,
+	)
+}
+
+func TestParseUBXFrame(t *testing.T) {
+	var (
+		e       error
+		frame   UBXFrame
+		payload UBXCFGRATEPayload
+		corrupt []byte
+	)
+
+	frame, e = ParseUBXFrame(cfgRateBytes)
+
+	assert.Nil(t, e)
+
+	e = frame.DecodePayload(&payload)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		cfgRatePayload, payload,
+	)
+
+	e = frame.DecodePayload(&UBXNAVPVTPayload{})
+
+	assert.Equal(t,
+		ErrUBXMessageMismatch, e,
+	)
+
+	corrupt = append([]byte(nil), cfgRateBytes...)
+
+	corrupt[7]++
+
+	_, e = ParseUBXFrame(corrupt)
+
+	assert.Equal(t,
+		ErrUBXChecksumMismatch, e,
+	)
+
+	_, e = ParseUBXFrame(cfgRateBytes[:10])
+
+	assert.Equal(t,
+		ErrUBXFrameTruncated, e,
+	)
+}
+
+func TestUBXReaderResynchronises(t *testing.T) {
+	var (
+		corrupt []byte
+		e       error
+		frame   UBXFrame
+		reader  *UBXReader
+		stream  []byte
+	)
+
+	corrupt = append([]byte(nil), cfgRateBytes...)
+
+	corrupt[8]++
+
+	stream = append(stream, 0x00, UBXSyncChar1, 0x13)
+	stream = append(stream, corrupt...)
+	stream = append(stream, cfgRateBytes...)
+	stream = append(stream, UBXSyncChar1, UBXSyncChar2, UBXClassNAV)
+
+	reader = NewUBXReader(bytes.NewReader(stream), 0)
+
+	frame, e = reader.ReadFrame()
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		cfgRateBytes[6:12], frame.Payload,
+	)
+
+	assert.Equal(t,
+		3+len(corrupt), reader.Skipped(),
+	)
+
+	_, e = reader.ReadFrame()
+
+	assert.Equal(t,
+		io.EOF, e,
+	)
+}
+
+func TestUBXBitfields(t *testing.T) {
+	var (
+		e      error
+		flags  UBXNAVPVTFlagsFormat
+		flags3 UBXNAVPVTFlags3Format
+		x      uint16
+	)
+
+	e = UnmarshalUBXX1(0b10000011, &flags)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint8(UBXNAVPVTCarrSolnFixed), flags.CarrSoln,
+	)
+
+	assert.True(t, flags.GNSSFixOK)
+
+	assert.True(t, flags.DiffSoln)
+
+	flags3.LastCorrectionAge = 0b1010
+	flags3.InvalidLLH = true
+
+	x, e = MarshalUBXX2(&flags3)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint16(0b10101), x,
+	)
+
+	_, e = MarshalUBXX2(&flags)
+
+	assert.Equal(t,
+		ErrUBXBitfieldLengthMismatch, e,
+	)
+}
+
+var (
+	cfgRatePayload = UBXCFGRATEPayload{
+		MeasRate: 1000,
+		NavRate:  1,
+		TimeRef:  UBXCFGRATETimeRefGPS,
+	}
+
+	cfgRateBytes = []byte{
+		0xb5, 0x62, 0x06, 0x08, 0x06, 0x00,
+		0xe8, 0x03, 0x01, 0x00, 0x01, 0x00,
+		0x01, 0x39,
+	}
+)
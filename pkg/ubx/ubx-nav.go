@@ -0,0 +1,302 @@
+package ubx
+
+const (
+	UBXNAVPOSLLH  = 0x02
+	UBXNAVSTATUS  = 0x03
+	UBXNAVPVT     = 0x07
+	UBXNAVVELNED  = 0x12
+	UBXNAVTIMEUTC = 0x21
+)
+
+type UBXNAVPOSLLHPayload struct {
+	// Reference: Section 32.18.14 "UBX-NAV-POSLLH (0x01 0x02)"
+
+	// > Geodetic Position Solution
+	// >
+	// > See important comments concerning validity of position given in
+	// > section Navigation Output Filters.
+	// > This message outputs the geodetic position in the currently selected
+	// > ellipsoid. The default is the WGS84 Ellipsoid, but can be changed
+	// > with the message UBX-CFG-DAT.
+
+	ITOW   uint32 // GPS time of week of the navigation epoch, ms
+	Lon    int32  // Longitude, 1e-7 deg
+	Lat    int32  // Latitude, 1e-7 deg
+	Height int32  // Height above ellipsoid, mm
+	HMSL   int32  // Height above mean sea level, mm
+	HAcc   uint32 // Horizontal accuracy estimate, mm
+	VAcc   uint32 // Vertical accuracy estimate, mm
+}
+
+func (UBXNAVPOSLLHPayload) UBXClass() uint8 {
+	return UBXClassNAV
+}
+
+func (UBXNAVPOSLLHPayload) UBXID() uint8 {
+	return UBXNAVPOSLLH
+}
+
+type UBXNAVSTATUSPayload struct {
+	// Reference: Section 32.18.21 "UBX-NAV-STATUS (0x01 0x03)"
+
+	// > Receiver Navigation Status
+
+	ITOW    uint32
+	GPSFix  uint8
+	Flags   uint8 // X1, see UBXNAVSTATUSFlagsFormat
+	FixStat uint8 // X1, see UBXNAVSTATUSFixStatFormat
+	Flags2  uint8 // X1, see UBXNAVSTATUSFlags2Format
+	TTFF    uint32
+	MSSS    uint32
+}
+
+func (UBXNAVSTATUSPayload) UBXClass() uint8 {
+	return UBXClassNAV
+}
+
+func (UBXNAVSTATUSPayload) UBXID() uint8 {
+	return UBXNAVSTATUS
+}
+
+const (
+	UBXNAVGPSFixNoFix = iota
+	UBXNAVGPSFixDeadReckoningOnly
+	UBXNAVGPSFix2D
+	UBXNAVGPSFix3D
+	UBXNAVGPSFixGPSAndDeadReckoning
+	UBXNAVGPSFixTimeOnly
+)
+
+type UBXNAVSTATUSFlagsFormat struct {
+	UBXNAVSTATUSFlagsFormatWord0 `word:"8"`
+}
+
+type UBXNAVSTATUSFlagsFormatWord0 struct {
+	Reserved uint8 `bitfield:"4"`
+	TOWSet   bool  `bitfield:"1"`
+	WKNSet   bool  `bitfield:"1"`
+	DiffSoln bool  `bitfield:"1"`
+	GPSFixOK bool  `bitfield:"1"`
+	// > gpsFixOk   position and velocity valid and within DOP and ACC Masks
+	// > diffSoln   differential corrections were applied
+	// > wknSet     Week Number valid
+	// > towSet     Time of Week valid
+}
+
+type UBXNAVSTATUSFixStatFormat struct {
+	UBXNAVSTATUSFixStatFormatWord0 `word:"8"`
+}
+
+type UBXNAVSTATUSFixStatFormatWord0 struct {
+	MapMatching   uint8 `bitfield:"2"`
+	Reserved      uint8 `bitfield:"4"`
+	CarrSolnValid bool  `bitfield:"1"`
+	DiffCorr      bool  `bitfield:"1"`
+}
+
+type UBXNAVSTATUSFlags2Format struct {
+	UBXNAVSTATUSFlags2FormatWord0 `word:"8"`
+}
+
+type UBXNAVSTATUSFlags2FormatWord0 struct {
+	CarrSoln      uint8 `bitfield:"2"`
+	Reserved      bool  `bitfield:"1"`
+	SpoofDetState uint8 `bitfield:"2"`
+	Reserved1     bool  `bitfield:"1"`
+	PSMState      uint8 `bitfield:"2"`
+}
+
+type UBXNAVPVTPayload struct {
+	// Reference: Section 32.18.15 "UBX-NAV-PVT (0x01 0x07)"
+
+	// > Navigation Position Velocity Time Solution
+	// >
+	// > This message combines position, velocity and time solution, including
+	// > accuracy figures.
+
+	ITOW      uint32
+	Year      uint16
+	Month     uint8
+	Day       uint8
+	Hour      uint8
+	Min       uint8
+	Sec       uint8
+	Valid     uint8 // X1, see UBXNAVPVTValidFormat
+	TAcc      uint32
+	Nano      int32
+	FixType   uint8
+	Flags     uint8 // X1, see UBXNAVPVTFlagsFormat
+	Flags2    uint8 // X1, see UBXNAVPVTFlags2Format
+	NumSV     uint8
+	Lon       int32
+	Lat       int32
+	Height    int32
+	HMSL      int32
+	HAcc      uint32
+	VAcc      uint32
+	VelN      int32
+	VelE      int32
+	VelD      int32
+	GSpeed    int32
+	HeadMot   int32
+	SAcc      uint32
+	HeadAcc   uint32
+	PDOP      uint16
+	Flags3    uint16 // X2, see UBXNAVPVTFlags3Format
+	Reserved0 [4]uint8
+	HeadVeh   int32
+	MagDec    int16
+	MagAcc    uint16
+}
+
+func (UBXNAVPVTPayload) UBXClass() uint8 {
+	return UBXClassNAV
+}
+
+func (UBXNAVPVTPayload) UBXID() uint8 {
+	return UBXNAVPVT
+}
+
+const (
+	UBXNAVPVTFixTypeNoFix = iota
+	UBXNAVPVTFixTypeDeadReckoningOnly
+	UBXNAVPVTFixType2D
+	UBXNAVPVTFixType3D
+	UBXNAVPVTFixTypeGNSSAndDeadReckoning
+	UBXNAVPVTFixTypeTimeOnly
+)
+
+type UBXNAVPVTValidFormat struct {
+	UBXNAVPVTValidFormatWord0 `word:"8"`
+}
+
+type UBXNAVPVTValidFormatWord0 struct {
+	Reserved      uint8 `bitfield:"4"`
+	ValidMag      bool  `bitfield:"1"`
+	FullyResolved bool  `bitfield:"1"`
+	ValidTime     bool  `bitfield:"1"`
+	ValidDate     bool  `bitfield:"1"`
+	// > validDate      1 = valid UTC Date
+	// > validTime      1 = valid UTC time of day
+	// > fullyResolved  1 = UTC time of day has been fully resolved
+	// >                (no seconds uncertainty).
+	// > validMag       1 = valid magnetic declination
+}
+
+type UBXNAVPVTFlagsFormat struct {
+	UBXNAVPVTFlagsFormatWord0 `word:"8"`
+}
+
+type UBXNAVPVTFlagsFormatWord0 struct {
+	CarrSoln     uint8 `bitfield:"2"`
+	HeadVehValid bool  `bitfield:"1"`
+	PSMState     uint8 `bitfield:"3"`
+	DiffSoln     bool  `bitfield:"1"`
+	GNSSFixOK    bool  `bitfield:"1"`
+	// > gnssFixOK     1 = valid fix (i.e within DOP & accuracy masks)
+	// > diffSoln      1 = differential corrections were applied
+	// > psmState      Power Save Mode state
+	// > headVehValid  1 = heading of vehicle is valid, only set if the
+	// >               receiver is in sensor fusion mode
+	// > carrSoln      Carrier phase range solution status:
+	// >               0: no carrier phase range solution
+	// >               1: carrier phase range solution with floating
+	// >                  ambiguities
+	// >               2: carrier phase range solution with fixed
+	// >                  ambiguities
+}
+
+const (
+	UBXNAVPVTCarrSolnNone = iota
+	UBXNAVPVTCarrSolnFloat
+	UBXNAVPVTCarrSolnFixed
+)
+
+type UBXNAVPVTFlags2Format struct {
+	UBXNAVPVTFlags2FormatWord0 `word:"8"`
+}
+
+type UBXNAVPVTFlags2FormatWord0 struct {
+	ConfirmedTime bool  `bitfield:"1"`
+	ConfirmedDate bool  `bitfield:"1"`
+	ConfirmedAvai bool  `bitfield:"1"`
+	Reserved      uint8 `bitfield:"5"`
+	// > confirmedAvai  1 = information about UTC Date and Time of Day
+	// >                validity confirmation is available
+	// > confirmedDate  1 = UTC Date validity could be confirmed
+	// > confirmedTime  1 = UTC Time of Day could be confirmed
+}
+
+type UBXNAVPVTFlags3Format struct {
+	UBXNAVPVTFlags3FormatWord0 `word:"16"`
+}
+
+type UBXNAVPVTFlags3FormatWord0 struct {
+	Reserved          uint16 `bitfield:"11"`
+	LastCorrectionAge uint8  `bitfield:"4"`
+	InvalidLLH        bool   `bitfield:"1"`
+	// > invalidLlh         1 = Invalid lon, lat, height and hMSL
+	// > lastCorrectionAge  Age of the most recently received differential
+	// >                    correction
+}
+
+type UBXNAVVELNEDPayload struct {
+	// Reference: Section 32.18.30 "UBX-NAV-VELNED (0x01 0x12)"
+
+	// > Velocity Solution in NED
+
+	ITOW    uint32
+	VelN    int32  // cm/s
+	VelE    int32  // cm/s
+	VelD    int32  // cm/s
+	Speed   uint32 // Speed (3-D), cm/s
+	GSpeed  uint32 // Ground speed (2-D), cm/s
+	Heading int32  // Heading of motion 2-D, 1e-5 deg
+	SAcc    uint32 // Speed accuracy estimate, cm/s
+	CAcc    uint32 // Course / Heading accuracy estimate, 1e-5 deg
+}
+
+func (UBXNAVVELNEDPayload) UBXClass() uint8 {
+	return UBXClassNAV
+}
+
+func (UBXNAVVELNEDPayload) UBXID() uint8 {
+	return UBXNAVVELNED
+}
+
+type UBXNAVTIMEUTCPayload struct {
+	// Reference: Section 32.18.27 "UBX-NAV-TIMEUTC (0x01 0x21)"
+
+	// > UTC Time Solution
+
+	ITOW  uint32
+	TAcc  uint32
+	Nano  int32
+	Year  uint16
+	Month uint8
+	Day   uint8
+	Hour  uint8
+	Min   uint8
+	Sec   uint8
+	Valid uint8 // X1, see UBXNAVTIMEUTCValidFormat
+}
+
+func (UBXNAVTIMEUTCPayload) UBXClass() uint8 {
+	return UBXClassNAV
+}
+
+func (UBXNAVTIMEUTCPayload) UBXID() uint8 {
+	return UBXNAVTIMEUTC
+}
+
+type UBXNAVTIMEUTCValidFormat struct {
+	UBXNAVTIMEUTCValidFormatWord0 `word:"8"`
+}
+
+type UBXNAVTIMEUTCValidFormatWord0 struct {
+	UTCStandard uint8 `bitfield:"4"`
+	Reserved    bool  `bitfield:"1"`
+	ValidUTC    bool  `bitfield:"1"`
+	ValidWKN    bool  `bitfield:"1"`
+	ValidTOW    bool  `bitfield:"1"`
+}
@@ -0,0 +1,169 @@
+package ubx
+
+import (
+	"errors"
+	"io"
+
+	"github.com/encodingx/binary"
+)
+
+const (
+	ubxReaderChunkLength = 512
+)
+
+type UBXReader struct {
+	reader           io.Reader
+	buffer           []byte
+	eof              bool
+	maxPayloadLength int
+	skipped          int
+}
+
+func NewUBXReader(reader io.Reader, maxPayloadLength int) (r *UBXReader) {
+	if maxPayloadLength <= 0 || maxPayloadLength > UBXFrameMaxPayloadLength {
+		maxPayloadLength = UBXFrameMaxPayloadLength
+	}
+
+	r = &UBXReader{
+		reader:           reader,
+		maxPayloadLength: maxPayloadLength,
+	}
+
+	return
+}
+
+func (r *UBXReader) ReadFrame() (frame UBXFrame, e error) {
+	// Frames are delimited only by their sync chars,
+	// so on any inconsistency the reader discards one byte
+	// and searches for the next pair of sync chars.
+
+	var (
+		frameLength int
+		i           int
+		length      int
+	)
+
+	for {
+		i = r.indexOfSyncChars()
+
+		r.discard(i)
+
+		if len(r.buffer) < UBXFrameHeaderLengthInBytes {
+			e = r.fill(UBXFrameHeaderLengthInBytes)
+			if e != nil {
+				return
+			}
+
+			continue
+		}
+
+		length = int(binary.LittleEndian.Uint16(r.buffer[4:6]))
+
+		if length > r.maxPayloadLength {
+			r.discard(1)
+
+			continue
+		}
+
+		frameLength = UBXFrameHeaderLengthInBytes + length +
+			UBXFrameChecksumLengthInBytes
+
+		if len(r.buffer) < frameLength {
+			e = r.fill(frameLength)
+			if e != nil {
+				return
+			}
+
+			continue
+		}
+
+		frame, e = ParseUBXFrame(r.buffer[:frameLength])
+		if errors.Is(e, ErrUBXChecksumMismatch) {
+			r.discard(1)
+
+			continue
+		}
+
+		if e != nil {
+			return
+		}
+
+		frame.Payload = append([]byte(nil), frame.Payload...)
+
+		r.buffer = r.buffer[frameLength:]
+
+		return
+	}
+}
+
+// Skipped returns the number of bytes discarded while resynchronising
+// since the reader was created.
+func (r *UBXReader) Skipped() int {
+	return r.skipped
+}
+
+func (r *UBXReader) indexOfSyncChars() (i int) {
+	for i = 0; i < len(r.buffer); i++ {
+		if r.buffer[i] != UBXSyncChar1 {
+			continue
+		}
+
+		if i+1 == len(r.buffer) || r.buffer[i+1] == UBXSyncChar2 {
+			return
+		}
+	}
+
+	return
+}
+
+func (r *UBXReader) discard(n int) {
+	r.buffer = r.buffer[n:]
+
+	r.skipped += n
+
+	return
+}
+
+func (r *UBXReader) fill(length int) (e error) {
+	// A frame cut short by the end of the stream is discarded
+	// one byte at a time, in case it hides the start of a whole frame.
+
+	var (
+		chunk []byte
+		n     int
+	)
+
+	if r.eof {
+		if len(r.buffer) == 0 {
+			e = io.EOF
+
+			return
+		}
+
+		r.discard(1)
+
+		return
+	}
+
+	chunk = make([]byte, ubxReaderChunkLength)
+
+	for len(r.buffer) < length {
+		n, e = r.reader.Read(chunk)
+
+		r.buffer = append(r.buffer, chunk[:n]...)
+
+		if e == io.EOF {
+			r.eof = true
+
+			e = nil
+
+			return
+		}
+
+		if e != nil {
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,353 @@
+package binary
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/encodingx/binary/internal/codecs"
+	"github.com/encodingx/binary/internal/validation"
+)
+
+// Payload is a field of variable length following the words of a format,
+// streamed by a Decoder or an Encoder rather than held in memory.
+// It is declared last in a format-struct, tagged with a key "payload"
+// and the name of a bit field holding its length in number of bytes:
+//
+//	type RecordFormat struct {
+//		RecordFormatWord0 `word:"64"`
+//		Payload binary.Payload `payload:"Length"`
+//	}
+//
+// Marshal and Unmarshal ignore payloads.
+type Payload = codecs.Payload
+
+// Decoder unmarshals format-structs from a stream, one record at a time.
+type Decoder struct {
+	codec   *Codec
+	reader  *countingReader
+	payload *io.LimitedReader
+	records int
+}
+
+// PositionError locates an error of a Decoder in its stream.
+// Errors returned by Decode, other than io.EOF, wrap a *PositionError,
+// which in turn wraps the error at that position.
+// Where a *DecodingError is wrapped, each of its errors is in turn
+// a *PositionError locating the word at fault.
+type PositionError struct {
+	// Record is the index of the record being decoded, counting from zero.
+	Record int
+
+	// Offset is the offset in number of bytes from the start of the stream
+	// of the word at fault, or of the record if no word is at fault.
+	Offset int64
+
+	// BitOffset is the offset in number of bits from the start of the stream
+	// of the bit at fault, such as the first non-zero bit of padding,
+	// or of the word or record at fault if no bit is known.
+	BitOffset int64
+
+	Err error
+}
+
+// newPositionError locates e in a record starting at offset start,
+// at the offset of the word at fault if e has one.
+func newPositionError(record int, start int64, e error) (
+	positionError *PositionError,
+) {
+	var (
+		bitOffsetError interface{ BitOffset() int }
+		offsetError    interface{ Offset() int }
+	)
+
+	positionError = &PositionError{
+		Record: record,
+		Offset: start,
+		Err:    e,
+	}
+
+	if errors.As(e, &offsetError) {
+		positionError.Offset += int64(offsetError.Offset())
+	}
+
+	positionError.BitOffset = positionError.Offset * 8
+
+	if errors.As(e, &bitOffsetError) {
+		positionError.BitOffset = start*8 + int64(bitOffsetError.BitOffset())
+	}
+
+	return
+}
+
+func (e *PositionError) Error() string {
+	const (
+		format = "record %d at byte offset %d (bit offset %d): %v"
+	)
+
+	return fmt.Sprintf(format, e.Record, e.Offset, e.BitOffset, e.Err)
+}
+
+func (e *PositionError) Unwrap() error {
+	return e.Err
+}
+
+// countingReader counts the bytes read from a stream,
+// including those of payloads.
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(bytes []byte) (n int, e error) {
+	n, e = r.reader.Read(bytes)
+
+	r.count += int64(n)
+
+	return
+}
+
+// NewDecoder returns a decoder reading from reader.
+func NewDecoder(reader io.Reader) *Decoder {
+	return defaultCodec.NewDecoder(reader)
+}
+
+// NewDecoder returns a decoder reading from reader with the codec.
+func (c *Codec) NewDecoder(reader io.Reader) *Decoder {
+	return &Decoder{
+		codec: c,
+		reader: &countingReader{
+			reader: reader,
+		},
+	}
+}
+
+// Decode reads the words of a format-struct and unmarshals them into it.
+// If the format has a payload, Decode sets it to a reader
+// limited to the length of the payload and valid until the next call,
+// which skips any part of the payload not read.
+// Decode returns io.EOF if the stream ends before a record,
+// and an error wrapping io.ErrUnexpectedEOF if it ends within one,
+// including within its payload.
+func (decoder *Decoder) Decode(iface interface{}) (e error) {
+	const (
+		functionName = "Decode"
+	)
+
+	var (
+		bytes     []byte
+		ok        bool
+		operation codecs.CodecOperation
+		record    int
+		start     int64
+	)
+
+	record = decoder.records
+	start = decoder.reader.count
+
+	defer func() {
+		const (
+			decodeError = "Decode error: %w"
+		)
+
+		var (
+			decodingError *DecodingError
+			functionError validation.FunctionError
+			ok            bool
+		)
+
+		if e == nil || e == io.EOF {
+			return
+		}
+
+		functionError, ok = e.(validation.FunctionError)
+		if ok {
+			functionError.SetFunctionName(functionName)
+		}
+
+		decodingError, ok = e.(*DecodingError)
+		if ok {
+			decodingError.WrapErrors(
+				func(wordError error) error {
+					return newPositionError(record, start, wordError)
+				},
+			)
+
+			e = fmt.Errorf(decodeError,
+				&PositionError{
+					Record:    record,
+					Offset:    start,
+					BitOffset: start * 8,
+					Err:       e,
+				},
+			)
+
+			return
+		}
+
+		e = fmt.Errorf(decodeError,
+			newPositionError(record, start, e),
+		)
+
+		return
+	}()
+
+	operation, e = decoder.codec.codec.NewOperation(iface)
+	if e != nil {
+		return
+	}
+
+	if operation.HasTrailer() {
+		e = operation.NewFormatWithInvalidTrailerError()
+
+		return
+	}
+
+	if decoder.payload != nil {
+		_, e = io.Copy(io.Discard, decoder.payload)
+		if e == nil && decoder.payload.N > 0 {
+			e = io.ErrUnexpectedEOF
+		}
+
+		if e != nil {
+			// The stream ends within the payload of the previous record.
+			record--
+
+			start = decoder.reader.count
+
+			return
+		}
+
+		decoder.payload = nil
+
+		start = decoder.reader.count
+	}
+
+	bytes = make([]byte, operation.LengthInBytes())
+
+	_, e = io.ReadFull(decoder.reader, bytes)
+	if e != nil {
+		return
+	}
+
+	decoder.records++
+
+	e = operation.Unmarshal(bytes)
+
+	if operation.HasPayload() {
+		// The payload is skipped by the next call even if the record
+		// fails to decode, so that the stream stays in step.
+		decoder.payload = &io.LimitedReader{
+			R: decoder.reader,
+			N: int64(operation.PayloadLengthOf(bytes)),
+		}
+
+		_, ok = e.(*DecodingError)
+
+		if e == nil || ok {
+			operation.SetPayload(decoder.payload)
+		}
+	}
+
+	return
+}
+
+// Encoder marshals format-structs to a stream, one record at a time.
+type Encoder struct {
+	codec  *Codec
+	writer io.Writer
+}
+
+// NewEncoder returns an encoder writing to writer.
+func NewEncoder(writer io.Writer) *Encoder {
+	return defaultCodec.NewEncoder(writer)
+}
+
+// NewEncoder returns an encoder writing to writer with the codec.
+func (c *Codec) NewEncoder(writer io.Writer) *Encoder {
+	return &Encoder{
+		codec:  c,
+		writer: writer,
+	}
+}
+
+// Encode marshals a format-struct and writes its words.
+// If the format has a payload, Encode then copies from it
+// as many bytes as its length bit field declares,
+// and returns io.ErrUnexpectedEOF if it holds fewer.
+// A nil payload is empty.
+func (encoder *Encoder) Encode(iface interface{}) (e error) {
+	const (
+		functionName = "Encode"
+	)
+
+	var (
+		bytes     []byte
+		length    int64
+		operation codecs.CodecOperation
+		reader    io.Reader
+	)
+
+	defer func() {
+		const (
+			encodeError = "Encode error: %w"
+		)
+
+		var (
+			functionError validation.FunctionError
+			ok            bool
+		)
+
+		functionError, ok = e.(validation.FunctionError)
+		if ok {
+			functionError.SetFunctionName(functionName)
+
+			e = fmt.Errorf(encodeError, e)
+		}
+
+		return
+	}()
+
+	operation, e = encoder.codec.codec.NewOperation(iface)
+	if e != nil {
+		return
+	}
+
+	if operation.HasTrailer() {
+		e = operation.NewFormatWithInvalidTrailerError()
+
+		return
+	}
+
+	bytes, e = operation.Marshal()
+	if e != nil {
+		return
+	}
+
+	_, e = encoder.writer.Write(bytes)
+	if e != nil {
+		return
+	}
+
+	if !operation.HasPayload() {
+		return
+	}
+
+	length = int64(operation.PayloadLength())
+
+	reader = operation.Payload()
+	if reader == nil {
+		if length > 0 {
+			e = io.ErrUnexpectedEOF
+		}
+
+		return
+	}
+
+	_, e = io.CopyN(encoder.writer, reader, length)
+	if e == io.EOF {
+		e = io.ErrUnexpectedEOF
+	}
+
+	return
+}
@@ -0,0 +1,495 @@
+package binary
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	chunkFormat struct {
+		ChunkFormatWord0 `word:"32"`
+		Payload          Payload `payload:"Length"`
+	}
+
+	ChunkFormatWord0 struct {
+		Type   uint8  `bitfield:"8"`
+		Length uint32 `bitfield:"24"`
+	}
+)
+
+func TestDecoderAndEncoder(t *testing.T) {
+	var (
+		buffer  bytes.Buffer
+		chunk   chunkFormat
+		decoder *Decoder
+		e       error
+		encoder *Encoder
+		payload []byte
+	)
+
+	encoder = NewEncoder(&buffer)
+
+	chunk.Type = 0x01
+	chunk.Length = 5
+	chunk.Payload = Payload{Reader: strings.NewReader("hello, world")}
+
+	e = encoder.Encode(&chunk)
+
+	assert.Nil(t, e)
+
+	chunk.Type = 0x02
+	chunk.Length = 3
+	chunk.Payload = Payload{Reader: strings.NewReader("abc")}
+
+	e = encoder.Encode(&chunk)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]byte{
+			0x01, 0x00, 0x00, 0x05, 'h', 'e', 'l', 'l', 'o',
+			0x02, 0x00, 0x00, 0x03, 'a', 'b', 'c',
+		},
+		buffer.Bytes(),
+	)
+
+	decoder = NewDecoder(&buffer)
+
+	e = decoder.Decode(&chunk)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint8(0x01), chunk.Type,
+	)
+
+	// The part of a payload not read is skipped by the next call.
+
+	payload = make([]byte, 2)
+
+	_, e = io.ReadFull(chunk.Payload, payload)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		"he", string(payload),
+	)
+
+	e = decoder.Decode(&chunk)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint8(0x02), chunk.Type,
+	)
+
+	payload, e = io.ReadAll(chunk.Payload)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		"abc", string(payload),
+	)
+
+	e = decoder.Decode(&chunk)
+
+	assert.Equal(t,
+		io.EOF, e,
+	)
+}
+
+func TestDecoderAndEncoderGivenTruncatedStream(t *testing.T) {
+	var (
+		buffer        bytes.Buffer
+		chunk         chunkFormat
+		decoder       *Decoder
+		e             error
+		positionError *PositionError
+	)
+
+	chunk.Length = 5
+	chunk.Payload = Payload{Reader: strings.NewReader("abc")}
+
+	e = NewEncoder(&buffer).Encode(&chunk)
+
+	assert.Equal(t,
+		io.ErrUnexpectedEOF, e,
+	)
+
+	e = NewDecoder(
+		bytes.NewReader([]byte{0x01, 0x00}),
+	).Decode(&chunk)
+
+	assert.True(t,
+		errors.Is(e, io.ErrUnexpectedEOF),
+	)
+
+	// A stream ending within a payload is truncated too.
+
+	decoder = NewDecoder(
+		bytes.NewReader([]byte{0x01, 0x00, 0x00, 0x05, 'a', 'b'}),
+	)
+
+	e = decoder.Decode(&chunk)
+
+	assert.Nil(t, e)
+
+	e = decoder.Decode(&chunk)
+
+	assert.True(t,
+		errors.Is(e, io.ErrUnexpectedEOF),
+	)
+
+	assert.True(t,
+		errors.As(e, &positionError),
+	)
+
+	assert.Equal(t,
+		PositionError{
+			Record:    0,
+			Offset:    6,
+			BitOffset: 48,
+			Err:       io.ErrUnexpectedEOF,
+		},
+		*positionError,
+	)
+}
+
+func TestDecoderPositionError(t *testing.T) {
+	type (
+		alignedChunkFormat struct {
+			ChunkFormatWord0  `word:"32"`
+			RecordFormatWord0 `word:"8"`
+			RecordFormatWord1 `word:"32,align=8,padding=zero"`
+			Payload           Payload `payload:"Length"`
+		}
+	)
+
+	var (
+		chunk         alignedChunkFormat
+		decoder       *Decoder
+		e             error
+		paddingError  *PaddingError
+		positionError *PositionError
+		stream        []byte
+	)
+
+	stream = []byte{
+		0x01, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 'a', 'b',
+		0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10, 0x00,
+		0x00, 0x00, 0x00, 0x00,
+	}
+
+	decoder = NewDecoder(
+		bytes.NewReader(stream),
+	)
+
+	e = decoder.Decode(&chunk)
+
+	assert.Nil(t, e)
+
+	e = decoder.Decode(&chunk)
+
+	assert.True(t,
+		errors.As(e, &positionError),
+	)
+
+	assert.Equal(t,
+		PositionError{
+			Record:    1,
+			Offset:    20,
+			BitOffset: 163,
+			Err:       positionError.Err,
+		},
+		*positionError,
+	)
+
+	assert.True(t,
+		errors.As(e, &paddingError),
+	)
+
+	assert.Equal(t,
+		"Decode error: record 1 at byte offset 20 (bit offset 163): "+
+			"Padding declared \"padding=zero\" should be zero. "+
+			"Argument to Decode points to a format-struct "+
+			"\"binary.alignedChunkFormat\" "+
+			"that has a word \"RecordFormatWord1\" "+
+			"preceded by padding with a non-zero byte at offset 6.",
+		e.Error(),
+	)
+}
+
+func TestDecoderGivenRecordFailingToDecode(t *testing.T) {
+	type (
+		alignedChunkFormat struct {
+			ChunkFormatWord0  `word:"32"`
+			RecordFormatWord0 `word:"8"`
+			RecordFormatWord1 `word:"32,align=8,padding=zero"`
+			Payload           Payload `payload:"Length"`
+		}
+	)
+
+	var (
+		chunk   alignedChunkFormat
+		codec   *Codec
+		decoder *Decoder
+		e       error
+		payload []byte
+		stream  []byte
+	)
+
+	stream = []byte{
+		0x01, 0x00, 0x00, 0x02, 0x00, 0x00, 0xff, 0x00,
+		0x00, 0x00, 0x00, 0x00, 'a', 'b',
+		0x02, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 'c',
+	}
+
+	// The payload of a record failing to decode is skipped all the same.
+
+	decoder = NewDecoder(
+		bytes.NewReader(stream),
+	)
+
+	e = decoder.Decode(&chunk)
+
+	assert.NotNil(t, e)
+
+	assert.Equal(t,
+		alignedChunkFormat{}, chunk,
+	)
+
+	e = decoder.Decode(&chunk)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint8(0x02), chunk.ChunkFormatWord0.Type,
+	)
+
+	payload, e = io.ReadAll(chunk.Payload)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		"c", string(payload),
+	)
+
+	// Decoding on a best-effort basis sets the payload of such a record.
+
+	codec = NewCodec(nil)
+
+	codec.SetUnmarshalPolicy(UnmarshalBestEffort)
+
+	decoder = codec.NewDecoder(
+		bytes.NewReader(stream),
+	)
+
+	e = decoder.Decode(&chunk)
+
+	assert.NotNil(t, e)
+
+	assert.Equal(t,
+		uint8(0x01), chunk.ChunkFormatWord0.Type,
+	)
+
+	payload, e = io.ReadAll(chunk.Payload)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		"ab", string(payload),
+	)
+
+	e = decoder.Decode(&chunk)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		uint8(0x02), chunk.ChunkFormatWord0.Type,
+	)
+
+	e = decoder.Decode(&chunk)
+
+	assert.Equal(t,
+		io.EOF, e,
+	)
+}
+
+func TestDecoderPositionErrorGivenUnmarshalBestEffort(t *testing.T) {
+	var (
+		codec         *Codec
+		decoder       *Decoder
+		decodingError *DecodingError
+		e             error
+		paddingError  *PaddingError
+		positionError *PositionError
+		sections      sectionsFormat
+		stream        []byte
+	)
+
+	stream = []byte{
+		0x01, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00,
+		0x03, 0x04, 0x05, 0x06, 0x00, 0x00, 0x00, 0x00,
+		0x07,
+		0x01, 0x00, 0xff, 0x00, 0x02, 0x00, 0x00, 0x00,
+		0x03, 0x04, 0x05, 0x06, 0x00, 0x01, 0x00, 0x00,
+		0x07,
+	}
+
+	codec = NewCodec(nil)
+
+	codec.SetUnmarshalPolicy(UnmarshalBestEffort)
+
+	decoder = codec.NewDecoder(
+		bytes.NewReader(stream),
+	)
+
+	e = decoder.Decode(&sections)
+
+	assert.Nil(t, e)
+
+	e = decoder.Decode(&sections)
+
+	assert.True(t,
+		errors.As(e, &decodingError),
+	)
+
+	assert.True(t,
+		errors.As(e, &positionError),
+	)
+
+	assert.Equal(t,
+		PositionError{
+			Record:    1,
+			Offset:    17,
+			BitOffset: 136,
+			Err:       decodingError,
+		},
+		*positionError,
+	)
+
+	assert.Equal(t,
+		2, len(decodingError.Errors()),
+	)
+
+	// Each error of the DecodingError locates its own word.
+
+	assert.True(t,
+		errors.As(decodingError.Errors()[0], &positionError),
+	)
+
+	assert.Equal(t,
+		int64(19), positionError.Offset,
+	)
+
+	assert.Equal(t,
+		int64(152), positionError.BitOffset,
+	)
+
+	assert.True(t,
+		errors.As(decodingError.Errors()[1], &positionError),
+	)
+
+	assert.Equal(t,
+		int64(30), positionError.Offset,
+	)
+
+	// The bit offset locates the first non-zero bit of the padding.
+
+	assert.Equal(t,
+		int64(247), positionError.BitOffset,
+	)
+
+	assert.True(t,
+		errors.As(decodingError.Errors()[1], &paddingError),
+	)
+
+	assert.Equal(t,
+		"record 1 at byte offset 30 (bit offset 247): "+
+			"Padding declared \"padding=zero\" should be zero. "+
+			"Argument to Decode points to a format-struct "+
+			"\"binary.sectionsFormat\" "+
+			"that has a word \"Last\" "+
+			"preceded by padding with a non-zero byte at offset 13.",
+		decodingError.Errors()[1].Error(),
+	)
+}
+
+func TestShouldReturnErrorGivenTrailerInStream(t *testing.T) {
+	var (
+		buffer  bytes.Buffer
+		e       error
+		trailer trailerFormat
+	)
+
+	e = NewEncoder(&buffer).Encode(&trailer)
+
+	assert.Equal(t,
+		"Encode error: "+
+			"A trailer should be the last field of a format-struct "+
+			"not nested in another, of a type the pointers to which "+
+			"implement binary.Trailer, "+
+			"and should not be streamed by an Encoder or Decoder "+
+			"(e.g. `trailer:\"\"`). "+
+			"Argument to Encode points to a format-struct "+
+			"\"binary.trailerFormat\" "+
+			"that has an invalid trailer \"Trailer\".",
+		e.Error(),
+	)
+
+	e = NewDecoder(
+		bytes.NewReader([]byte{0x01}),
+	).Decode(&trailer)
+
+	assert.NotNil(t, e)
+
+	assert.Zero(t,
+		buffer.Len(),
+	)
+}
+
+func TestShouldReturnErrorGivenInvalidPayload(t *testing.T) {
+	var (
+		e error
+	)
+
+	_, e = Marshal(
+		&struct {
+			ChunkFormatWord0 `word:"32"`
+			Payload          Payload `payload:"Size"`
+		}{},
+	)
+
+	assert.Contains(t, e.Error(),
+		"that has an invalid payload \"Payload\".",
+	)
+
+	_, e = Marshal(
+		&struct {
+			Payload          Payload `payload:"Length"`
+			ChunkFormatWord0 `word:"32"`
+		}{},
+	)
+
+	assert.Contains(t, e.Error(),
+		"that has an invalid payload \"Payload\".",
+	)
+
+	_, e = Marshal(
+		&struct {
+			ChunkFormatWord0 `word:"32"`
+			Payload          io.Reader `payload:"Length"`
+		}{},
+	)
+
+	assert.Contains(t, e.Error(),
+		"that has an invalid payload \"Payload\".",
+	)
+}
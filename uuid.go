@@ -0,0 +1,27 @@
+package binary
+
+import (
+	"github.com/encodingx/binary/internal/codecs"
+)
+
+// UUID is a 128-bit field holding a UUID (RFC 9562) or GUID.
+// It is declared in a format-struct as a field of type UUID,
+// optionally tagged with a key "uuid" and a value naming its layout,
+// followed by options of alignment as for words:
+//
+//	ID         binary.UUID
+//	TypeGUID   binary.UUID `uuid:"guid"`
+//	UniqueGUID binary.UUID `uuid:"guid,align=8"`
+//
+// By default, or with the layout "rfc9562", all bytes are big-endian.
+// The layout "guid" stores the first three groups little-endian
+// and the last two big-endian, as GPT, SMB and many Windows formats do.
+// Either way the bytes of a UUID value are in the order of its string form.
+type UUID = codecs.UUID
+
+// ParseUUID parses the string form of a UUID,
+// xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx in either case,
+// optionally enclosed in braces, as GUIDs often are.
+func ParseUUID(s string) (UUID, error) {
+	return codecs.ParseUUID(s)
+}
@@ -0,0 +1,141 @@
+package binary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	partitionFormat struct {
+		PartitionFormatWord0 `word:"8"`
+		TypeGUID             UUID `uuid:"guid,align=4"`
+		ID                   UUID
+	}
+
+	PartitionFormatWord0 struct {
+		Index uint8 `bitfield:"8"`
+	}
+)
+
+func TestUUID(t *testing.T) {
+	const (
+		// The type GUID of an EFI system partition
+		efiSystemPartition = "c12a7328-f81f-11d2-ba4b-00a0c93ec93b"
+	)
+
+	var (
+		bytes      []byte
+		e          error
+		partition  partitionFormat
+		partition1 partitionFormat
+	)
+
+	partition.Index = 0x01
+
+	partition.TypeGUID, e = ParseUUID(efiSystemPartition)
+
+	assert.Nil(t, e)
+
+	partition.ID, e = ParseUUID("{C12A7328-F81F-11D2-BA4B-00A0C93EC93B}")
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		efiSystemPartition, partition.ID.String(),
+	)
+
+	bytes, e = Marshal(&partition)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		[]byte{
+			0x01, 0x00, 0x00, 0x00,
+			0x28, 0x73, 0x2a, 0xc1, 0x1f, 0xf8, 0xd2, 0x11,
+			0xba, 0x4b, 0x00, 0xa0, 0xc9, 0x3e, 0xc9, 0x3b,
+			0xc1, 0x2a, 0x73, 0x28, 0xf8, 0x1f, 0x11, 0xd2,
+			0xba, 0x4b, 0x00, 0xa0, 0xc9, 0x3e, 0xc9, 0x3b,
+		},
+		bytes,
+	)
+
+	e = Unmarshal(bytes, &partition1)
+
+	assert.Nil(t, e)
+
+	assert.Equal(t,
+		partition, partition1,
+	)
+}
+
+func TestParseUUID(t *testing.T) {
+	var (
+		e error
+		s string
+	)
+
+	for _, s = range []string{
+		"",
+		"c12a7328f81f11d2ba4b00a0c93ec93b",
+		"c12a7328-f81f-11d2-ba4b-00a0c93ec93",
+		"c12a7328-f81f-11d2-ba4b00-a0c93ec93b",
+		"c12a7328-f81f-11d2-ba4b-00a0c93ec93g",
+		"{c12a7328-f81f-11d2-ba4b-00a0c93ec93b",
+	} {
+		_, e = ParseUUID(s)
+
+		assert.Equal(t,
+			"ParseUUID error: "+
+				"A UUID should be of the form "+
+				"xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx. "+
+				"Argument to ParseUUID \""+s+"\" is not.",
+			e.Error(),
+		)
+	}
+}
+
+type (
+	arrayUUIDFormat struct {
+		PartitionFormatWord0 `word:"8"`
+		ID                   [16]byte `uuid:""`
+	}
+
+	mixedUUIDFormat struct {
+		PartitionFormatWord0 `word:"8"`
+		ID                   UUID `uuid:"mixed"`
+	}
+)
+
+func TestShouldReturnErrorGivenUUIDOfUnsupportedType(t *testing.T) {
+	var (
+		e error
+	)
+
+	_, e = Marshal(&arrayUUIDFormat{})
+
+	assert.Equal(t,
+		"Marshal error: "+
+			"A field tagged with a key \"uuid\" "+
+			"should be of type binary.UUID. "+
+			"Argument to Marshal points to a format-struct "+
+			"\"binary.arrayUUIDFormat\" "+
+			"that has a UUID \"ID\" "+
+			"of unsupported type \"[16]uint8\".",
+		e.Error(),
+	)
+
+	_, e = Marshal(&mixedUUIDFormat{})
+
+	assert.Equal(t,
+		"Marshal error: "+
+			"Options following the length of a word in its struct tag "+
+			"should be supported and well-formed "+
+			"(e.g. `word:\"64,mac=hmac-sha256\"`). "+
+			"Argument to Marshal points to a format-struct "+
+			"\"binary.mixedUUIDFormat\" "+
+			"that has a word \"ID\" "+
+			"with an invalid option \"mixed\".",
+		e.Error(),
+	)
+}